@@ -0,0 +1,156 @@
+package analysis
+
+import (
+	"errors"
+	"math"
+
+	tgmath "github.com/bcokert/terragen/math"
+	"github.com/bcokert/terragen/noise"
+)
+
+// Derivatives are the first and second partial derivatives of a 2D heightfield at each of its samples, in world units
+// The x axis runs along the first dimension of the noise and the y axis along the second, which is down the image in previews
+// Each channel is laid out the same way as the noise values
+type Derivatives struct {
+	Width  int
+	Height int
+	DX     []float64
+	DY     []float64
+	DXX    []float64
+	DYY    []float64
+	DXY    []float64
+}
+
+// NewDerivatives estimates the derivatives of 2D noise with finite differences, using the spacing between its samples
+// ZFactor exaggerates (or flattens) the noise values relative to that spacing
+// Samples on the edges reuse the differences of their nearest interior neighbour, so the edges don't flatten out
+func NewDerivatives(noise *noise.Noise, zFactor float64) (*Derivatives, error) {
+	shape := noise.Shape()
+	if len(shape) != 2 {
+		return nil, errors.New("Derivatives can only be computed from 2 dimensional noise")
+	}
+	width, height := shape[0], shape[1]
+	spacing := 1 / float64(noise.Resolution)
+
+	at := func(x, y int) float64 {
+		return noise.Values[x*height+y] * zFactor
+	}
+
+	derivatives := &Derivatives{
+		Width:  width,
+		Height: height,
+		DX:     make([]float64, len(noise.Values)),
+		DY:     make([]float64, len(noise.Values)),
+		DXX:    make([]float64, len(noise.Values)),
+		DYY:    make([]float64, len(noise.Values)),
+		DXY:    make([]float64, len(noise.Values)),
+	}
+
+	for x := 0; x < width; x++ {
+		for y := 0; y < height; y++ {
+			index := x*height + y
+
+			// Differences are centred on the nearest sample with neighbours on both sides, and are zero across dimensions that don't have any
+			cx, cy := interiorIndex(x, width), interiorIndex(y, height)
+			if width > 2 {
+				derivatives.DX[index] = (at(cx+1, y) - at(cx-1, y)) / (2 * spacing)
+				derivatives.DXX[index] = (at(cx+1, y) - 2*at(cx, y) + at(cx-1, y)) / (spacing * spacing)
+			} else if width == 2 {
+				derivatives.DX[index] = (at(1, y) - at(0, y)) / spacing
+			}
+			if height > 2 {
+				derivatives.DY[index] = (at(x, cy+1) - at(x, cy-1)) / (2 * spacing)
+				derivatives.DYY[index] = (at(x, cy+1) - 2*at(x, cy) + at(x, cy-1)) / (spacing * spacing)
+			} else if height == 2 {
+				derivatives.DY[index] = (at(x, 1) - at(x, 0)) / spacing
+			}
+			if width > 2 && height > 2 {
+				derivatives.DXY[index] = (at(cx+1, cy+1) - at(cx+1, cy-1) - at(cx-1, cy+1) + at(cx-1, cy-1)) / (4 * spacing * spacing)
+			}
+		}
+	}
+
+	return derivatives, nil
+}
+
+// Slope returns the steepness of each sample in degrees, from 0 for flat ground to 90 for a vertical cliff
+func (derivatives *Derivatives) Slope() []float64 {
+	slopes := make([]float64, len(derivatives.DX))
+	for i := range slopes {
+		slopes[i] = math.Atan(math.Hypot(derivatives.DX[i], derivatives.DY[i])) * 180 / math.Pi
+	}
+	return slopes
+}
+
+// Aspect returns the compass direction each sample faces downhill in degrees, clockwise from the top of the image like a Hillshade azimuth
+// Flat samples have no aspect, and are -1
+func (derivatives *Derivatives) Aspect() []float64 {
+	aspects := make([]float64, len(derivatives.DX))
+	for i := range aspects {
+		if derivatives.DX[i] == 0 && derivatives.DY[i] == 0 {
+			aspects[i] = -1
+			continue
+		}
+		// Downhill is (-dx, -dy), and the top of the image is -y
+		aspect := math.Atan2(-derivatives.DX[i], derivatives.DY[i]) * 180 / math.Pi
+		if aspect < 0 {
+			aspect += 360
+		}
+		aspects[i] = aspect
+	}
+	return aspects
+}
+
+// ProfileCurvature returns the curvature of each sample along its direction of steepest descent, in 1 / world units
+// It is positive where the slope gets steeper downhill (convex, where flow speeds up) and negative where it flattens out (concave)
+// Flat samples have no direction, and are 0
+func (derivatives *Derivatives) ProfileCurvature() []float64 {
+	curvatures := make([]float64, len(derivatives.DX))
+	for i := range curvatures {
+		p, q := derivatives.DX[i], derivatives.DY[i]
+		gradient := p*p + q*q
+		if gradient == 0 {
+			continue
+		}
+		curvatures[i] = -(p*p*derivatives.DXX[i] + 2*p*q*derivatives.DXY[i] + q*q*derivatives.DYY[i]) / (gradient * math.Pow(1+gradient, 1.5))
+	}
+	return curvatures
+}
+
+// PlanCurvature returns the curvature of the contour line through each sample, in 1 / world units
+// It is positive on ridges and spurs where flow diverges, and negative in valleys where it converges
+// Flat samples have no contour, and are 0
+func (derivatives *Derivatives) PlanCurvature() []float64 {
+	curvatures := make([]float64, len(derivatives.DX))
+	for i := range curvatures {
+		p, q := derivatives.DX[i], derivatives.DY[i]
+		gradient := p*p + q*q
+		if gradient == 0 {
+			continue
+		}
+		curvatures[i] = -(q*q*derivatives.DXX[i] - 2*p*q*derivatives.DXY[i] + p*p*derivatives.DYY[i]) / math.Pow(gradient, 1.5)
+	}
+	return curvatures
+}
+
+// Normals returns the unit surface normal of each sample, in the same axes as the derivatives with z up
+func (derivatives *Derivatives) Normals() []tgmath.Vec3 {
+	normals := make([]tgmath.Vec3, len(derivatives.DX))
+	for i := range normals {
+		normal := tgmath.Vec3{-derivatives.DX[i], -derivatives.DY[i], 1}
+		normal.Normalize()
+		normals[i] = normal
+	}
+	return normals
+}
+
+// interiorIndex clamps an index to the samples that have neighbours on both sides
+func interiorIndex(i, length int) int {
+	if i < 1 {
+		return 1
+	}
+	if i > length-2 {
+		return length - 2
+	}
+	return i
+}
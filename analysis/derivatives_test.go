@@ -0,0 +1,136 @@
+package analysis_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/bcokert/terragen/analysis"
+	tgmath "github.com/bcokert/terragen/math"
+	"github.com/bcokert/terragen/noise"
+)
+
+func generate(resolution int, fn noise.Function) *noise.Noise {
+	terrain := noise.NewNoise("test")
+	terrain.Generate([]int{-2, -2}, []int{2, 2}, resolution, fn)
+	return terrain
+}
+
+func TestNewDerivatives(t *testing.T) {
+	testCases := map[string]struct {
+		Noise      *noise.Noise
+		ZFactor    float64
+		ExpectedDX float64
+		ExpectedDY float64
+	}{
+		"flat":             {Noise: generate(4, func(t []float64) float64 { return 3 }), ZFactor: 1, ExpectedDX: 0, ExpectedDY: 0},
+		"ramp":             {Noise: generate(4, func(t []float64) float64 { return 2*t[0] - t[1] }), ZFactor: 1, ExpectedDX: 2, ExpectedDY: -1},
+		"finer ramp":       {Noise: generate(16, func(t []float64) float64 { return 2*t[0] - t[1] }), ZFactor: 1, ExpectedDX: 2, ExpectedDY: -1},
+		"exaggerated ramp": {Noise: generate(4, func(t []float64) float64 { return 2*t[0] - t[1] }), ZFactor: 3, ExpectedDX: 6, ExpectedDY: -3},
+	}
+
+	for name, testCase := range testCases {
+		derivatives, err := analysis.NewDerivatives(testCase.Noise, testCase.ZFactor)
+		if err != nil {
+			t.Errorf("'%s' failed. Unexpected error: %s", name, err.Error())
+			continue
+		}
+
+		// Planes have the same gradient everywhere, including the edges, and no curvature
+		for i := range derivatives.DX {
+			if math.Abs(derivatives.DX[i]-testCase.ExpectedDX) > 1e-9 || math.Abs(derivatives.DY[i]-testCase.ExpectedDY) > 1e-9 {
+				t.Errorf("'%s' failed. Expected gradient (%v, %v) at %d, received (%v, %v)", name, testCase.ExpectedDX, testCase.ExpectedDY, i, derivatives.DX[i], derivatives.DY[i])
+				break
+			}
+			if math.Abs(derivatives.DXX[i]) > 1e-9 || math.Abs(derivatives.DYY[i]) > 1e-9 || math.Abs(derivatives.DXY[i]) > 1e-9 {
+				t.Errorf("'%s' failed. Expected no curvature at %d, received (%v, %v, %v)", name, i, derivatives.DXX[i], derivatives.DYY[i], derivatives.DXY[i])
+				break
+			}
+		}
+	}
+}
+
+func TestNewDerivatives_Dimensions(t *testing.T) {
+	line := noise.NewNoise("line")
+	line.Generate([]int{0}, []int{4}, 4, func(t []float64) float64 { return t[0] })
+
+	expected := "Derivatives can only be computed from 2 dimensional noise"
+	if _, err := analysis.NewDerivatives(line, 1); err == nil || err.Error() != expected {
+		t.Errorf("Expected error '%s', received %v", expected, err)
+	}
+}
+
+func TestDerivatives_SlopeAndAspect(t *testing.T) {
+	testCases := map[string]struct {
+		Fn             noise.Function
+		ExpectedSlope  float64
+		ExpectedAspect float64
+	}{
+		"flat":              {Fn: func(t []float64) float64 { return 0 }, ExpectedSlope: 0, ExpectedAspect: -1},
+		"facing top":        {Fn: func(t []float64) float64 { return t[1] }, ExpectedSlope: 45, ExpectedAspect: 0},
+		"facing right":      {Fn: func(t []float64) float64 { return -t[0] }, ExpectedSlope: 45, ExpectedAspect: 90},
+		"facing bottom":     {Fn: func(t []float64) float64 { return -t[1] }, ExpectedSlope: 45, ExpectedAspect: 180},
+		"facing left steep": {Fn: func(t []float64) float64 { return math.Sqrt(3) * t[0] }, ExpectedSlope: 60, ExpectedAspect: 270},
+	}
+
+	for name, testCase := range testCases {
+		derivatives, _ := analysis.NewDerivatives(generate(4, testCase.Fn), 1)
+		slopes, aspects := derivatives.Slope(), derivatives.Aspect()
+
+		for i := range slopes {
+			if math.Abs(slopes[i]-testCase.ExpectedSlope) > 1e-9 || math.Abs(aspects[i]-testCase.ExpectedAspect) > 1e-9 {
+				t.Errorf("'%s' failed. Expected slope %v and aspect %v, received %v and %v", name, testCase.ExpectedSlope, testCase.ExpectedAspect, slopes[i], aspects[i])
+				break
+			}
+		}
+	}
+}
+
+func TestDerivatives_Curvature(t *testing.T) {
+	testCases := map[string]struct {
+		Fn              noise.Function
+		ExpectedProfile float64
+		ExpectedPlan    float64
+	}{
+		// On the flank of a dome at (1, 0) the gradient is (-2, 0) and every second derivative is -2
+		"dome": {
+			Fn:              func(t []float64) float64 { return -(t[0]*t[0] + t[1]*t[1]) },
+			ExpectedProfile: 2 / math.Pow(5, 1.5),
+			ExpectedPlan:    1,
+		},
+		"bowl": {
+			Fn:              func(t []float64) float64 { return t[0]*t[0] + t[1]*t[1] },
+			ExpectedProfile: -2 / math.Pow(5, 1.5),
+			ExpectedPlan:    -1,
+		},
+		"plane": {
+			Fn:              func(t []float64) float64 { return t[0] + t[1] },
+			ExpectedProfile: 0,
+			ExpectedPlan:    0,
+		},
+	}
+
+	for name, testCase := range testCases {
+		terrain := generate(4, testCase.Fn)
+		derivatives, _ := analysis.NewDerivatives(terrain, 1)
+		shape := terrain.Shape()
+
+		// (1, 0) is 3 world units from the -2 origin along x, and 2 along y
+		index := (3*terrain.Resolution)*shape[1] + 2*terrain.Resolution
+		profile, plan := derivatives.ProfileCurvature()[index], derivatives.PlanCurvature()[index]
+		if math.Abs(profile-testCase.ExpectedProfile) > 1e-9 || math.Abs(plan-testCase.ExpectedPlan) > 1e-9 {
+			t.Errorf("'%s' failed. Expected profile %v and plan %v, received %v and %v", name, testCase.ExpectedProfile, testCase.ExpectedPlan, profile, plan)
+		}
+	}
+}
+
+func TestDerivatives_Normals(t *testing.T) {
+	derivatives, _ := analysis.NewDerivatives(generate(4, func(t []float64) float64 { return t[0] }), 1)
+	expected := tgmath.Vec3{-1 / math.Sqrt2, 0, 1 / math.Sqrt2}
+
+	for i, normal := range derivatives.Normals() {
+		if !normal.IsEqual(expected) {
+			t.Errorf("Expected normal %v at %d, received %v", expected, i, normal)
+			break
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package analysis
+
+import (
+	"errors"
+	"math"
+	"math/cmplx"
+
+	tgmath "github.com/bcokert/terragen/math"
+	"github.com/bcokert/terragen/noise"
+)
+
+// Spectrum is the radially averaged power spectrum of 1D or 2D noise
+// Frequencies are in cycles per unit, spaced by one cycle over the noise's longest dimension, and only frequencies with any power are included
+// Slope is the gradient of the line fitted to log power against log frequency, which is 0 for white noise, -1 for pink and -2 for red
+type Spectrum struct {
+	Frequencies []float64 `json:"frequencies"`
+	Power       []float64 `json:"power"`
+	Slope       float64   `json:"slope"`
+}
+
+// NewSpectrum computes the power spectrum of the noise with the FFT, up to the Nyquist frequency of its resolution
+// The noise is windowed before it's transformed, so the jump between its opposite edges doesn't leak power into every frequency
+func NewSpectrum(noise *noise.Noise) (*Spectrum, error) {
+	grid, extent, err := analysisGrid(noise)
+	if err != nil {
+		return nil, err
+	}
+
+	mean := tgmath.Mean(noise.Values)
+	windowed := make([]complex128, len(noise.Values))
+	for x := 0; x < grid[0]; x++ {
+		for y := 0; y < grid[1]; y++ {
+			index := x*grid[1] + y
+			windowed[index] = complex((noise.Values[index]-mean)*hann(x, grid[0])*hann(y, grid[1]), 0)
+		}
+	}
+
+	transform, err := tgmath.FFT2D(windowed, grid)
+	if err != nil {
+		return nil, err
+	}
+
+	// Each frequency is binned by its distance from the origin, in multiples of the lowest frequency of the longest dimension
+	spacing := 1 / math.Max(extent[0], extent[1])
+	bins := int(float64(noise.Resolution) / 2 / spacing)
+	sums, counts := make([]float64, bins+1), make([]int, bins+1)
+	for x := 0; x < grid[0]; x++ {
+		for y := 0; y < grid[1]; y++ {
+			frequency := math.Hypot(signedFrequency(x, grid[0])/extent[0], signedFrequency(y, grid[1])/extent[1])
+			if bin := int(math.Round(frequency / spacing)); bin >= 1 && bin <= bins {
+				sums[bin] += math.Pow(cmplx.Abs(transform[x*grid[1]+y]), 2) / float64(len(transform))
+				counts[bin]++
+			}
+		}
+	}
+
+	spectrum := &Spectrum{Frequencies: []float64{}, Power: []float64{}}
+	var logFrequencies, logPowers []float64
+	for bin := 1; bin <= bins; bin++ {
+		if counts[bin] == 0 || sums[bin] == 0 {
+			continue
+		}
+		frequency, power := float64(bin)*spacing, sums[bin]/float64(counts[bin])
+		spectrum.Frequencies = append(spectrum.Frequencies, frequency)
+		spectrum.Power = append(spectrum.Power, power)
+		logFrequencies, logPowers = append(logFrequencies, math.Log(frequency)), append(logPowers, math.Log(power))
+	}
+
+	// The slope is left at 0 when there aren't enough frequencies to fit a line to
+	if slope, _, err := tgmath.LinearRegression(logFrequencies, logPowers); err == nil {
+		spectrum.Slope = slope
+	}
+
+	return spectrum, nil
+}
+
+// analysisGrid returns the shape of 1D or 2D noise as a 2D grid, with 1D noise as a single column, along with the size of each dimension in units
+func analysisGrid(noise *noise.Noise) (grid [2]int, extent [2]float64, err error) {
+	shape := noise.Shape()
+	if len(shape) != 1 && len(shape) != 2 {
+		return grid, extent, errors.New("Noise can only be analysed in 1 or 2 dimensions")
+	}
+
+	grid, extent = [2]int{shape[0], 1}, [2]float64{float64(noise.To[0] - noise.From[0]), 1}
+	if len(shape) == 2 {
+		grid[1], extent[1] = shape[1], float64(noise.To[1]-noise.From[1])
+	}
+	return grid, extent, nil
+}
+
+// hann is the periodic Hann window, which tapers smoothly to zero at the start of a dimension
+func hann(index, n int) float64 {
+	if n < 2 {
+		return 1
+	}
+	return 0.5 * (1 - math.Cos(2*math.Pi*float64(index)/float64(n)))
+}
+
+// signedFrequency returns the frequency of the index of a transform of n values, in cycles over the n values
+// Indices past the middle are negative frequencies
+func signedFrequency(index, n int) float64 {
+	if index > n/2 {
+		return float64(index - n)
+	}
+	return float64(index)
+}
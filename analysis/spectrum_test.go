@@ -0,0 +1,103 @@
+package analysis_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/bcokert/terragen/analysis"
+	tgmath "github.com/bcokert/terragen/math"
+	"github.com/bcokert/terragen/noise"
+)
+
+func TestNewSpectrum(t *testing.T) {
+	testCases := map[string]struct {
+		From, To         []int
+		Resolution       int
+		Fn               noise.Function
+		ExpectedPeak     float64
+		ExpectedErrorMsg string
+	}{
+		"1d sinusoid": {
+			From: []int{0}, To: []int{4}, Resolution: 16,
+			Fn:           func(t []float64) float64 { return math.Sin(2 * math.Pi * 3 * t[0]) },
+			ExpectedPeak: 3,
+		},
+		"2d sinusoid": {
+			From: []int{0, 0}, To: []int{2, 2}, Resolution: 16,
+			Fn:           func(t []float64) float64 { return math.Sin(2*math.Pi*3*t[0]) + math.Sin(2*math.Pi*4*t[1]) },
+			ExpectedPeak: 3,
+		},
+		"non square": {
+			From: []int{0, 0}, To: []int{4, 1}, Resolution: 16,
+			Fn:           func(t []float64) float64 { return math.Cos(2 * math.Pi * 2 * t[1]) },
+			ExpectedPeak: 2,
+		},
+		"3d": {
+			From: []int{0, 0, 0}, To: []int{1, 1, 1}, Resolution: 4,
+			Fn:               func(t []float64) float64 { return 0 },
+			ExpectedErrorMsg: "Noise can only be analysed in 1 or 2 dimensions",
+		},
+	}
+
+	for name, testCase := range testCases {
+		sampled := noise.NewNoise("test")
+		sampled.Generate(testCase.From, testCase.To, testCase.Resolution, testCase.Fn)
+
+		spectrum, err := analysis.NewSpectrum(sampled)
+		if testCase.ExpectedErrorMsg != "" {
+			if err == nil || err.Error() != testCase.ExpectedErrorMsg {
+				t.Errorf("'%s' failed. Expected error %s, received %v", name, testCase.ExpectedErrorMsg, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("'%s' failed. Expected no error, received %s", name, err.Error())
+			continue
+		}
+
+		peak := 0
+		for i := range spectrum.Power {
+			if spectrum.Power[i] > spectrum.Power[peak] {
+				peak = i
+			}
+		}
+		if spectrum.Frequencies[peak] != testCase.ExpectedPeak {
+			t.Errorf("'%s' failed. Expected the peak to be at %v, received %v", name, testCase.ExpectedPeak, spectrum.Frequencies[peak])
+		}
+		if last := spectrum.Frequencies[len(spectrum.Frequencies)-1]; last > float64(testCase.Resolution)/2 {
+			t.Errorf("'%s' failed. Expected no frequencies past the Nyquist frequency, received %v", name, last)
+		}
+	}
+}
+
+func TestNewSpectrum_Slope(t *testing.T) {
+	for _, beta := range []float64{0, 1, 2, 2.5} {
+		values, _ := noise.SpectralSynthesis(beta)(tgmath.NewDefaultSource(42), []int{128, 128}, 0)
+		synthesized := &noise.Noise{Values: values, From: []int{0, 0}, To: []int{4, 4}, Resolution: 32}
+
+		spectrum, _ := analysis.NewSpectrum(synthesized)
+		if math.Abs(spectrum.Slope+beta) > 0.3 {
+			t.Errorf("'beta %v' failed. Expected a slope of about %v, received %v", beta, -beta, spectrum.Slope)
+		}
+	}
+}
+
+func TestNewSpectrum_Presets(t *testing.T) {
+	// The spectral presets should get redder in order, which is a regression test for their definitions
+	order := []string{"violet", "blue", "white", "pink", "red"}
+
+	slopes := make([]float64, len(order))
+	for i, name := range order {
+		sampled := noise.NewNoise(name)
+		sampled.Generate([]int{0}, []int{8}, 256, noise.SpectralPresets[name](tgmath.NewDefaultSource(42), []float64{1, 2, 4, 8, 16, 32, 64}))
+
+		spectrum, _ := analysis.NewSpectrum(sampled)
+		slopes[i] = spectrum.Slope
+	}
+
+	for i := 1; i < len(slopes); i++ {
+		if slopes[i] >= slopes[i-1] {
+			t.Errorf("Expected %s to have a lower slope than %s, received %v and %v", order[i], order[i-1], slopes[i], slopes[i-1])
+		}
+	}
+}
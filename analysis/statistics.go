@@ -0,0 +1,101 @@
+package analysis
+
+import (
+	"math"
+	"math/cmplx"
+
+	tgmath "github.com/bcokert/terragen/math"
+	"github.com/bcokert/terragen/noise"
+)
+
+// Statistics summarize the distribution of the values of 1D or 2D noise
+// The histogram has equal bins from Min to Max, and AutocorrelationLength is the distance in units at which the noise decorrelates
+type Statistics struct {
+	Mean                  float64 `json:"mean"`
+	Variance              float64 `json:"variance"`
+	Min                   float64 `json:"min"`
+	Max                   float64 `json:"max"`
+	Histogram             []int   `json:"histogram"`
+	AutocorrelationLength float64 `json:"autocorrelationLength"`
+}
+
+// NewStatistics computes the statistics of the noise, with the given number of histogram bins
+func NewStatistics(noise *noise.Noise, bins int) (*Statistics, error) {
+	grid, _, err := analysisGrid(noise)
+	if err != nil {
+		return nil, err
+	}
+
+	statistics := &Statistics{
+		Mean:     tgmath.Mean(noise.Values),
+		Variance: tgmath.Variance(noise.Values),
+	}
+	statistics.Min, statistics.Max = tgmath.MinMax(noise.Values)
+	if statistics.Histogram, err = tgmath.Histogram(noise.Values, bins, statistics.Min, statistics.Max); err != nil {
+		return nil, err
+	}
+
+	if statistics.AutocorrelationLength, err = autocorrelationLength(noise, grid, statistics.Mean); err != nil {
+		return nil, err
+	}
+	return statistics, nil
+}
+
+// autocorrelationLength returns the distance at which the radially averaged autocorrelation first falls below 1/e
+// The autocorrelation is the inverse FFT of the power, padded to twice the size so that lags don't wrap around the edges
+// Noise that never decorrelates has the longest lag it was measured over, and constant noise has a length of 0
+func autocorrelationLength(noise *noise.Noise, grid [2]int, mean float64) (float64, error) {
+	padded := [2]int{grid[0] * 2, grid[1]}
+	if grid[1] > 1 {
+		padded[1] = grid[1] * 2
+	}
+
+	values := make([]complex128, padded[0]*padded[1])
+	for x := 0; x < grid[0]; x++ {
+		for y := 0; y < grid[1]; y++ {
+			values[x*padded[1]+y] = complex(noise.Values[x*grid[1]+y]-mean, 0)
+		}
+	}
+
+	transform, err := tgmath.FFT2D(values, padded)
+	if err != nil {
+		return 0, err
+	}
+	for i, value := range transform {
+		transform[i] = complex(math.Pow(cmplx.Abs(value), 2), 0)
+	}
+	correlation, err := tgmath.InverseFFT2D(transform, padded)
+	if err != nil {
+		return 0, err
+	}
+	if real(correlation[0]) <= 0 {
+		return 0, nil
+	}
+
+	// Average the correlation over every lag at the same distance, in samples, relative to the correlation at no lag
+	longest := int(math.Max(float64(grid[0]), float64(grid[1]))) - 1
+	sums, counts := make([]float64, longest+1), make([]int, longest+1)
+	for x := 0; x < padded[0]; x++ {
+		for y := 0; y < padded[1]; y++ {
+			if lag := int(math.Round(math.Hypot(signedFrequency(x, padded[0]), signedFrequency(y, padded[1])))); lag <= longest {
+				sums[lag] += real(correlation[x*padded[1]+y]) / real(correlation[0])
+				counts[lag]++
+			}
+		}
+	}
+
+	threshold := 1 / math.E
+	previousLag, previous := 0, 1.0
+	for lag := 1; lag <= longest; lag++ {
+		if counts[lag] == 0 {
+			continue
+		}
+		current := sums[lag] / float64(counts[lag])
+		if current < threshold {
+			crossing := float64(previousLag) + float64(lag-previousLag)*(previous-threshold)/(previous-current)
+			return crossing / float64(noise.Resolution), nil
+		}
+		previousLag, previous = lag, current
+	}
+	return float64(longest) / float64(noise.Resolution), nil
+}
@@ -0,0 +1,67 @@
+package analysis_test
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/bcokert/terragen/analysis"
+	tgmath "github.com/bcokert/terragen/math"
+	"github.com/bcokert/terragen/noise"
+)
+
+func TestNewStatistics(t *testing.T) {
+	ramp := noise.NewNoise("ramp")
+	ramp.Generate([]int{0}, []int{1}, 4, func(t []float64) float64 { return t[0] })
+
+	statistics, err := analysis.NewStatistics(ramp, 2)
+	if err != nil {
+		t.Fatalf("Expected no error, received %s", err.Error())
+	}
+
+	expected := &analysis.Statistics{Mean: 0.375, Variance: 0.078125, Min: 0, Max: 0.75, Histogram: []int{2, 2}}
+	if statistics.Mean != expected.Mean || statistics.Variance != expected.Variance || statistics.Min != expected.Min || statistics.Max != expected.Max {
+		t.Errorf("Expected %+v, received %+v", expected, statistics)
+	}
+	if !reflect.DeepEqual(statistics.Histogram, expected.Histogram) {
+		t.Errorf("Expected histogram %v, received %v", expected.Histogram, statistics.Histogram)
+	}
+
+	if _, err := analysis.NewStatistics(ramp, 0); err == nil || err.Error() != "Histograms require at least 1 bin" {
+		t.Errorf("Expected a bins error, received %v", err)
+	}
+
+	cube := noise.NewNoise("cube")
+	cube.Generate([]int{0, 0, 0}, []int{1, 1, 1}, 2, func(t []float64) float64 { return 0 })
+	if _, err := analysis.NewStatistics(cube, 2); err == nil || err.Error() != "Noise can only be analysed in 1 or 2 dimensions" {
+		t.Errorf("Expected a dimensions error, received %v", err)
+	}
+}
+
+func TestNewStatistics_AutocorrelationLength(t *testing.T) {
+	sinusoid := func(period float64) noise.Function {
+		return func(t []float64) float64 { return math.Sin(2*math.Pi*t[0]/period) + math.Sin(2*math.Pi*t[1]/period) }
+	}
+	white := noise.Random(tgmath.NewDefaultSource(42))
+
+	testCases := map[string]struct {
+		Fn       noise.Function
+		Min, Max float64
+	}{
+		"constant":     {Fn: func(t []float64) float64 { return 2 }, Min: 0, Max: 0},
+		"white":        {Fn: white, Min: 0, Max: 1.0 / 16},
+		"short period": {Fn: sinusoid(1), Min: 0.22, Max: 0.32},
+		"long period":  {Fn: sinusoid(2), Min: 0.45, Max: 0.65},
+	}
+
+	for name, testCase := range testCases {
+		sampled := noise.NewNoise(name)
+		sampled.Generate([]int{0, 0}, []int{4, 4}, 16, testCase.Fn)
+
+		// Averaged over every direction, these sinusoids decorrelate a little over a quarter of their period away
+		statistics, _ := analysis.NewStatistics(sampled, 10)
+		if statistics.AutocorrelationLength < testCase.Min || statistics.AutocorrelationLength > testCase.Max {
+			t.Errorf("'%s' failed. Expected an autocorrelation length between %v and %v, received %v", name, testCase.Min, testCase.Max, statistics.AutocorrelationLength)
+		}
+	}
+}
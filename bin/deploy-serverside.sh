@@ -2,6 +2,7 @@
 
 SERVICE_DIRECTORY="/usr/local/terragen"
 CONFIG_DIRECTORY="/etc/terragen"
+DATA_DIRECTORY="/var/lib/terragen"
 
 # Stop the running service if it exists
 if [ -e "running_pid" ]; then
@@ -22,6 +23,10 @@ mv build/static/main.js ./build/static/${TERRAGEN_JAVASCRIPT_BUNDLE}.js
 # Deploy the new artifacts to the service directory
 sudo mv build/ ${SERVICE_DIRECTORY}
 
+# Make sure the database has a directory outside of the service directory, so it's kept between deploys
+sudo mkdir -p ${DATA_DIRECTORY}
+sudo chown "$(whoami)" ${DATA_DIRECTORY}
+
 # Deploy the configs
 sudo mkdir -p ${CONFIG_DIRECTORY}
 sudo mv etc/logrotate.conf ${CONFIG_DIRECTORY}/logrotate.conf
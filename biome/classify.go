@@ -0,0 +1,115 @@
+package biome
+
+import (
+	"errors"
+	"math"
+
+	"github.com/bcokert/terragen/noise"
+)
+
+// A Climate turns raw temperature and moisture noise into the climate of each sample
+// Temperature falls from EquatorTemperature at y = 0 to PoleTemperature at PoleDistance world units north or south of it,
+// then falls LapseRate degrees per unit of elevation above SeaLevel, and finally varies by up to TemperatureVariation degrees with the temperature noise
+// Moisture maps the moisture noise from [-1, 1] into [0, 1]
+type Climate struct {
+	SeaLevel             float64
+	EquatorTemperature   float64
+	PoleTemperature      float64
+	PoleDistance         float64
+	LapseRate            float64
+	TemperatureVariation float64
+}
+
+// NewDefaultClimate creates a Climate for an earth like world spanning 64 units from equator to pole
+func NewDefaultClimate() Climate {
+	return Climate{
+		SeaLevel:             0,
+		EquatorTemperature:   30,
+		PoleTemperature:      -25,
+		PoleDistance:         64,
+		LapseRate:            20,
+		TemperatureVariation: 5,
+	}
+}
+
+// A LegendEntry describes one biome of a Classification, and how many samples were classified as it
+type LegendEntry struct {
+	ID      int    `json:"id"`
+	Name    string `json:"name"`
+	Color   string `json:"color"`
+	Samples int    `json:"samples"`
+}
+
+// A Classification is the biome of every sample of a heightfield, along with the climate that decided it
+// Every channel is laid out the same way as the noise values
+type Classification struct {
+	Biomes      []int         `json:"biomes"`
+	Temperature []float64     `json:"temperature"`
+	Moisture    []float64     `json:"moisture"`
+	Legend      []LegendEntry `json:"legend"`
+}
+
+// Classify looks up the biome of each sample of 2D elevation noise in the table, using the climate given by the temperature and moisture noise
+// All three layers must cover the same range at the same resolution
+func Classify(elevation, temperature, moisture *noise.Noise, climate Climate, table Table) (*Classification, error) {
+	shape := elevation.Shape()
+	if len(shape) != 2 {
+		return nil, errors.New("Biomes can only be classified from 2 dimensional noise")
+	}
+	if len(temperature.Values) != len(elevation.Values) || len(moisture.Values) != len(elevation.Values) {
+		return nil, errors.New("Biome layers must all have the same shape")
+	}
+	height := shape[1]
+	spacing := 1 / float64(elevation.Resolution)
+
+	temperatures := make([]float64, len(elevation.Values))
+	moistures := make([]float64, len(elevation.Values))
+	for i, value := range elevation.Values {
+		y := float64(elevation.From[1]) + float64(i%height)*spacing
+		latitude := 1.0
+		if climate.PoleDistance > 0 {
+			latitude = math.Min(1, math.Abs(y)/climate.PoleDistance)
+		}
+
+		temperatures[i] = climate.EquatorTemperature +
+			(climate.PoleTemperature-climate.EquatorTemperature)*latitude -
+			climate.LapseRate*math.Max(0, value-climate.SeaLevel) +
+			climate.TemperatureVariation*temperature.Values[i]
+		moistures[i] = math.Max(0, math.Min(1, (moisture.Values[i]+1)/2))
+	}
+
+	return ClassifyChannels(elevation, temperatures, moistures, climate.SeaLevel, table)
+}
+
+// ClassifyChannels looks up the biome of each sample of 2D elevation noise in the table, from a temperature in degrees and a moisture
+// in [0, 1] for each sample, such as those from a climate simulation
+func ClassifyChannels(elevation *noise.Noise, temperature, moisture []float64, seaLevel float64, table Table) (*Classification, error) {
+	if len(elevation.Shape()) != 2 {
+		return nil, errors.New("Biomes can only be classified from 2 dimensional noise")
+	}
+	if len(temperature) != len(elevation.Values) || len(moisture) != len(elevation.Values) {
+		return nil, errors.New("Biome layers must all have the same shape")
+	}
+
+	classification := &Classification{
+		Biomes:      make([]int, len(elevation.Values)),
+		Temperature: temperature,
+		Moisture:    moisture,
+	}
+
+	counts := map[int]int{}
+	for i, value := range elevation.Values {
+		classification.Biomes[i] = table.Lookup(value-seaLevel, temperature[i], moisture[i])
+		counts[classification.Biomes[i]]++
+	}
+
+	classification.Legend = make([]LegendEntry, 0, len(table.Biomes)+1)
+	for _, biome := range table.Biomes {
+		classification.Legend = append(classification.Legend, LegendEntry{ID: biome.ID, Name: biome.Name, Color: biome.Color, Samples: counts[biome.ID]})
+	}
+	if counts[Unclassified] > 0 {
+		classification.Legend = append(classification.Legend, LegendEntry{ID: Unclassified, Name: "Unclassified", Color: "000000", Samples: counts[Unclassified]})
+	}
+
+	return classification, nil
+}
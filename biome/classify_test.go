@@ -0,0 +1,141 @@
+package biome_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/bcokert/terragen/biome"
+	"github.com/bcokert/terragen/noise"
+)
+
+func layer(fn noise.Function) *noise.Noise {
+	layer := noise.NewNoise("layer")
+	layer.Generate([]int{0, 0}, []int{2, 4}, 2, fn)
+	return layer
+}
+
+func TestClassify(t *testing.T) {
+	climate := biome.Climate{SeaLevel: 0, EquatorTemperature: 30, PoleTemperature: -10, PoleDistance: 4, LapseRate: 10, TemperatureVariation: 2}
+	table := biome.Table{Biomes: []biome.Biome{
+		{ID: 0, Name: "Sea", Color: "0000ff", Elevation: &biome.Range{Min: -10, Max: 0}},
+		{ID: 1, Name: "Hot", Color: "ff0000", Temperature: &biome.Range{Min: 20, Max: 100}},
+		{ID: 2, Name: "Cold", Color: "ffffff", Temperature: &biome.Range{Min: -100, Max: 0}},
+	}}
+
+	// The first half of the range is ocean, and the second is land a unit above sea level
+	elevation := layer(func(t []float64) float64 {
+		if t[0] < 1 {
+			return -1
+		}
+		return 1
+	})
+	temperature := layer(func(t []float64) float64 { return 0.5 })
+	moisture := layer(func(t []float64) float64 { return t[1] - 2 })
+
+	classification, err := biome.Classify(elevation, temperature, moisture, climate, table)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err.Error())
+	}
+
+	shape := elevation.Shape()
+	for x := 0; x < shape[0]; x++ {
+		for y := 0; y < shape[1]; y++ {
+			index := x*shape[1] + y
+			latitude := float64(y) / 2 / 4
+			expectedTemperature := 30 - 40*latitude + 1
+			if x >= 2 {
+				expectedTemperature -= 10
+			}
+			if math.Abs(classification.Temperature[index]-expectedTemperature) > 1e-9 {
+				t.Errorf("Expected temperature %v at (%d, %d), received %v", expectedTemperature, x, y, classification.Temperature[index])
+			}
+
+			expectedMoisture := math.Max(0, math.Min(1, (float64(y)/2-1)/2))
+			if math.Abs(classification.Moisture[index]-expectedMoisture) > 1e-9 {
+				t.Errorf("Expected moisture %v at (%d, %d), received %v", expectedMoisture, x, y, classification.Moisture[index])
+			}
+
+			expectedBiome := biome.Unclassified
+			switch {
+			case x < 2:
+				expectedBiome = 0
+			case expectedTemperature >= 20:
+				expectedBiome = 1
+			case expectedTemperature < 0:
+				expectedBiome = 2
+			}
+			if classification.Biomes[index] != expectedBiome {
+				t.Errorf("Expected biome %d at (%d, %d), received %d", expectedBiome, x, y, classification.Biomes[index])
+			}
+		}
+	}
+
+	expectedLegend := []biome.LegendEntry{
+		{ID: 0, Name: "Sea", Color: "0000ff", Samples: 16},
+		{ID: 1, Name: "Hot", Color: "ff0000", Samples: 2},
+		{ID: 2, Name: "Cold", Color: "ffffff", Samples: 6},
+		{ID: biome.Unclassified, Name: "Unclassified", Color: "000000", Samples: 8},
+	}
+	if len(classification.Legend) != len(expectedLegend) {
+		t.Fatalf("Expected legend %v, received %v", expectedLegend, classification.Legend)
+	}
+	for i := range expectedLegend {
+		if classification.Legend[i] != expectedLegend[i] {
+			t.Errorf("Expected legend entry %v, received %v", expectedLegend[i], classification.Legend[i])
+		}
+	}
+}
+
+func TestClassify_Errors(t *testing.T) {
+	flat := layer(func(t []float64) float64 { return 0 })
+	line := noise.NewNoise("line")
+	line.Generate([]int{0}, []int{4}, 2, func(t []float64) float64 { return 0 })
+	small := noise.NewNoise("small")
+	small.Generate([]int{0, 0}, []int{1, 1}, 2, func(t []float64) float64 { return 0 })
+
+	testCases := map[string]struct {
+		Elevation, Temperature, Moisture *noise.Noise
+		ExpectedErrorMsg                 string
+	}{
+		"1d":         {Elevation: line, Temperature: line, Moisture: line, ExpectedErrorMsg: "Biomes can only be classified from 2 dimensional noise"},
+		"mismatched": {Elevation: flat, Temperature: flat, Moisture: small, ExpectedErrorMsg: "Biome layers must all have the same shape"},
+	}
+
+	for name, testCase := range testCases {
+		_, err := biome.Classify(testCase.Elevation, testCase.Temperature, testCase.Moisture, biome.NewDefaultClimate(), biome.DefaultTable)
+		if err == nil || err.Error() != testCase.ExpectedErrorMsg {
+			t.Errorf("'%s' failed. Expected error '%v', received '%v'", name, testCase.ExpectedErrorMsg, err)
+		}
+	}
+}
+
+func TestClassifyChannels(t *testing.T) {
+	table := biome.Table{Biomes: []biome.Biome{
+		{ID: 0, Name: "Sea", Color: "0000ff", Elevation: &biome.Range{Min: -10, Max: 0}},
+		{ID: 1, Name: "Desert", Color: "ffff00", Moisture: &biome.Range{Min: 0, Max: 0.5}},
+		{ID: 2, Name: "Forest", Color: "00ff00", Moisture: &biome.Range{Min: 0.5, Max: 1}},
+	}}
+	elevation := &noise.Noise{Values: []float64{0.5, 1.5, 1.5, 1.5}, From: []int{0, 0}, To: []int{2, 2}, Resolution: 1}
+	temperature := []float64{10, 10, 10, 10}
+	moisture := []float64{0.9, 0.9, 0.1, 0.5}
+
+	classification, err := biome.ClassifyChannels(elevation, temperature, moisture, 1, table)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err.Error())
+	}
+
+	expected := []int{0, 2, 1, 2}
+	for i := range expected {
+		if classification.Biomes[i] != expected[i] {
+			t.Errorf("Expected biomes %v, received %v", expected, classification.Biomes)
+			break
+		}
+	}
+	if classification.Moisture[2] != 0.1 || classification.Temperature[0] != 10 {
+		t.Errorf("Expected the channels to be passed through, received %v and %v", classification.Temperature, classification.Moisture)
+	}
+
+	if _, err := biome.ClassifyChannels(elevation, temperature, moisture[:3], 1, table); err == nil || err.Error() != "Biome layers must all have the same shape" {
+		t.Errorf("Expected a shape error, received %v", err)
+	}
+}
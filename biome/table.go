@@ -0,0 +1,118 @@
+package biome
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+)
+
+// A Range is a half open interval [Min, Max) of a climate variable
+type Range struct {
+	Min float64 `json:"min"`
+	Max float64 `json:"max"`
+}
+
+// Contains returns whether the value lies within the range
+func (r *Range) Contains(value float64) bool {
+	return value >= r.Min && value < r.Max
+}
+
+// A Biome is one entry of a Table, which a sample belongs to when its climate lies within every range the biome sets
+// Ranges that aren't set don't restrict the biome. Color is a 6 digit hex color, for drawing the biome on a map
+type Biome struct {
+	ID          int    `json:"id"`
+	Name        string `json:"name"`
+	Color       string `json:"color"`
+	Elevation   *Range `json:"elevation,omitempty"`
+	Temperature *Range `json:"temperature,omitempty"`
+	Moisture    *Range `json:"moisture,omitempty"`
+}
+
+// A Table is a Whittaker style lookup from climate to biome. Biomes are checked in order, and the first match wins
+// Elevation is in noise units above sea level, temperature in degrees Celsius, and moisture in the range [0, 1]
+type Table struct {
+	Biomes []Biome `json:"biomes"`
+}
+
+// Unclassified is the id of samples that don't match any biome of a Table
+const Unclassified = -1
+
+// DefaultTable is a simplified Whittaker diagram, with oceans below sea level and snow on the coldest peaks
+var DefaultTable = Table{
+	Biomes: []Biome{
+		{ID: 0, Name: "Ocean", Color: "2e76b2", Elevation: &Range{Min: -1e9, Max: 0}},
+		{ID: 1, Name: "Snow", Color: "fafafc", Temperature: &Range{Min: -1e9, Max: -10}},
+		{ID: 2, Name: "Tundra", Color: "a0a890", Temperature: &Range{Min: -10, Max: 0}},
+		{ID: 3, Name: "Taiga", Color: "5a7a5c", Temperature: &Range{Min: 0, Max: 7}, Moisture: &Range{Min: 0.3, Max: 1e9}},
+		{ID: 4, Name: "Cold Desert", Color: "c8bc9a", Temperature: &Range{Min: 0, Max: 7}},
+		{ID: 5, Name: "Temperate Rainforest", Color: "2c6a3e", Temperature: &Range{Min: 7, Max: 20}, Moisture: &Range{Min: 0.7, Max: 1e9}},
+		{ID: 6, Name: "Temperate Forest", Color: "4a8c46", Temperature: &Range{Min: 7, Max: 20}, Moisture: &Range{Min: 0.4, Max: 0.7}},
+		{ID: 7, Name: "Grassland", Color: "9cb85a", Temperature: &Range{Min: 7, Max: 20}, Moisture: &Range{Min: 0.15, Max: 0.4}},
+		{ID: 8, Name: "Shrubland", Color: "b4a86a", Temperature: &Range{Min: 7, Max: 20}},
+		{ID: 9, Name: "Tropical Rainforest", Color: "1e5a28", Temperature: &Range{Min: 20, Max: 1e9}, Moisture: &Range{Min: 0.65, Max: 1e9}},
+		{ID: 10, Name: "Savanna", Color: "bcb04e", Temperature: &Range{Min: 20, Max: 1e9}, Moisture: &Range{Min: 0.25, Max: 0.65}},
+		{ID: 11, Name: "Desert", Color: "e2d296", Temperature: &Range{Min: 20, Max: 1e9}},
+	},
+}
+
+// ParseTable parses and validates a Table from JSON
+func ParseTable(data []byte) (Table, error) {
+	table := Table{}
+	if err := json.Unmarshal(data, &table); err != nil {
+		return Table{}, errors.New("Biome tables must be valid JSON")
+	}
+
+	if err := table.Validate(); err != nil {
+		return Table{}, err
+	}
+
+	return table, nil
+}
+
+// Validate checks that the table has biomes with unique non negative ids, hex colors, and ranges that aren't empty
+func (table Table) Validate() error {
+	if len(table.Biomes) == 0 {
+		return errors.New("Biome tables must have at least one biome")
+	}
+
+	ids := map[int]bool{}
+	for _, biome := range table.Biomes {
+		if biome.ID < 0 {
+			return errors.New("Biome ids must be non negative integers")
+		}
+		if ids[biome.ID] {
+			return errors.New("Biome ids must be unique")
+		}
+		ids[biome.ID] = true
+
+		if rgb, err := hex.DecodeString(biome.Color); err != nil || len(rgb) != 3 {
+			return errors.New("Biome colors must be 6 digit hex colors")
+		}
+
+		for _, r := range []*Range{biome.Elevation, biome.Temperature, biome.Moisture} {
+			if r != nil && r.Min >= r.Max {
+				return errors.New("Biome ranges must have a min less than their max")
+			}
+		}
+	}
+
+	return nil
+}
+
+// Lookup returns the id of the first biome whose ranges contain the climate, or Unclassified if none do
+func (table Table) Lookup(elevation, temperature, moisture float64) int {
+	for _, biome := range table.Biomes {
+		if biome.Elevation != nil && !biome.Elevation.Contains(elevation) {
+			continue
+		}
+		if biome.Temperature != nil && !biome.Temperature.Contains(temperature) {
+			continue
+		}
+		if biome.Moisture != nil && !biome.Moisture.Contains(moisture) {
+			continue
+		}
+		return biome.ID
+	}
+
+	return Unclassified
+}
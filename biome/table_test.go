@@ -0,0 +1,108 @@
+package biome_test
+
+import (
+	"testing"
+
+	"github.com/bcokert/terragen/biome"
+)
+
+func TestParseTable(t *testing.T) {
+	testCases := map[string]struct {
+		JSON             string
+		ExpectedBiomes   int
+		ExpectedErrorMsg string
+	}{
+		"valid": {
+			JSON:           `{"biomes": [{"id": 1, "name": "Sea", "color": "0000ff", "elevation": {"min": -10, "max": 0}}, {"id": 2, "name": "Land", "color": "00ff00"}]}`,
+			ExpectedBiomes: 2,
+		},
+		"invalid json": {
+			JSON:             `{"biomes": [`,
+			ExpectedErrorMsg: "Biome tables must be valid JSON",
+		},
+		"no biomes": {
+			JSON:             `{"biomes": []}`,
+			ExpectedErrorMsg: "Biome tables must have at least one biome",
+		},
+		"negative id": {
+			JSON:             `{"biomes": [{"id": -1, "name": "Sea", "color": "0000ff"}]}`,
+			ExpectedErrorMsg: "Biome ids must be non negative integers",
+		},
+		"duplicate ids": {
+			JSON:             `{"biomes": [{"id": 1, "name": "Sea", "color": "0000ff"}, {"id": 1, "name": "Land", "color": "00ff00"}]}`,
+			ExpectedErrorMsg: "Biome ids must be unique",
+		},
+		"invalid color": {
+			JSON:             `{"biomes": [{"id": 1, "name": "Sea", "color": "blue"}]}`,
+			ExpectedErrorMsg: "Biome colors must be 6 digit hex colors",
+		},
+		"empty range": {
+			JSON:             `{"biomes": [{"id": 1, "name": "Sea", "color": "0000ff", "moisture": {"min": 0.5, "max": 0.5}}]}`,
+			ExpectedErrorMsg: "Biome ranges must have a min less than their max",
+		},
+	}
+
+	for name, testCase := range testCases {
+		table, err := biome.ParseTable([]byte(testCase.JSON))
+		if testCase.ExpectedErrorMsg != "" {
+			if err == nil || err.Error() != testCase.ExpectedErrorMsg {
+				t.Errorf("'%s' failed. Expected error '%v', received '%v'", name, testCase.ExpectedErrorMsg, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("'%s' failed. An unexpected error occurred: %v", name, err.Error())
+			continue
+		}
+
+		if len(table.Biomes) != testCase.ExpectedBiomes {
+			t.Errorf("'%s' failed. Expected %d biomes, received %d", name, testCase.ExpectedBiomes, len(table.Biomes))
+		}
+	}
+}
+
+func TestTable_Lookup(t *testing.T) {
+	testCases := map[string]struct {
+		Elevation   float64
+		Temperature float64
+		Moisture    float64
+		Expected    string
+	}{
+		"ocean":               {Elevation: -0.5, Temperature: 25, Moisture: 1, Expected: "Ocean"},
+		"frozen peak":         {Elevation: 2, Temperature: -20, Moisture: 0.5, Expected: "Snow"},
+		"tundra":              {Elevation: 0.5, Temperature: -5, Moisture: 0.5, Expected: "Tundra"},
+		"taiga":               {Elevation: 0.5, Temperature: 3, Moisture: 0.5, Expected: "Taiga"},
+		"cold desert":         {Elevation: 0.5, Temperature: 3, Moisture: 0.1, Expected: "Cold Desert"},
+		"temperate forest":    {Elevation: 0.2, Temperature: 12, Moisture: 0.5, Expected: "Temperate Forest"},
+		"grassland":           {Elevation: 0.2, Temperature: 12, Moisture: 0.2, Expected: "Grassland"},
+		"tropical rainforest": {Elevation: 0.1, Temperature: 27, Moisture: 0.9, Expected: "Tropical Rainforest"},
+		"desert":              {Elevation: 0.1, Temperature: 27, Moisture: 0, Expected: "Desert"},
+		"shore":               {Elevation: 0, Temperature: 27, Moisture: 0.5, Expected: "Savanna"},
+	}
+
+	names := map[int]string{}
+	for _, b := range biome.DefaultTable.Biomes {
+		names[b.ID] = b.Name
+	}
+
+	for name, testCase := range testCases {
+		if result := names[biome.DefaultTable.Lookup(testCase.Elevation, testCase.Temperature, testCase.Moisture)]; result != testCase.Expected {
+			t.Errorf("'%s' failed. Expected %s, received %s", name, testCase.Expected, result)
+		}
+	}
+
+	if err := biome.DefaultTable.Validate(); err != nil {
+		t.Errorf("Expected the default table to be valid, received error: %s", err.Error())
+	}
+}
+
+func TestTable_LookupUnclassified(t *testing.T) {
+	table := biome.Table{Biomes: []biome.Biome{{ID: 3, Name: "Warm", Color: "ff0000", Temperature: &biome.Range{Min: 10, Max: 20}}}}
+
+	if result := table.Lookup(0, 5, 0.5); result != biome.Unclassified {
+		t.Errorf("Expected %d, received %d", biome.Unclassified, result)
+	}
+	if result := table.Lookup(0, 10, 0.5); result != 3 {
+		t.Errorf("Expected 3, received %d", result)
+	}
+}
@@ -0,0 +1,162 @@
+package brush
+
+import (
+	"errors"
+	"math"
+	"sync"
+
+	"github.com/bcokert/terragen/noise"
+)
+
+// An Operation is how a stroke changes the terrain beneath it
+type Operation string
+
+const (
+	// Raise adds the strength to the terrain
+	Raise Operation = "raise"
+	// Lower subtracts the strength from the terrain
+	Lower Operation = "lower"
+	// Flatten blends the terrain towards its height at the center of the stroke
+	Flatten Operation = "flatten"
+	// Smooth blends the terrain towards its average over a quarter of the stroke's radius
+	Smooth Operation = "smooth"
+)
+
+// A Stroke is a single edit of a brush, which is at full strength within its radius less its falloff, and fades to nothing at its radius
+// Strength is a height for Raise and Lower, and how much of the way to blend the terrain, from 0 to 1, for Flatten and Smooth
+type Stroke struct {
+	Position  [2]float64 `json:"position"`
+	Radius    float64    `json:"radius"`
+	Falloff   float64    `json:"falloff"`
+	Operation Operation  `json:"operation"`
+	Strength  float64    `json:"strength"`
+}
+
+// Validate returns an error if the stroke can't be applied
+func (stroke Stroke) Validate() error {
+	if stroke.Radius <= 0 || math.IsInf(stroke.Radius, 0) || math.IsNaN(stroke.Radius) {
+		return errors.New("Radius must be a positive number")
+	}
+	if stroke.Falloff < 0 || stroke.Falloff > stroke.Radius {
+		return errors.New("Falloff must be between 0 and the radius")
+	}
+	if stroke.Strength < 0 || math.IsInf(stroke.Strength, 0) || math.IsNaN(stroke.Strength) {
+		return errors.New("Strength must be a non negative number")
+	}
+
+	switch stroke.Operation {
+	case Raise, Lower:
+	case Flatten, Smooth:
+		if stroke.Strength > 1 {
+			return errors.New("Strength must be between 0 and 1 for flatten and smooth")
+		}
+	default:
+		return errors.New("Operation must be one of raise, lower, flatten or smooth")
+	}
+
+	return nil
+}
+
+// Apply wraps a noise function with the stroke, so the edit is evaluated wherever the function is, at any resolution
+// Points outside of the stroke's radius are left as they are
+func (stroke Stroke) Apply(fn noise.Function) noise.Function {
+	weight := noise.RadialMask(stroke.Position[:], stroke.Radius, stroke.Falloff)
+
+	// Smooth averages the terrain on a lattice anchored to the stroke, so each point of it is evaluated once however many points are smoothed
+	// Evaluating the terrain around every point instead would evaluate the strokes beneath a smooth stroke exponentially many times
+	smoothing := newLattice(fn, stroke.Position, stroke.Radius/4)
+
+	// Flatten's target is the height beneath the center of the stroke, which is only evaluated once it's needed
+	var once sync.Once
+	var target float64
+	targetHeight := func() float64 {
+		once.Do(func() {
+			target = fn(stroke.Position[:])
+		})
+		return target
+	}
+
+	return func(t []float64) float64 {
+		height := fn(t)
+		w := weight(t)
+		if w == 0 {
+			return height
+		}
+
+		switch stroke.Operation {
+		case Raise:
+			return height + stroke.Strength*w
+		case Lower:
+			return height - stroke.Strength*w
+		case Flatten:
+			return height + (targetHeight()-height)*stroke.Strength*w
+		case Smooth:
+			return height + (smoothing.smoothed(t)-height)*stroke.Strength*w
+		default:
+			return height
+		}
+	}
+}
+
+// A lattice caches a function at points evenly spaced from an origin, evaluating each point the first time it's needed
+// It is safe to use concurrently
+type lattice struct {
+	fn      noise.Function
+	origin  [2]float64
+	spacing float64
+	mutex   sync.Mutex
+	values  map[[2]int]float64
+}
+
+func newLattice(fn noise.Function, origin [2]float64, spacing float64) *lattice {
+	return &lattice{fn: fn, origin: origin, spacing: spacing, values: map[[2]int]float64{}}
+}
+
+// at returns the function at the point x, y spacings from the origin
+func (lattice *lattice) at(x, y int) float64 {
+	lattice.mutex.Lock()
+	value, ok := lattice.values[[2]int{x, y}]
+	lattice.mutex.Unlock()
+	if ok {
+		return value
+	}
+
+	value = lattice.fn([]float64{lattice.origin[0] + float64(x)*lattice.spacing, lattice.origin[1] + float64(y)*lattice.spacing})
+	lattice.mutex.Lock()
+	lattice.values[[2]int{x, y}] = value
+	lattice.mutex.Unlock()
+	return value
+}
+
+// average returns the average of the function at the point x, y spacings from the origin and its 8 neighbours
+func (lattice *lattice) average(x, y int) float64 {
+	sum := 0.0
+	for dx := -1; dx <= 1; dx++ {
+		for dy := -1; dy <= 1; dy++ {
+			sum += lattice.at(x+dx, y+dy)
+		}
+	}
+	return sum / 9
+}
+
+// smoothed interpolates the averages of the 4 lattice points around t
+func (lattice *lattice) smoothed(t []float64) float64 {
+	fx, fy := (t[0]-lattice.origin[0])/lattice.spacing, (t[1]-lattice.origin[1])/lattice.spacing
+	x, y := int(math.Floor(fx)), int(math.Floor(fy))
+	u, v := fx-float64(x), fy-float64(y)
+
+	bottom := lattice.average(x, y)*(1-u) + lattice.average(x+1, y)*u
+	top := lattice.average(x, y+1)*(1-u) + lattice.average(x+1, y+1)*u
+	return bottom*(1-v) + top*v
+}
+
+// A Layer is a list of strokes, which are applied in order so later strokes edit the result of earlier ones
+type Layer []Stroke
+
+// Apply wraps a noise function with every stroke of the layer
+func (layer Layer) Apply(fn noise.Function) noise.Function {
+	for _, stroke := range layer {
+		fn = stroke.Apply(fn)
+	}
+	return fn
+}
@@ -0,0 +1,154 @@
+package brush_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/bcokert/terragen/brush"
+	"github.com/bcokert/terragen/noise"
+)
+
+func TestStroke_Validate(t *testing.T) {
+	testCases := map[string]struct {
+		Stroke        brush.Stroke
+		ExpectedError string
+	}{
+		"raise":           {Stroke: brush.Stroke{Radius: 2, Falloff: 1, Operation: brush.Raise, Strength: 5}},
+		"flatten":         {Stroke: brush.Stroke{Radius: 2, Falloff: 0, Operation: brush.Flatten, Strength: 1}},
+		"no radius":       {Stroke: brush.Stroke{Radius: 0, Operation: brush.Raise, Strength: 1}, ExpectedError: "Radius must be a positive number"},
+		"wide falloff":    {Stroke: brush.Stroke{Radius: 1, Falloff: 2, Operation: brush.Raise, Strength: 1}, ExpectedError: "Falloff must be between 0 and the radius"},
+		"negative":        {Stroke: brush.Stroke{Radius: 1, Operation: brush.Lower, Strength: -1}, ExpectedError: "Strength must be a non negative number"},
+		"strong smooth":   {Stroke: brush.Stroke{Radius: 1, Operation: brush.Smooth, Strength: 2}, ExpectedError: "Strength must be between 0 and 1 for flatten and smooth"},
+		"unknown":         {Stroke: brush.Stroke{Radius: 1, Operation: "carve", Strength: 1}, ExpectedError: "Operation must be one of raise, lower, flatten or smooth"},
+		"missing operand": {Stroke: brush.Stroke{Radius: 1, Strength: 1}, ExpectedError: "Operation must be one of raise, lower, flatten or smooth"},
+	}
+
+	for name, testCase := range testCases {
+		err := testCase.Stroke.Validate()
+		if testCase.ExpectedError == "" && err != nil {
+			t.Errorf("'%s' failed. Expected no error, received %s", name, err.Error())
+		}
+		if testCase.ExpectedError != "" && (err == nil || err.Error() != testCase.ExpectedError) {
+			t.Errorf("'%s' failed. Expected error %s, received %v", name, testCase.ExpectedError, err)
+		}
+	}
+}
+
+func TestStroke_Apply(t *testing.T) {
+	ramp := func(t []float64) float64 { return t[0] }
+	bowl := func(t []float64) float64 { return t[0]*t[0] + t[1]*t[1] }
+
+	testCases := map[string]struct {
+		Base     noise.Function
+		Stroke   brush.Stroke
+		Point    []float64
+		Expected float64
+	}{
+		"raise": {
+			Base:   ramp,
+			Stroke: brush.Stroke{Position: [2]float64{1, 1}, Radius: 2, Falloff: 1, Operation: brush.Raise, Strength: 3},
+			Point:  []float64{1.5, 1}, Expected: 4.5,
+		},
+		"raise in the falloff": {
+			Base:   ramp,
+			Stroke: brush.Stroke{Position: [2]float64{1, 1}, Radius: 2, Falloff: 1, Operation: brush.Raise, Strength: 3},
+			Point:  []float64{1, 2.5}, Expected: 1 + 3*0.5,
+		},
+		"lower": {
+			Base:   ramp,
+			Stroke: brush.Stroke{Position: [2]float64{1, 1}, Radius: 2, Falloff: 0, Operation: brush.Lower, Strength: 3},
+			Point:  []float64{2, 2}, Expected: -1,
+		},
+		"outside": {
+			Base:   ramp,
+			Stroke: brush.Stroke{Position: [2]float64{1, 1}, Radius: 2, Falloff: 1, Operation: brush.Raise, Strength: 3},
+			Point:  []float64{4, 1}, Expected: 4,
+		},
+		"flatten": {
+			Base:   ramp,
+			Stroke: brush.Stroke{Position: [2]float64{1, 1}, Radius: 2, Falloff: 1, Operation: brush.Flatten, Strength: 1},
+			Point:  []float64{1.8, 1}, Expected: 1,
+		},
+		"half flatten": {
+			Base:   ramp,
+			Stroke: brush.Stroke{Position: [2]float64{1, 1}, Radius: 2, Falloff: 1, Operation: brush.Flatten, Strength: 0.5},
+			Point:  []float64{0, 1}, Expected: 0.5,
+		},
+		"smooth a ramp": {
+			Base:   ramp,
+			Stroke: brush.Stroke{Position: [2]float64{0, 0}, Radius: 4, Falloff: 1, Operation: brush.Smooth, Strength: 1},
+			Point:  []float64{0.5, 0.5}, Expected: 0.5,
+		},
+		"smooth a bowl": {
+			// Averaging over the 3x3 points 1 apart around the center raises the bottom of the bowl by 12/9
+			Base:   bowl,
+			Stroke: brush.Stroke{Position: [2]float64{0, 0}, Radius: 4, Falloff: 1, Operation: brush.Smooth, Strength: 1},
+			Point:  []float64{0, 0}, Expected: 12.0 / 9,
+		},
+	}
+
+	for name, testCase := range testCases {
+		result := testCase.Stroke.Apply(testCase.Base)(testCase.Point)
+		if math.Abs(result-testCase.Expected) > 1e-9 {
+			t.Errorf("'%s' failed. Expected %v, received %v", name, testCase.Expected, result)
+		}
+	}
+}
+
+func TestLayer_Apply(t *testing.T) {
+	flat := func(t []float64) float64 { return 0 }
+	layer := brush.Layer{
+		{Position: [2]float64{0, 0}, Radius: 2, Operation: brush.Raise, Strength: 4},
+		{Position: [2]float64{1, 0}, Radius: 2, Operation: brush.Flatten, Strength: 0.5},
+		{Position: [2]float64{0, 0}, Radius: 1, Operation: brush.Lower, Strength: 1},
+	}
+
+	testCases := map[string]struct {
+		Point    []float64
+		Expected float64
+	}{
+		// Raised to 4, flattened halfway towards the 4 at (1, 0), then lowered by 1
+		"all strokes": {Point: []float64{0, 0}, Expected: 3},
+		// Flattened halfway from 0 towards 4
+		"flattened": {Point: []float64{2.5, 0}, Expected: 2},
+		"untouched": {Point: []float64{5, 5}, Expected: 0},
+	}
+
+	fn := layer.Apply(flat)
+	for name, testCase := range testCases {
+		if result := fn(testCase.Point); math.Abs(result-testCase.Expected) > 1e-9 {
+			t.Errorf("'%s' failed. Expected %v, received %v", name, testCase.Expected, result)
+		}
+	}
+
+	if result := (brush.Layer{}).Apply(flat)([]float64{0, 0}); result != 0 {
+		t.Errorf("Expected an empty layer to leave the function as it is, received %v", result)
+	}
+}
+
+func TestLayer_Apply_ManySmooth(t *testing.T) {
+	// Each smooth stroke evaluates the strokes beneath it on its own lattice, rather than around every point it smooths
+	// Points within the radius only reach the 13x13 lattice points within a radius and a half of the center, so each stroke adds at most 169 calls
+	calls := 0
+	bowl := func(t []float64) float64 {
+		calls++
+		return t[0]*t[0] + t[1]*t[1]
+	}
+
+	layer := brush.Layer{}
+	for i := 0; i < 64; i++ {
+		layer = append(layer, brush.Stroke{Position: [2]float64{0, 0}, Radius: 4, Falloff: 1, Operation: brush.Smooth, Strength: 0.5})
+	}
+
+	fn := layer.Apply(bowl)
+	points := [][]float64{{0, 0}, {0.3, -1.7}, {2.5, 2.5}, {5, 5}}
+	for _, point := range points {
+		if result := fn(point); math.IsNaN(result) || result < 0 {
+			t.Errorf("Expected smoothing a bowl to stay above 0 at %v, received %v", point, result)
+		}
+	}
+
+	if expected := len(layer)*13*13 + len(points); calls > expected {
+		t.Errorf("Expected at most %d calls to the base function, received %d", expected, calls)
+	}
+}
@@ -0,0 +1,131 @@
+package cache
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+
+	"github.com/bcokert/terragen/log"
+)
+
+// An Entry is a cached value, along with an ETag that changes whenever the value does
+type Entry struct {
+	Value []byte
+	ETag  string
+}
+
+// A Cache keeps the most recently used values in memory, up to a budget of bytes, and optionally keeps more of them on disk
+// Values found on disk are moved back into memory. It is safe to use concurrently
+type Cache struct {
+	mutex   sync.Mutex
+	budget  int
+	size    int
+	entries map[string]*list.Element
+	order   *list.List
+	disk    *Disk
+}
+
+// element is an entry in the cache's order, which is from most to least recently used
+type element struct {
+	key   string
+	entry Entry
+}
+
+// New creates an empty cache with a budget of bytes in memory, and an optional disk tier
+func New(budget int, disk *Disk) *Cache {
+	return &Cache{budget: budget, entries: map[string]*list.Element{}, order: list.New(), disk: disk}
+}
+
+// Key hashes the parts into a key, so equal parts always give the same key
+func Key(parts ...string) string {
+	hash := sha256.New()
+	for _, part := range parts {
+		hash.Write([]byte(part))
+		hash.Write([]byte{0})
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// Get returns the entry with the key, from memory or from disk
+func (cache *Cache) Get(key string) (Entry, bool) {
+	cache.mutex.Lock()
+	if e, ok := cache.entries[key]; ok {
+		cache.order.MoveToFront(e)
+		cache.mutex.Unlock()
+		return e.Value.(*element).entry, true
+	}
+	cache.mutex.Unlock()
+
+	if cache.disk == nil {
+		return Entry{}, false
+	}
+	value, ok := cache.disk.Get(key)
+	if !ok {
+		return Entry{}, false
+	}
+
+	entry := newEntry(value)
+	cache.mutex.Lock()
+	cache.add(key, entry)
+	cache.mutex.Unlock()
+	return entry, true
+}
+
+// Put saves the value under the key, and returns its entry
+func (cache *Cache) Put(key string, value []byte) Entry {
+	entry := newEntry(value)
+
+	cache.mutex.Lock()
+	cache.add(key, entry)
+	cache.mutex.Unlock()
+
+	if cache.disk != nil {
+		if err := cache.disk.Put(key, value); err != nil {
+			log.Error("Failed to cache %s on disk: %s", key, err.Error())
+		}
+	}
+	return entry
+}
+
+// Size returns the number of bytes the cache is using in memory
+func (cache *Cache) Size() int {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	return cache.size
+}
+
+// add saves the entry in memory, and evicts the least recently used entries until the cache is within its budget
+// Values larger than the whole budget aren't kept in memory. The cache must be locked
+func (cache *Cache) add(key string, entry Entry) {
+	if e, ok := cache.entries[key]; ok {
+		cache.remove(e)
+	}
+	if entrySize(key, entry) > cache.budget {
+		return
+	}
+
+	cache.entries[key] = cache.order.PushFront(&element{key: key, entry: entry})
+	cache.size += entrySize(key, entry)
+	for cache.size > cache.budget {
+		cache.remove(cache.order.Back())
+	}
+}
+
+// remove removes an entry from memory. The cache must be locked
+func (cache *Cache) remove(e *list.Element) {
+	removed := cache.order.Remove(e).(*element)
+	delete(cache.entries, removed.key)
+	cache.size -= entrySize(removed.key, removed.entry)
+}
+
+// newEntry creates an entry for a value, with an ETag from a hash of its content
+func newEntry(value []byte) Entry {
+	hash := sha256.Sum256(value)
+	return Entry{Value: value, ETag: `"` + hex.EncodeToString(hash[:16]) + `"`}
+}
+
+// entrySize is the number of bytes an entry counts towards the budget
+func entrySize(key string, entry Entry) int {
+	return len(key) + len(entry.Value) + len(entry.ETag)
+}
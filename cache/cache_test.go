@@ -0,0 +1,120 @@
+package cache_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/bcokert/terragen/cache"
+)
+
+func TestKey(t *testing.T) {
+	testCases := map[string]struct {
+		A, B          []string
+		ExpectedEqual bool
+	}{
+		"same":        {A: []string{"/noise", "seed=1"}, B: []string{"/noise", "seed=1"}, ExpectedEqual: true},
+		"different":   {A: []string{"/noise", "seed=1"}, B: []string{"/noise", "seed=2"}, ExpectedEqual: false},
+		"other parts": {A: []string{"/noise", "seed=1"}, B: []string{"/noi", "seseed=1"}, ExpectedEqual: false},
+		"empty":       {A: []string{}, B: []string{""}, ExpectedEqual: false},
+	}
+
+	for name, testCase := range testCases {
+		if equal := cache.Key(testCase.A...) == cache.Key(testCase.B...); equal != testCase.ExpectedEqual {
+			t.Errorf("'%s' failed. Expected %v, received %v", name, testCase.ExpectedEqual, equal)
+		}
+	}
+}
+
+func TestCache(t *testing.T) {
+	// Each entry is a 1 byte key, a 10 byte value and a 34 byte ETag, so 3 fit in the budget
+	results := cache.New(150, nil)
+	values := map[string][]byte{}
+	for _, key := range []string{"a", "b", "c"} {
+		values[key] = bytes.Repeat([]byte(key), 10)
+		results.Put(key, values[key])
+	}
+	if results.Size() != 135 {
+		t.Errorf("Expected the cache to use 135 bytes, received %d", results.Size())
+	}
+
+	// Using a makes b the least recently used entry, so it's evicted for d
+	results.Get("a")
+	values["d"] = bytes.Repeat([]byte("d"), 10)
+	results.Put("d", values["d"])
+
+	testCases := map[string]struct {
+		Key      string
+		Expected bool
+	}{
+		"used":    {Key: "a", Expected: true},
+		"evicted": {Key: "b", Expected: false},
+		"kept":    {Key: "c", Expected: true},
+		"newest":  {Key: "d", Expected: true},
+		"missing": {Key: "e", Expected: false},
+	}
+
+	for name, testCase := range testCases {
+		entry, ok := results.Get(testCase.Key)
+		if ok != testCase.Expected {
+			t.Errorf("'%s' failed. Expected %v, received %v", name, testCase.Expected, ok)
+			continue
+		}
+		if ok && !bytes.Equal(entry.Value, values[testCase.Key]) {
+			t.Errorf("'%s' failed. Expected %s, received %s", name, values[testCase.Key], entry.Value)
+		}
+	}
+
+	// Values too large for the budget aren't kept
+	results.Put("huge", make([]byte, 200))
+	if _, ok := results.Get("huge"); ok || results.Size() > 150 {
+		t.Errorf("Expected a value larger than the budget to not be kept, and the cache to use at most 150 bytes, received %d", results.Size())
+	}
+}
+
+func TestCache_ETag(t *testing.T) {
+	results := cache.New(1<<10, nil)
+	first := results.Put("a", []byte("one"))
+	same := results.Put("b", []byte("one"))
+	replaced := results.Put("a", []byte("two"))
+
+	if first.ETag != same.ETag {
+		t.Errorf("Expected equal values to have equal ETags, received %s and %s", first.ETag, same.ETag)
+	}
+	if first.ETag == replaced.ETag {
+		t.Errorf("Expected a changed value to change its ETag, received %s twice", first.ETag)
+	}
+	if entry, _ := results.Get("a"); entry.ETag != replaced.ETag || string(entry.Value) != "two" {
+		t.Errorf("Expected the replaced entry, received %+v", entry)
+	}
+}
+
+func TestCache_Disk(t *testing.T) {
+	dir, err := ioutil.TempDir("", "terragen")
+	if err != nil {
+		t.Fatalf("Failed to create a temporary directory: %s", err.Error())
+	}
+	defer os.RemoveAll(dir)
+
+	disk, err := cache.OpenDisk(dir, 1<<10)
+	if err != nil {
+		t.Fatalf("Failed to open the disk: %s", err.Error())
+	}
+
+	// Only one value fits in memory, so the other is only on disk
+	results := cache.New(60, disk)
+	first := results.Put("a", bytes.Repeat([]byte("a"), 20))
+	results.Put("b", bytes.Repeat([]byte("b"), 20))
+
+	entry, ok := results.Get("a")
+	if !ok || entry.ETag != first.ETag || !bytes.Equal(entry.Value, bytes.Repeat([]byte("a"), 20)) {
+		t.Errorf("Expected the evicted value to be found on disk, received %+v", entry)
+	}
+
+	// A new cache on the same directory finds values from before
+	disk, _ = cache.OpenDisk(dir, 1<<10)
+	if _, ok := cache.New(60, disk).Get("b"); !ok {
+		t.Errorf("Expected values to be kept on disk between caches")
+	}
+}
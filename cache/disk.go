@@ -0,0 +1,164 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// tempPrefix marks files that are still being written, so they're never read as values
+const tempPrefix = ".tmp-"
+
+// A Disk keeps cached values as files in a directory, up to a budget of bytes
+// Files are touched whenever they're read, so the least recently used files are removed first. It is safe to use concurrently
+type Disk struct {
+	mutex     sync.Mutex
+	directory string
+	budget    int64
+	size      int64
+}
+
+// OpenDisk opens the directory as a disk tier, creating it if it doesn't exist, and keeping any values already in it
+// Temporary files left by values that were being written when the service stopped are removed. Files that aren't values are left alone
+func OpenDisk(directory string, budget int64) (*Disk, error) {
+	if err := os.MkdirAll(directory, 0700); err != nil {
+		return nil, err
+	}
+
+	temps, err := filepath.Glob(filepath.Join(directory, tempPrefix+"*"))
+	if err != nil {
+		return nil, err
+	}
+	for _, temp := range temps {
+		if err := os.Remove(temp); err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+
+	disk := &Disk{directory: directory, budget: budget}
+	files, err := disk.files()
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		disk.size += file.Size()
+	}
+
+	disk.mutex.Lock()
+	defer disk.mutex.Unlock()
+	return disk, disk.evict()
+}
+
+// Get returns the value with the key, if it's on disk
+func (disk *Disk) Get(key string) ([]byte, bool) {
+	path := disk.path(key)
+	value, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+
+	now := time.Now()
+	os.Chtimes(path, now, now)
+	return value, true
+}
+
+// Put writes the value under the key, and removes the least recently used values until the disk is within its budget
+// Values are written to a temporary file first, so a value is never read half written
+func (disk *Disk) Put(key string, value []byte) error {
+	if int64(len(value)) > disk.budget {
+		return nil
+	}
+
+	temp, err := ioutil.TempFile(disk.directory, tempPrefix)
+	if err != nil {
+		return err
+	}
+	if _, err := temp.Write(value); err != nil {
+		temp.Close()
+		os.Remove(temp.Name())
+		return err
+	}
+	if err := temp.Close(); err != nil {
+		os.Remove(temp.Name())
+		return err
+	}
+
+	disk.mutex.Lock()
+	defer disk.mutex.Unlock()
+
+	path := disk.path(key)
+	if existing, err := os.Stat(path); err == nil {
+		disk.size -= existing.Size()
+	}
+	if err := os.Rename(temp.Name(), path); err != nil {
+		os.Remove(temp.Name())
+		return err
+	}
+	disk.size += int64(len(value))
+
+	return disk.evict()
+}
+
+// evict removes the least recently used values until the disk is within its budget. The disk must be locked
+func (disk *Disk) evict() error {
+	if disk.size <= disk.budget {
+		return nil
+	}
+
+	files, err := disk.files()
+	if err != nil {
+		return err
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].ModTime().Before(files[j].ModTime()) })
+
+	for _, file := range files {
+		if disk.size <= disk.budget {
+			break
+		}
+		if err := os.Remove(filepath.Join(disk.directory, file.Name())); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		disk.size -= file.Size()
+	}
+	return nil
+}
+
+// files returns every value in the directory, which are the files named like a path, so other files in the directory are never evicted
+func (disk *Disk) files() ([]os.FileInfo, error) {
+	all, err := ioutil.ReadDir(disk.directory)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]os.FileInfo, 0, len(all))
+	for _, file := range all {
+		if !file.IsDir() && isValueName(file.Name()) {
+			files = append(files, file)
+		}
+	}
+	return files, nil
+}
+
+// isValueName returns true if a file name is a hash written by path, which is 64 lowercase hex characters
+func isValueName(name string) bool {
+	if len(name) != hex.EncodedLen(sha256.Size) {
+		return false
+	}
+	for _, c := range name {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// path returns the file of a key, which is named by the key's hash so any key is a safe file name
+func (disk *Disk) path(key string) string {
+	hash := sha256.Sum256([]byte(key))
+	return filepath.Join(disk.directory, hex.EncodeToString(hash[:]))
+}
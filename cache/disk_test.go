@@ -0,0 +1,83 @@
+package cache_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bcokert/terragen/cache"
+)
+
+func TestDisk(t *testing.T) {
+	dir, err := ioutil.TempDir("", "terragen")
+	if err != nil {
+		t.Fatalf("Failed to create a temporary directory: %s", err.Error())
+	}
+	defer os.RemoveAll(dir)
+
+	disk, err := cache.OpenDisk(dir, 30)
+	if err != nil {
+		t.Fatalf("Failed to open the disk: %s", err.Error())
+	}
+
+	// Values are touched when they're read, so a is more recently used than b when c is written
+	disk.Put("a", bytes.Repeat([]byte("a"), 10))
+	disk.Put("b", bytes.Repeat([]byte("b"), 10))
+	past := time.Now().Add(-time.Hour)
+	for _, file := range mustReadDir(t, dir) {
+		os.Chtimes(filepath.Join(dir, file.Name()), past, past)
+	}
+	disk.Get("a")
+	disk.Put("c", bytes.Repeat([]byte("c"), 15))
+
+	testCases := map[string]struct {
+		Key      string
+		Expected []byte
+	}{
+		"used":    {Key: "a", Expected: bytes.Repeat([]byte("a"), 10)},
+		"evicted": {Key: "b", Expected: nil},
+		"newest":  {Key: "c", Expected: bytes.Repeat([]byte("c"), 15)},
+		"missing": {Key: "../d", Expected: nil},
+	}
+
+	for name, testCase := range testCases {
+		value, ok := disk.Get(testCase.Key)
+		if ok != (testCase.Expected != nil) || !bytes.Equal(value, testCase.Expected) {
+			t.Errorf("'%s' failed. Expected %s, received %s", name, testCase.Expected, value)
+		}
+	}
+
+	// Values larger than the budget aren't written
+	disk.Put("huge", make([]byte, 100))
+	if _, ok := disk.Get("huge"); ok {
+		t.Errorf("Expected a value larger than the budget to not be written")
+	}
+	if files := mustReadDir(t, dir); len(files) != 2 {
+		t.Errorf("Expected 2 files to be left, received %d", len(files))
+	}
+
+	// Reopening with a smaller budget evicts values down to it, removes temporary files, and leaves other files alone
+	ioutil.WriteFile(filepath.Join(dir, ".tmp-123"), make([]byte, 5), 0600)
+	ioutil.WriteFile(filepath.Join(dir, "terragen.db"), make([]byte, 100), 0600)
+	if _, err := cache.OpenDisk(dir, 20); err != nil {
+		t.Fatalf("Failed to reopen the disk: %s", err.Error())
+	}
+	names := []string{}
+	for _, file := range mustReadDir(t, dir) {
+		names = append(names, file.Name())
+	}
+	if len(names) != 2 || names[1] != "terragen.db" {
+		t.Errorf("Expected a value and the database to be left, received %v", names)
+	}
+}
+
+func mustReadDir(t *testing.T, dir string) []os.FileInfo {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("Failed to read %s: %s", dir, err.Error())
+	}
+	return files
+}
@@ -0,0 +1,146 @@
+package climate
+
+import (
+	"errors"
+	"math"
+
+	"github.com/bcokert/terragen/noise"
+)
+
+// Options controls how the prevailing wind carries moisture over a heightfield, and how temperature falls with latitude and elevation
+// Rates are per world unit the air travels, so the results don't depend on the resolution of the heightfield
+type Options struct {
+	Wind                   [2]float64 // The direction the prevailing wind blows towards, which only needs to be non zero
+	SeaLevel               float64
+	InflowHumidity         float64 // The humidity of air blowing in from beyond the heightfield, from 0 (dry) to 1 (saturated)
+	Evaporation            float64 // How quickly air over water takes up moisture
+	Rainfall               float64 // How quickly air loses moisture everywhere
+	Orographic             float64 // How much faster air loses moisture for each unit it's forced to rise per unit it travels
+	ReferencePrecipitation float64 // The precipitation that counts as fully moist
+	EquatorTemperature     float64
+	PoleTemperature        float64
+	PoleDistance           float64 // Temperature falls from EquatorTemperature at y = 0 to PoleTemperature this many units north or south of it
+	LapseRate              float64 // How many degrees temperature falls per unit of elevation above SeaLevel
+}
+
+// NewDefaultOptions returns options for a westerly wind blowing in from a saturated ocean, with the temperatures of biome.NewDefaultClimate
+func NewDefaultOptions() Options {
+	return Options{
+		Wind:                   [2]float64{1, 0},
+		SeaLevel:               0,
+		InflowHumidity:         1,
+		Evaporation:            2,
+		Rainfall:               0.1,
+		Orographic:             4,
+		ReferencePrecipitation: 0.2,
+		EquatorTemperature:     30,
+		PoleTemperature:        -25,
+		PoleDistance:           64,
+		LapseRate:              20,
+	}
+}
+
+// Weather is the climate of each sample of a heightfield
+// Every channel is laid out the same way as the noise values
+type Weather struct {
+	Humidity      []float64 `json:"humidity"`      // The humidity of the air after passing over the sample, from 0 to 1
+	Precipitation []float64 `json:"precipitation"` // The moisture the air dropped per unit it travelled over the sample
+	Moisture      []float64 `json:"moisture"`      // Precipitation relative to ReferencePrecipitation, clamped to [0, 1] for the biome classifier
+	Temperature   []float64 `json:"temperature"`   // Degrees
+}
+
+// Simulate carries moisture along the prevailing wind over 2D noise, treating its values as heights
+// Air takes up moisture over water and drops it as it's forced up windward slopes, so the lee sides of ridges are left in a rain shadow
+// Each sample takes its air from one step upwind, which lands between two samples that have already been simulated, so a single sweep
+// from the upwind edge is exact and deterministic
+func Simulate(noise *noise.Noise, options Options) (*Weather, error) {
+	shape := noise.Shape()
+	if len(shape) != 2 {
+		return nil, errors.New("Climate can only be simulated over 2 dimensional noise")
+	}
+	if options.Wind[0] == 0 && options.Wind[1] == 0 {
+		return nil, errors.New("Wind must not be zero")
+	}
+	width, height := shape[0], shape[1]
+	spacing := 1 / float64(noise.Resolution)
+
+	weather := &Weather{
+		Humidity:      make([]float64, len(noise.Values)),
+		Precipitation: make([]float64, len(noise.Values)),
+		Moisture:      make([]float64, len(noise.Values)),
+		Temperature:   make([]float64, len(noise.Values)),
+	}
+
+	// The step upwind is scaled so that it's exactly one sample along the axis the wind mostly follows
+	dominant, minor := 0, 1
+	if math.Abs(options.Wind[1]) > math.Abs(options.Wind[0]) {
+		dominant, minor = 1, 0
+	}
+	step := [2]float64{options.Wind[0] / math.Abs(options.Wind[dominant]), options.Wind[1] / math.Abs(options.Wind[dominant])}
+	distance := math.Hypot(step[0], step[1]) * spacing
+
+	// upwind interpolates a channel one step upwind of a sample, returning false when that's beyond the heightfield
+	upwind := func(channel []float64, sample [2]int) (float64, bool) {
+		var position [2]float64
+		for i := range position {
+			position[i] = float64(sample[i]) - step[i]
+		}
+		if position[0] < 0 || position[0] > float64(width-1) || position[1] < 0 || position[1] > float64(height-1) {
+			return 0, false
+		}
+
+		lower := [2]int{int(position[0]), int(position[1])}
+		upper := lower
+		upper[minor] = int(math.Min(float64(shape[minor]-1), float64(lower[minor]+1)))
+		t := position[minor] - float64(lower[minor])
+		return channel[lower[0]*height+lower[1]]*(1-t) + channel[upper[0]*height+upper[1]]*t, true
+	}
+
+	var sample [2]int
+	for i := 0; i < shape[dominant]; i++ {
+		sample[dominant] = i
+		if step[dominant] < 0 {
+			sample[dominant] = shape[dominant] - 1 - i
+		}
+
+		for j := 0; j < shape[minor]; j++ {
+			sample[minor] = j
+			index := sample[0]*height + sample[1]
+			elevation := noise.Values[index]
+
+			humidity, inside := upwind(weather.Humidity, sample)
+			rise := 0.0
+			if inside {
+				upwindElevation, _ := upwind(noise.Values, sample)
+				rise = math.Max(0, math.Max(elevation, options.SeaLevel)-math.Max(upwindElevation, options.SeaLevel)) / distance
+			} else {
+				humidity = options.InflowHumidity
+			}
+
+			if elevation <= options.SeaLevel {
+				humidity += (1 - humidity) * (1 - math.Exp(-options.Evaporation*distance))
+			}
+			rain := humidity * (1 - math.Exp(-(options.Rainfall+options.Orographic*rise)*distance))
+
+			weather.Humidity[index] = humidity - rain
+			weather.Precipitation[index] = rain / distance
+			if options.ReferencePrecipitation > 0 {
+				weather.Moisture[index] = math.Min(1, weather.Precipitation[index]/options.ReferencePrecipitation)
+			}
+			weather.Temperature[index] = temperature(float64(noise.From[1])+float64(sample[1])*spacing, elevation, options)
+		}
+	}
+
+	return weather, nil
+}
+
+// temperature returns the temperature at a latitude and elevation
+func temperature(y, elevation float64, options Options) float64 {
+	latitude := 1.0
+	if options.PoleDistance > 0 {
+		latitude = math.Min(1, math.Abs(y)/options.PoleDistance)
+	}
+	return options.EquatorTemperature +
+		(options.PoleTemperature-options.EquatorTemperature)*latitude -
+		options.LapseRate*math.Max(0, elevation-options.SeaLevel)
+}
@@ -0,0 +1,151 @@
+package climate_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/bcokert/terragen/climate"
+	"github.com/bcokert/terragen/noise"
+)
+
+// ridge returns 2D noise over 0..8 x 0..2 with ocean for x < 2, and a ridge peaking at x = 4 that falls back to lowland by x = 6
+func ridge(resolution int) *noise.Noise {
+	ridge := &noise.Noise{From: []int{0, 0}, To: []int{8, 2}, Resolution: resolution}
+	ridge.Generate(ridge.From, ridge.To, resolution, func(t []float64) float64 {
+		switch {
+		case t[0] < 2:
+			return -0.5
+		case t[0] < 6:
+			return 0.1 + 0.9*(1-math.Abs(t[0]-4)/2)
+		}
+		return 0.1
+	})
+	return ridge
+}
+
+// along averages a channel over the samples at x, across every y
+func along(channel []float64, field *noise.Noise, x float64) float64 {
+	shape := field.Shape()
+	column := int(math.Round((x - float64(field.From[0])) * float64(field.Resolution)))
+
+	sum := 0.0
+	for y := 0; y < shape[1]; y++ {
+		sum += channel[column*shape[1]+y]
+	}
+	return sum / float64(shape[1])
+}
+
+func TestSimulate_RainShadow(t *testing.T) {
+	field := ridge(4)
+	weather, err := climate.Simulate(field, climate.NewDefaultOptions())
+	if err != nil {
+		t.Fatalf("Expected no error, received %s", err.Error())
+	}
+
+	windward, lee, lowland := along(weather.Precipitation, field, 3.5), along(weather.Precipitation, field, 4.5), along(weather.Precipitation, field, 7)
+	if windward < 4*lee {
+		t.Errorf("Expected the windward slope to get many times the rain of the lee slope, received %v and %v", windward, lee)
+	}
+	if lowland >= along(weather.Precipitation, field, 1) {
+		t.Errorf("Expected the lowland in the rain shadow to be drier than the ocean, received %v and %v", lowland, along(weather.Precipitation, field, 1))
+	}
+	if before, after := along(weather.Humidity, field, 1.75), along(weather.Humidity, field, 6); after >= before/2 {
+		t.Errorf("Expected the ridge to wring out the air, received a humidity of %v before and %v after", before, after)
+	}
+
+	for i := range weather.Moisture {
+		if weather.Moisture[i] < 0 || weather.Moisture[i] > 1 {
+			t.Errorf("Expected moisture between 0 and 1, received %v", weather.Moisture[i])
+			break
+		}
+		if weather.Humidity[i] < 0 || weather.Humidity[i] > 1 {
+			t.Errorf("Expected humidity between 0 and 1, received %v", weather.Humidity[i])
+			break
+		}
+	}
+
+	again, _ := climate.Simulate(field, climate.NewDefaultOptions())
+	for i := range weather.Precipitation {
+		if again.Precipitation[i] != weather.Precipitation[i] {
+			t.Errorf("Expected the simulation to be deterministic")
+			break
+		}
+	}
+}
+
+func TestSimulate_Wind(t *testing.T) {
+	field := ridge(4)
+
+	testCases := map[string]struct {
+		Wind        [2]float64
+		WetterSlope float64 // The x of the slope that should be wetter
+		DrierSlope  float64
+	}{
+		"westerly":          {Wind: [2]float64{1, 0}, WetterSlope: 3.5, DrierSlope: 4.5},
+		"easterly":          {Wind: [2]float64{-1, 0}, WetterSlope: 4.5, DrierSlope: 3.5},
+		"diagonal westerly": {Wind: [2]float64{2, 1}, WetterSlope: 3.5, DrierSlope: 4.5},
+		"steep diagonal":    {Wind: [2]float64{1, -0.9}, WetterSlope: 3.5, DrierSlope: 4.5},
+	}
+
+	for name, testCase := range testCases {
+		options := climate.NewDefaultOptions()
+		options.Wind = testCase.Wind
+		weather, err := climate.Simulate(field, options)
+		if err != nil {
+			t.Errorf("'%s' failed. Expected no error, received %s", name, err.Error())
+			continue
+		}
+
+		if wetter, drier := along(weather.Precipitation, field, testCase.WetterSlope), along(weather.Precipitation, field, testCase.DrierSlope); wetter <= drier {
+			t.Errorf("'%s' failed. Expected the slope at %v to be wetter than at %v, received %v and %v", name, testCase.WetterSlope, testCase.DrierSlope, wetter, drier)
+		}
+	}
+}
+
+func TestSimulate_Resolution(t *testing.T) {
+	// Rates are per world unit, so the same terrain at double the resolution should get about the same climate
+	coarse, _ := climate.Simulate(ridge(4), climate.NewDefaultOptions())
+	fine, _ := climate.Simulate(ridge(8), climate.NewDefaultOptions())
+
+	for _, x := range []float64{1, 3.5, 4.5, 7} {
+		if a, b := along(coarse.Humidity, ridge(4), x), along(fine.Humidity, ridge(8), x); math.Abs(a-b) > 0.1 {
+			t.Errorf("Expected the humidity at %v to be about the same at both resolutions, received %v and %v", x, a, b)
+		}
+	}
+}
+
+func TestSimulate_Temperature(t *testing.T) {
+	options := climate.NewDefaultOptions()
+	field := &noise.Noise{Values: []float64{-1, 0.5}, From: []int{0, 32}, To: []int{2, 33}, Resolution: 1}
+
+	weather, _ := climate.Simulate(field, options)
+	expected := []float64{
+		30 + (-25-30)*0.5,
+		30 + (-25-30)*0.5 - 20*0.5,
+	}
+	for i := range expected {
+		if math.Abs(weather.Temperature[i]-expected[i]) > 0.00000000001 {
+			t.Errorf("Expected temperatures %v, received %v", expected, weather.Temperature)
+			break
+		}
+	}
+}
+
+func TestSimulate_Errors(t *testing.T) {
+	testCases := map[string]struct {
+		Noise    *noise.Noise
+		Wind     [2]float64
+		Expected string
+	}{
+		"1d":      {Noise: &noise.Noise{Values: []float64{0, 0}, From: []int{0}, To: []int{2}, Resolution: 1}, Wind: [2]float64{1, 0}, Expected: "Climate can only be simulated over 2 dimensional noise"},
+		"no wind": {Noise: ridge(1), Wind: [2]float64{0, 0}, Expected: "Wind must not be zero"},
+	}
+
+	for name, testCase := range testCases {
+		options := climate.NewDefaultOptions()
+		options.Wind = testCase.Wind
+		if _, err := climate.Simulate(testCase.Noise, options); err == nil || err.Error() != testCase.Expected {
+			t.Errorf("'%s' failed. Expected error %s, received %v", name, testCase.Expected, err)
+		}
+	}
+}
@@ -3,3 +3,5 @@
 export TERRAGEN_PORT="8080"
 export TERRAGEN_STATIC_ASSETS="build"
 export TERRAGEN_JAVASCRIPT_BUNDLE="main"
+export TERRAGEN_DATABASE="build/terragen.db"
+export TERRAGEN_CACHE_DIRECTORY="build/cache"
@@ -3,3 +3,5 @@
 export TERRAGEN_PORT="8080"
 export TERRAGEN_STATIC_ASSETS="/usr/local/terragen"
 #export TERRAGEN_JAVASCRIPT_BUNDLE="main" This is auto generated on deploy with a real hash
+export TERRAGEN_DATABASE="/var/lib/terragen/terragen.db"
+export TERRAGEN_CACHE_DIRECTORY="/var/lib/terragen/cache"
@@ -0,0 +1,81 @@
+package contour
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/bcokert/terragen/geojson"
+	"github.com/bcokert/terragen/noise"
+)
+
+// A Contour is every line along one level of a heightfield, in world coordinates
+type Contour struct {
+	Level float64 `json:"level"`
+	Lines []Line  `json:"lines"`
+}
+
+// IntervalLevels returns every multiple of interval that lies strictly between the lowest and highest values
+// It fails without building any levels if there would be more than limit of them, since a tiny interval gives billions
+func IntervalLevels(values []float64, interval float64, limit int) ([]float64, error) {
+	levels := []float64{}
+	if len(values) == 0 || interval <= 0 {
+		return levels, nil
+	}
+
+	min, max := math.Inf(1), math.Inf(-1)
+	for _, value := range values {
+		min = math.Min(min, value)
+		max = math.Max(max, value)
+	}
+
+	lower, upper := min/interval, max/interval
+	if math.IsInf(lower, 0) || math.IsNaN(lower) || math.IsInf(upper, 0) || math.IsNaN(upper) {
+		return nil, errors.New("Interval must give a finite number of levels")
+	}
+
+	first, last := math.Floor(lower)+1, math.Ceil(upper)-1
+	if last-first+1 > float64(limit) {
+		return nil, fmt.Errorf("Interval must give at most %d levels", limit)
+	}
+
+	// Levels are counted from the first rather than incremented, since adding 1 to a float above 2^53 doesn't change it
+	for k := 0; k < int(last-first+1); k++ {
+		if level := (first + float64(k)) * interval; level > min && level < max {
+			levels = append(levels, level)
+		}
+	}
+	return levels, nil
+}
+
+// Trace traces the contours of 2D noise at each of the levels, smoothing each line with the given number of iterations
+func Trace(noise *noise.Noise, levels []float64, smoothing int) ([]Contour, error) {
+	shape := noise.Shape()
+	if len(shape) != 2 {
+		return nil, errors.New("Contours can only be traced from 2 dimensional noise")
+	}
+
+	origin := [2]float64{float64(noise.From[0]), float64(noise.From[1])}
+	spacing := 1 / float64(noise.Resolution)
+
+	contours := make([]Contour, len(levels))
+	for i, level := range levels {
+		contours[i] = Contour{Level: level, Lines: []Line{}}
+		for _, line := range Isolines(noise.Values, shape[0], shape[1], level) {
+			contours[i].Lines = append(contours[i].Lines, line.Smooth(smoothing).Transform(origin, spacing))
+		}
+	}
+
+	return contours, nil
+}
+
+// ToGeoJSON converts contours into a collection of LineStrings, with the level of each line and whether it is closed as properties
+func ToGeoJSON(contours []Contour) *geojson.FeatureCollection {
+	collection := geojson.NewFeatureCollection()
+	for _, contour := range contours {
+		for _, line := range contour.Lines {
+			collection.Add(geojson.NewLineString(line), map[string]interface{}{"level": contour.Level, "closed": line.IsClosed()})
+		}
+	}
+	return collection
+}
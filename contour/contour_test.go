@@ -0,0 +1,92 @@
+package contour_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/bcokert/terragen/contour"
+	"github.com/bcokert/terragen/noise"
+)
+
+func TestIntervalLevels(t *testing.T) {
+	testCases := map[string]struct {
+		Values        []float64
+		Interval      float64
+		Expected      []float64
+		ExpectedError bool
+	}{
+		"positive":       {Values: []float64{0.1, 0.95}, Interval: 0.25, Expected: []float64{0.25, 0.5, 0.75}},
+		"negative":       {Values: []float64{-1, 0.5, 0}, Interval: 0.5, Expected: []float64{-0.5, 0}},
+		"exact extreme":  {Values: []float64{0, 1}, Interval: 0.5, Expected: []float64{0.5}},
+		"at the limit":   {Values: []float64{0, 1}, Interval: 0.25, Expected: []float64{0.25, 0.5, 0.75}},
+		"over the limit": {Values: []float64{0, 1}, Interval: 0.2, ExpectedError: true},
+		"tiny interval":  {Values: []float64{-1, 1}, Interval: 1e-9, ExpectedError: true},
+		"huge values":    {Values: []float64{1e17, 1e17 + 500}, Interval: 1, ExpectedError: true},
+		"huge levels":    {Values: []float64{1e17, 1e17 + 64}, Interval: 16, Expected: []float64{1e17 + 16, 1e17 + 32, 1e17 + 48}},
+		"infinite":       {Values: []float64{0, math.Inf(1)}, Interval: 1, ExpectedError: true},
+		"no interval":    {Values: []float64{0, 1}, Interval: 0, Expected: []float64{}},
+		"no values":      {Values: []float64{}, Interval: 1, Expected: []float64{}},
+	}
+
+	for name, testCase := range testCases {
+		result, err := contour.IntervalLevels(testCase.Values, testCase.Interval, 3)
+		if (err != nil) != testCase.ExpectedError {
+			t.Errorf("'%s' failed. Expected error %v, received %v", name, testCase.ExpectedError, err)
+			continue
+		}
+		if len(result) != len(testCase.Expected) {
+			t.Errorf("'%s' failed. Expected %v, received %v", name, testCase.Expected, result)
+			continue
+		}
+		for i := range result {
+			if math.Abs(result[i]-testCase.Expected[i]) > 1e-9 {
+				t.Errorf("'%s' failed. Expected %v, received %v", name, testCase.Expected, result)
+				break
+			}
+		}
+	}
+}
+
+func TestTrace(t *testing.T) {
+	// A cone centered on (2, 2), whose contours are circles of radius 2 - level
+	cone := noise.NewNoise("cone")
+	cone.Generate([]int{0, 0}, []int{4, 4}, 8, func(t []float64) float64 {
+		return 2 - math.Hypot(t[0]-2, t[1]-2)
+	})
+
+	contours, err := contour.Trace(cone, []float64{0.5, 1, 1.5}, 2)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err.Error())
+	}
+
+	for _, c := range contours {
+		if len(c.Lines) != 1 || !c.Lines[0].IsClosed() {
+			t.Errorf("Expected a single closed line at level %v, received %v", c.Level, c.Lines)
+			continue
+		}
+		for _, point := range c.Lines[0] {
+			if radius := math.Hypot(point[0]-2, point[1]-2); math.Abs(radius-(2-c.Level)) > 0.1 {
+				t.Errorf("Expected the line at level %v to be a circle of radius %v, received a point at radius %v", c.Level, 2-c.Level, radius)
+				break
+			}
+		}
+	}
+
+	collection := contour.ToGeoJSON(contours)
+	if len(collection.Features) != 3 {
+		t.Fatalf("Expected 3 features, received %d", len(collection.Features))
+	}
+	if level := collection.Features[1].Properties["level"]; level != 1.0 {
+		t.Errorf("Expected the second feature to be at level 1, received %v", level)
+	}
+}
+
+func TestTrace_Dimensions(t *testing.T) {
+	line := noise.NewNoise("line")
+	line.Generate([]int{0}, []int{4}, 4, func(t []float64) float64 { return t[0] })
+
+	expected := "Contours can only be traced from 2 dimensional noise"
+	if _, err := contour.Trace(line, []float64{1}, 0); err == nil || err.Error() != expected {
+		t.Errorf("Expected error '%s', received %v", expected, err)
+	}
+}
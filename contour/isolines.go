@@ -0,0 +1,116 @@
+package contour
+
+import (
+	"sort"
+)
+
+// crossing is where the level set crosses an edge of a grid cell. entry is true if the level set is entered
+// (the values go from at or above the level to below it) walking counter-clockwise around the cell
+type crossing struct {
+	edge  int
+	point [2]float64
+	entry bool
+}
+
+// Isolines traces the level set of a width x height grid of values with marching squares, stitching the pieces from each cell into polylines
+// The lines are in grid coordinates, where (x, y) is the sample at index x*height + y
+// Lines are oriented so that values below the level are on their left, with x pointing right and y pointing up, so loops around
+// low regions run counter-clockwise and loops around high regions run clockwise
+// Lines that reach the edge of the grid stay open, and saddle cells are resolved by the average of their corners
+func Isolines(values []float64, width, height int, level float64) []Line {
+	// Every crossing is keyed by the grid edge it lies on, so pieces from neighbouring cells share keys and can be joined exactly
+	horizontalEdge := func(x, y int) int { return 2 * (x*height + y) }
+	verticalEdge := func(x, y int) int { return 2*(x*height+y) + 1 }
+
+	next := map[int]int{}
+	points := map[int][2]float64{}
+	incoming := map[int]bool{}
+
+	crossings := make([]crossing, 0, 4)
+	for x := 0; x < width-1; x++ {
+		for y := 0; y < height-1; y++ {
+			corners := [4][2]int{{x, y}, {x + 1, y}, {x + 1, y + 1}, {x, y + 1}}
+			edges := [4]int{horizontalEdge(x, y), verticalEdge(x+1, y), horizontalEdge(x, y+1), verticalEdge(x, y)}
+
+			// Walk counter-clockwise around the cell, recording where it crosses the level
+			crossings = crossings[:0]
+			sum := 0.0
+			for i := range corners {
+				a, b := corners[i], corners[(i+1)%4]
+				va, vb := values[a[0]*height+a[1]], values[b[0]*height+b[1]]
+				sum += va
+				if (va < level) == (vb < level) {
+					continue
+				}
+				t := (level - va) / (vb - va)
+				crossings = append(crossings, crossing{
+					edge:  edges[i],
+					point: [2]float64{float64(a[0]) + t*float64(b[0]-a[0]), float64(a[1]) + t*float64(b[1]-a[1])},
+					entry: vb < level,
+				})
+			}
+			if len(crossings) == 0 {
+				continue
+			}
+
+			// Each exit joins the entry that closes off the region below it. Below regions stay separate unless a saddle's center is below too
+			joinForward := len(crossings) == 4 && sum/4 < level
+			for i, exit := range crossings {
+				if exit.entry {
+					continue
+				}
+				entry := crossings[(i+len(crossings)-1)%len(crossings)]
+				if joinForward {
+					entry = crossings[(i+1)%len(crossings)]
+				}
+				next[exit.edge] = entry.edge
+				incoming[entry.edge] = true
+				points[exit.edge] = exit.point
+				points[entry.edge] = entry.point
+			}
+		}
+	}
+
+	lines := []Line{}
+
+	// Open lines start at the edge of the grid, where nothing leads into them
+	for _, start := range sortedKeys(next) {
+		if _, ok := next[start]; ok && !incoming[start] {
+			lines = append(lines, follow(next, points, start))
+		}
+	}
+	// Everything left is a closed loop
+	for _, start := range sortedKeys(next) {
+		if _, ok := next[start]; ok {
+			lines = append(lines, follow(next, points, start))
+		}
+	}
+
+	return lines
+}
+
+// follow traces a line from the start edge, consuming the links it follows so each is only traced once
+// It stops at the end of an open line, or when a loop returns to its start
+func follow(next map[int]int, points map[int][2]float64, start int) Line {
+	line := Line{points[start]}
+	for edge := start; ; {
+		to, ok := next[edge]
+		if !ok {
+			break
+		}
+		delete(next, edge)
+		line = append(line, points[to])
+		edge = to
+	}
+	return line
+}
+
+// sortedKeys returns the keys of the links in ascending order, so lines are traced in a deterministic order
+func sortedKeys(next map[int]int) []int {
+	keys := make([]int, 0, len(next))
+	for key := range next {
+		keys = append(keys, key)
+	}
+	sort.Ints(keys)
+	return keys
+}
@@ -0,0 +1,120 @@
+package contour_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/bcokert/terragen/contour"
+)
+
+// signedArea is positive for counter-clockwise loops
+func signedArea(line contour.Line) float64 {
+	area := 0.0
+	for i := 0; i+1 < len(line); i++ {
+		area += line[i][0]*line[i+1][1] - line[i+1][0]*line[i][1]
+	}
+	return area / 2
+}
+
+func TestIsolines(t *testing.T) {
+	testCases := map[string]struct {
+		Values         []float64
+		Width, Height  int
+		Level          float64
+		ExpectedLines  int
+		ExpectedClosed []bool
+		ExpectedArea   []float64
+	}{
+		"flat": {
+			Values: []float64{1, 1, 1, 1},
+			Width:  2, Height: 2, Level: 0.5,
+			ExpectedLines: 0,
+		},
+		"pit": {
+			Values: []float64{
+				1, 1, 1,
+				1, 0, 1,
+				1, 1, 1,
+			},
+			Width: 3, Height: 3, Level: 0.5,
+			ExpectedLines:  1,
+			ExpectedClosed: []bool{true},
+			ExpectedArea:   []float64{0.5},
+		},
+		"peak": {
+			Values: []float64{
+				0, 0, 0,
+				0, 1, 0,
+				0, 0, 0,
+			},
+			Width: 3, Height: 3, Level: 0.5,
+			ExpectedLines:  1,
+			ExpectedClosed: []bool{true},
+			ExpectedArea:   []float64{-0.5},
+		},
+		"ramp": {
+			Values: []float64{
+				0, 0, 0,
+				1, 1, 1,
+				2, 2, 2,
+			},
+			Width: 3, Height: 3, Level: 1.5,
+			ExpectedLines:  1,
+			ExpectedClosed: []bool{false},
+		},
+		"separated saddle": {
+			Values: []float64{
+				0, 1,
+				1, 0.1,
+			},
+			Width: 2, Height: 2, Level: 0.6,
+			ExpectedLines:  2,
+			ExpectedClosed: []bool{false, false},
+		},
+		"two pits": {
+			Values: []float64{
+				1, 1, 1, 1, 1,
+				1, 0, 1, 0, 1,
+				1, 1, 1, 1, 1,
+			},
+			Width: 3, Height: 5, Level: 0.5,
+			ExpectedLines:  2,
+			ExpectedClosed: []bool{true, true},
+			ExpectedArea:   []float64{0.5, 0.5},
+		},
+	}
+
+	for name, testCase := range testCases {
+		lines := contour.Isolines(testCase.Values, testCase.Width, testCase.Height, testCase.Level)
+		if len(lines) != testCase.ExpectedLines {
+			t.Errorf("'%s' failed. Expected %d lines, received %d: %v", name, testCase.ExpectedLines, len(lines), lines)
+			continue
+		}
+
+		for i, line := range lines {
+			if line.IsClosed() != testCase.ExpectedClosed[i] {
+				t.Errorf("'%s' failed. Expected line %d closed to be %v, received %v", name, i, testCase.ExpectedClosed[i], line.IsClosed())
+			}
+			if testCase.ExpectedArea != nil && math.Abs(signedArea(line)-testCase.ExpectedArea[i]) > 1e-9 {
+				t.Errorf("'%s' failed. Expected line %d to have signed area %v, received %v", name, i, testCase.ExpectedArea[i], signedArea(line))
+			}
+		}
+	}
+}
+
+func TestIsolines_Interpolated(t *testing.T) {
+	// A ramp rising along x crosses 1.25 a quarter of the way between the samples at x = 1 and x = 2
+	lines := contour.Isolines([]float64{0, 0, 1, 1, 2, 2}, 3, 2, 1.25)
+	if len(lines) != 1 || len(lines[0]) != 2 {
+		t.Fatalf("Expected 1 line of 2 points, received %v", lines)
+	}
+
+	// Values below the level are on the left, so the line runs up the y axis
+	expected := contour.Line{{1.25, 0}, {1.25, 1}}
+	for i := range expected {
+		if math.Abs(lines[0][i][0]-expected[i][0]) > 1e-9 || math.Abs(lines[0][i][1]-expected[i][1]) > 1e-9 {
+			t.Errorf("Expected %v, received %v", expected, lines[0])
+			break
+		}
+	}
+}
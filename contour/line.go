@@ -0,0 +1,47 @@
+package contour
+
+// A Line is a polyline. Closed lines end with the same point they start with
+type Line [][2]float64
+
+// IsClosed returns whether the line is a loop
+func (line Line) IsClosed() bool {
+	return len(line) > 2 && line[0] == line[len(line)-1]
+}
+
+// Transform returns the line scaled by spacing and then moved to origin, such as to convert grid coordinates into world coordinates
+func (line Line) Transform(origin [2]float64, spacing float64) Line {
+	transformed := make(Line, len(line))
+	for i, point := range line {
+		transformed[i] = [2]float64{origin[0] + point[0]*spacing, origin[1] + point[1]*spacing}
+	}
+	return transformed
+}
+
+// Smooth rounds off the corners of the line with the given number of iterations of Chaikin's algorithm
+// Each iteration replaces every segment with points a quarter and three quarters along it, so the line shrinks slightly into its curves
+// Open lines keep their end points, so lines that end on the edge of a grid still reach it, and closed lines stay closed
+func (line Line) Smooth(iterations int) Line {
+	closed := line.IsClosed()
+	for i := 0; i < iterations && len(line) > 2; i++ {
+		smoothed := make(Line, 0, 2*len(line))
+		if !closed {
+			smoothed = append(smoothed, line[0])
+		}
+		for j := 0; j+1 < len(line); j++ {
+			a, b := line[j], line[j+1]
+			smoothed = append(smoothed,
+				[2]float64{0.75*a[0] + 0.25*b[0], 0.75*a[1] + 0.25*b[1]},
+				[2]float64{0.25*a[0] + 0.75*b[0], 0.25*a[1] + 0.75*b[1]},
+			)
+		}
+		if closed {
+			smoothed = append(smoothed, smoothed[0])
+		} else {
+			// The first and last cuts are dropped, so the line runs straight into its end points
+			smoothed = append(smoothed[:1], smoothed[2:len(smoothed)-1]...)
+			smoothed = append(smoothed, line[len(line)-1])
+		}
+		line = smoothed
+	}
+	return line
+}
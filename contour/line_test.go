@@ -0,0 +1,71 @@
+package contour_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/bcokert/terragen/contour"
+)
+
+func isLineEqual(a, b contour.Line) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if math.Abs(a[i][0]-b[i][0]) > 1e-9 || math.Abs(a[i][1]-b[i][1]) > 1e-9 {
+			return false
+		}
+	}
+	return true
+}
+
+func TestLine_Smooth(t *testing.T) {
+	testCases := map[string]struct {
+		Line       contour.Line
+		Iterations int
+		Expected   contour.Line
+	}{
+		"unsmoothed": {
+			Line:       contour.Line{{0, 0}, {4, 0}, {4, 4}},
+			Iterations: 0,
+			Expected:   contour.Line{{0, 0}, {4, 0}, {4, 4}},
+		},
+		"open corner": {
+			Line:       contour.Line{{0, 0}, {4, 0}, {4, 4}},
+			Iterations: 1,
+			Expected:   contour.Line{{0, 0}, {3, 0}, {4, 1}, {4, 4}},
+		},
+		"straight segment": {
+			Line:       contour.Line{{0, 0}, {4, 0}},
+			Iterations: 3,
+			Expected:   contour.Line{{0, 0}, {4, 0}},
+		},
+		"closed square": {
+			Line:       contour.Line{{0, 0}, {4, 0}, {4, 4}, {0, 4}, {0, 0}},
+			Iterations: 1,
+			Expected:   contour.Line{{1, 0}, {3, 0}, {4, 1}, {4, 3}, {3, 4}, {1, 4}, {0, 3}, {0, 1}, {1, 0}},
+		},
+	}
+
+	for name, testCase := range testCases {
+		if result := testCase.Line.Smooth(testCase.Iterations); !isLineEqual(result, testCase.Expected) {
+			t.Errorf("'%s' failed. Expected %v, received %v", name, testCase.Expected, result)
+		}
+	}
+}
+
+func TestLine_SmoothKeepsClosed(t *testing.T) {
+	line := contour.Line{{0, 0}, {4, 0}, {4, 4}, {0, 4}, {0, 0}}
+	if smoothed := line.Smooth(4); !smoothed.IsClosed() {
+		t.Errorf("Expected a smoothed loop to stay closed, received %v", smoothed)
+	}
+}
+
+func TestLine_Transform(t *testing.T) {
+	line := contour.Line{{0, 0}, {2, 1}}
+	expected := contour.Line{{-1, 3}, {0, 3.5}}
+
+	if result := line.Transform([2]float64{-1, 3}, 0.5); !isLineEqual(result, expected) {
+		t.Errorf("Expected %v, received %v", expected, result)
+	}
+}
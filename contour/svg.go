@@ -0,0 +1,69 @@
+package contour
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+)
+
+// SVGOptions control how contours are drawn as a topographic map
+// Width is the width of the map in pixels, and its height follows from the aspect ratio of the bounds
+// Every IndexInterval-th contour is an index contour, drawn thicker and labelled with its level, or none are if it is 0
+// Stroke and Background are 6 digit hex colors, and an empty Background leaves the map transparent
+type SVGOptions struct {
+	Width         int
+	IndexInterval int
+	Stroke        string
+	Background    string
+}
+
+// NewDefaultSVGOptions creates SVGOptions for a conventional brown topographic map on white, with every fifth contour indexed
+func NewDefaultSVGOptions() SVGOptions {
+	return SVGOptions{Width: 800, IndexInterval: 5, Stroke: "8c5a2b", Background: "ffffff"}
+}
+
+// WriteSVG writes the contours to w as an SVG topographic map of the world between from and to
+// The world's x axis runs right and its y axis runs down, the same as heightmaps and previews of the same noise
+func WriteSVG(w io.Writer, contours []Contour, from, to [2]float64, options SVGOptions) error {
+	buffered := bufio.NewWriter(w)
+
+	size := [2]float64{to[0] - from[0], to[1] - from[1]}
+	height := int(float64(options.Width)*size[1]/size[0] + 0.5)
+	fontSize := size[0] / 80
+
+	fmt.Fprintf(buffered, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="%g %g %g %g">`+"\n", options.Width, height, from[0], from[1], size[0], size[1])
+	if options.Background != "" {
+		fmt.Fprintf(buffered, `<rect x="%g" y="%g" width="%g" height="%g" fill="#%s"/>`+"\n", from[0], from[1], size[0], size[1], options.Background)
+	}
+	fmt.Fprintf(buffered, `<g fill="none" stroke="#%s" stroke-linejoin="round" stroke-linecap="round">`+"\n", options.Stroke)
+
+	for i, contour := range contours {
+		index := options.IndexInterval > 0 && i%options.IndexInterval == 0
+		strokeWidth := 0.5
+		if index {
+			strokeWidth = 1.5
+		}
+
+		for _, line := range contour.Lines {
+			if len(line) < 2 {
+				continue
+			}
+			fmt.Fprintf(buffered, `<path data-level="%g" stroke-width="%g" vector-effect="non-scaling-stroke" d="M%g %g`, contour.Level, strokeWidth, line[0][0], line[0][1])
+			for _, point := range line[1:] {
+				fmt.Fprintf(buffered, " L%g %g", point[0], point[1])
+			}
+			if line.IsClosed() {
+				fmt.Fprint(buffered, " Z")
+			}
+			fmt.Fprint(buffered, `"/>`+"\n")
+
+			if index {
+				label := line[len(line)/2]
+				fmt.Fprintf(buffered, `<text x="%g" y="%g" font-size="%g" fill="#%s" stroke="none" text-anchor="middle">%g</text>`+"\n", label[0], label[1], fontSize, options.Stroke, contour.Level)
+			}
+		}
+	}
+
+	fmt.Fprint(buffered, "</g>\n</svg>\n")
+	return buffered.Flush()
+}
@@ -0,0 +1,55 @@
+package contour_test
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/bcokert/terragen/contour"
+)
+
+func TestWriteSVG(t *testing.T) {
+	contours := []contour.Contour{
+		{Level: 0, Lines: []contour.Line{{{0, 0}, {1, 0}, {1, 1}, {0, 0}}}},
+		{Level: 0.5, Lines: []contour.Line{{{0, 1}, {2, 1}}, {{0, 0.5}}}},
+	}
+
+	buffer := &bytes.Buffer{}
+	if err := contour.WriteSVG(buffer, contours, [2]float64{0, 0}, [2]float64{4, 2}, contour.NewDefaultSVGOptions()); err != nil {
+		t.Fatalf("Unexpected error: %s", err.Error())
+	}
+	svg := buffer.String()
+
+	var parsed struct {
+		Width  string `xml:"width,attr"`
+		Height string `xml:"height,attr"`
+		Paths  []struct {
+			D           string `xml:"d,attr"`
+			StrokeWidth string `xml:"stroke-width,attr"`
+		} `xml:"g>path"`
+		Labels []string `xml:"g>text"`
+	}
+	if err := xml.Unmarshal(buffer.Bytes(), &parsed); err != nil {
+		t.Fatalf("Expected valid SVG, received error: %s\n%s", err.Error(), svg)
+	}
+
+	if parsed.Width != "800" || parsed.Height != "400" {
+		t.Errorf("Expected an 800x400 map, received %sx%s", parsed.Width, parsed.Height)
+	}
+	if len(parsed.Paths) != 2 {
+		t.Fatalf("Expected 2 paths, skipping lines with a single point, received %d", len(parsed.Paths))
+	}
+	if parsed.Paths[0].D != "M0 0 L1 0 L1 1 L0 0 Z" || parsed.Paths[0].StrokeWidth != "1.5" {
+		t.Errorf("Expected a closed index contour, received %+v", parsed.Paths[0])
+	}
+	if parsed.Paths[1].D != "M0 1 L2 1" || parsed.Paths[1].StrokeWidth != "0.5" {
+		t.Errorf("Expected an open intermediate contour, received %+v", parsed.Paths[1])
+	}
+	if len(parsed.Labels) != 1 || parsed.Labels[0] != "0" {
+		t.Errorf("Expected only the index contour to be labelled, received %v", parsed.Labels)
+	}
+	if !strings.Contains(svg, `fill="#ffffff"`) {
+		t.Errorf("Expected a white background")
+	}
+}
@@ -0,0 +1,20 @@
+package erosion
+
+import (
+	"errors"
+
+	"github.com/bcokert/terragen/noise"
+)
+
+// An Eroder erodes 2D noise in place, treating its values as a heightfield
+// Eroders can be chained by applying them one after another to the same noise
+type Eroder func(noise *noise.Noise) error
+
+// heightfieldShape returns the width and height of 2D noise, or an error if the noise isn't a 2D heightfield
+func heightfieldShape(noise *noise.Noise) (width, height int, err error) {
+	shape := noise.Shape()
+	if len(shape) != 2 {
+		return 0, 0, errors.New("Erosion can only be applied to 2 dimensional noise")
+	}
+	return shape[0], shape[1], nil
+}
@@ -0,0 +1,191 @@
+package erosion
+
+import (
+	"math"
+
+	tgmath "github.com/bcokert/terragen/math"
+	"github.com/bcokert/terragen/noise"
+)
+
+// HydraulicOptions control the droplets of a hydraulic Eroder
+// Iterations is the number of droplets to simulate, or 0 for one droplet per sample
+// Inertia [0, 1] is how much a droplet keeps its direction rather than following the slope
+// Capacity scales how much sediment a droplet can carry for its speed, water and slope, with MinCapacity as a floor on flat ground
+// Deposition [0, 1] is the fraction of excess sediment dropped each step, and Erosion [0, 1] the fraction of spare capacity filled
+// Evaporation [0, 1] is the fraction of water lost each step, and Gravity how quickly droplets accelerate downhill
+// Radius is how many samples around the droplet are eroded, and MaxLifetime the most steps a droplet takes before evaporating
+type HydraulicOptions struct {
+	Iterations   int
+	Inertia      float64
+	Capacity     float64
+	MinCapacity  float64
+	Deposition   float64
+	Erosion      float64
+	Evaporation  float64
+	Gravity      float64
+	Radius       int
+	MaxLifetime  int
+	InitialSpeed float64
+	InitialWater float64
+}
+
+// NewDefaultHydraulicOptions creates HydraulicOptions tuned for noise values of roughly unit magnitude
+func NewDefaultHydraulicOptions() HydraulicOptions {
+	return HydraulicOptions{
+		Iterations:   0,
+		Inertia:      0.05,
+		Capacity:     4,
+		MinCapacity:  0.01,
+		Deposition:   0.3,
+		Erosion:      0.3,
+		Evaporation:  0.01,
+		Gravity:      4,
+		Radius:       3,
+		MaxLifetime:  30,
+		InitialSpeed: 1,
+		InitialWater: 1,
+	}
+}
+
+// Hydraulic builds an Eroder that simulates water droplets running downhill, picking up sediment where they speed up
+// and depositing it where they slow down or evaporate
+// Droplets start at random positions drawn from source, so the result is deterministic for a given seed
+func Hydraulic(source tgmath.Source, options HydraulicOptions) Eroder {
+	return func(noise *noise.Noise) error {
+		width, height, err := heightfieldShape(noise)
+		if err != nil {
+			return err
+		}
+		if width < 2 || height < 2 {
+			return nil
+		}
+
+		heights := noise.Values
+		brushes := newErosionBrushes(width, height, options.Radius)
+
+		iterations := options.Iterations
+		if iterations == 0 {
+			iterations = width * height
+		}
+
+		for iteration := 0; iteration < iterations; iteration++ {
+			posX := source.Float64() * float64(width-1)
+			posY := source.Float64() * float64(height-1)
+			dirX, dirY := 0.0, 0.0
+			speed := options.InitialSpeed
+			water := options.InitialWater
+			sediment := 0.0
+
+			for lifetime := 0; lifetime < options.MaxLifetime; lifetime++ {
+				nodeX, nodeY := int(posX), int(posY)
+				offsetX, offsetY := posX-float64(nodeX), posY-float64(nodeY)
+
+				// Follow the slope, keeping some of the previous direction
+				currentHeight, gradientX, gradientY := heightAndGradient(heights, width, height, posX, posY)
+				dirX = dirX*options.Inertia - gradientX*(1-options.Inertia)
+				dirY = dirY*options.Inertia - gradientY*(1-options.Inertia)
+				length := math.Sqrt(dirX*dirX + dirY*dirY)
+				if length == 0 {
+					break
+				}
+				dirX /= length
+				dirY /= length
+				posX += dirX
+				posY += dirY
+
+				// Droplets that run off the map take their sediment with them
+				if posX < 0 || posX >= float64(width-1) || posY < 0 || posY >= float64(height-1) {
+					break
+				}
+
+				newHeight, _, _ := heightAndGradient(heights, width, height, posX, posY)
+				deltaHeight := newHeight - currentHeight
+				capacity := math.Max(-deltaHeight*speed*water*options.Capacity, options.MinCapacity)
+
+				if sediment > capacity || deltaHeight > 0 {
+					// Fill the pit the droplet climbed out of, or drop the sediment it can no longer carry
+					deposit := (sediment - capacity) * options.Deposition
+					if deltaHeight > 0 {
+						deposit = math.Min(deltaHeight, sediment)
+					}
+					sediment -= deposit
+
+					node := nodeX*height + nodeY
+					heights[node] += deposit * (1 - offsetX) * (1 - offsetY)
+					heights[node+height] += deposit * offsetX * (1 - offsetY)
+					heights[node+1] += deposit * (1 - offsetX) * offsetY
+					heights[node+height+1] += deposit * offsetX * offsetY
+				} else {
+					// Never erode more than the height difference, so droplets don't dig holes behind them
+					erode := math.Min((capacity-sediment)*options.Erosion, -deltaHeight)
+					brush := brushes[nodeX*height+nodeY]
+					for i, index := range brush.indices {
+						heights[index] -= erode * brush.weights[i]
+					}
+					sediment += erode
+				}
+
+				speed = math.Sqrt(math.Max(0, speed*speed+deltaHeight*options.Gravity))
+				water *= 1 - options.Evaporation
+			}
+		}
+
+		return nil
+	}
+}
+
+// heightAndGradient bilinearly interpolates the height and gradient of the heightfield at a position between samples
+func heightAndGradient(heights []float64, width, height int, posX, posY float64) (value, gradientX, gradientY float64) {
+	nodeX, nodeY := int(posX), int(posY)
+	x, y := posX-float64(nodeX), posY-float64(nodeY)
+
+	node := nodeX*height + nodeY
+	nw, ne := heights[node], heights[node+height]
+	sw, se := heights[node+1], heights[node+height+1]
+
+	gradientX = (ne-nw)*(1-y) + (se-sw)*y
+	gradientY = (sw-nw)*(1-x) + (se-ne)*x
+	value = nw*(1-x)*(1-y) + ne*x*(1-y) + sw*(1-x)*y + se*x*y
+	return value, gradientX, gradientY
+}
+
+// erosionBrush is the set of samples eroded around a node, with weights that fall off with distance and sum to 1
+type erosionBrush struct {
+	indices []int
+	weights []float64
+}
+
+// newErosionBrushes precomputes the brush for every sample of the heightfield, clipped to its edges
+func newErosionBrushes(width, height, radius int) []erosionBrush {
+	brushes := make([]erosionBrush, width*height)
+	r := float64(radius)
+
+	for centerX := 0; centerX < width; centerX++ {
+		for centerY := 0; centerY < height; centerY++ {
+			brush := erosionBrush{}
+			sum := 0.0
+			for dx := -radius; dx <= radius; dx++ {
+				for dy := -radius; dy <= radius; dy++ {
+					x, y := centerX+dx, centerY+dy
+					if x < 0 || x >= width || y < 0 || y >= height {
+						continue
+					}
+					distance := math.Sqrt(float64(dx*dx + dy*dy))
+					if distance >= r && radius > 0 {
+						continue
+					}
+					weight := 1 - distance/math.Max(r, 1)
+					brush.indices = append(brush.indices, x*height+y)
+					brush.weights = append(brush.weights, weight)
+					sum += weight
+				}
+			}
+			for i := range brush.weights {
+				brush.weights[i] /= sum
+			}
+			brushes[centerX*height+centerY] = brush
+		}
+	}
+
+	return brushes
+}
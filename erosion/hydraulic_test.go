@@ -0,0 +1,123 @@
+package erosion_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/bcokert/terragen/erosion"
+	tgmath "github.com/bcokert/terragen/math"
+	"github.com/bcokert/terragen/noise"
+)
+
+// hill generates a round hill on a slope, which droplets carve gullies into
+func hill(resolution int) *noise.Noise {
+	hill := noise.NewNoise("hill")
+	hill.Generate([]int{0, 0}, []int{4, 4}, resolution, func(t []float64) float64 {
+		dx, dy := t[0]-2, t[1]-2
+		return math.Exp(-(dx*dx+dy*dy)/2) + 0.1*t[0]
+	})
+	return hill
+}
+
+func TestHydraulic(t *testing.T) {
+	testCases := map[string]struct {
+		Noise         *noise.Noise
+		Options       erosion.HydraulicOptions
+		ExpectChanged bool
+	}{
+		"flat": {
+			Noise: func() *noise.Noise {
+				flat := noise.NewNoise("flat")
+				flat.Generate([]int{0, 0}, []int{2, 2}, 8, func(t []float64) float64 { return 0.5 })
+				return flat
+			}(),
+			Options:       erosion.NewDefaultHydraulicOptions(),
+			ExpectChanged: false,
+		},
+		"hill": {
+			Noise:         hill(8),
+			Options:       erosion.NewDefaultHydraulicOptions(),
+			ExpectChanged: true,
+		},
+		"no droplet lifetime": {
+			Noise: hill(8),
+			Options: func() erosion.HydraulicOptions {
+				options := erosion.NewDefaultHydraulicOptions()
+				options.MaxLifetime = 0
+				return options
+			}(),
+			ExpectChanged: false,
+		},
+	}
+
+	for name, testCase := range testCases {
+		original := append([]float64{}, testCase.Noise.Values...)
+		if err := erosion.Hydraulic(tgmath.NewDefaultSource(42), testCase.Options)(testCase.Noise); err != nil {
+			t.Errorf("'%s' failed. Unexpected error: %s", name, err.Error())
+			continue
+		}
+
+		changed := false
+		for i, value := range testCase.Noise.Values {
+			if math.IsNaN(value) || math.IsInf(value, 0) {
+				t.Errorf("'%s' failed. Expected finite heights, received %v at %d", name, value, i)
+				break
+			}
+			if value != original[i] {
+				changed = true
+			}
+		}
+		if changed != testCase.ExpectChanged {
+			t.Errorf("'%s' failed. Expected changed to be %v, received %v", name, testCase.ExpectChanged, changed)
+		}
+	}
+}
+
+func TestHydraulic_ErodesPeaks(t *testing.T) {
+	terrain := hill(16)
+	shape := terrain.Shape()
+	peak := (shape[0]/2)*shape[1] + shape[1]/2
+	originalPeak := terrain.Values[peak]
+
+	options := erosion.NewDefaultHydraulicOptions()
+	options.Iterations = 20000
+	if err := erosion.Hydraulic(tgmath.NewDefaultSource(7), options)(terrain); err != nil {
+		t.Fatalf("Unexpected error: %s", err.Error())
+	}
+
+	if terrain.Values[peak] >= originalPeak {
+		t.Errorf("Expected the peak to be eroded below %v, received %v", originalPeak, terrain.Values[peak])
+	}
+}
+
+func TestHydraulic_Deterministic(t *testing.T) {
+	erode := func(seed int64) []float64 {
+		terrain := hill(8)
+		erosion.Hydraulic(tgmath.NewDefaultSource(seed), erosion.NewDefaultHydraulicOptions())(terrain)
+		return terrain.Values
+	}
+
+	first, second, other := erode(3), erode(3), erode(4)
+	sameAsOther := true
+	for i := range first {
+		if first[i] != second[i] {
+			t.Fatalf("Expected the same seed to erode identically, received %v and %v at %d", first[i], second[i], i)
+		}
+		if first[i] != other[i] {
+			sameAsOther = false
+		}
+	}
+	if sameAsOther {
+		t.Errorf("Expected different seeds to erode differently")
+	}
+}
+
+func TestHydraulic_Dimensions(t *testing.T) {
+	line := noise.NewNoise("line")
+	line.Generate([]int{0}, []int{4}, 4, func(t []float64) float64 { return t[0] })
+
+	expected := "Erosion can only be applied to 2 dimensional noise"
+	if err := erosion.Hydraulic(tgmath.NewDefaultSource(1), erosion.NewDefaultHydraulicOptions())(line); err == nil || err.Error() != expected {
+		t.Errorf("Expected error '%s', received %v", expected, err)
+	}
+}
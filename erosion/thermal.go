@@ -0,0 +1,115 @@
+package erosion
+
+import (
+	"math"
+
+	"github.com/bcokert/terragen/noise"
+)
+
+// A Neighborhood is the set of neighbours material can slide to during thermal erosion
+type Neighborhood int
+
+const (
+	// VonNeumann slides material to the 4 orthogonal neighbours of a sample
+	VonNeumann Neighborhood = iota
+	// Moore slides material to all 8 neighbours of a sample, including diagonals
+	Moore
+)
+
+// neighborOffsets are the (x, y) offsets of the orthogonal neighbours, followed by the diagonal ones
+var neighborOffsets = [8][2]int{{1, 0}, {-1, 0}, {0, 1}, {0, -1}, {1, 1}, {1, -1}, {-1, 1}, {-1, -1}}
+
+// ThermalOptions control a thermal Eroder
+// TalusAngle is the steepest slope in degrees that material can rest at, measured in world units using the resolution of the noise
+// Rate (0, 0.5] is the fraction of excess material that slides each iteration
+type ThermalOptions struct {
+	Iterations   int
+	TalusAngle   float64
+	Rate         float64
+	Neighborhood Neighborhood
+}
+
+// NewDefaultThermalOptions creates ThermalOptions for a loose scree slope
+func NewDefaultThermalOptions() ThermalOptions {
+	return ThermalOptions{
+		Iterations:   50,
+		TalusAngle:   35,
+		Rate:         0.5,
+		Neighborhood: Moore,
+	}
+}
+
+// Thermal builds an Eroder that weathers slopes steeper than the talus angle, sliding material down to lower neighbours
+// Every sample is relaxed against the heights from the previous iteration, so the result doesn't depend on scan order, and material is conserved
+func Thermal(options ThermalOptions) Eroder {
+	return func(noise *noise.Noise) error {
+		width, height, err := heightfieldShape(noise)
+		if err != nil {
+			return err
+		}
+
+		neighbors := 4
+		if options.Neighborhood == Moore {
+			neighbors = 8
+		}
+
+		// The height difference each neighbour can hold before material slides, which is further for diagonals
+		spacing := 1 / float64(noise.Resolution)
+		slope := math.Tan(options.TalusAngle * math.Pi / 180)
+		talus := make([]float64, neighbors)
+		for i := range talus {
+			offset := neighborOffsets[i]
+			talus[i] = slope * spacing * math.Sqrt(float64(offset[0]*offset[0]+offset[1]*offset[1]))
+		}
+
+		heights := noise.Values
+		deltas := make([]float64, len(heights))
+		excess := make([]float64, neighbors)
+
+		for iteration := 0; iteration < options.Iterations; iteration++ {
+			moved := false
+			for x := 0; x < width; x++ {
+				for y := 0; y < height; y++ {
+					index := x*height + y
+					total, largest := 0.0, 0.0
+					for i := range excess {
+						excess[i] = 0
+						nx, ny := x+neighborOffsets[i][0], y+neighborOffsets[i][1]
+						if nx < 0 || nx >= width || ny < 0 || ny >= height {
+							continue
+						}
+						if difference := heights[index] - heights[nx*height+ny] - talus[i]; difference > 0 {
+							excess[i] = difference
+							total += difference
+							largest = math.Max(largest, difference)
+						}
+					}
+					if total == 0 {
+						continue
+					}
+
+					// Move a share of the steepest excess, split between the neighbours in proportion to their own excess
+					amount := options.Rate * largest
+					for i, difference := range excess {
+						if difference > 0 {
+							nx, ny := x+neighborOffsets[i][0], y+neighborOffsets[i][1]
+							deltas[nx*height+ny] += amount * difference / total
+						}
+					}
+					deltas[index] -= amount
+					moved = true
+				}
+			}
+
+			if !moved {
+				break
+			}
+			for i := range heights {
+				heights[i] += deltas[i]
+				deltas[i] = 0
+			}
+		}
+
+		return nil
+	}
+}
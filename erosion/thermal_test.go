@@ -0,0 +1,109 @@
+package erosion_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/bcokert/terragen/erosion"
+	"github.com/bcokert/terragen/noise"
+)
+
+// cliff generates a sheer step, far steeper than any talus angle
+func cliff() *noise.Noise {
+	cliff := noise.NewNoise("cliff")
+	cliff.Generate([]int{0, 0}, []int{4, 4}, 4, func(t []float64) float64 {
+		if t[0] < 2 {
+			return 1
+		}
+		return 0
+	})
+	return cliff
+}
+
+// maxSlope returns the steepest slope between orthogonal neighbours of 2D noise
+func maxSlope(noise *noise.Noise) float64 {
+	shape := noise.Shape()
+	spacing := 1 / float64(noise.Resolution)
+	slope := 0.0
+	for x := 0; x < shape[0]; x++ {
+		for y := 0; y < shape[1]; y++ {
+			if x+1 < shape[0] {
+				slope = math.Max(slope, math.Abs(noise.Values[x*shape[1]+y]-noise.Values[(x+1)*shape[1]+y])/spacing)
+			}
+			if y+1 < shape[1] {
+				slope = math.Max(slope, math.Abs(noise.Values[x*shape[1]+y]-noise.Values[x*shape[1]+y+1])/spacing)
+			}
+		}
+	}
+	return slope
+}
+
+func sum(values []float64) float64 {
+	total := 0.0
+	for _, value := range values {
+		total += value
+	}
+	return total
+}
+
+func TestThermal(t *testing.T) {
+	testCases := map[string]struct {
+		Options erosion.ThermalOptions
+	}{
+		"von neumann": {Options: erosion.ThermalOptions{Iterations: 500, TalusAngle: 30, Rate: 0.5, Neighborhood: erosion.VonNeumann}},
+		"moore":       {Options: erosion.ThermalOptions{Iterations: 500, TalusAngle: 30, Rate: 0.5, Neighborhood: erosion.Moore}},
+		"defaults":    {Options: erosion.NewDefaultThermalOptions()},
+	}
+
+	for name, testCase := range testCases {
+		terrain := cliff()
+		originalSlope, originalSum := maxSlope(terrain), sum(terrain.Values)
+
+		if err := erosion.Thermal(testCase.Options)(terrain); err != nil {
+			t.Errorf("'%s' failed. Unexpected error: %s", name, err.Error())
+			continue
+		}
+
+		if slope := maxSlope(terrain); slope >= originalSlope {
+			t.Errorf("'%s' failed. Expected the cliff to be relaxed below a slope of %v, received %v", name, originalSlope, slope)
+		}
+		if total := sum(terrain.Values); math.Abs(total-originalSum) > 1e-9 {
+			t.Errorf("'%s' failed. Expected material to be conserved. Expected %v, received %v", name, originalSum, total)
+		}
+	}
+}
+
+func TestThermal_RelaxesToTalus(t *testing.T) {
+	terrain := cliff()
+	talusAngle := 30.0
+	erosion.Thermal(erosion.ThermalOptions{Iterations: 5000, TalusAngle: talusAngle, Rate: 0.5, Neighborhood: erosion.VonNeumann})(terrain)
+
+	// Jacobi relaxation converges on the talus slope from above, so allow a little excess
+	if slope, talus := maxSlope(terrain), math.Tan(talusAngle*math.Pi/180); slope > talus*1.01 {
+		t.Errorf("Expected the slope to relax to at most %v, received %v", talus, slope)
+	}
+}
+
+func TestThermal_GentleSlopesUnchanged(t *testing.T) {
+	terrain := noise.NewNoise("ramp")
+	terrain.Generate([]int{0, 0}, []int{2, 2}, 8, func(t []float64) float64 { return 0.1 * t[0] })
+	original := append([]float64{}, terrain.Values...)
+
+	erosion.Thermal(erosion.NewDefaultThermalOptions())(terrain)
+
+	for i := range original {
+		if terrain.Values[i] != original[i] {
+			t.Fatalf("Expected slopes below the talus angle to be unchanged. Expected %v, received %v at %d", original[i], terrain.Values[i], i)
+		}
+	}
+}
+
+func TestThermal_Dimensions(t *testing.T) {
+	line := noise.NewNoise("line")
+	line.Generate([]int{0}, []int{4}, 4, func(t []float64) float64 { return t[0] })
+
+	expected := "Erosion can only be applied to 2 dimensional noise"
+	if err := erosion.Thermal(erosion.NewDefaultThermalOptions())(line); err == nil || err.Error() != expected {
+		t.Errorf("Expected error '%s', received %v", expected, err)
+	}
+}
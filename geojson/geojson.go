@@ -0,0 +1,44 @@
+package geojson
+
+// A FeatureCollection is a GeoJSON collection of features, which map clients can draw directly
+type FeatureCollection struct {
+	Type     string    `json:"type"`
+	Features []Feature `json:"features"`
+}
+
+// A Feature is a GeoJSON geometry with arbitrary properties attached
+type Feature struct {
+	Type       string                 `json:"type"`
+	Geometry   Geometry               `json:"geometry"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+// A Geometry is a GeoJSON geometry. Coordinates holds a position, a list of positions, or a list of lists of positions, depending on Type
+type Geometry struct {
+	Type        string      `json:"type"`
+	Coordinates interface{} `json:"coordinates"`
+}
+
+// NewFeatureCollection creates an empty FeatureCollection
+func NewFeatureCollection() *FeatureCollection {
+	return &FeatureCollection{Type: "FeatureCollection", Features: []Feature{}}
+}
+
+// Add adds a feature with the given geometry and properties to the collection
+func (collection *FeatureCollection) Add(geometry Geometry, properties map[string]interface{}) {
+	if properties == nil {
+		properties = map[string]interface{}{}
+	}
+	collection.Features = append(collection.Features, Feature{Type: "Feature", Geometry: geometry, Properties: properties})
+}
+
+// NewLineString creates a LineString geometry through the given positions
+func NewLineString(positions [][2]float64) Geometry {
+	return Geometry{Type: "LineString", Coordinates: positions}
+}
+
+// NewPolygon creates a Polygon geometry from the given rings. The first ring is the outer boundary, and any others are holes
+// Each ring should be closed, with its last position equal to its first
+func NewPolygon(rings [][][2]float64) Geometry {
+	return Geometry{Type: "Polygon", Coordinates: rings}
+}
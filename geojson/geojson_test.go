@@ -0,0 +1,45 @@
+package geojson_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/bcokert/terragen/geojson"
+)
+
+func TestFeatureCollection_Add(t *testing.T) {
+	testCases := map[string]struct {
+		Geometry   geojson.Geometry
+		Properties map[string]interface{}
+		Expected   string
+	}{
+		"empty": {
+			Expected: `{"type":"FeatureCollection","features":[]}`,
+		},
+		"line string": {
+			Geometry:   geojson.NewLineString([][2]float64{{0, 1}, {2, 3.5}}),
+			Properties: map[string]interface{}{"order": 2},
+			Expected:   `{"type":"FeatureCollection","features":[{"type":"Feature","geometry":{"type":"LineString","coordinates":[[0,1],[2,3.5]]},"properties":{"order":2}}]}`,
+		},
+		"polygon without properties": {
+			Geometry: geojson.NewPolygon([][][2]float64{{{0, 0}, {1, 0}, {1, 1}, {0, 0}}}),
+			Expected: `{"type":"FeatureCollection","features":[{"type":"Feature","geometry":{"type":"Polygon","coordinates":[[[0,0],[1,0],[1,1],[0,0]]]},"properties":{}}]}`,
+		},
+	}
+
+	for name, testCase := range testCases {
+		collection := geojson.NewFeatureCollection()
+		if testCase.Geometry.Type != "" {
+			collection.Add(testCase.Geometry, testCase.Properties)
+		}
+
+		result, err := json.Marshal(collection)
+		if err != nil {
+			t.Errorf("'%s' failed. Unexpected error: %s", name, err.Error())
+			continue
+		}
+		if string(result) != testCase.Expected {
+			t.Errorf("'%s' failed. Expected %s, received %s", name, testCase.Expected, string(result))
+		}
+	}
+}
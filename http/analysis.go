@@ -0,0 +1,176 @@
+package http
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/bcokert/terragen/analysis"
+	"github.com/bcokert/terragen/image"
+	"github.com/bcokert/terragen/log"
+	"github.com/bcokert/terragen/noise"
+	"github.com/julienschmidt/httprouter"
+)
+
+// HandleDerivatives generates 2D noise with the given params, and responds with it along with the requested derivative channels. It is an idempotent call
+func HandleDerivatives() httprouter.Handle {
+	return Handle(func(response http.ResponseWriter, request *http.Request, _ httprouter.Params) (interface{}, int) {
+		log.Info("Request Started: %s %s", request.Method, request.URL.String())
+
+		// Validate the params, and get the related data
+		params, err := validateNoiseParams(request.URL.Query())
+		if err != nil {
+			return fmt.Errorf("Invalid param: (%s)", err.Error()), http.StatusBadRequest
+		}
+
+		analysisParams, err := validateAnalysisParams(request.URL.Query(), params)
+		if err != nil {
+			return fmt.Errorf("Invalid param: (%s)", err.Error()), http.StatusBadRequest
+		}
+
+		noise := generateNoise(params)
+		derivatives, err := analysis.NewDerivatives(noise, analysisParams.zFactor)
+		if err != nil {
+			return fmt.Errorf("Failed to compute derivatives: (%s)", err.Error()), http.StatusInternalServerError
+		}
+
+		channels := map[string][]float64{}
+		for _, channel := range analysisParams.channels {
+			channels[channel] = derivativeChannels[channel](derivatives)
+		}
+
+		return derivativesResponse{Noise: noise, Channels: channels}, http.StatusOK
+	})
+}
+
+// HandleNormalMap generates 2D noise with the given params, and responds with it as a PNG normal map. It is an idempotent call
+func HandleNormalMap() httprouter.Handle {
+	return Handle(func(response http.ResponseWriter, request *http.Request, _ httprouter.Params) (interface{}, int) {
+		log.Info("Request Started: %s %s", request.Method, request.URL.String())
+
+		// Validate the params, and get the related data
+		params, err := validateNoiseParams(request.URL.Query())
+		if err != nil {
+			return fmt.Errorf("Invalid param: (%s)", err.Error()), http.StatusBadRequest
+		}
+
+		analysisParams, err := validateAnalysisParams(request.URL.Query(), params)
+		if err != nil {
+			return fmt.Errorf("Invalid param: (%s)", err.Error()), http.StatusBadRequest
+		}
+
+		buffer := &bytes.Buffer{}
+		if err := image.EncodeNormalMap(buffer, generateNoise(params), analysisParams.zFactor); err != nil {
+			return fmt.Errorf("Failed to encode normal map: (%s)", err.Error()), http.StatusInternalServerError
+		}
+
+		response.Header().Add("Content-Type", "image/png")
+		response.Write(buffer.Bytes())
+		return nil, http.StatusOK
+	})
+}
+
+// maxHistogramBins limits the number of histogram bins HandleStatistics responds with
+const maxHistogramBins = 1000
+
+// HandleStatistics generates 1D or 2D noise with the given params, and responds with its power spectrum and statistics. It is an idempotent call
+func HandleStatistics() httprouter.Handle {
+	return Handle(func(response http.ResponseWriter, request *http.Request, _ httprouter.Params) (interface{}, int) {
+		log.Info("Request Started: %s %s", request.Method, request.URL.String())
+
+		// Validate the params, and get the related data
+		params, err := validateNoiseParams(request.URL.Query())
+		if err != nil {
+			return fmt.Errorf("Invalid param: (%s)", err.Error()), http.StatusBadRequest
+		}
+
+		bins, err := validateStatisticsParams(request.URL.Query(), params)
+		if err != nil {
+			return fmt.Errorf("Invalid param: (%s)", err.Error()), http.StatusBadRequest
+		}
+
+		noise := generateNoise(params)
+		spectrum, err := analysis.NewSpectrum(noise)
+		if err != nil {
+			return fmt.Errorf("Failed to compute spectrum: (%s)", err.Error()), http.StatusInternalServerError
+		}
+		statistics, err := analysis.NewStatistics(noise, bins)
+		if err != nil {
+			return fmt.Errorf("Failed to compute statistics: (%s)", err.Error()), http.StatusInternalServerError
+		}
+
+		return statisticsResponse{Spectrum: spectrum, Statistics: statistics}, http.StatusOK
+	})
+}
+
+type statisticsResponse struct {
+	Spectrum   *analysis.Spectrum   `json:"spectrum"`
+	Statistics *analysis.Statistics `json:"statistics"`
+}
+
+func validateStatisticsParams(params url.Values, noiseParams queryParams) (bins int, err error) {
+	binsParam := params.Get("bins")
+
+	if len(noiseParams.from) > 2 {
+		return 0, errors.New("Statistics require a 1 or 2 dimensional From and To")
+	}
+
+	bins = 20
+	if binsParam != "" {
+		if bins, err = strconv.Atoi(binsParam); err != nil || bins < 1 || bins > maxHistogramBins {
+			return 0, fmt.Errorf("Bins must be an integer between 1 and %d", maxHistogramBins)
+		}
+	}
+
+	return bins, nil
+}
+
+type derivativesResponse struct {
+	Noise    *noise.Noise         `json:"noise"`
+	Channels map[string][]float64 `json:"channels"`
+}
+
+// derivativeChannels are the valid values of the channels param, and how to compute each of them
+var derivativeChannels = map[string]func(derivatives *analysis.Derivatives) []float64{
+	"slope":            (*analysis.Derivatives).Slope,
+	"aspect":           (*analysis.Derivatives).Aspect,
+	"profileCurvature": (*analysis.Derivatives).ProfileCurvature,
+	"planCurvature":    (*analysis.Derivatives).PlanCurvature,
+}
+
+type analysisParams struct {
+	zFactor  float64
+	channels []string
+}
+
+func validateAnalysisParams(params url.Values, noiseParams queryParams) (response analysisParams, err error) {
+	zFactor := params.Get("zFactor")
+	channels := params.Get("channels")
+
+	if len(noiseParams.from) != 2 {
+		return analysisParams{}, errors.New("Derivatives require a 2 dimensional From and To")
+	}
+
+	response.zFactor = 1
+	if zFactor != "" {
+		if response.zFactor, err = strconv.ParseFloat(zFactor, 64); err != nil {
+			return analysisParams{}, errors.New("ZFactor must be a number")
+		}
+	}
+
+	response.channels = []string{"slope", "aspect", "profileCurvature", "planCurvature"}
+	if channels != "" {
+		response.channels = strings.Split(channels, ",")
+		for _, channel := range response.channels {
+			if _, ok := derivativeChannels[channel]; !ok {
+				return analysisParams{}, errors.New("Channels must be a list of slope, aspect, profileCurvature or planCurvature")
+			}
+		}
+	}
+
+	return response, nil
+}
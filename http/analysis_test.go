@@ -0,0 +1,222 @@
+package http_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	tghttp "github.com/bcokert/terragen/http"
+)
+
+func TestHandleDerivatives(t *testing.T) {
+	testCases := map[string]struct {
+		Query              string
+		ExpectedStatusCode int
+		ExpectedErrorBody  string
+		ExpectedChannels   []string
+		ExpectedSamples    int
+	}{
+		"defaults": {
+			Query:              "seed=42",
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedChannels:   []string{"slope", "aspect", "profileCurvature", "planCurvature"},
+			ExpectedSamples:    10000,
+		},
+		"some channels": {
+			Query:              "from=0,0&to=2,3&resolution=4&seed=42&channels=slope,planCurvature&zFactor=2",
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedChannels:   []string{"slope", "planCurvature"},
+			ExpectedSamples:    96,
+		},
+		"1d": {
+			Query:              "from=0&to=2&seed=42",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (Derivatives require a 2 dimensional From and To)"}`,
+		},
+		"invalid channel": {
+			Query:              "seed=42&channels=slope,roughness",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (Channels must be a list of slope, aspect, profileCurvature or planCurvature)"}`,
+		},
+		"invalid zfactor": {
+			Query:              "seed=42&zFactor=tall",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (ZFactor must be a number)"}`,
+		},
+	}
+
+	for name, tc := range testCases {
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest(http.MethodGet, fmt.Sprintf("/derivatives?%s", tc.Query), nil)
+		tghttp.HandleDerivatives()(w, r, nil)
+
+		if w.Code != tc.ExpectedStatusCode {
+			t.Errorf("'%s' failed. Expected status code %d, received %d", name, tc.ExpectedStatusCode, w.Code)
+			t.Logf("Response: %s", w.Body.String())
+			continue
+		}
+
+		if tc.ExpectedErrorBody != "" {
+			if w.Body.String() != tc.ExpectedErrorBody {
+				t.Errorf("'%s' failed. Expected error response '%s', received '%s'", name, tc.ExpectedErrorBody, w.Body.String())
+			}
+			continue
+		}
+
+		var result struct {
+			Channels map[string][]float64 `json:"channels"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+			t.Errorf("'%s' failed. Expected a derivatives response, received '%s'", name, w.Body.String())
+			continue
+		}
+
+		if len(result.Channels) != len(tc.ExpectedChannels) {
+			t.Errorf("'%s' failed. Expected %d channels, received %d", name, len(tc.ExpectedChannels), len(result.Channels))
+		}
+		for _, channel := range tc.ExpectedChannels {
+			if len(result.Channels[channel]) != tc.ExpectedSamples {
+				t.Errorf("'%s' failed. Expected %d samples of %s, received %d", name, tc.ExpectedSamples, channel, len(result.Channels[channel]))
+			}
+		}
+	}
+}
+
+func TestHandleNormalMap(t *testing.T) {
+	testCases := map[string]struct {
+		Query              string
+		ExpectedStatusCode int
+		ExpectedErrorBody  string
+		ExpectedWidth      int
+		ExpectedHeight     int
+	}{
+		"defaults": {
+			Query:              "seed=42",
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedWidth:      100,
+			ExpectedHeight:     100,
+		},
+		"exaggerated": {
+			Query:              "from=0,0&to=3,2&resolution=4&seed=42&zFactor=5",
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedWidth:      12,
+			ExpectedHeight:     8,
+		},
+		"1d": {
+			Query:              "from=0&to=2&seed=42",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (Derivatives require a 2 dimensional From and To)"}`,
+		},
+	}
+
+	for name, tc := range testCases {
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest(http.MethodGet, fmt.Sprintf("/normalmap?%s", tc.Query), nil)
+		tghttp.HandleNormalMap()(w, r, nil)
+
+		if w.Code != tc.ExpectedStatusCode {
+			t.Errorf("'%s' failed. Expected status code %d, received %d", name, tc.ExpectedStatusCode, w.Code)
+			t.Logf("Response: %s", w.Body.String())
+			continue
+		}
+
+		if tc.ExpectedErrorBody != "" {
+			if w.Body.String() != tc.ExpectedErrorBody {
+				t.Errorf("'%s' failed. Expected error response '%s', received '%s'", name, tc.ExpectedErrorBody, w.Body.String())
+			}
+			continue
+		}
+
+		if contentType := w.Header().Get("Content-Type"); contentType != "image/png" {
+			t.Errorf("'%s' failed. Expected content type image/png, received %s", name, contentType)
+		}
+
+		img, err := png.Decode(w.Body)
+		if err != nil {
+			t.Errorf("'%s' failed. Expected a valid png, received error: %s", name, err.Error())
+			continue
+		}
+		if bounds := img.Bounds(); bounds.Dx() != tc.ExpectedWidth || bounds.Dy() != tc.ExpectedHeight {
+			t.Errorf("'%s' failed. Expected a %dx%d image, received %dx%d", name, tc.ExpectedWidth, tc.ExpectedHeight, bounds.Dx(), bounds.Dy())
+		}
+	}
+}
+
+func TestHandleStatistics(t *testing.T) {
+	testCases := map[string]struct {
+		Query              string
+		ExpectedStatusCode int
+		ExpectedErrorBody  string
+		ExpectedBins       int
+	}{
+		"defaults": {
+			Query:              "seed=42",
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedBins:       20,
+		},
+		"1d": {
+			Query:              "from=0&to=4&resolution=32&noiseFunction=pink&seed=42&bins=5",
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedBins:       5,
+		},
+		"3d": {
+			Query:              "from=0,0,0&to=1,1,1&seed=42",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (Statistics require a 1 or 2 dimensional From and To)"}`,
+		},
+		"invalid bins": {
+			Query:              "seed=42&bins=0",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (Bins must be an integer between 1 and 1000)"}`,
+		},
+	}
+
+	for name, tc := range testCases {
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest(http.MethodGet, fmt.Sprintf("/statistics?%s", tc.Query), nil)
+		tghttp.HandleStatistics()(w, r, nil)
+
+		if w.Code != tc.ExpectedStatusCode {
+			t.Errorf("'%s' failed. Expected status code %d, received %d", name, tc.ExpectedStatusCode, w.Code)
+			t.Logf("Response: %s", w.Body.String())
+			continue
+		}
+
+		if tc.ExpectedErrorBody != "" {
+			if w.Body.String() != tc.ExpectedErrorBody {
+				t.Errorf("'%s' failed. Expected error response '%s', received '%s'", name, tc.ExpectedErrorBody, w.Body.String())
+			}
+			continue
+		}
+
+		var result struct {
+			Spectrum struct {
+				Frequencies []float64 `json:"frequencies"`
+				Power       []float64 `json:"power"`
+				Slope       float64   `json:"slope"`
+			} `json:"spectrum"`
+			Statistics struct {
+				Min       float64 `json:"min"`
+				Max       float64 `json:"max"`
+				Histogram []int   `json:"histogram"`
+			} `json:"statistics"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+			t.Errorf("'%s' failed. Expected a statistics response, received '%s'", name, w.Body.String())
+			continue
+		}
+
+		if len(result.Spectrum.Frequencies) == 0 || len(result.Spectrum.Frequencies) != len(result.Spectrum.Power) {
+			t.Errorf("'%s' failed. Expected a power for each frequency, received %d frequencies and %d powers", name, len(result.Spectrum.Frequencies), len(result.Spectrum.Power))
+		}
+		if len(result.Statistics.Histogram) != tc.ExpectedBins {
+			t.Errorf("'%s' failed. Expected %d histogram bins, received %d", name, tc.ExpectedBins, len(result.Statistics.Histogram))
+		}
+		if result.Statistics.Min > result.Statistics.Max {
+			t.Errorf("'%s' failed. Expected min to be no more than max, received %v and %v", name, result.Statistics.Min, result.Statistics.Max)
+		}
+	}
+}
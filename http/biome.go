@@ -0,0 +1,167 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/bcokert/terragen/biome"
+	"github.com/bcokert/terragen/climate"
+	"github.com/bcokert/terragen/log"
+	"github.com/bcokert/terragen/math"
+	"github.com/bcokert/terragen/noise"
+	"github.com/julienschmidt/httprouter"
+)
+
+// maxBiomeTableBytes limits the size of biome tables posted to HandleBiomes
+const maxBiomeTableBytes = 1 << 20
+
+// HandleBiomes generates 2D elevation, temperature and moisture noise with the given params, and responds with the biome of each sample
+// The biome table can be posted as the JSON body, or given as JSON in the table param, and defaults to biome.DefaultTable. It is an idempotent call
+func HandleBiomes() httprouter.Handle {
+	return Handle(func(response http.ResponseWriter, request *http.Request, _ httprouter.Params) (interface{}, int) {
+		log.Info("Request Started: %s %s", request.Method, request.URL.String())
+
+		// Validate the params, and get the related data
+		params, err := validateNoiseParams(request.URL.Query())
+		if err != nil {
+			return fmt.Errorf("Invalid param: (%s)", err.Error()), http.StatusBadRequest
+		}
+
+		biomeParams, err := validateBiomeParams(request.URL.Query(), params)
+		if err != nil {
+			return fmt.Errorf("Invalid param: (%s)", err.Error()), http.StatusBadRequest
+		}
+
+		if request.Method == http.MethodPost {
+			body, err := ioutil.ReadAll(http.MaxBytesReader(response, request.Body, maxBiomeTableBytes))
+			if err != nil {
+				return fmt.Errorf("Invalid body: (%s)", err.Error()), http.StatusBadRequest
+			}
+			if biomeParams.table, err = biome.ParseTable(body); err != nil {
+				return fmt.Errorf("Invalid body: (%s)", err.Error()), http.StatusBadRequest
+			}
+		}
+
+		// The climate layers are generated from their own seeds, so they don't simply mirror the terrain
+		elevation := generateNoise(params)
+		temperature := noise.NewNoise(biomeParams.temperatureName)
+		temperature.Generate(params.from, params.to, params.resolution, biomeParams.temperaturePreset(math.NewDefaultSource(params.seed+1), presetFrequencies))
+
+		var classification *biome.Classification
+		if biomeParams.wind != nil {
+			classification, err = classifySimulatedClimate(elevation, temperature, biomeParams)
+		} else {
+			moisture := noise.NewNoise(biomeParams.moistureName)
+			moisture.Generate(params.from, params.to, params.resolution, biomeParams.moisturePreset(math.NewDefaultSource(params.seed+2), presetFrequencies))
+			classification, err = biome.Classify(elevation, temperature, moisture, biomeParams.climate, biomeParams.table)
+		}
+		if err != nil {
+			return fmt.Errorf("Failed to classify biomes: (%s)", err.Error()), http.StatusInternalServerError
+		}
+
+		return biomesResponse{Noise: elevation, Classification: classification}, http.StatusOK
+	})
+}
+
+// classifySimulatedClimate classifies biomes with the moisture the prevailing wind brings, and the simulated temperature varied by the temperature noise
+func classifySimulatedClimate(elevation, temperature *noise.Noise, biomeParams biomeParams) (*biome.Classification, error) {
+	weather, err := climate.Simulate(elevation, *biomeParams.wind)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range weather.Temperature {
+		weather.Temperature[i] += biomeParams.climate.TemperatureVariation * temperature.Values[i]
+	}
+	return biome.ClassifyChannels(elevation, weather.Temperature, weather.Moisture, biomeParams.climate.SeaLevel, biomeParams.table)
+}
+
+type biomesResponse struct {
+	Noise          *noise.Noise          `json:"noise"`
+	Classification *biome.Classification `json:"classification"`
+}
+
+type biomeParams struct {
+	climate           biome.Climate
+	table             biome.Table
+	temperatureName   string
+	temperaturePreset noise.Preset
+	moistureName      string
+	moisturePreset    noise.Preset
+	wind              *climate.Options
+}
+
+func validateBiomeParams(params url.Values, noiseParams queryParams) (response biomeParams, err error) {
+	table := params.Get("table")
+	temperatureFunction := params.Get("temperatureFunction")
+	moistureFunction := params.Get("moistureFunction")
+
+	if len(noiseParams.from) != 2 {
+		return biomeParams{}, errors.New("Biomes require a 2 dimensional From and To")
+	}
+
+	// Validate the climate, each part of which is an optional number
+	response.climate = biome.NewDefaultClimate()
+	climateParams := []struct {
+		name  string
+		value *float64
+		err   string
+	}{
+		{"seaLevel", &response.climate.SeaLevel, "SeaLevel must be a number"},
+		{"equatorTemperature", &response.climate.EquatorTemperature, "EquatorTemperature must be a number"},
+		{"poleTemperature", &response.climate.PoleTemperature, "PoleTemperature must be a number"},
+		{"poleDistance", &response.climate.PoleDistance, "PoleDistance must be a number"},
+		{"lapseRate", &response.climate.LapseRate, "LapseRate must be a number"},
+		{"temperatureVariation", &response.climate.TemperatureVariation, "TemperatureVariation must be a number"},
+	}
+	for _, climateParam := range climateParams {
+		if value := params.Get(climateParam.name); value != "" {
+			if *climateParam.value, err = strconv.ParseFloat(value, 64); err != nil {
+				return biomeParams{}, errors.New(climateParam.err)
+			}
+		}
+	}
+
+	// Moisture is simulated from the prevailing wind when one is given, rather than taken from the moisture noise
+	if params.Get("wind") != "" {
+		options := climate.NewDefaultOptions()
+		options.SeaLevel = response.climate.SeaLevel
+		options.EquatorTemperature = response.climate.EquatorTemperature
+		options.PoleTemperature = response.climate.PoleTemperature
+		options.PoleDistance = response.climate.PoleDistance
+		options.LapseRate = response.climate.LapseRate
+		if options, err = validateWindParams(params, options); err != nil {
+			return biomeParams{}, err
+		}
+		response.wind = &options
+	}
+
+	// Validate the table
+	response.table = biome.DefaultTable
+	if table != "" {
+		if response.table, err = biome.ParseTable([]byte(table)); err != nil {
+			return biomeParams{}, fmt.Errorf("Table must be a valid biome table: %s", err.Error())
+		}
+	}
+
+	// Validate the climate noise functions
+	response.temperatureName, response.moistureName = "red", "red"
+	if temperatureFunction != "" {
+		response.temperatureName = temperatureFunction
+	}
+	if moistureFunction != "" {
+		response.moistureName = moistureFunction
+	}
+	if response.temperaturePreset = searchPresets(response.temperatureName); response.temperaturePreset == nil {
+		return biomeParams{}, errors.New("TemperatureFunction must be a valid preset")
+	}
+	if response.moisturePreset = searchPresets(response.moistureName); response.moisturePreset == nil {
+		return biomeParams{}, errors.New("MoistureFunction must be a valid preset")
+	}
+
+	return response, nil
+}
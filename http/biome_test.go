@@ -0,0 +1,149 @@
+package http_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	tghttp "github.com/bcokert/terragen/http"
+)
+
+func TestHandleBiomes(t *testing.T) {
+	table := `{"biomes": [{"id": 4, "name": "Everything", "color": "00ff00"}]}`
+
+	testCases := map[string]struct {
+		Method             string
+		Query              string
+		Body               string
+		ExpectedStatusCode int
+		ExpectedErrorBody  string
+		ExpectedSamples    int
+		ExpectedLegend     []string
+	}{
+		"defaults": {
+			Method:             http.MethodGet,
+			Query:              "seed=42",
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedSamples:    10000,
+		},
+		"climate": {
+			Method:             http.MethodGet,
+			Query:              "from=0,0&to=2,2&resolution=4&seed=42&seaLevel=0.2&equatorTemperature=25&poleTemperature=-30&poleDistance=10&lapseRate=5&temperatureVariation=0&temperatureFunction=white&moistureFunction=pink",
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedSamples:    64,
+		},
+		"simulated climate": {
+			Method:             http.MethodGet,
+			Query:              "from=0,0&to=2,2&resolution=4&seed=42&wind=1,1&orographic=8",
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedSamples:    64,
+		},
+		"table param": {
+			Method:             http.MethodGet,
+			Query:              "from=0,0&to=2,2&resolution=4&seed=42&table=" + url.QueryEscape(table),
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedSamples:    64,
+			ExpectedLegend:     []string{"Everything"},
+		},
+		"posted table": {
+			Method:             http.MethodPost,
+			Query:              "from=0,0&to=2,2&resolution=4&seed=42",
+			Body:               table,
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedSamples:    64,
+			ExpectedLegend:     []string{"Everything"},
+		},
+		"invalid posted table": {
+			Method:             http.MethodPost,
+			Query:              "seed=42",
+			Body:               `{"biomes": []}`,
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid body: (Biome tables must have at least one biome)"}`,
+		},
+		"invalid table param": {
+			Method:             http.MethodGet,
+			Query:              "seed=42&table=" + url.QueryEscape(`{"biomes": [{"id": 1, "color": "green"}]}`),
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (Table must be a valid biome table: Biome colors must be 6 digit hex colors)"}`,
+		},
+		"1d": {
+			Method:             http.MethodGet,
+			Query:              "from=0&to=2&seed=42",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (Biomes require a 2 dimensional From and To)"}`,
+		},
+		"invalid climate": {
+			Method:             http.MethodGet,
+			Query:              "seed=42&lapseRate=steep",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (LapseRate must be a number)"}`,
+		},
+		"invalid wind": {
+			Method:             http.MethodGet,
+			Query:              "seed=42&wind=0,0",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (Wind must be a non zero x,y direction)"}`,
+		},
+		"invalid moisture function": {
+			Method:             http.MethodGet,
+			Query:              "seed=42&moistureFunction=damp",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (MoistureFunction must be a valid preset)"}`,
+		},
+	}
+
+	for name, tc := range testCases {
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest(tc.Method, fmt.Sprintf("/biomes?%s", tc.Query), strings.NewReader(tc.Body))
+		tghttp.HandleBiomes()(w, r, nil)
+
+		if w.Code != tc.ExpectedStatusCode {
+			t.Errorf("'%s' failed. Expected status code %d, received %d", name, tc.ExpectedStatusCode, w.Code)
+			t.Logf("Response: %s", w.Body.String())
+			continue
+		}
+
+		if tc.ExpectedErrorBody != "" {
+			if w.Body.String() != tc.ExpectedErrorBody {
+				t.Errorf("'%s' failed. Expected error response '%s', received '%s'", name, tc.ExpectedErrorBody, w.Body.String())
+			}
+			continue
+		}
+
+		var result struct {
+			Classification struct {
+				Biomes []int `json:"biomes"`
+				Legend []struct {
+					Name    string `json:"name"`
+					Samples int    `json:"samples"`
+				} `json:"legend"`
+			} `json:"classification"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+			t.Errorf("'%s' failed. Expected a biomes response, received '%s'", name, w.Body.String())
+			continue
+		}
+
+		if len(result.Classification.Biomes) != tc.ExpectedSamples {
+			t.Errorf("'%s' failed. Expected %d biomes, received %d", name, tc.ExpectedSamples, len(result.Classification.Biomes))
+		}
+
+		total := 0
+		for _, entry := range result.Classification.Legend {
+			total += entry.Samples
+		}
+		if total != tc.ExpectedSamples {
+			t.Errorf("'%s' failed. Expected the legend to count %d samples, received %d", name, tc.ExpectedSamples, total)
+		}
+
+		if tc.ExpectedLegend != nil {
+			if len(result.Classification.Legend) != len(tc.ExpectedLegend) || result.Classification.Legend[0].Name != tc.ExpectedLegend[0] {
+				t.Errorf("'%s' failed. Expected legend %v, received %+v", name, tc.ExpectedLegend, result.Classification.Legend)
+			}
+		}
+	}
+}
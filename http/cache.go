@@ -0,0 +1,185 @@
+package http
+
+import (
+	"bufio"
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/bcokert/terragen/cache"
+	"github.com/bcokert/terragen/log"
+	"github.com/julienschmidt/httprouter"
+)
+
+// cacheFormat is part of every cache key, and must be changed whenever the way responses are cached changes
+const cacheFormat = "1"
+
+// CachedRequest responds from the cache to requests for noise that has already been generated, and caches successful responses
+// Only GET requests with a seed are cached, since they're the only ones that always generate the same result
+// Responses carry an ETag, and requests whose If-None-Match has it are answered with a Not Modified instead of the response
+// The version is part of every key, so a new build of the service, which may generate different results, never reads the results of an old one
+func CachedRequest(handlerFunc httprouter.Handle, results *cache.Cache, version string) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+		key, ok := cacheKey(r, version)
+		if !ok {
+			handlerFunc(w, r, p)
+			return
+		}
+
+		entry, ok := results.Get(key)
+		if ok {
+			log.Info("Cache hit for %s", r.URL.String())
+		} else {
+			recorder := &responseRecorder{header: http.Header{}, code: http.StatusOK}
+			handlerFunc(recorder, r, p)
+
+			// Only successes are cached, so errors are always reported by the handler itself
+			if recorder.code != http.StatusOK {
+				recorder.writeTo(w)
+				return
+			}
+			entry = results.Put(key, recorder.encode())
+		}
+
+		header, body, err := decodeCachedResponse(entry.Value)
+		if err != nil {
+			log.Error("Failed to decode cached response for %s: %s", r.URL.String(), err.Error())
+			handlerFunc(w, r, p)
+			return
+		}
+
+		for name, values := range header {
+			w.Header()[name] = values
+		}
+		w.Header().Set("ETag", entry.ETag)
+		if matchesETag(r.Header.Get("If-None-Match"), entry.ETag) {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Write(body)
+	}
+}
+
+// cacheKey hashes the version, path and the validated noise params of a request, so requests for the same noise share a key
+// Params are given their defaults, ordered, and their numbers are written the same way, so requests that only differ in how their
+// params are written share a key too
+func cacheKey(r *http.Request, version string) (string, bool) {
+	query := r.URL.Query()
+	if r.Method != http.MethodGet || query.Get("seed") == "" {
+		return "", false
+	}
+
+	params, err := validateNoiseParams(query)
+	if err != nil {
+		return "", false
+	}
+
+	canonical := url.Values{}
+	for name := range query {
+		if value := query.Get(name); value != "" {
+			canonical.Set(name, canonicalNumbers(value))
+		}
+	}
+	canonical.Set("from", joinInts(params.from))
+	canonical.Set("to", joinInts(params.to))
+	canonical.Set("resolution", strconv.Itoa(params.resolution))
+	canonical.Set("noiseFunction", params.presetName)
+	canonical.Set("seed", strconv.FormatInt(params.seed, 10))
+	canonical.Set("roughness", strconv.FormatFloat(params.roughness, 'g', -1, 64))
+
+	return cache.Key(cacheFormat, version, r.URL.Path, canonical.Encode()), true
+}
+
+// canonicalNumbers rewrites a param that is a number, or a comma separated array of them, the same way however it's written, so 0.5 and 0.50 are equal
+// Integers and other numbers are written differently, since integer params reject numbers like 1e3 that equal an integer they accept
+// Any other param is returned as it is
+func canonicalNumbers(value string) string {
+	parts := strings.Split(value, ",")
+	for i, part := range parts {
+		if integer, err := strconv.Atoi(part); err == nil {
+			parts[i] = strconv.Itoa(integer)
+			continue
+		}
+		number, err := strconv.ParseFloat(part, 64)
+		if err != nil {
+			return value
+		}
+		parts[i] = strconv.FormatFloat(number, 'e', -1, 64)
+	}
+	return strings.Join(parts, ",")
+}
+
+// matchesETag returns true if an If-None-Match header has the ETag, or matches any ETag
+func matchesETag(ifNoneMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == etag || candidate == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// joinInts joins ints with commas, the same way they're written in params
+func joinInts(values []int) string {
+	strs := make([]string, len(values))
+	for i, value := range values {
+		strs[i] = strconv.Itoa(value)
+	}
+	return strings.Join(strs, ",")
+}
+
+// A responseRecorder keeps a handler's response, so it can be cached before it's written
+type responseRecorder struct {
+	header http.Header
+	code   int
+	body   bytes.Buffer
+}
+
+func (recorder *responseRecorder) Header() http.Header {
+	return recorder.header
+}
+
+func (recorder *responseRecorder) Write(data []byte) (int, error) {
+	return recorder.body.Write(data)
+}
+
+func (recorder *responseRecorder) WriteHeader(code int) {
+	recorder.code = code
+}
+
+// writeTo writes the recorded response
+func (recorder *responseRecorder) writeTo(w http.ResponseWriter) {
+	for name, values := range recorder.header {
+		w.Header()[name] = values
+	}
+	w.WriteHeader(recorder.code)
+	w.Write(recorder.body.Bytes())
+}
+
+// encode writes the recorded headers and body the way they're written in an http response, so they can be cached together
+func (recorder *responseRecorder) encode() []byte {
+	encoded := &bytes.Buffer{}
+	recorder.header.Write(encoded)
+	encoded.WriteString("\r\n")
+	encoded.Write(recorder.body.Bytes())
+	return encoded.Bytes()
+}
+
+// decodeCachedResponse reads the headers and body of an encoded response
+func decodeCachedResponse(encoded []byte) (http.Header, []byte, error) {
+	reader := bufio.NewReader(bytes.NewReader(encoded))
+	header, err := textproto.NewReader(reader).ReadMIMEHeader()
+	if err != nil {
+		return nil, nil, err
+	}
+	body, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	return http.Header(header), body, nil
+}
@@ -0,0 +1,248 @@
+package http_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bcokert/terragen/cache"
+	tghttp "github.com/bcokert/terragen/http"
+	"github.com/julienschmidt/httprouter"
+)
+
+func TestCachedRequest(t *testing.T) {
+	// The handler counts its calls, and responds with the query it was called with, so hits respond with the first query of their key
+	calls := 0
+	handler := tghttp.Handle(func(response http.ResponseWriter, request *http.Request, _ httprouter.Params) (interface{}, int) {
+		calls++
+		if request.URL.Query().Get("fail") != "" {
+			return fmt.Errorf("Failed"), http.StatusInternalServerError
+		}
+		return map[string]string{"query": request.URL.RawQuery}, http.StatusOK
+	})
+	cached := tghttp.CachedRequest(handler, cache.New(1<<20, nil), "1")
+
+	testCases := []struct {
+		Name               string
+		Method             string
+		URL                string
+		IfNoneMatch        string
+		ExpectedStatusCode int
+		ExpectedBody       string
+		ExpectedCalls      int
+	}{
+		{
+			Name:               "miss",
+			URL:                "/noise?seed=42&from=0,0&to=2,2",
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedBody:       `{"query":"seed=42\u0026from=0,0\u0026to=2,2"}`,
+			ExpectedCalls:      1,
+		},
+		{
+			Name:               "hit",
+			URL:                "/noise?seed=42&from=0,0&to=2,2",
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedBody:       `{"query":"seed=42\u0026from=0,0\u0026to=2,2"}`,
+			ExpectedCalls:      1,
+		},
+		{
+			Name:               "hit with defaults and reordered params",
+			URL:                "/noise?to=2,2&noiseFunction=red&seed=42&resolution=20&shape=",
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedBody:       `{"query":"seed=42\u0026from=0,0\u0026to=2,2"}`,
+			ExpectedCalls:      1,
+		},
+		{
+			Name:               "other path",
+			URL:                "/preview?seed=42&from=0,0&to=2,2",
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedCalls:      2,
+		},
+		{
+			Name:               "other params",
+			URL:                "/noise?seed=42&from=0,0&to=2,2&normalize=minmax",
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedCalls:      3,
+		},
+		{
+			Name:               "unseeded",
+			URL:                "/noise?from=0,0&to=2,2",
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedCalls:      4,
+		},
+		{
+			Name:               "unseeded again",
+			URL:                "/noise?from=0,0&to=2,2",
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedCalls:      5,
+		},
+		{
+			Name:               "post",
+			Method:             http.MethodPost,
+			URL:                "/noise?seed=42&from=0,0&to=2,2",
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedCalls:      6,
+		},
+		{
+			Name:               "invalid",
+			URL:                "/noise?seed=42&resolution=0",
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedCalls:      7,
+		},
+		{
+			Name:               "failure",
+			URL:                "/noise?seed=42&fail=true",
+			ExpectedStatusCode: http.StatusInternalServerError,
+			ExpectedBody:       `{"error": "Failed"}`,
+			ExpectedCalls:      8,
+		},
+		{
+			Name:               "failure again",
+			URL:                "/noise?seed=42&fail=true",
+			ExpectedStatusCode: http.StatusInternalServerError,
+			ExpectedBody:       `{"error": "Failed"}`,
+			ExpectedCalls:      9,
+		},
+		{
+			Name:               "not modified",
+			URL:                "/noise?seed=42&from=0,0&to=2,2",
+			IfNoneMatch:        "etag",
+			ExpectedStatusCode: http.StatusNotModified,
+			ExpectedBody:       "",
+			ExpectedCalls:      9,
+		},
+		{
+			Name:               "not modified by any",
+			URL:                "/noise?seed=42&from=0,0&to=2,2",
+			IfNoneMatch:        "*",
+			ExpectedStatusCode: http.StatusNotModified,
+			ExpectedBody:       "",
+			ExpectedCalls:      9,
+		},
+		{
+			Name:               "modified",
+			URL:                "/noise?seed=42&from=0,0&to=2,2",
+			IfNoneMatch:        `"stale"`,
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedBody:       `{"query":"seed=42\u0026from=0,0\u0026to=2,2"}`,
+			ExpectedCalls:      9,
+		},
+		{
+			Name:               "numbers",
+			URL:                "/noise?seed=42&mask=radial&maskFalloff=0.5&maskPolygon=1.5,2,3",
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedBody:       `{"query":"seed=42\u0026mask=radial\u0026maskFalloff=0.5\u0026maskPolygon=1.5,2,3"}`,
+			ExpectedCalls:      10,
+		},
+		{
+			Name:               "numbers written differently",
+			URL:                "/noise?seed=42&mask=radial&maskFalloff=.50&maskPolygon=1.50,02,03",
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedBody:       `{"query":"seed=42\u0026mask=radial\u0026maskFalloff=0.5\u0026maskPolygon=1.5,2,3"}`,
+			ExpectedCalls:      10,
+		},
+		{
+			Name:               "integer",
+			URL:                "/noise?seed=42&droplets=1000",
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedBody:       `{"query":"seed=42\u0026droplets=1000"}`,
+			ExpectedCalls:      11,
+		},
+		{
+			Name:               "integer written as a float",
+			URL:                "/noise?seed=42&droplets=1e3",
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedBody:       `{"query":"seed=42\u0026droplets=1e3"}`,
+			ExpectedCalls:      12,
+		},
+	}
+
+	// The steps share a cache, so they're run in order, and responses with the same body must have the same ETag
+	etag, body := "", ""
+	for _, tc := range testCases {
+		method := tc.Method
+		if method == "" {
+			method = http.MethodGet
+		}
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest(method, tc.URL, nil)
+		if tc.IfNoneMatch == "etag" {
+			r.Header.Set("If-None-Match", fmt.Sprintf(`"stale", W/%s`, etag))
+		} else if tc.IfNoneMatch != "" {
+			r.Header.Set("If-None-Match", tc.IfNoneMatch)
+		}
+		cached(w, r, nil)
+
+		if w.Code != tc.ExpectedStatusCode {
+			t.Errorf("'%s' failed. Expected status code %d, received %d", tc.Name, tc.ExpectedStatusCode, w.Code)
+		}
+		if (tc.ExpectedBody != "" || tc.ExpectedStatusCode == http.StatusNotModified) && w.Body.String() != tc.ExpectedBody {
+			t.Errorf("'%s' failed. Expected response '%s', received '%s'", tc.Name, tc.ExpectedBody, w.Body.String())
+		}
+		if calls != tc.ExpectedCalls {
+			t.Errorf("'%s' failed. Expected %d calls to the handler, received %d", tc.Name, tc.ExpectedCalls, calls)
+		}
+
+		// Cached responses keep their headers, and have an ETag
+		if tc.ExpectedStatusCode == http.StatusOK && tc.ExpectedBody != "" {
+			if w.Header().Get("Content-Type") != "application/json" || w.Header().Get("ETag") == "" {
+				t.Errorf("'%s' failed. Expected a json response with an ETag, received %v", tc.Name, w.Header())
+			}
+			if body == w.Body.String() && w.Header().Get("ETag") != etag {
+				t.Errorf("'%s' failed. Expected the ETag %s, received %s", tc.Name, etag, w.Header().Get("ETag"))
+			}
+			etag, body = w.Header().Get("ETag"), w.Body.String()
+		}
+	}
+}
+
+func TestCachedRequest_Version(t *testing.T) {
+	// Builds with different versions share a cache without reading each other's results
+	calls := 0
+	handler := tghttp.Handle(func(response http.ResponseWriter, request *http.Request, _ httprouter.Params) (interface{}, int) {
+		calls++
+		return calls, http.StatusOK
+	})
+	results := cache.New(1<<20, nil)
+
+	testCases := []struct {
+		Version       string
+		ExpectedBody  string
+		ExpectedCalls int
+	}{
+		{Version: "1", ExpectedBody: "1", ExpectedCalls: 1},
+		{Version: "2", ExpectedBody: "2", ExpectedCalls: 2},
+		{Version: "1", ExpectedBody: "1", ExpectedCalls: 2},
+	}
+
+	for i, tc := range testCases {
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest(http.MethodGet, "/noise?seed=42", nil)
+		tghttp.CachedRequest(handler, results, tc.Version)(w, r, nil)
+
+		if w.Body.String() != tc.ExpectedBody || calls != tc.ExpectedCalls {
+			t.Errorf("'%d' failed. Expected %s after %d calls, received %s after %d calls", i, tc.ExpectedBody, tc.ExpectedCalls, w.Body.String(), calls)
+		}
+	}
+}
+
+func TestCachedRequest_Noise(t *testing.T) {
+	// Cached noise is exactly the noise the handler generates
+	results := cache.New(1<<20, nil)
+	for _, url := range []string{"/noise?seed=42&from=0,0&to=2,2&resolution=4", "/noise?seed=42&from=0,0&to=2,2&resolution=4"} {
+		cached := httptest.NewRecorder()
+		r, _ := http.NewRequest(http.MethodGet, url, nil)
+		tghttp.CachedRequest(tghttp.HandleNoise(), results, "1")(cached, r, nil)
+
+		uncached := httptest.NewRecorder()
+		tghttp.HandleNoise()(uncached, r, nil)
+
+		if cached.Body.String() != uncached.Body.String() {
+			t.Errorf("Expected cached noise to equal generated noise, received %s and %s", cached.Body.String(), uncached.Body.String())
+		}
+	}
+	if results.Size() == 0 {
+		t.Errorf("Expected the noise to be cached")
+	}
+}
@@ -0,0 +1,125 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/bcokert/terragen/climate"
+	"github.com/bcokert/terragen/log"
+	"github.com/bcokert/terragen/noise"
+	"github.com/julienschmidt/httprouter"
+)
+
+// HandleClimate generates 2D noise with the given params, and responds with it along with the climate the prevailing wind gives it. It is an idempotent call
+func HandleClimate() httprouter.Handle {
+	return Handle(func(response http.ResponseWriter, request *http.Request, _ httprouter.Params) (interface{}, int) {
+		log.Info("Request Started: %s %s", request.Method, request.URL.String())
+
+		// Validate the params, and get the related data
+		params, err := validateNoiseParams(request.URL.Query())
+		if err != nil {
+			return fmt.Errorf("Invalid param: (%s)", err.Error()), http.StatusBadRequest
+		}
+
+		options, err := validateClimateParams(request.URL.Query(), params)
+		if err != nil {
+			return fmt.Errorf("Invalid param: (%s)", err.Error()), http.StatusBadRequest
+		}
+
+		noise := generateNoise(params)
+		weather, err := climate.Simulate(noise, options)
+		if err != nil {
+			return fmt.Errorf("Failed to simulate climate: (%s)", err.Error()), http.StatusInternalServerError
+		}
+
+		return climateResponse{Noise: noise, Weather: weather}, http.StatusOK
+	})
+}
+
+type climateResponse struct {
+	Noise   *noise.Noise     `json:"noise"`
+	Weather *climate.Weather `json:"weather"`
+}
+
+func validateClimateParams(params url.Values, noiseParams queryParams) (options climate.Options, err error) {
+	if len(noiseParams.from) != 2 {
+		return climate.Options{}, errors.New("Climate requires a 2 dimensional From and To")
+	}
+
+	// Validate the temperature, each part of which is an optional number
+	options = climate.NewDefaultOptions()
+	temperatureParams := []struct {
+		name  string
+		value *float64
+		err   string
+	}{
+		{"seaLevel", &options.SeaLevel, "SeaLevel must be a number"},
+		{"equatorTemperature", &options.EquatorTemperature, "EquatorTemperature must be a number"},
+		{"poleTemperature", &options.PoleTemperature, "PoleTemperature must be a number"},
+		{"poleDistance", &options.PoleDistance, "PoleDistance must be a number"},
+		{"lapseRate", &options.LapseRate, "LapseRate must be a number"},
+	}
+	for _, temperatureParam := range temperatureParams {
+		if value := params.Get(temperatureParam.name); value != "" {
+			if *temperatureParam.value, err = strconv.ParseFloat(value, 64); err != nil {
+				return climate.Options{}, errors.New(temperatureParam.err)
+			}
+		}
+	}
+
+	return validateWindParams(params, options)
+}
+
+// validateWindParams validates how the prevailing wind carries moisture, which every endpoint that simulates climate shares
+// Any wind params that are missing keep their value from defaults
+func validateWindParams(params url.Values, defaults climate.Options) (options climate.Options, err error) {
+	wind := params.Get("wind")
+	inflowHumidity := params.Get("inflowHumidity")
+	referencePrecipitation := params.Get("referencePrecipitation")
+
+	options = defaults
+	if wind != "" {
+		direction := ParseFloatArray(wind)
+		if len(direction) != 2 || (direction[0] == 0 && direction[1] == 0) ||
+			math.IsNaN(direction[0]) || math.IsInf(direction[0], 0) || math.IsNaN(direction[1]) || math.IsInf(direction[1], 0) {
+			return climate.Options{}, errors.New("Wind must be a non zero x,y direction")
+		}
+		options.Wind = [2]float64{direction[0], direction[1]}
+	}
+
+	if inflowHumidity != "" {
+		if options.InflowHumidity, err = strconv.ParseFloat(inflowHumidity, 64); err != nil || options.InflowHumidity < 0 || options.InflowHumidity > 1 {
+			return climate.Options{}, errors.New("InflowHumidity must be a number between 0 and 1")
+		}
+	}
+
+	// Validate the rates, each of which is an optional non negative number
+	rateParams := []struct {
+		name  string
+		value *float64
+		err   string
+	}{
+		{"evaporation", &options.Evaporation, "Evaporation must be a non negative number"},
+		{"rainfall", &options.Rainfall, "Rainfall must be a non negative number"},
+		{"orographic", &options.Orographic, "Orographic must be a non negative number"},
+	}
+	for _, rateParam := range rateParams {
+		if value := params.Get(rateParam.name); value != "" {
+			if *rateParam.value, err = strconv.ParseFloat(value, 64); err != nil || *rateParam.value < 0 {
+				return climate.Options{}, errors.New(rateParam.err)
+			}
+		}
+	}
+
+	if referencePrecipitation != "" {
+		if options.ReferencePrecipitation, err = strconv.ParseFloat(referencePrecipitation, 64); err != nil || options.ReferencePrecipitation <= 0 {
+			return climate.Options{}, errors.New("ReferencePrecipitation must be a positive number")
+		}
+	}
+
+	return options, nil
+}
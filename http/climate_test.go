@@ -0,0 +1,123 @@
+package http_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	tghttp "github.com/bcokert/terragen/http"
+)
+
+func TestHandleClimate(t *testing.T) {
+	testCases := map[string]struct {
+		Query              string
+		ExpectedStatusCode int
+		ExpectedErrorBody  string
+		ExpectedSamples    int
+	}{
+		"defaults": {
+			Query:              "seed=42",
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedSamples:    10000,
+		},
+		"custom": {
+			Query:              "from=0,0&to=2,3&resolution=4&seed=42&wind=-1,0.5&inflowHumidity=0.5&evaporation=1&rainfall=0.2&orographic=2&referencePrecipitation=0.5&seaLevel=0.1&lapseRate=10",
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedSamples:    96,
+		},
+		"1d": {
+			Query:              "from=0&to=2&seed=42",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (Climate requires a 2 dimensional From and To)"}`,
+		},
+		"invalid wind": {
+			Query:              "seed=42&wind=east",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (Wind must be a non zero x,y direction)"}`,
+		},
+		"3d wind": {
+			Query:              "seed=42&wind=1,0,0",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (Wind must be a non zero x,y direction)"}`,
+		},
+		"infinite wind": {
+			Query:              "seed=42&wind=Inf,1",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (Wind must be a non zero x,y direction)"}`,
+		},
+		"nan wind": {
+			Query:              "seed=42&wind=1,NaN",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (Wind must be a non zero x,y direction)"}`,
+		},
+		"invalid inflow humidity": {
+			Query:              "seed=42&inflowHumidity=2",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (InflowHumidity must be a number between 0 and 1)"}`,
+		},
+		"negative orographic": {
+			Query:              "seed=42&orographic=-1",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (Orographic must be a non negative number)"}`,
+		},
+		"zero reference precipitation": {
+			Query:              "seed=42&referencePrecipitation=0",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (ReferencePrecipitation must be a positive number)"}`,
+		},
+		"invalid temperature": {
+			Query:              "seed=42&poleTemperature=cold",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (PoleTemperature must be a number)"}`,
+		},
+	}
+
+	for name, tc := range testCases {
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest(http.MethodGet, fmt.Sprintf("/climate?%s", tc.Query), nil)
+		tghttp.HandleClimate()(w, r, nil)
+
+		if w.Code != tc.ExpectedStatusCode {
+			t.Errorf("'%s' failed. Expected status code %d, received %d", name, tc.ExpectedStatusCode, w.Code)
+			t.Logf("Response: %s", w.Body.String())
+			continue
+		}
+
+		if tc.ExpectedErrorBody != "" {
+			if w.Body.String() != tc.ExpectedErrorBody {
+				t.Errorf("'%s' failed. Expected error response '%s', received '%s'", name, tc.ExpectedErrorBody, w.Body.String())
+			}
+			continue
+		}
+
+		var result struct {
+			Noise struct {
+				Values []float64 `json:"values"`
+			} `json:"noise"`
+			Weather struct {
+				Humidity      []float64 `json:"humidity"`
+				Precipitation []float64 `json:"precipitation"`
+				Moisture      []float64 `json:"moisture"`
+				Temperature   []float64 `json:"temperature"`
+			} `json:"weather"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+			t.Errorf("'%s' failed. Expected a climate response, received '%s'", name, w.Body.String())
+			continue
+		}
+
+		for channel, length := range map[string]int{
+			"values":        len(result.Noise.Values),
+			"humidity":      len(result.Weather.Humidity),
+			"precipitation": len(result.Weather.Precipitation),
+			"moisture":      len(result.Weather.Moisture),
+			"temperature":   len(result.Weather.Temperature),
+		} {
+			if length != tc.ExpectedSamples {
+				t.Errorf("'%s' failed. Expected %d %s, received %d", name, tc.ExpectedSamples, channel, length)
+			}
+		}
+	}
+}
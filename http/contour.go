@@ -0,0 +1,130 @@
+package http
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/bcokert/terragen/contour"
+	"github.com/bcokert/terragen/log"
+	"github.com/julienschmidt/httprouter"
+)
+
+// maxSmoothing limits the smoothing iterations of HandleContours, since each one doubles the number of points in every line
+const maxSmoothing = 8
+
+// maxContourLevels limits the levels traced by HandleContours, since each one is traced over the whole heightfield
+const maxContourLevels = 1000
+
+// HandleContours generates 2D noise with the given params, and responds with its contour lines as GeoJSON or an SVG topographic map. It is an idempotent call
+func HandleContours() httprouter.Handle {
+	return Handle(func(response http.ResponseWriter, request *http.Request, _ httprouter.Params) (interface{}, int) {
+		log.Info("Request Started: %s %s", request.Method, request.URL.String())
+
+		// Validate the params, and get the related data
+		params, err := validateNoiseParams(request.URL.Query())
+		if err != nil {
+			return fmt.Errorf("Invalid param: (%s)", err.Error()), http.StatusBadRequest
+		}
+
+		contourParams, err := validateContourParams(request.URL.Query(), params)
+		if err != nil {
+			return fmt.Errorf("Invalid param: (%s)", err.Error()), http.StatusBadRequest
+		}
+
+		noise := generateNoise(params)
+		levels := contourParams.levels
+		if levels == nil {
+			if levels, err = contour.IntervalLevels(noise.Values, contourParams.interval, maxContourLevels); err != nil {
+				return fmt.Errorf("Invalid param: (%s)", err.Error()), http.StatusBadRequest
+			}
+		}
+
+		contours, err := contour.Trace(noise, levels, contourParams.smoothing)
+		if err != nil {
+			return fmt.Errorf("Failed to trace contours: (%s)", err.Error()), http.StatusInternalServerError
+		}
+
+		if contourParams.format != "svg" {
+			return contour.ToGeoJSON(contours), http.StatusOK
+		}
+
+		from := [2]float64{float64(params.from[0]), float64(params.from[1])}
+		to := [2]float64{float64(params.to[0]), float64(params.to[1])}
+		buffer := &bytes.Buffer{}
+		if err := contour.WriteSVG(buffer, contours, from, to, contourParams.svg); err != nil {
+			return fmt.Errorf("Failed to encode contours: (%s)", err.Error()), http.StatusInternalServerError
+		}
+
+		response.Header().Add("Content-Type", "image/svg+xml")
+		response.Write(buffer.Bytes())
+		return nil, http.StatusOK
+	})
+}
+
+type contourParams struct {
+	format    string
+	interval  float64
+	levels    []float64
+	smoothing int
+	svg       contour.SVGOptions
+}
+
+func validateContourParams(params url.Values, noiseParams queryParams) (response contourParams, err error) {
+	format := params.Get("format")
+	interval := params.Get("interval")
+	levels := params.Get("levels")
+	smoothing := params.Get("smoothing")
+	width := params.Get("width")
+	indexInterval := params.Get("indexInterval")
+
+	if len(noiseParams.from) != 2 {
+		return contourParams{}, errors.New("Contours require a 2 dimensional From and To")
+	}
+
+	response.format = "geojson"
+	if format != "" {
+		if format != "geojson" && format != "svg" {
+			return contourParams{}, errors.New("Format must be one of geojson or svg")
+		}
+		response.format = format
+	}
+
+	// Validate the levels, which are either listed explicitly or every multiple of an interval
+	response.interval = 0.1
+	if interval != "" {
+		if response.interval, err = strconv.ParseFloat(interval, 64); err != nil || response.interval <= 0 {
+			return contourParams{}, errors.New("Interval must be a positive number")
+		}
+	}
+	if levels != "" {
+		if response.levels = ParseFloatArray(levels); len(response.levels) == 0 || len(response.levels) > maxContourLevels {
+			return contourParams{}, fmt.Errorf("Levels must be an array of at most %d numbers", maxContourLevels)
+		}
+	}
+
+	response.smoothing = 2
+	if smoothing != "" {
+		if response.smoothing, err = strconv.Atoi(smoothing); err != nil || response.smoothing < 0 || response.smoothing > maxSmoothing {
+			return contourParams{}, fmt.Errorf("Smoothing must be an integer between 0 and %d", maxSmoothing)
+		}
+	}
+
+	// Validate the map options, which only apply to svg
+	response.svg = contour.NewDefaultSVGOptions()
+	if width != "" {
+		if response.svg.Width, err = strconv.Atoi(width); err != nil || response.svg.Width < 1 {
+			return contourParams{}, errors.New("Width must be a positive integer")
+		}
+	}
+	if indexInterval != "" {
+		if response.svg.IndexInterval, err = strconv.Atoi(indexInterval); err != nil || response.svg.IndexInterval < 0 {
+			return contourParams{}, errors.New("IndexInterval must be a non negative integer")
+		}
+	}
+
+	return response, nil
+}
@@ -0,0 +1,132 @@
+package http_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	tghttp "github.com/bcokert/terragen/http"
+)
+
+func TestHandleContours(t *testing.T) {
+	testCases := map[string]struct {
+		Query               string
+		ExpectedStatusCode  int
+		ExpectedErrorBody   string
+		ExpectedContentType string
+		ExpectedLevels      []float64
+	}{
+		"defaults": {
+			Query:               "seed=42",
+			ExpectedStatusCode:  http.StatusOK,
+			ExpectedContentType: "application/json",
+		},
+		"explicit levels": {
+			Query:               "from=0,0&to=2,2&resolution=8&seed=42&levels=0,0.25&smoothing=0",
+			ExpectedStatusCode:  http.StatusOK,
+			ExpectedContentType: "application/json",
+			ExpectedLevels:      []float64{0, 0.25},
+		},
+		"svg": {
+			Query:               "from=0,0&to=2,2&resolution=8&seed=42&format=svg&interval=0.2&width=300&indexInterval=2",
+			ExpectedStatusCode:  http.StatusOK,
+			ExpectedContentType: "image/svg+xml",
+		},
+		"1d": {
+			Query:              "from=0&to=2&seed=42",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (Contours require a 2 dimensional From and To)"}`,
+		},
+		"invalid format": {
+			Query:              "seed=42&format=pdf",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (Format must be one of geojson or svg)"}`,
+		},
+		"invalid interval": {
+			Query:              "seed=42&interval=0",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (Interval must be a positive number)"}`,
+		},
+		"invalid levels": {
+			Query:              "seed=42&levels=low,high",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (Levels must be an array of at most 1000 numbers)"}`,
+		},
+		"too many levels": {
+			Query:              "seed=42&interval=0.000000001",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (Interval must give at most 1000 levels)"}`,
+		},
+		"too much smoothing": {
+			Query:              "seed=42&smoothing=20",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (Smoothing must be an integer between 0 and 8)"}`,
+		},
+		"invalid width": {
+			Query:              "seed=42&format=svg&width=0",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (Width must be a positive integer)"}`,
+		},
+		"invalid index interval": {
+			Query:              "seed=42&format=svg&indexInterval=-5",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (IndexInterval must be a non negative integer)"}`,
+		},
+	}
+
+	for name, tc := range testCases {
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest(http.MethodGet, fmt.Sprintf("/contours?%s", tc.Query), nil)
+		tghttp.HandleContours()(w, r, nil)
+
+		if w.Code != tc.ExpectedStatusCode {
+			t.Errorf("'%s' failed. Expected status code %d, received %d", name, tc.ExpectedStatusCode, w.Code)
+			t.Logf("Response: %s", w.Body.String())
+			continue
+		}
+
+		if tc.ExpectedErrorBody != "" {
+			if w.Body.String() != tc.ExpectedErrorBody {
+				t.Errorf("'%s' failed. Expected error response '%s', received '%s'", name, tc.ExpectedErrorBody, w.Body.String())
+			}
+			continue
+		}
+
+		if contentType := w.Header().Get("Content-Type"); contentType != tc.ExpectedContentType {
+			t.Errorf("'%s' failed. Expected content type %s, received %s", name, tc.ExpectedContentType, contentType)
+		}
+
+		if tc.ExpectedContentType == "image/svg+xml" {
+			if !strings.HasPrefix(w.Body.String(), "<svg") {
+				t.Errorf("'%s' failed. Expected an svg, received %s", name, w.Body.String())
+			}
+			continue
+		}
+
+		var result struct {
+			Type     string `json:"type"`
+			Features []struct {
+				Properties struct {
+					Level float64 `json:"level"`
+				} `json:"properties"`
+			} `json:"features"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil || result.Type != "FeatureCollection" {
+			t.Errorf("'%s' failed. Expected a FeatureCollection, received '%s'", name, w.Body.String())
+			continue
+		}
+		if len(result.Features) == 0 {
+			t.Errorf("'%s' failed. Expected some contours", name)
+		}
+		if tc.ExpectedLevels != nil {
+			for _, feature := range result.Features {
+				if feature.Properties.Level != tc.ExpectedLevels[0] && feature.Properties.Level != tc.ExpectedLevels[1] {
+					t.Errorf("'%s' failed. Expected only levels %v, received %v", name, tc.ExpectedLevels, feature.Properties.Level)
+				}
+			}
+		}
+	}
+}
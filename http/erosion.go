@@ -0,0 +1,138 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/bcokert/terragen/erosion"
+	"github.com/bcokert/terragen/math"
+)
+
+// maxDropletsPerSample limits the droplets of hydraulic erosion, relative to the samples they erode, since each one is simulated in turn
+const maxDropletsPerSample = 16
+
+// maxThermalIterations limits the iterations of thermal erosion, since each one is a pass over every sample
+const maxThermalIterations = 1000
+
+// A postProcess builds an Eroder for the seed of a request, so that post processing is as repeatable as the noise itself
+type postProcess func(seed int64) erosion.Eroder
+
+// validatePostProcessParams validates the comma separated list of post processes to apply to generated noise, in order
+// Post processes operate on heightfields of the given shape, so they require 2 dimensional noise
+func validatePostProcessParams(params url.Values, shape []int) (processes []postProcess, err error) {
+	postProcessParam := params.Get("postProcess")
+	if postProcessParam == "" {
+		return nil, nil
+	}
+
+	if len(shape) != 2 {
+		return nil, errors.New("PostProcess requires a 2 dimensional From and To")
+	}
+
+	for _, name := range strings.Split(postProcessParam, ",") {
+		switch name {
+		case "hydraulic":
+			options, err := validateHydraulicParams(params, shape[0]*shape[1])
+			if err != nil {
+				return nil, err
+			}
+			processes = append(processes, func(seed int64) erosion.Eroder {
+				return erosion.Hydraulic(math.NewDefaultSource(seed), options)
+			})
+		case "thermal":
+			options, err := validateThermalParams(params)
+			if err != nil {
+				return nil, err
+			}
+			processes = append(processes, func(seed int64) erosion.Eroder {
+				return erosion.Thermal(options)
+			})
+		default:
+			return nil, errors.New("PostProcess must be a list of hydraulic or thermal")
+		}
+	}
+
+	return processes, nil
+}
+
+// validateHydraulicParams validates the droplet params of hydraulic erosion, falling back to the defaults for any that are missing
+// The droplets are limited by the number of samples they erode
+func validateHydraulicParams(params url.Values, samples int) (options erosion.HydraulicOptions, err error) {
+	options = erosion.NewDefaultHydraulicOptions()
+
+	if droplets := params.Get("droplets"); droplets != "" {
+		if options.Iterations, err = strconv.Atoi(droplets); err != nil || options.Iterations < 0 {
+			return erosion.HydraulicOptions{}, errors.New("Droplets must be a non negative integer")
+		}
+		if options.Iterations/maxDropletsPerSample > samples {
+			return erosion.HydraulicOptions{}, fmt.Errorf("Droplets must be at most %d per sample", maxDropletsPerSample)
+		}
+	}
+
+	if capacity := params.Get("capacity"); capacity != "" {
+		if options.Capacity, err = strconv.ParseFloat(capacity, 64); err != nil || options.Capacity < 0 {
+			return erosion.HydraulicOptions{}, errors.New("Capacity must be a non negative number")
+		}
+	}
+
+	if inertia := params.Get("inertia"); inertia != "" {
+		if options.Inertia, err = strconv.ParseFloat(inertia, 64); err != nil || options.Inertia < 0 || options.Inertia > 1 {
+			return erosion.HydraulicOptions{}, errors.New("Inertia must be a number between 0 and 1")
+		}
+	}
+
+	if deposition := params.Get("deposition"); deposition != "" {
+		if options.Deposition, err = strconv.ParseFloat(deposition, 64); err != nil || options.Deposition < 0 || options.Deposition > 1 {
+			return erosion.HydraulicOptions{}, errors.New("Deposition must be a number between 0 and 1")
+		}
+	}
+
+	if erosionRate := params.Get("erosionRate"); erosionRate != "" {
+		if options.Erosion, err = strconv.ParseFloat(erosionRate, 64); err != nil || options.Erosion < 0 || options.Erosion > 1 {
+			return erosion.HydraulicOptions{}, errors.New("ErosionRate must be a number between 0 and 1")
+		}
+	}
+
+	if evaporation := params.Get("evaporation"); evaporation != "" {
+		if options.Evaporation, err = strconv.ParseFloat(evaporation, 64); err != nil || options.Evaporation < 0 || options.Evaporation > 1 {
+			return erosion.HydraulicOptions{}, errors.New("Evaporation must be a number between 0 and 1")
+		}
+	}
+
+	return options, nil
+}
+
+// neighborhoods are the valid values of the neighborhood param
+var neighborhoods = map[string]erosion.Neighborhood{
+	"vonNeumann": erosion.VonNeumann,
+	"moore":      erosion.Moore,
+}
+
+// validateThermalParams validates the talus params of thermal erosion, falling back to the defaults for any that are missing
+func validateThermalParams(params url.Values) (options erosion.ThermalOptions, err error) {
+	options = erosion.NewDefaultThermalOptions()
+
+	if thermalIterations := params.Get("thermalIterations"); thermalIterations != "" {
+		if options.Iterations, err = strconv.Atoi(thermalIterations); err != nil || options.Iterations < 0 || options.Iterations > maxThermalIterations {
+			return erosion.ThermalOptions{}, fmt.Errorf("ThermalIterations must be an integer between 0 and %d", maxThermalIterations)
+		}
+	}
+
+	if talusAngle := params.Get("talusAngle"); talusAngle != "" {
+		if options.TalusAngle, err = strconv.ParseFloat(talusAngle, 64); err != nil || options.TalusAngle < 0 || options.TalusAngle >= 90 {
+			return erosion.ThermalOptions{}, errors.New("TalusAngle must be a number of degrees between 0 and 90")
+		}
+	}
+
+	if neighborhood := params.Get("neighborhood"); neighborhood != "" {
+		var ok bool
+		if options.Neighborhood, ok = neighborhoods[neighborhood]; !ok {
+			return erosion.ThermalOptions{}, errors.New("Neighborhood must be one of vonNeumann or moore")
+		}
+	}
+
+	return options, nil
+}
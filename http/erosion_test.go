@@ -0,0 +1,142 @@
+package http_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	tghttp "github.com/bcokert/terragen/http"
+	"github.com/bcokert/terragen/noise"
+)
+
+func TestHandleNoise_PostProcess(t *testing.T) {
+	testCases := map[string]struct {
+		Query              string
+		ExpectedStatusCode int
+		ExpectedErrorBody  string
+	}{
+		"hydraulic": {
+			Query:              "from=0,0&to=2,2&resolution=8&seed=42&postProcess=hydraulic",
+			ExpectedStatusCode: http.StatusOK,
+		},
+		"hydraulic with options": {
+			Query:              "from=0,0&to=2,2&resolution=8&seed=42&postProcess=hydraulic&droplets=500&inertia=0.1&capacity=8&deposition=0.2&erosionRate=0.5&evaporation=0.02",
+			ExpectedStatusCode: http.StatusOK,
+		},
+		"thermal": {
+			Query:              "from=0,0&to=2,2&resolution=8&seed=42&postProcess=thermal&thermalIterations=10&talusAngle=20&neighborhood=vonNeumann",
+			ExpectedStatusCode: http.StatusOK,
+		},
+		"chained": {
+			Query:              "from=0,0&to=2,2&resolution=8&seed=42&postProcess=hydraulic,thermal",
+			ExpectedStatusCode: http.StatusOK,
+		},
+		"1d": {
+			Query:              "from=0&to=2&seed=42&postProcess=hydraulic",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (PostProcess requires a 2 dimensional From and To)"}`,
+		},
+		"unknown process": {
+			Query:              "seed=42&postProcess=glacial",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (PostProcess must be a list of hydraulic or thermal)"}`,
+		},
+		"negative droplets": {
+			Query:              "seed=42&postProcess=hydraulic&droplets=-5",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (Droplets must be a non negative integer)"}`,
+		},
+		"too many thermal iterations": {
+			Query:              "from=0,0&to=2,2&resolution=8&seed=42&postProcess=thermal&thermalIterations=2000000000",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (ThermalIterations must be an integer between 0 and 1000)"}`,
+		},
+		"too many droplets": {
+			Query:              "from=0,0&to=2,2&resolution=8&seed=42&postProcess=hydraulic&droplets=2000000000",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (Droplets must be at most 16 per sample)"}`,
+		},
+		"invalid capacity": {
+			Query:              "seed=42&postProcess=hydraulic&capacity=lots",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (Capacity must be a non negative number)"}`,
+		},
+		"inertia out of range": {
+			Query:              "seed=42&postProcess=hydraulic&inertia=1.5",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (Inertia must be a number between 0 and 1)"}`,
+		},
+		"invalid erosion rate": {
+			Query:              "seed=42&postProcess=hydraulic&erosionRate=-1",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (ErosionRate must be a number between 0 and 1)"}`,
+		},
+		"negative thermal iterations": {
+			Query:              "seed=42&postProcess=thermal&thermalIterations=-1",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (ThermalIterations must be an integer between 0 and 1000)"}`,
+		},
+		"vertical talus angle": {
+			Query:              "seed=42&postProcess=thermal&talusAngle=90",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (TalusAngle must be a number of degrees between 0 and 90)"}`,
+		},
+		"invalid neighborhood": {
+			Query:              "seed=42&postProcess=thermal&neighborhood=hex",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (Neighborhood must be one of vonNeumann or moore)"}`,
+		},
+	}
+
+	for name, tc := range testCases {
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest(http.MethodGet, fmt.Sprintf("/noise?%s", tc.Query), nil)
+		tghttp.HandleNoise()(w, r, nil)
+
+		if w.Code != tc.ExpectedStatusCode {
+			t.Errorf("'%s' failed. Expected status code %d, received %d", name, tc.ExpectedStatusCode, w.Code)
+			t.Logf("Response: %s", w.Body.String())
+			continue
+		}
+
+		if tc.ExpectedErrorBody != "" {
+			if w.Body.String() != tc.ExpectedErrorBody {
+				t.Errorf("'%s' failed. Expected error response '%s', received '%s'", name, tc.ExpectedErrorBody, w.Body.String())
+			}
+			continue
+		}
+
+		var result noise.Noise
+		if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+			t.Errorf("'%s' failed. Expected a noise response, received '%s'", name, w.Body.String())
+		}
+	}
+}
+
+func TestHandleNoise_PostProcessChangesNoise(t *testing.T) {
+	query := "from=0,0&to=2,2&resolution=16&seed=42&noiseFunction=red"
+	generate := func(query string) noise.Noise {
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest(http.MethodGet, fmt.Sprintf("/noise?%s", query), nil)
+		tghttp.HandleNoise()(w, r, nil)
+		var result noise.Noise
+		json.Unmarshal(w.Body.Bytes(), &result)
+		return result
+	}
+
+	raw, eroded, again := generate(query), generate(query+"&postProcess=hydraulic"), generate(query+"&postProcess=hydraulic")
+	changed := false
+	for i := range raw.Values {
+		if raw.Values[i] != eroded.Values[i] {
+			changed = true
+		}
+		if eroded.Values[i] != again.Values[i] {
+			t.Fatalf("Expected erosion to be repeatable for a seed, received %v and %v at %d", eroded.Values[i], again.Values[i], i)
+		}
+	}
+	if !changed {
+		t.Errorf("Expected hydraulic erosion to change the noise")
+	}
+}
@@ -0,0 +1,109 @@
+package http
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/bcokert/terragen/image"
+	"github.com/bcokert/terragen/log"
+	"github.com/bcokert/terragen/noise"
+	"github.com/julienschmidt/httprouter"
+)
+
+// HandleHeightmap generates 2D noise with the given params, and responds with it as a grayscale PNG. It is an idempotent call
+func HandleHeightmap() httprouter.Handle {
+	return Handle(func(response http.ResponseWriter, request *http.Request, _ httprouter.Params) (interface{}, int) {
+		log.Info("Request Started: %s %s", request.Method, request.URL.String())
+
+		// Validate the params, and get the related data
+		params, err := validateNoiseParams(request.URL.Query())
+		if err != nil {
+			return fmt.Errorf("Invalid param: (%s)", err.Error()), http.StatusBadRequest
+		}
+
+		heightmapParams, err := validateHeightmapParams(request.URL.Query(), params)
+		if err != nil {
+			return fmt.Errorf("Invalid param: (%s)", err.Error()), http.StatusBadRequest
+		}
+
+		noise := generateNoise(params)
+
+		buffer := &bytes.Buffer{}
+		if err := image.EncodeHeightmap(buffer, noise, heightmapParams.normalizer(noise), heightmapParams.bitDepth); err != nil {
+			return fmt.Errorf("Failed to encode heightmap: (%s)", err.Error()), http.StatusInternalServerError
+		}
+
+		response.Header().Add("Content-Type", "image/png")
+		response.Write(buffer.Bytes())
+		return nil, http.StatusOK
+	})
+}
+
+type heightmapParams struct {
+	bitDepth   image.BitDepth
+	normalizer func(noise *noise.Noise) image.Normalizer
+}
+
+func validateHeightmapParams(params url.Values, noiseParams queryParams) (response heightmapParams, err error) {
+	bitDepth := params.Get("bitDepth")
+
+	if len(noiseParams.from) != 2 {
+		return heightmapParams{}, errors.New("Heightmaps require a 2 dimensional From and To")
+	}
+
+	// Validate bit depth
+	response.bitDepth = image.Gray16
+	if bitDepth != "" {
+		depth, err := strconv.Atoi(bitDepth)
+		if err != nil || (image.BitDepth(depth) != image.Gray8 && image.BitDepth(depth) != image.Gray16) {
+			return heightmapParams{}, errors.New("BitDepth must be 8 or 16")
+		}
+		response.bitDepth = image.BitDepth(depth)
+	}
+
+	if response.normalizer, err = validateNormalizationParams(params); err != nil {
+		return heightmapParams{}, err
+	}
+
+	return response, nil
+}
+
+// validateNormalizationParams builds a constructor for the normalizer described by the params
+// The normalizer can't be built until the noise is generated, since some of them depend on the generated values
+func validateNormalizationParams(params url.Values) (func(noise *noise.Noise) image.Normalizer, error) {
+	normalization := params.Get("normalization")
+	valueRange := params.Get("range")
+	percentiles := params.Get("percentiles")
+
+	switch normalization {
+	case "", "minmax":
+		return func(noise *noise.Noise) image.Normalizer {
+			return image.NewMinMaxNormalizer(noise.Values)
+		}, nil
+	case "range":
+		bounds := ParseFloatArray(valueRange)
+		if len(bounds) != 2 || bounds[0] >= bounds[1] {
+			return nil, errors.New("Range must be two increasing numbers when using range normalization")
+		}
+		return func(noise *noise.Noise) image.Normalizer {
+			return image.NewRangeNormalizer(bounds[0], bounds[1])
+		}, nil
+	case "percentile":
+		bounds := []float64{0.01, 0.99}
+		if percentiles != "" {
+			bounds = ParseFloatArray(percentiles)
+			if len(bounds) != 2 || bounds[0] < 0 || bounds[1] > 1 || bounds[0] >= bounds[1] {
+				return nil, errors.New("Percentiles must be two increasing numbers between 0 and 1")
+			}
+		}
+		return func(noise *noise.Noise) image.Normalizer {
+			return image.NewPercentileNormalizer(noise.Values, bounds[0], bounds[1])
+		}, nil
+	default:
+		return nil, errors.New("Normalization must be one of minmax, range or percentile")
+	}
+}
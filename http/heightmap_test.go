@@ -0,0 +1,113 @@
+package http_test
+
+import (
+	"fmt"
+	"image/color"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	tghttp "github.com/bcokert/terragen/http"
+)
+
+func TestHandleHeightmap(t *testing.T) {
+	testCases := map[string]struct {
+		Query              string
+		ExpectedStatusCode int
+		ExpectedErrorBody  string
+		ExpectedModel      color.Model
+		ExpectedWidth      int
+		ExpectedHeight     int
+	}{
+		"defaults": {
+			Query:              "seed=42",
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedModel:      color.Gray16Model,
+			ExpectedWidth:      100,
+			ExpectedHeight:     100,
+		},
+		"8 bit range": {
+			Query:              "from=0,0&to=3,2&resolution=4&seed=42&bitDepth=8&normalization=range&range=-1,1",
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedModel:      color.GrayModel,
+			ExpectedWidth:      12,
+			ExpectedHeight:     8,
+		},
+		"percentile": {
+			Query:              "from=0,0&to=2,2&resolution=4&seed=42&normalization=percentile&percentiles=0.05,0.95",
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedModel:      color.Gray16Model,
+			ExpectedWidth:      8,
+			ExpectedHeight:     8,
+		},
+		"invalid noise param": {
+			Query:              "from=0,0&to=2,2&resolution=0&seed=42",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (Resolution must be a positive integer)"}`,
+		},
+		"1d": {
+			Query:              "from=0&to=2&seed=42",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (Heightmaps require a 2 dimensional From and To)"}`,
+		},
+		"invalid bit depth": {
+			Query:              "seed=42&bitDepth=12",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (BitDepth must be 8 or 16)"}`,
+		},
+		"missing range": {
+			Query:              "seed=42&normalization=range",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (Range must be two increasing numbers when using range normalization)"}`,
+		},
+		"invalid percentiles": {
+			Query:              "seed=42&normalization=percentile&percentiles=0.5,1.5",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (Percentiles must be two increasing numbers between 0 and 1)"}`,
+		},
+		"invalid normalization": {
+			Query:              "seed=42&normalization=banana",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (Normalization must be one of minmax, range or percentile)"}`,
+		},
+	}
+
+	for name, tc := range testCases {
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest(http.MethodGet, fmt.Sprintf("/heightmap?%s", tc.Query), nil)
+		tghttp.HandleHeightmap()(w, r, nil)
+
+		if w.Code != tc.ExpectedStatusCode {
+			t.Errorf("'%s' failed. Expected status code %d, received %d", name, tc.ExpectedStatusCode, w.Code)
+			t.Logf("Response: %s", w.Body.String())
+			continue
+		}
+
+		if tc.ExpectedErrorBody != "" {
+			if w.Body.String() != tc.ExpectedErrorBody {
+				t.Errorf("'%s' failed. Expected error response '%s', received '%s'", name, tc.ExpectedErrorBody, w.Body.String())
+			}
+			continue
+		}
+
+		if contentType := w.Header().Get("Content-Type"); contentType != "image/png" {
+			t.Errorf("'%s' failed. Expected content type image/png, received %s", name, contentType)
+		}
+		if origin := w.Result().Header.Get("Access-Control-Allow-Origin"); origin != "*" {
+			t.Errorf("'%s' failed. Expected the response to allow any origin, received '%s'", name, origin)
+		}
+
+		img, err := png.Decode(w.Body)
+		if err != nil {
+			t.Errorf("'%s' failed. Failed to decode response: %s", name, err.Error())
+			continue
+		}
+		if img.ColorModel() != tc.ExpectedModel {
+			t.Errorf("'%s' failed. Expected color model %v, received %v", name, tc.ExpectedModel, img.ColorModel())
+		}
+		if bounds := img.Bounds(); bounds.Dx() != tc.ExpectedWidth || bounds.Dy() != tc.ExpectedHeight {
+			t.Errorf("'%s' failed. Expected %dx%d image, received %v", name, tc.ExpectedWidth, tc.ExpectedHeight, bounds)
+		}
+	}
+}
@@ -1,16 +1,43 @@
 package http
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/julienschmidt/httprouter"
 )
 
+// shutdownTimeout is how long requests in flight are given to finish once the server is asked to stop
+const shutdownTimeout = 30 * time.Second
+
 // ListendAndServe starts the server on the specified port with the specified handler
+// It returns nil once the server is stopped by SIGINT or SIGTERM and requests in flight have finished, so the caller can clean up
 func ListenAndServe(addr string, handler http.Handler) error {
-	return http.ListenAndServe(addr, handler)
+	server := &http.Server{Addr: addr, Handler: handler}
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(stop)
+
+	served := make(chan error, 1)
+	go func() {
+		served <- server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-served:
+		return err
+	case <-stop:
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		return server.Shutdown(ctx)
+	}
 }
 
 // A handlerFunc is exported for each endoint, and it always returns the http code of the result
@@ -23,10 +50,12 @@ type HandlerFunc func(w http.ResponseWriter, r *http.Request, _ httprouter.Param
 // Handle converts a handlerFunc into a httprouter.Handle, so that it can be easily used with the httprouter.Router
 func Handle(h HandlerFunc) httprouter.Handle {
 	return func(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+		// The header is added before the handler runs, since handlers that write their own response send the headers with it
+		w.Header().Add("Access-Control-Allow-Origin", "*") //TODO: Find out if this is necessary for other domains than terragen.brandonokert.com
+
 		response, code := h(w, r, p)
 		output, finalCode := marshalOutput(response, code)
 
-		w.Header().Add("Access-Control-Allow-Origin", "*") //TODO: Find out if this is necessary for other domains than terragen.brandonokert.com
 		if len(output) > 0 {
 			w.Header().Add("Content-Type", "application/json")
 		}
@@ -0,0 +1,77 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/bcokert/terragen/hydrology"
+	"github.com/bcokert/terragen/log"
+	"github.com/bcokert/terragen/noise"
+	"github.com/julienschmidt/httprouter"
+)
+
+// HandleHydrology generates 2D noise with the given params, and responds with it along with how water drains over it. It is an idempotent call
+func HandleHydrology() httprouter.Handle {
+	return Handle(func(response http.ResponseWriter, request *http.Request, _ httprouter.Params) (interface{}, int) {
+		log.Info("Request Started: %s %s", request.Method, request.URL.String())
+
+		// Validate the params, and get the related data
+		params, err := validateNoiseParams(request.URL.Query())
+		if err != nil {
+			return fmt.Errorf("Invalid param: (%s)", err.Error()), http.StatusBadRequest
+		}
+
+		options, err := validateHydrologyParams(request.URL.Query(), params)
+		if err != nil {
+			return fmt.Errorf("Invalid param: (%s)", err.Error()), http.StatusBadRequest
+		}
+
+		noise := generateNoise(params)
+		drainage, err := hydrology.Analyze(noise, options)
+		if err != nil {
+			return fmt.Errorf("Failed to analyze hydrology: (%s)", err.Error()), http.StatusInternalServerError
+		}
+
+		return hydrologyResponse{Noise: noise, Drainage: drainage}, http.StatusOK
+	})
+}
+
+type hydrologyResponse struct {
+	Noise    *noise.Noise        `json:"noise"`
+	Drainage *hydrology.Drainage `json:"drainage"`
+}
+
+// flowMethods are the valid values of the flowMethod param
+var flowMethods = map[string]hydrology.FlowMethod{
+	"d8":        hydrology.D8,
+	"dInfinity": hydrology.DInfinity,
+}
+
+func validateHydrologyParams(params url.Values, noiseParams queryParams) (options hydrology.Options, err error) {
+	flowMethod := params.Get("flowMethod")
+	riverThreshold := params.Get("riverThreshold")
+
+	if len(noiseParams.from) != 2 {
+		return hydrology.Options{}, errors.New("Hydrology requires a 2 dimensional From and To")
+	}
+
+	options = hydrology.NewDefaultOptions()
+
+	if flowMethod != "" {
+		var ok bool
+		if options.Method, ok = flowMethods[flowMethod]; !ok {
+			return hydrology.Options{}, errors.New("FlowMethod must be one of d8 or dInfinity")
+		}
+	}
+
+	if riverThreshold != "" {
+		if options.RiverThreshold, err = strconv.ParseFloat(riverThreshold, 64); err != nil || options.RiverThreshold < 0 {
+			return hydrology.Options{}, errors.New("RiverThreshold must be a non negative number")
+		}
+	}
+
+	return options, nil
+}
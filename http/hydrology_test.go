@@ -0,0 +1,102 @@
+package http_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	tghttp "github.com/bcokert/terragen/http"
+)
+
+func TestHandleHydrology(t *testing.T) {
+	testCases := map[string]struct {
+		Query              string
+		ExpectedStatusCode int
+		ExpectedErrorBody  string
+		ExpectedSamples    int
+	}{
+		"defaults": {
+			Query:              "seed=42",
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedSamples:    10000,
+		},
+		"d infinity": {
+			Query:              "from=0,0&to=2,3&resolution=4&seed=42&flowMethod=dInfinity&riverThreshold=0.5",
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedSamples:    96,
+		},
+		"eroded": {
+			Query:              "from=0,0&to=2,2&resolution=8&seed=42&postProcess=hydraulic",
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedSamples:    256,
+		},
+		"1d": {
+			Query:              "from=0&to=2&seed=42",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (Hydrology requires a 2 dimensional From and To)"}`,
+		},
+		"invalid flow method": {
+			Query:              "seed=42&flowMethod=d4",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (FlowMethod must be one of d8 or dInfinity)"}`,
+		},
+		"negative river threshold": {
+			Query:              "seed=42&riverThreshold=-1",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (RiverThreshold must be a non negative number)"}`,
+		},
+	}
+
+	for name, tc := range testCases {
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest(http.MethodGet, fmt.Sprintf("/hydrology?%s", tc.Query), nil)
+		tghttp.HandleHydrology()(w, r, nil)
+
+		if w.Code != tc.ExpectedStatusCode {
+			t.Errorf("'%s' failed. Expected status code %d, received %d", name, tc.ExpectedStatusCode, w.Code)
+			t.Logf("Response: %s", w.Body.String())
+			continue
+		}
+
+		if tc.ExpectedErrorBody != "" {
+			if w.Body.String() != tc.ExpectedErrorBody {
+				t.Errorf("'%s' failed. Expected error response '%s', received '%s'", name, tc.ExpectedErrorBody, w.Body.String())
+			}
+			continue
+		}
+
+		var result struct {
+			Noise struct {
+				Values []float64 `json:"values"`
+			} `json:"noise"`
+			Drainage struct {
+				Accumulation []float64 `json:"accumulation"`
+				Watersheds   []int     `json:"watersheds"`
+				RiverMask    []float64 `json:"riverMask"`
+				Rivers       struct {
+					Type string `json:"type"`
+				} `json:"rivers"`
+			} `json:"drainage"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+			t.Errorf("'%s' failed. Expected a hydrology response, received '%s'", name, w.Body.String())
+			continue
+		}
+
+		for channel, length := range map[string]int{
+			"values":       len(result.Noise.Values),
+			"accumulation": len(result.Drainage.Accumulation),
+			"watersheds":   len(result.Drainage.Watersheds),
+			"riverMask":    len(result.Drainage.RiverMask),
+		} {
+			if length != tc.ExpectedSamples {
+				t.Errorf("'%s' failed. Expected %d %s, received %d", name, tc.ExpectedSamples, channel, length)
+			}
+		}
+		if result.Drainage.Rivers.Type != "FeatureCollection" {
+			t.Errorf("'%s' failed. Expected rivers to be a FeatureCollection, received '%s'", name, result.Drainage.Rivers.Type)
+		}
+	}
+}
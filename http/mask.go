@@ -0,0 +1,111 @@
+package http
+
+import (
+	"errors"
+	"math"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/bcokert/terragen/noise"
+)
+
+// A maskShaper shapes the noise function of a request with its masks
+type maskShaper func(fn noise.Function) noise.Function
+
+// validateMaskParams validates the comma separated list of masks to shape the noise with, and how to combine them with it
+// Masks are fitted to the requested range, and their falloff and corner radius are fractions of the distance from its edge to its center
+func validateMaskParams(params url.Values, from, to []int) (shaper maskShaper, err error) {
+	mask := params.Get("mask")
+	maskFalloff := params.Get("maskFalloff")
+	maskCornerRadius := params.Get("maskCornerRadius")
+	maskPolygon := params.Get("maskPolygon")
+	maskMode := params.Get("maskMode")
+	maskDepth := params.Get("maskDepth")
+	maskFloor := params.Get("maskFloor")
+
+	if mask == "" {
+		return nil, nil
+	}
+
+	// The masks fit within the range, and fall off towards its edges
+	lower, upper, center := make([]float64, len(from)), make([]float64, len(from)), make([]float64, len(from))
+	halfSize := math.Inf(1)
+	for i := range from {
+		lower[i], upper[i] = float64(from[i]), float64(to[i])
+		center[i] = (lower[i] + upper[i]) / 2
+		halfSize = math.Min(halfSize, (upper[i]-lower[i])/2)
+	}
+
+	falloff := 0.5
+	if maskFalloff != "" {
+		if falloff, err = strconv.ParseFloat(maskFalloff, 64); err != nil || falloff < 0 || falloff > 1 {
+			return nil, errors.New("MaskFalloff must be a number between 0 and 1")
+		}
+	}
+
+	cornerRadius := 0.25
+	if maskCornerRadius != "" {
+		if cornerRadius, err = strconv.ParseFloat(maskCornerRadius, 64); err != nil || cornerRadius < 0 || cornerRadius > 1 {
+			return nil, errors.New("MaskCornerRadius must be a number between 0 and 1")
+		}
+	}
+
+	masks := []noise.Function{}
+	for _, name := range strings.Split(mask, ",") {
+		switch name {
+		case "radial":
+			masks = append(masks, noise.RadialMask(center, halfSize, falloff*halfSize))
+		case "edge":
+			masks = append(masks, noise.EdgeMask(lower, upper, falloff*halfSize))
+		case "roundedRect":
+			if len(from) != 2 {
+				return nil, errors.New("RoundedRect masks require a 2 dimensional From and To")
+			}
+			masks = append(masks, noise.RoundedRectMask(lower, upper, cornerRadius*halfSize, falloff*halfSize))
+		case "polygon":
+			if len(from) != 2 {
+				return nil, errors.New("Polygon masks require a 2 dimensional From and To")
+			}
+			coordinates := ParseFloatArray(maskPolygon)
+			if len(coordinates) < 6 || len(coordinates)%2 != 0 {
+				return nil, errors.New("MaskPolygon must be a list of at least 3 x,y points")
+			}
+			points := make([][2]float64, len(coordinates)/2)
+			for i := range points {
+				points[i] = [2]float64{coordinates[2*i], coordinates[2*i+1]}
+			}
+			masks = append(masks, noise.PolygonMask(points, falloff*halfSize))
+		default:
+			return nil, errors.New("Mask must be a list of radial, roundedRect, edge or polygon")
+		}
+	}
+	combined := noise.ProductMask(masks...)
+
+	depth := 2.0
+	if maskDepth != "" {
+		if depth, err = strconv.ParseFloat(maskDepth, 64); err != nil || depth < 0 {
+			return nil, errors.New("MaskDepth must be a non negative number")
+		}
+	}
+
+	floor := -1.0
+	if maskFloor != "" {
+		if floor, err = strconv.ParseFloat(maskFloor, 64); err != nil {
+			return nil, errors.New("MaskFloor must be a number")
+		}
+	}
+
+	switch maskMode {
+	case "", "multiply":
+		return func(fn noise.Function) noise.Function {
+			return noise.MultiplyMask(fn, combined)
+		}, nil
+	case "subtract":
+		return func(fn noise.Function) noise.Function {
+			return noise.SubtractMask(fn, combined, depth, floor)
+		}, nil
+	default:
+		return nil, errors.New("MaskMode must be one of multiply or subtract")
+	}
+}
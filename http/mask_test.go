@@ -0,0 +1,107 @@
+package http_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	tghttp "github.com/bcokert/terragen/http"
+	"github.com/bcokert/terragen/noise"
+)
+
+func TestHandleNoise_Mask(t *testing.T) {
+	testCases := map[string]struct {
+		Query              string
+		ExpectedStatusCode int
+		ExpectedErrorBody  string
+		ExpectedCorner     float64
+	}{
+		"radial": {
+			Query:              "from=0,0&to=4,4&resolution=4&seed=42&mask=radial&maskFalloff=0.3",
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedCorner:     0,
+		},
+		"combined subtract": {
+			Query:              "from=0,0&to=4,4&resolution=4&seed=42&mask=roundedRect,edge&maskCornerRadius=0.5&maskMode=subtract&maskDepth=3&maskFloor=-0.75",
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedCorner:     -0.75,
+		},
+		"polygon": {
+			Query:              "from=0,0&to=4,4&resolution=4&seed=42&mask=polygon&maskPolygon=1,1,3,1,2,3",
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedCorner:     0,
+		},
+		"unknown mask": {
+			Query:              "seed=42&mask=star",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (Mask must be a list of radial, roundedRect, edge or polygon)"}`,
+		},
+		"invalid falloff": {
+			Query:              "seed=42&mask=radial&maskFalloff=2",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (MaskFalloff must be a number between 0 and 1)"}`,
+		},
+		"invalid corner radius": {
+			Query:              "seed=42&mask=roundedRect&maskCornerRadius=-1",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (MaskCornerRadius must be a number between 0 and 1)"}`,
+		},
+		"1d rounded rect": {
+			Query:              "from=0&to=4&seed=42&mask=roundedRect",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (RoundedRect masks require a 2 dimensional From and To)"}`,
+		},
+		"short polygon": {
+			Query:              "seed=42&mask=polygon&maskPolygon=0,0,1,1",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (MaskPolygon must be a list of at least 3 x,y points)"}`,
+		},
+		"invalid mode": {
+			Query:              "seed=42&mask=radial&maskMode=divide",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (MaskMode must be one of multiply or subtract)"}`,
+		},
+		"invalid depth": {
+			Query:              "seed=42&mask=radial&maskDepth=-2",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (MaskDepth must be a non negative number)"}`,
+		},
+		"invalid floor": {
+			Query:              "seed=42&mask=radial&maskFloor=low",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (MaskFloor must be a number)"}`,
+		},
+	}
+
+	for name, tc := range testCases {
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest(http.MethodGet, fmt.Sprintf("/noise?%s", tc.Query), nil)
+		tghttp.HandleNoise()(w, r, nil)
+
+		if w.Code != tc.ExpectedStatusCode {
+			t.Errorf("'%s' failed. Expected status code %d, received %d", name, tc.ExpectedStatusCode, w.Code)
+			t.Logf("Response: %s", w.Body.String())
+			continue
+		}
+
+		if tc.ExpectedErrorBody != "" {
+			if w.Body.String() != tc.ExpectedErrorBody {
+				t.Errorf("'%s' failed. Expected error response '%s', received '%s'", name, tc.ExpectedErrorBody, w.Body.String())
+			}
+			continue
+		}
+
+		var result noise.Noise
+		if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+			t.Errorf("'%s' failed. Expected a noise response, received '%s'", name, w.Body.String())
+			continue
+		}
+
+		// The corner of the range is on the edge of every mask, so it is fully masked
+		if result.Values[0] != tc.ExpectedCorner {
+			t.Errorf("'%s' failed. Expected the corner to be masked to %v, received %v", name, tc.ExpectedCorner, result.Values[0])
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package http
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/bcokert/terragen/log"
+	"github.com/bcokert/terragen/mesh"
+	"github.com/julienschmidt/httprouter"
+)
+
+// meshFormat describes how to write a mesh for a given format param
+type meshFormat struct {
+	contentType string
+	extension   string
+	write       func(w io.Writer, mesh *mesh.Mesh) error
+}
+
+var meshFormats = map[string]meshFormat{
+	"obj": {contentType: "model/obj", extension: "obj", write: mesh.WriteOBJ},
+	"stl": {contentType: "model/stl", extension: "stl", write: mesh.WriteSTL},
+	"glb": {contentType: "model/gltf-binary", extension: "glb", write: mesh.WriteGLB},
+}
+
+// HandleMesh generates 2D noise with the given params, and responds with it triangulated into a downloadable mesh. It is an idempotent call
+func HandleMesh() httprouter.Handle {
+	return Handle(func(response http.ResponseWriter, request *http.Request, _ httprouter.Params) (interface{}, int) {
+		log.Info("Request Started: %s %s", request.Method, request.URL.String())
+
+		// Validate the params, and get the related data
+		params, err := validateNoiseParams(request.URL.Query())
+		if err != nil {
+			return fmt.Errorf("Invalid param: (%s)", err.Error()), http.StatusBadRequest
+		}
+
+		meshParams, err := validateMeshParams(request.URL.Query(), params)
+		if err != nil {
+			return fmt.Errorf("Invalid param: (%s)", err.Error()), http.StatusBadRequest
+		}
+
+		terrain, err := mesh.FromNoise(generateNoise(params), meshParams.options)
+		if err != nil {
+			return fmt.Errorf("Failed to build mesh: (%s)", err.Error()), http.StatusBadRequest
+		}
+
+		buffer := &bytes.Buffer{}
+		if err := meshParams.format.write(buffer, terrain); err != nil {
+			return fmt.Errorf("Failed to encode mesh: (%s)", err.Error()), http.StatusInternalServerError
+		}
+
+		response.Header().Add("Content-Type", meshParams.format.contentType)
+		response.Header().Add("Content-Disposition", fmt.Sprintf(`attachment; filename="terrain.%s"`, meshParams.format.extension))
+		response.Write(buffer.Bytes())
+		return nil, http.StatusOK
+	})
+}
+
+type meshParams struct {
+	format  meshFormat
+	options mesh.Options
+}
+
+func validateMeshParams(params url.Values, noiseParams queryParams) (response meshParams, err error) {
+	format := params.Get("format")
+
+	if len(noiseParams.from) != 2 {
+		return meshParams{}, errors.New("Meshes require a 2 dimensional From and To")
+	}
+
+	// Validate format
+	response.format = meshFormats["glb"]
+	if format != "" {
+		var ok bool
+		if response.format, ok = meshFormats[format]; !ok {
+			return meshParams{}, errors.New("Format must be one of obj, stl or glb")
+		}
+	}
+
+	if response.options, err = validateMeshOptions(params); err != nil {
+		return meshParams{}, err
+	}
+
+	return response, nil
+}
+
+// validateMeshOptions validates the options for turning a heightfield into a mesh, which every endpoint that returns meshes shares
+func validateMeshOptions(params url.Values) (options mesh.Options, err error) {
+	verticalScale := params.Get("verticalScale")
+	skirt := params.Get("skirt")
+	closed := params.Get("closed")
+	maxError := params.Get("maxError")
+
+	options = mesh.NewDefaultOptions()
+	if verticalScale != "" {
+		if options.VerticalScale, err = strconv.ParseFloat(verticalScale, 64); err != nil {
+			return mesh.Options{}, errors.New("VerticalScale must be a number")
+		}
+	}
+	if skirt != "" {
+		if options.Skirt, err = strconv.ParseFloat(skirt, 64); err != nil || options.Skirt < 0 {
+			return mesh.Options{}, errors.New("Skirt must be a non negative number")
+		}
+	}
+	if closed != "" {
+		if options.Closed, err = strconv.ParseBool(closed); err != nil {
+			return mesh.Options{}, errors.New("Closed must be true or false")
+		}
+	}
+	if maxError != "" {
+		if options.MaxError, err = strconv.ParseFloat(maxError, 64); err != nil || options.MaxError < 0 {
+			return mesh.Options{}, errors.New("MaxError must be a non negative number")
+		}
+	}
+
+	return options, nil
+}
@@ -0,0 +1,115 @@
+package http_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	tghttp "github.com/bcokert/terragen/http"
+)
+
+func TestHandleMesh(t *testing.T) {
+	testCases := map[string]struct {
+		Query               string
+		ExpectedStatusCode  int
+		ExpectedErrorBody   string
+		ExpectedContentType string
+		ExpectedFilename    string
+		ExpectedPrefix      string
+	}{
+		"defaults": {
+			Query:               "from=0,0&to=2,2&resolution=4&seed=42",
+			ExpectedStatusCode:  http.StatusOK,
+			ExpectedContentType: "model/gltf-binary",
+			ExpectedFilename:    "terrain.glb",
+			ExpectedPrefix:      "glTF",
+		},
+		"obj": {
+			Query:               "from=0,0&to=2,2&resolution=4&seed=42&format=obj&verticalScale=10",
+			ExpectedStatusCode:  http.StatusOK,
+			ExpectedContentType: "model/obj",
+			ExpectedFilename:    "terrain.obj",
+			ExpectedPrefix:      "# terragen heightfield",
+		},
+		"closed stl": {
+			Query:               "from=0,0&to=2,2&resolution=4&seed=42&format=stl&skirt=1&closed=true",
+			ExpectedStatusCode:  http.StatusOK,
+			ExpectedContentType: "model/stl",
+			ExpectedFilename:    "terrain.stl",
+			ExpectedPrefix:      "terragen heightfield",
+		},
+		"simplified": {
+			Query:               "from=0,0&to=2,2&resolution=8&seed=42&format=obj&maxError=0.05",
+			ExpectedStatusCode:  http.StatusOK,
+			ExpectedContentType: "model/obj",
+			ExpectedFilename:    "terrain.obj",
+			ExpectedPrefix:      "# terragen heightfield",
+		},
+		"1d": {
+			Query:              "from=0&to=2&seed=42",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (Meshes require a 2 dimensional From and To)"}`,
+		},
+		"single sample": {
+			Query:              "from=0,0&to=1,1&resolution=1&seed=42",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Failed to build mesh: (Meshes require at least 2 samples in each dimension)"}`,
+		},
+		"invalid format": {
+			Query:              "seed=42&format=fbx",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (Format must be one of obj, stl or glb)"}`,
+		},
+		"invalid vertical scale": {
+			Query:              "seed=42&verticalScale=tall",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (VerticalScale must be a number)"}`,
+		},
+		"negative skirt": {
+			Query:              "seed=42&skirt=-1",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (Skirt must be a non negative number)"}`,
+		},
+		"negative max error": {
+			Query:              "seed=42&maxError=-0.1",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (MaxError must be a non negative number)"}`,
+		},
+		"invalid closed": {
+			Query:              "seed=42&closed=banana",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (Closed must be true or false)"}`,
+		},
+	}
+
+	for name, tc := range testCases {
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest(http.MethodGet, fmt.Sprintf("/mesh?%s", tc.Query), nil)
+		tghttp.HandleMesh()(w, r, nil)
+
+		if w.Code != tc.ExpectedStatusCode {
+			t.Errorf("'%s' failed. Expected status code %d, received %d", name, tc.ExpectedStatusCode, w.Code)
+			t.Logf("Response: %s", w.Body.String())
+			continue
+		}
+
+		if tc.ExpectedErrorBody != "" {
+			if w.Body.String() != tc.ExpectedErrorBody {
+				t.Errorf("'%s' failed. Expected error response '%s', received '%s'", name, tc.ExpectedErrorBody, w.Body.String())
+			}
+			continue
+		}
+
+		if contentType := w.Header().Get("Content-Type"); contentType != tc.ExpectedContentType {
+			t.Errorf("'%s' failed. Expected content type %s, received %s", name, tc.ExpectedContentType, contentType)
+		}
+		if disposition := w.Header().Get("Content-Disposition"); !strings.Contains(disposition, tc.ExpectedFilename) {
+			t.Errorf("'%s' failed. Expected a download named %s, received %s", name, tc.ExpectedFilename, disposition)
+		}
+		if !strings.HasPrefix(w.Body.String(), tc.ExpectedPrefix) {
+			t.Errorf("'%s' failed. Expected the body to start with %q", name, tc.ExpectedPrefix)
+		}
+	}
+}
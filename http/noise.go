@@ -1,6 +1,7 @@
 package http
 
 import (
+	"math"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -10,7 +11,7 @@ import (
 	"time"
 
 	"github.com/bcokert/terragen/log"
-	"github.com/bcokert/terragen/math"
+	tgmath "github.com/bcokert/terragen/math"
 	"github.com/bcokert/terragen/noise"
 	"github.com/julienschmidt/httprouter"
 )
@@ -26,23 +27,92 @@ func HandleNoise() httprouter.Handle {
 			return fmt.Errorf("Invalid param: (%s)", err.Error()), http.StatusBadRequest
 		}
 
-		// Generate noise from the given params and preset
-		log.Info("Generating noise with the following params: %+v", params)
-		noise := noise.NewNoise(params.presetName)
-		noiseFn := params.preset(math.NewDefaultSource(params.seed), []float64{1, 2, 4, 8, 16, 32, 64})
-		noise.Generate(params.from, params.to, params.resolution, noiseFn)
-
-		return noise, http.StatusOK
+		return generateNoise(params), http.StatusOK
 	})
 }
 
+// spectralSynthesisPreset is the noise function that synthesizes noise with a 1/f^beta power spectrum
+const spectralSynthesisPreset = "spectral"
+
+// maxSubdivisionSamples limits the lattice that subdividing grid presets allocate, since it's square even when the range is long and thin
+const maxSubdivisionSamples = 1 << 25
+
+// presetFrequencies are the octave frequencies every preset is built with
+var presetFrequencies = []float64{1, 2, 4, 8, 16, 32, 64}
+
+// generateNoise generates noise from the given params and preset
+func generateNoise(params queryParams) *noise.Noise {
+	return generateNoiseFrom(params, presetFunction(params))
+}
+
+// presetFunction builds the params' preset into a noise function
+// Grid presets are generated over the requested lattice first, and then looked up by the sample nearest to each point
+func presetFunction(params queryParams) noise.Function {
+	if params.gridPreset == nil {
+		return params.preset(tgmath.NewDefaultSource(params.seed), presetFrequencies)
+	}
+
+	grid := noise.NewNoise(params.presetName)
+	if err := grid.GenerateGrid(params.from, params.to, params.resolution, params.gridPreset, tgmath.NewDefaultSource(params.seed), params.roughness); err != nil {
+		log.Error("Failed to generate grid noise: %s", err.Error())
+		return func(t []float64) float64 { return 0 }
+	}
+	return grid.At
+}
+
+// generateNoiseFrom generates noise from the given params, sampling noiseFn in place of the preset
+// The mask, post processes and normalization are applied just as they are to the preset
+func generateNoiseFrom(params queryParams, noiseFn noise.Function) *noise.Noise {
+	log.Info("Generating noise with the following params: %+v", params)
+	noise := noise.NewNoise(params.presetName)
+	noise.Generate(params.from, params.to, params.resolution, maskFunction(params, noiseFn))
+
+	for _, process := range params.postProcesses {
+		if err := process(params.seed)(noise); err != nil {
+			log.Error("Failed to post process noise: %s", err.Error())
+		}
+	}
+
+	if params.normalization != nil {
+		if err := params.normalization(noise); err != nil {
+			log.Error("Failed to normalize noise: %s", err.Error())
+		}
+	}
+
+	return noise
+}
+
+// noiseFunction builds the masked noise function that generateNoise samples, for endpoints that evaluate it at arbitrary points
+func noiseFunction(params queryParams) noise.Function {
+	return maskFunction(params, presetFunction(params))
+}
+
+// maskFunction shapes the noise function with the params' mask, if there is one
+func maskFunction(params queryParams, noiseFn noise.Function) noise.Function {
+	if params.mask != nil {
+		return params.mask(noiseFn)
+	}
+	return noiseFn
+}
+
 type queryParams struct {
 	from       []int
 	to         []int
 	resolution int
 	presetName string
 	preset     noise.Preset
+	gridPreset noise.GridPreset
+	roughness  float64
 	seed       int64
+
+	mask          maskShaper
+	postProcesses []postProcess
+	normalization normalization
+}
+
+// shape returns the number of samples the params generate in each dimension
+func (params queryParams) shape() []int {
+	return (&noise.Noise{From: params.from, To: params.to, Resolution: params.resolution}).Shape()
 }
 
 func validateNoiseParams(params url.Values) (response queryParams, err error) {
@@ -51,6 +121,7 @@ func validateNoiseParams(params url.Values) (response queryParams, err error) {
 	resolution := params.Get("resolution")
 	noiseFunction := params.Get("noiseFunction")
 	seed := params.Get("seed")
+	roughness := params.Get("roughness")
 
 	// Validate from and to values
 	response.from = []int{0, 0}
@@ -90,25 +161,87 @@ func validateNoiseParams(params url.Values) (response queryParams, err error) {
 		}
 	}
 
+	// Spectral synthesis is a grid preset for any spectral exponent, so it's built from the beta param
+	gridPreset, isGridPreset := noise.GridPresets[noiseFunction]
+	if noiseFunction == spectralSynthesisPreset {
+		beta := 2.0
+		if params.Get("beta") != "" {
+			if beta, err = strconv.ParseFloat(params.Get("beta"), 64); err != nil || math.IsNaN(beta) || math.IsInf(beta, 0) {
+				return queryParams{}, errors.New("Beta must be a number")
+			}
+		}
+		gridPreset, isGridPreset = noise.SpectralSynthesis(beta), true
+	}
+
+	// Grid presets generate the whole lattice at once, and only in 1 or 2 dimensions
+	if isGridPreset {
+		if len(response.from) > 2 {
+			return queryParams{}, errors.New("Grid presets require a 1 or 2 dimensional From and To")
+		}
+		response.presetName, response.gridPreset = noiseFunction, gridPreset
+		if _, isSubdivision := noise.GridPresets[noiseFunction]; isSubdivision {
+			if noise.SubdivisionSamples(response.shape()) > maxSubdivisionSamples {
+				return queryParams{}, fmt.Errorf("Grid presets are limited to a lattice of %d samples, which is square over the longest dimension, so reduce the range or resolution", maxSubdivisionSamples)
+			}
+		}
+		if response.seed, err = validateSeed(seed); err != nil {
+			return queryParams{}, err
+		}
+	} else if response.presetName, response.preset, response.seed, err = validatePresetParams(noiseFunction, seed); err != nil {
+		return queryParams{}, err
+	}
+
+	response.roughness = 0.5
+	if roughness != "" {
+		if response.roughness, err = strconv.ParseFloat(roughness, 64); err != nil || response.roughness < 0 || response.roughness > 1 {
+			return queryParams{}, errors.New("Roughness must be a number between 0 and 1")
+		}
+	}
+
+	if response.mask, err = validateMaskParams(params, response.from, response.to); err != nil {
+		return queryParams{}, err
+	}
+
+	if response.postProcesses, err = validatePostProcessParams(params, response.shape()); err != nil {
+		return queryParams{}, err
+	}
+
+	if response.normalization, err = validateNormalizeParams(params, response); err != nil {
+		return queryParams{}, err
+	}
+
+	return response, nil
+}
+
+// validatePresetParams validates the noise function and seed, which every endpoint that generates noise shares
+func validatePresetParams(noiseFunction, seed string) (presetName string, preset noise.Preset, seedValue int64, err error) {
 	// Validate noise params value
-	response.presetName = "red"
+	presetName = "red"
 	if noiseFunction != "" {
-		response.presetName = noiseFunction
+		presetName = noiseFunction
+	}
+	preset = searchPresets(presetName)
+	if preset == nil {
+		return "", nil, 0, errors.New("NoiseFunction must be a valid preset")
 	}
-	response.preset = searchPresets(response.presetName)
-	if response.preset == nil {
-		return queryParams{}, errors.New("NoiseFunction must be a valid preset")
+
+	if seedValue, err = validateSeed(seed); err != nil {
+		return "", nil, 0, err
 	}
 
-	// Validate seed, or generate if missing
-	response.seed = time.Now().Unix()
+	return presetName, preset, seedValue, nil
+}
+
+// validateSeed validates the seed, or generates one if it's missing
+func validateSeed(seed string) (seedValue int64, err error) {
+	seedValue = time.Now().Unix()
 	if seed != "" {
-		if response.seed, err = strconv.ParseInt(seed, 10, 0); err != nil {
-			return queryParams{}, errors.New("Seed must be a positive integer")
+		if seedValue, err = strconv.ParseInt(seed, 10, 0); err != nil {
+			return 0, errors.New("Seed must be a positive integer")
 		}
 	}
 
-	return response, nil
+	return seedValue, nil
 }
 
 // search through each preset collection for the specified preset
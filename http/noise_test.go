@@ -296,3 +296,189 @@ func TestHandleNoise_Permutations(t *testing.T) {
 		}
 	}
 }
+
+func TestHandleNoise_GridPresets(t *testing.T) {
+	testCases := map[string]struct {
+		Query              string
+		PresetName         string
+		Preset             noise.GridPreset
+		From               []int
+		To                 []int
+		Resolution         int
+		Roughness          float64
+		ExpectedStatusCode int
+		ExpectedErrorBody  string
+	}{
+		"diamond square": {
+			Query:      "from=0,0&to=2,3&resolution=8&noiseFunction=diamondSquare&roughness=0.7&seed=42",
+			PresetName: "diamondSquare", Preset: noise.DiamondSquare, From: []int{0, 0}, To: []int{2, 3}, Resolution: 8, Roughness: 0.7,
+			ExpectedStatusCode: http.StatusOK,
+		},
+		"midpoint displacement": {
+			Query:      "from=-2&to=3&resolution=10&noiseFunction=midpointDisplacement&seed=42",
+			PresetName: "midpointDisplacement", Preset: noise.MidpointDisplacement, From: []int{-2}, To: []int{3}, Resolution: 10, Roughness: 0.5,
+			ExpectedStatusCode: http.StatusOK,
+		},
+		"spectral": {
+			Query:      "from=0,0&to=4,2&resolution=6&noiseFunction=spectral&beta=1.5&seed=42",
+			PresetName: "spectral", Preset: noise.SpectralSynthesis(1.5), From: []int{0, 0}, To: []int{4, 2}, Resolution: 6, Roughness: 0.5,
+			ExpectedStatusCode: http.StatusOK,
+		},
+		"spectral default beta": {
+			Query:      "from=1&to=3&resolution=9&noiseFunction=spectral&seed=42",
+			PresetName: "spectral", Preset: noise.SpectralSynthesis(2), From: []int{1}, To: []int{3}, Resolution: 9, Roughness: 0.5,
+			ExpectedStatusCode: http.StatusOK,
+		},
+		"invalid beta": {
+			Query:              "noiseFunction=spectral&beta=steep&seed=42",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (Beta must be a number)"}`,
+		},
+		"3d": {
+			Query:              "from=0,0,0&to=2,2,2&noiseFunction=diamondSquare&seed=42",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (Grid presets require a 1 or 2 dimensional From and To)"}`,
+		},
+		"invalid roughness": {
+			Query:              "noiseFunction=diamondSquare&roughness=1.5&seed=42",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (Roughness must be a number between 0 and 1)"}`,
+		},
+		"long thin grid": {
+			Query:              "from=0,0&to=500,1&resolution=20&noiseFunction=diamondSquare&seed=42",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (Grid presets are limited to a lattice of 33554432 samples, which is square over the longest dimension, so reduce the range or resolution)"}`,
+		},
+	}
+
+	for name, tc := range testCases {
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest(http.MethodGet, fmt.Sprintf("/noise?%s", tc.Query), nil)
+		tghttp.HandleNoise()(w, r, nil)
+
+		if w.Code != tc.ExpectedStatusCode {
+			t.Errorf("'%s' failed. Expected status code %d, received %d", name, tc.ExpectedStatusCode, w.Code)
+			t.Logf("Response: %s", w.Body.String())
+			continue
+		}
+
+		if tc.ExpectedErrorBody != "" {
+			if w.Body.String() != tc.ExpectedErrorBody {
+				t.Errorf("'%s' failed. Expected error response '%s', received '%s'", name, tc.ExpectedErrorBody, w.Body.String())
+			}
+			continue
+		}
+
+		expectedResponse := noise.NewNoise(tc.PresetName)
+		if err := expectedResponse.GenerateGrid(tc.From, tc.To, tc.Resolution, tc.Preset, math.NewDefaultSource(42), tc.Roughness); err != nil {
+			t.Errorf("'%s' failed. Failed to generate the expected grid: %s", name, err.Error())
+			continue
+		}
+
+		responseObject := noise.Noise{}
+		if err := json.NewDecoder(w.Body).Decode(&responseObject); err != nil {
+			t.Errorf("'%s' failed. Failed to decode response: %s", name, w.Body.String())
+			continue
+		}
+
+		if !responseObject.IsEqual(expectedResponse) {
+			t.Errorf("'%s' failed. Expected response '%+v', received '%+v'", name, expectedResponse, responseObject)
+		}
+	}
+}
+
+func TestHandleNoise_Normalize(t *testing.T) {
+	testCases := map[string]struct {
+		Query              string
+		ExpectedStatusCode int
+		ExpectedErrorBody  string
+		ExpectedRange      *noise.Range
+	}{
+		"none": {
+			Query:              "seed=42",
+			ExpectedStatusCode: http.StatusOK,
+		},
+		"bounds": {
+			Query:              "seed=42&noiseFunction=violet&normalize=bounds",
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedRange:      &noise.Range{Min: -1, Max: 1},
+		},
+		"grid bounds": {
+			Query:              "seed=42&noiseFunction=diamondSquare&roughness=0.9&normalize=bounds",
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedRange:      &noise.Range{Min: -1, Max: 1},
+		},
+		"minmax": {
+			Query:              "seed=42&from=0&to=3&normalize=minmax",
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedRange:      &noise.Range{Min: -1, Max: 1},
+		},
+		"equalize": {
+			Query:              "seed=42&normalize=equalize",
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedRange:      &noise.Range{Min: -1, Max: 1},
+		},
+		"curve": {
+			Query:              "seed=42&normalize=curve&curve=-1:0,0:0.1,1:2",
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedRange:      &noise.Range{Min: 0, Max: 2},
+		},
+		"invalid normalize": {
+			Query:              "seed=42&normalize=squash",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (Normalize must be one of bounds, minmax, zscore, equalize or curve)"}`,
+		},
+		"missing curve": {
+			Query:              "seed=42&normalize=curve",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (Curve must be a list of at least 2 x:y points with increasing xs)"}`,
+		},
+		"decreasing curve": {
+			Query:              "seed=42&normalize=curve&curve=0:0,-1:1",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (Curve must be a list of at least 2 x:y points with increasing xs)"}`,
+		},
+	}
+
+	for name, tc := range testCases {
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest(http.MethodGet, fmt.Sprintf("/noise?%s", tc.Query), nil)
+		tghttp.HandleNoise()(w, r, nil)
+
+		if w.Code != tc.ExpectedStatusCode {
+			t.Errorf("'%s' failed. Expected status code %d, received %d", name, tc.ExpectedStatusCode, w.Code)
+			t.Logf("Response: %s", w.Body.String())
+			continue
+		}
+
+		if tc.ExpectedErrorBody != "" {
+			if w.Body.String() != tc.ExpectedErrorBody {
+				t.Errorf("'%s' failed. Expected error response '%s', received '%s'", name, tc.ExpectedErrorBody, w.Body.String())
+			}
+			continue
+		}
+
+		responseObject := noise.Noise{}
+		if err := json.NewDecoder(w.Body).Decode(&responseObject); err != nil {
+			t.Errorf("'%s' failed. Failed to decode response: %s", name, w.Body.String())
+			continue
+		}
+
+		if tc.ExpectedRange == nil {
+			if responseObject.Range != nil {
+				t.Errorf("'%s' failed. Expected no range, received %+v", name, responseObject.Range)
+			}
+			continue
+		}
+		if responseObject.Range == nil || *responseObject.Range != *tc.ExpectedRange {
+			t.Errorf("'%s' failed. Expected range %+v, received %+v", name, tc.ExpectedRange, responseObject.Range)
+			continue
+		}
+		for _, value := range responseObject.Values {
+			if value < tc.ExpectedRange.Min || value > tc.ExpectedRange.Max {
+				t.Errorf("'%s' failed. Expected every value to be within %+v, received %v", name, tc.ExpectedRange, value)
+				break
+			}
+		}
+	}
+}
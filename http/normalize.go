@@ -0,0 +1,103 @@
+package http
+
+import (
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/bcokert/terragen/noise"
+)
+
+// A normalization remaps the values of generated noise, and records the range it remapped them to
+type normalization func(noise *noise.Noise) error
+
+// validateNormalizeParams validates how generated noise is normalized, if at all
+// Normalization is applied last, after the mask and post processes
+func validateNormalizeParams(params url.Values, noiseParams queryParams) (normalization, error) {
+	normalize := params.Get("normalize")
+	curve := params.Get("curve")
+
+	switch normalize {
+	case "":
+		return nil, nil
+	case "bounds":
+		bound, err := presetBound(noiseParams)
+		if err != nil {
+			return nil, errors.New("Normalize can only be bounds for spectral, lattice or grid presets")
+		}
+		return func(noise *noise.Noise) error {
+			return noise.NormalizeBounds(bound)
+		}, nil
+	case "minmax":
+		return func(noise *noise.Noise) error {
+			noise.NormalizeMinMax()
+			return nil
+		}, nil
+	case "zscore":
+		return func(noise *noise.Noise) error {
+			noise.NormalizeZScore()
+			return nil
+		}, nil
+	case "equalize":
+		return func(noise *noise.Noise) error {
+			noise.Equalize()
+			return nil
+		}, nil
+	case "curve":
+		points, err := parseCurve(curve)
+		if err != nil {
+			return nil, err
+		}
+		return func(noise *noise.Noise) error {
+			noise.ApplyCurve(points)
+			return nil
+		}, nil
+	default:
+		return nil, errors.New("Normalize must be one of bounds, minmax, zscore, equalize or curve")
+	}
+}
+
+// presetBound returns the bound on the magnitude of the params' preset
+func presetBound(params queryParams) (float64, error) {
+	switch {
+	case params.presetName == spectralSynthesisPreset:
+		return 1, nil
+	case params.gridPreset != nil:
+		shape := make([]int, len(params.from))
+		for i := range shape {
+			shape[i] = (params.to[i] - params.from[i]) * params.resolution
+		}
+		return noise.SubdivisionBound(shape, params.roughness), nil
+	default:
+		return noise.PresetBound(params.presetName, presetFrequencies)
+	}
+}
+
+// parseCurve parses a curve given as a comma separated list of x:y points
+func parseCurve(curve string) (noise.Curve, error) {
+	invalid := errors.New("Curve must be a list of at least 2 x:y points with increasing xs")
+	if curve == "" {
+		return nil, invalid
+	}
+
+	var points [][2]float64
+	for _, point := range strings.Split(curve, ",") {
+		coordinates := strings.Split(point, ":")
+		if len(coordinates) != 2 {
+			return nil, invalid
+		}
+		x, xErr := strconv.ParseFloat(coordinates[0], 64)
+		y, yErr := strconv.ParseFloat(coordinates[1], 64)
+		if xErr != nil || yErr != nil {
+			return nil, invalid
+		}
+		points = append(points, [2]float64{x, y})
+	}
+
+	parsed, err := noise.NewCurve(points)
+	if err != nil {
+		return nil, invalid
+	}
+	return parsed, nil
+}
@@ -0,0 +1,100 @@
+package http
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/bcokert/terragen/image"
+	"github.com/bcokert/terragen/log"
+	"github.com/bcokert/terragen/noise"
+	"github.com/julienschmidt/httprouter"
+)
+
+// HandlePreview generates 2D noise with the given params, and responds with a color ramped, optionally hillshaded PNG. It is an idempotent call
+func HandlePreview() httprouter.Handle {
+	return Handle(func(response http.ResponseWriter, request *http.Request, _ httprouter.Params) (interface{}, int) {
+		log.Info("Request Started: %s %s", request.Method, request.URL.String())
+
+		// Validate the params, and get the related data
+		params, err := validateNoiseParams(request.URL.Query())
+		if err != nil {
+			return fmt.Errorf("Invalid param: (%s)", err.Error()), http.StatusBadRequest
+		}
+
+		previewParams, err := validatePreviewParams(request.URL.Query(), params)
+		if err != nil {
+			return fmt.Errorf("Invalid param: (%s)", err.Error()), http.StatusBadRequest
+		}
+
+		noise := generateNoise(params)
+
+		buffer := &bytes.Buffer{}
+		if err := image.EncodePreview(buffer, noise, previewParams.normalizer(noise), previewParams.ramp, previewParams.hillshade); err != nil {
+			return fmt.Errorf("Failed to encode preview: (%s)", err.Error()), http.StatusInternalServerError
+		}
+
+		response.Header().Add("Content-Type", "image/png")
+		response.Write(buffer.Bytes())
+		return nil, http.StatusOK
+	})
+}
+
+type previewParams struct {
+	ramp       image.ColorRamp
+	hillshade  *image.Hillshade
+	normalizer func(noise *noise.Noise) image.Normalizer
+}
+
+func validatePreviewParams(params url.Values, noiseParams queryParams) (response previewParams, err error) {
+	ramp := params.Get("ramp")
+	hillshade := params.Get("hillshade")
+	azimuth := params.Get("azimuth")
+	altitude := params.Get("altitude")
+	zFactor := params.Get("zFactor")
+
+	if len(noiseParams.from) != 2 {
+		return previewParams{}, errors.New("Previews require a 2 dimensional From and To")
+	}
+
+	// Validate the ramp, which is either a predefined name or a list of stops
+	response.ramp = image.ColorRamps["terrain"]
+	if ramp != "" {
+		var ok bool
+		if response.ramp, ok = image.ColorRamps[ramp]; !ok {
+			if response.ramp, err = image.ParseColorRamp(ramp); err != nil {
+				return previewParams{}, fmt.Errorf("Ramp must be a valid ramp name or list of color stops: %s", err.Error())
+			}
+		}
+	}
+
+	// Validate the hillshade and its sun
+	if hillshade != "false" {
+		shade := image.NewDefaultHillshade()
+		if azimuth != "" {
+			if shade.Azimuth, err = strconv.ParseFloat(azimuth, 64); err != nil {
+				return previewParams{}, errors.New("Azimuth must be a number of degrees")
+			}
+		}
+		if altitude != "" {
+			if shade.Altitude, err = strconv.ParseFloat(altitude, 64); err != nil || shade.Altitude < 0 || shade.Altitude > 90 {
+				return previewParams{}, errors.New("Altitude must be a number of degrees between 0 and 90")
+			}
+		}
+		if zFactor != "" {
+			if shade.ZFactor, err = strconv.ParseFloat(zFactor, 64); err != nil {
+				return previewParams{}, errors.New("ZFactor must be a number")
+			}
+		}
+		response.hillshade = &shade
+	}
+
+	if response.normalizer, err = validateNormalizationParams(params); err != nil {
+		return previewParams{}, err
+	}
+
+	return response, nil
+}
@@ -0,0 +1,102 @@
+package http_test
+
+import (
+	"fmt"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	tghttp "github.com/bcokert/terragen/http"
+)
+
+func TestHandlePreview(t *testing.T) {
+	testCases := map[string]struct {
+		Query              string
+		ExpectedStatusCode int
+		ExpectedErrorBody  string
+		ExpectedWidth      int
+		ExpectedHeight     int
+	}{
+		"defaults": {
+			Query:              "seed=42",
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedWidth:      100,
+			ExpectedHeight:     100,
+		},
+		"named ramp without hillshade": {
+			Query:              "from=0,0&to=3,2&resolution=4&seed=42&ramp=hypsometric&hillshade=false",
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedWidth:      12,
+			ExpectedHeight:     8,
+		},
+		"custom ramp and sun": {
+			Query:              "from=0,0&to=2,2&resolution=4&seed=42&ramp=0:000000,1:ffffff&azimuth=90&altitude=30&zFactor=2",
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedWidth:      8,
+			ExpectedHeight:     8,
+		},
+		"1d": {
+			Query:              "from=0&to=2&seed=42",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (Previews require a 2 dimensional From and To)"}`,
+		},
+		"invalid ramp": {
+			Query:              "seed=42&ramp=banana",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (Ramp must be a valid ramp name or list of color stops: Color stops must be of the form position:rrggbb)"}`,
+		},
+		"invalid azimuth": {
+			Query:              "seed=42&azimuth=north",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (Azimuth must be a number of degrees)"}`,
+		},
+		"invalid altitude": {
+			Query:              "seed=42&altitude=120",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (Altitude must be a number of degrees between 0 and 90)"}`,
+		},
+		"invalid zfactor": {
+			Query:              "seed=42&zFactor=tall",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (ZFactor must be a number)"}`,
+		},
+		"invalid normalization": {
+			Query:              "seed=42&normalization=banana",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (Normalization must be one of minmax, range or percentile)"}`,
+		},
+	}
+
+	for name, tc := range testCases {
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest(http.MethodGet, fmt.Sprintf("/preview?%s", tc.Query), nil)
+		tghttp.HandlePreview()(w, r, nil)
+
+		if w.Code != tc.ExpectedStatusCode {
+			t.Errorf("'%s' failed. Expected status code %d, received %d", name, tc.ExpectedStatusCode, w.Code)
+			t.Logf("Response: %s", w.Body.String())
+			continue
+		}
+
+		if tc.ExpectedErrorBody != "" {
+			if w.Body.String() != tc.ExpectedErrorBody {
+				t.Errorf("'%s' failed. Expected error response '%s', received '%s'", name, tc.ExpectedErrorBody, w.Body.String())
+			}
+			continue
+		}
+
+		if contentType := w.Header().Get("Content-Type"); contentType != "image/png" {
+			t.Errorf("'%s' failed. Expected content type image/png, received %s", name, contentType)
+		}
+
+		img, err := png.Decode(w.Body)
+		if err != nil {
+			t.Errorf("'%s' failed. Failed to decode response: %s", name, err.Error())
+			continue
+		}
+		if bounds := img.Bounds(); bounds.Dx() != tc.ExpectedWidth || bounds.Dy() != tc.ExpectedHeight {
+			t.Errorf("'%s' failed. Expected %dx%d image, received %v", name, tc.ExpectedWidth, tc.ExpectedHeight, bounds)
+		}
+	}
+}
@@ -25,3 +25,24 @@ func ParseIntArray(v string) []int {
 
 	return ints
 }
+
+// ParseFloatArray tries to parse the given query param into an array of floats
+// If it can't, it always returns an empty list
+func ParseFloatArray(v string) []float64 {
+	floats := make([]float64, 0, 3)
+
+	if v == "" {
+		return []float64{}
+	}
+
+	for _, value := range strings.Split(v, ",") {
+		num, err := strconv.ParseFloat(value, 64)
+		if err == nil {
+			floats = append(floats, num)
+		} else {
+			return []float64{}
+		}
+	}
+
+	return floats
+}
@@ -0,0 +1,199 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/bcokert/terragen/log"
+	"github.com/bcokert/terragen/storage"
+	"github.com/julienschmidt/httprouter"
+)
+
+// maxRecipeNameLength limits the length of recipe names
+const maxRecipeNameLength = 100
+
+// HandleCreateRecipe saves the noise params as the first version of a new recipe with the name param, and responds with it
+// The recipe's id is short, so it can be shared in a url
+func HandleCreateRecipe(store storage.Store) httprouter.Handle {
+	return Handle(func(response http.ResponseWriter, request *http.Request, _ httprouter.Params) (interface{}, int) {
+		log.Info("Request Started: %s %s", request.Method, request.URL.String())
+
+		// Validate the params, so only recipes that can be generated are saved
+		name, query, err := validateRecipeParams(request.URL.Query(), "")
+		if err != nil {
+			return fmt.Errorf("Invalid param: (%s)", err.Error()), http.StatusBadRequest
+		}
+
+		recipe, err := store.Create(name, query)
+		if err != nil {
+			return fmt.Errorf("Failed to save recipe: (%s)", err.Error()), http.StatusInternalServerError
+		}
+		return recipe, http.StatusCreated
+	})
+}
+
+// HandleRecipes responds with the latest version of every recipe. It is an idempotent call
+func HandleRecipes(store storage.Store) httprouter.Handle {
+	return Handle(func(response http.ResponseWriter, request *http.Request, _ httprouter.Params) (interface{}, int) {
+		log.Info("Request Started: %s %s", request.Method, request.URL.String())
+
+		recipes, err := store.List()
+		if err != nil {
+			return recipeError(err)
+		}
+		return recipes, http.StatusOK
+	})
+}
+
+// HandleRecipe responds with the recipe with the id in the path, at the version param or its latest version. It is an idempotent call
+func HandleRecipe(store storage.Store) httprouter.Handle {
+	return Handle(func(response http.ResponseWriter, request *http.Request, p httprouter.Params) (interface{}, int) {
+		log.Info("Request Started: %s %s", request.Method, request.URL.String())
+
+		version, err := validateVersionParam(request.URL.Query())
+		if err != nil {
+			return fmt.Errorf("Invalid param: (%s)", err.Error()), http.StatusBadRequest
+		}
+
+		recipe, err := getRecipe(store, p.ByName("id"), version)
+		if err != nil {
+			return recipeError(err)
+		}
+		return recipe, http.StatusOK
+	})
+}
+
+// HandleRecipeVersions responds with every version of the recipe with the id in the path, oldest first. It is an idempotent call
+func HandleRecipeVersions(store storage.Store) httprouter.Handle {
+	return Handle(func(response http.ResponseWriter, request *http.Request, p httprouter.Params) (interface{}, int) {
+		log.Info("Request Started: %s %s", request.Method, request.URL.String())
+
+		versions, err := store.Versions(p.ByName("id"))
+		if err != nil {
+			return recipeError(err)
+		}
+		return versions, http.StatusOK
+	})
+}
+
+// HandleUpdateRecipe saves the noise params as a new version of the recipe with the id in the path, and responds with it
+// The name param is optional, and defaults to the recipe's current name
+func HandleUpdateRecipe(store storage.Store) httprouter.Handle {
+	return Handle(func(response http.ResponseWriter, request *http.Request, p httprouter.Params) (interface{}, int) {
+		log.Info("Request Started: %s %s", request.Method, request.URL.String())
+
+		current, err := store.Get(p.ByName("id"))
+		if err != nil {
+			return recipeError(err)
+		}
+
+		name, query, err := validateRecipeParams(request.URL.Query(), current.Name)
+		if err != nil {
+			return fmt.Errorf("Invalid param: (%s)", err.Error()), http.StatusBadRequest
+		}
+
+		recipe, err := store.Update(current.ID, name, query)
+		if err != nil {
+			return recipeError(err)
+		}
+		return recipe, http.StatusOK
+	})
+}
+
+// HandleDeleteRecipe deletes the recipe with the id in the path, and all of its versions
+func HandleDeleteRecipe(store storage.Store) httprouter.Handle {
+	return Handle(func(response http.ResponseWriter, request *http.Request, p httprouter.Params) (interface{}, int) {
+		log.Info("Request Started: %s %s", request.Method, request.URL.String())
+
+		if err := store.Delete(p.ByName("id")); err != nil {
+			return recipeError(err)
+		}
+		return nil, http.StatusNoContent
+	})
+}
+
+// HandleRecipeNoise generates the recipe with the id in the path, at the version param or its latest version. It is an idempotent call
+// The from, to and resolution params override the recipe's own, and any other params are ignored
+func HandleRecipeNoise(store storage.Store) httprouter.Handle {
+	return Handle(func(response http.ResponseWriter, request *http.Request, p httprouter.Params) (interface{}, int) {
+		log.Info("Request Started: %s %s", request.Method, request.URL.String())
+
+		version, err := validateVersionParam(request.URL.Query())
+		if err != nil {
+			return fmt.Errorf("Invalid param: (%s)", err.Error()), http.StatusBadRequest
+		}
+
+		recipe, err := getRecipe(store, p.ByName("id"), version)
+		if err != nil {
+			return recipeError(err)
+		}
+
+		query, err := overrideQuery(recipe.Query, request.URL.Query())
+		if err != nil {
+			return fmt.Errorf("Failed to read recipe: (%s)", err.Error()), http.StatusInternalServerError
+		}
+
+		params, err := validateNoiseParams(query)
+		if err != nil {
+			return fmt.Errorf("Invalid param: (%s)", err.Error()), http.StatusBadRequest
+		}
+
+		return generateNoise(params), http.StatusOK
+	})
+}
+
+// getRecipe gets a version of a recipe, or its latest version if the version is 0
+func getRecipe(store storage.Store, id string, version int) (storage.Recipe, error) {
+	if version == 0 {
+		return store.Get(id)
+	}
+	return store.GetVersion(id, version)
+}
+
+// recipeError responds with a not found for missing recipes, and an internal error if the store failed
+func recipeError(err error) (interface{}, int) {
+	if err == storage.ErrNotFound {
+		return err, http.StatusNotFound
+	}
+	return fmt.Errorf("Failed to access recipes: (%s)", err.Error()), http.StatusInternalServerError
+}
+
+// validateVersionParam validates the version param, which is 0 if it's missing
+func validateVersionParam(params url.Values) (version int, err error) {
+	if params.Get("version") == "" {
+		return 0, nil
+	}
+	if version, err = strconv.Atoi(params.Get("version")); err != nil || version < 1 {
+		return 0, errors.New("Version must be a positive integer")
+	}
+	return version, nil
+}
+
+// validateRecipeParams validates the name and noise params of a recipe, and encodes the noise params as the recipe's query
+// The seed is saved even if it was generated, so the recipe is the same each time it's generated
+func validateRecipeParams(params url.Values, defaultName string) (name string, query string, err error) {
+	name = defaultName
+	if params.Get("name") != "" {
+		name = params.Get("name")
+	}
+	if len(name) < 1 || len(name) > maxRecipeNameLength {
+		return "", "", fmt.Errorf("Name must be between 1 and %d characters", maxRecipeNameLength)
+	}
+
+	noiseParams, err := validateNoiseParams(params)
+	if err != nil {
+		return "", "", err
+	}
+
+	values := url.Values{}
+	for key, value := range params {
+		values[key] = value
+	}
+	values.Del("name")
+	values.Set("seed", strconv.FormatInt(noiseParams.seed, 10))
+
+	return name, values.Encode(), nil
+}
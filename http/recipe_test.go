@@ -0,0 +1,290 @@
+package http_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	tghttp "github.com/bcokert/terragen/http"
+	"github.com/bcokert/terragen/noise"
+	"github.com/bcokert/terragen/storage"
+	"github.com/julienschmidt/httprouter"
+)
+
+func TestHandleCreateRecipe(t *testing.T) {
+	testCases := map[string]struct {
+		Query              string
+		ExpectedStatusCode int
+		ExpectedErrorBody  string
+		ExpectedName       string
+	}{
+		"seeded": {
+			Query:              "name=hills&from=0,0&to=2,2&resolution=4&seed=42&noiseFunction=red&normalize=minmax",
+			ExpectedStatusCode: http.StatusCreated,
+			ExpectedName:       "hills",
+		},
+		"unseeded 1d": {
+			Query:              "name=line&from=0&to=2",
+			ExpectedStatusCode: http.StatusCreated,
+			ExpectedName:       "line",
+		},
+		"missing name": {
+			Query:              "seed=42",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (Name must be between 1 and 100 characters)"}`,
+		},
+		"long name": {
+			Query:              "seed=42&name=" + strings.Repeat("a", 101),
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (Name must be between 1 and 100 characters)"}`,
+		},
+		"invalid preset": {
+			Query:              "name=nope&noiseFunction=nope",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (NoiseFunction must be a valid preset)"}`,
+		},
+	}
+
+	for name, tc := range testCases {
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest(http.MethodPost, fmt.Sprintf("/recipes?%s", tc.Query), nil)
+		tghttp.HandleCreateRecipe(storage.NewMemoryStore())(w, r, nil)
+
+		if w.Code != tc.ExpectedStatusCode {
+			t.Errorf("'%s' failed. Expected status code %d, received %d", name, tc.ExpectedStatusCode, w.Code)
+			t.Logf("Response: %s", w.Body.String())
+			continue
+		}
+
+		if tc.ExpectedErrorBody != "" {
+			if w.Body.String() != tc.ExpectedErrorBody {
+				t.Errorf("'%s' failed. Expected error response '%s', received '%s'", name, tc.ExpectedErrorBody, w.Body.String())
+			}
+			continue
+		}
+
+		recipe := storage.Recipe{}
+		if err := json.NewDecoder(w.Body).Decode(&recipe); err != nil {
+			t.Errorf("'%s' failed. Failed to decode response: %s", name, w.Body.String())
+			continue
+		}
+		// The name isn't part of the query, and generated seeds are saved with it
+		query, _ := url.ParseQuery(recipe.Query)
+		if recipe.Name != tc.ExpectedName || recipe.Version != 1 || query.Get("name") != "" || query.Get("seed") == "" {
+			t.Errorf("'%s' failed. Expected a first seeded version named %s, received %+v", name, tc.ExpectedName, recipe)
+		}
+	}
+}
+
+func TestHandleRecipes(t *testing.T) {
+	store := storage.NewMemoryStore()
+	hills, _ := store.Create("hills", "from=0,0&to=2,2&resolution=4&seed=42")
+	store.Create("valleys", "seed=7")
+	params := httprouter.Params{{Key: "id", Value: hills.ID}}
+	missing := httprouter.Params{{Key: "id", Value: "missing"}}
+
+	testCases := []struct {
+		Name               string
+		Method             string
+		Query              string
+		Handler            httprouter.Handle
+		Params             httprouter.Params
+		ExpectedStatusCode int
+		ExpectedBody       string
+		ExpectedVersions   []int
+	}{
+		{
+			Name:               "update",
+			Method:             http.MethodPut,
+			Query:              "from=0,0&to=3,3&resolution=4&seed=42",
+			Handler:            tghttp.HandleUpdateRecipe(store),
+			Params:             params,
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedVersions:   []int{2},
+		},
+		{
+			Name:               "rename",
+			Method:             http.MethodPut,
+			Query:              "name=big%20hills&from=0,0&to=4,4&resolution=4&seed=42",
+			Handler:            tghttp.HandleUpdateRecipe(store),
+			Params:             params,
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedVersions:   []int{3},
+		},
+		{
+			Name:               "invalid update",
+			Method:             http.MethodPut,
+			Query:              "resolution=0",
+			Handler:            tghttp.HandleUpdateRecipe(store),
+			Params:             params,
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedBody:       `{"error": "Invalid param: (Resolution must be a positive integer)"}`,
+		},
+		{
+			Name:               "latest",
+			Method:             http.MethodGet,
+			Handler:            tghttp.HandleRecipe(store),
+			Params:             params,
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedVersions:   []int{3},
+		},
+		{
+			Name:               "version",
+			Method:             http.MethodGet,
+			Query:              "version=2",
+			Handler:            tghttp.HandleRecipe(store),
+			Params:             params,
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedVersions:   []int{2},
+		},
+		{
+			Name:               "missing version",
+			Method:             http.MethodGet,
+			Query:              "version=4",
+			Handler:            tghttp.HandleRecipe(store),
+			Params:             params,
+			ExpectedStatusCode: http.StatusNotFound,
+			ExpectedBody:       `{"error": "Recipe not found"}`,
+		},
+		{
+			Name:               "invalid version",
+			Method:             http.MethodGet,
+			Query:              "version=latest",
+			Handler:            tghttp.HandleRecipe(store),
+			Params:             params,
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedBody:       `{"error": "Invalid param: (Version must be a positive integer)"}`,
+		},
+		{
+			Name:               "versions",
+			Method:             http.MethodGet,
+			Handler:            tghttp.HandleRecipeVersions(store),
+			Params:             params,
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedVersions:   []int{1, 2, 3},
+		},
+		{
+			Name:               "list",
+			Method:             http.MethodGet,
+			Handler:            tghttp.HandleRecipes(store),
+			ExpectedStatusCode: http.StatusOK,
+		},
+		{
+			Name:               "delete",
+			Method:             http.MethodDelete,
+			Handler:            tghttp.HandleDeleteRecipe(store),
+			Params:             params,
+			ExpectedStatusCode: http.StatusNoContent,
+			ExpectedBody:       "",
+		},
+		{
+			Name:               "deleted",
+			Method:             http.MethodGet,
+			Handler:            tghttp.HandleRecipe(store),
+			Params:             params,
+			ExpectedStatusCode: http.StatusNotFound,
+			ExpectedBody:       `{"error": "Recipe not found"}`,
+		},
+		{
+			Name:               "update missing",
+			Method:             http.MethodPut,
+			Query:              "seed=42",
+			Handler:            tghttp.HandleUpdateRecipe(store),
+			Params:             missing,
+			ExpectedStatusCode: http.StatusNotFound,
+			ExpectedBody:       `{"error": "Recipe not found"}`,
+		},
+	}
+
+	// The steps share a store, so they're run in order
+	for _, tc := range testCases {
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest(tc.Method, fmt.Sprintf("/recipes?%s", tc.Query), nil)
+		tc.Handler(w, r, tc.Params)
+
+		if w.Code != tc.ExpectedStatusCode {
+			t.Errorf("'%s' failed. Expected status code %d, received %d", tc.Name, tc.ExpectedStatusCode, w.Code)
+			t.Logf("Response: %s", w.Body.String())
+			continue
+		}
+		if tc.ExpectedVersions == nil {
+			if tc.ExpectedBody != "" || tc.ExpectedStatusCode == http.StatusNoContent {
+				if w.Body.String() != tc.ExpectedBody {
+					t.Errorf("'%s' failed. Expected response '%s', received '%s'", tc.Name, tc.ExpectedBody, w.Body.String())
+				}
+			}
+			continue
+		}
+
+		// Single recipes and lists of versions are both compared as lists
+		body := strings.TrimSpace(w.Body.String())
+		if !strings.HasPrefix(body, "[") {
+			body = "[" + body + "]"
+		}
+		recipes := []storage.Recipe{}
+		if err := json.Unmarshal([]byte(body), &recipes); err != nil {
+			t.Errorf("'%s' failed. Failed to decode response: %s", tc.Name, w.Body.String())
+			continue
+		}
+		versions := []int{}
+		for _, recipe := range recipes {
+			versions = append(versions, recipe.Version)
+		}
+		if fmt.Sprint(versions) != fmt.Sprint(tc.ExpectedVersions) {
+			t.Errorf("'%s' failed. Expected versions %v, received %v", tc.Name, tc.ExpectedVersions, versions)
+		}
+	}
+
+	if recipes, _ := store.List(); len(recipes) != 1 || recipes[0].Name != "valleys" {
+		t.Errorf("Expected only the other recipe to be left, received %+v", recipes)
+	}
+}
+
+func TestHandleRecipeNoise(t *testing.T) {
+	store := storage.NewMemoryStore()
+	recipe, _ := store.Create("hills", "from=0,0&to=2,2&resolution=4&seed=42&noiseFunction=pink")
+	store.Update(recipe.ID, "hills", "from=0,0&to=2,2&resolution=4&seed=42&noiseFunction=blue")
+	params := httprouter.Params{{Key: "id", Value: recipe.ID}}
+
+	testCases := map[string]struct {
+		Query              string
+		Expected           string
+		ExpectedStatusCode int
+	}{
+		"latest":   {Query: "", Expected: "from=0,0&to=2,2&resolution=4&seed=42&noiseFunction=blue", ExpectedStatusCode: http.StatusOK},
+		"version":  {Query: "version=1", Expected: "from=0,0&to=2,2&resolution=4&seed=42&noiseFunction=pink", ExpectedStatusCode: http.StatusOK},
+		"override": {Query: "version=1&from=1,1&to=3,2&resolution=2&seed=7&noiseFunction=red", Expected: "from=1,1&to=3,2&resolution=2&seed=42&noiseFunction=pink", ExpectedStatusCode: http.StatusOK},
+		"invalid":  {Query: "resolution=-1", ExpectedStatusCode: http.StatusBadRequest},
+	}
+
+	for name, tc := range testCases {
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest(http.MethodGet, fmt.Sprintf("/recipes/%s/noise?%s", recipe.ID, tc.Query), nil)
+		tghttp.HandleRecipeNoise(store)(w, r, params)
+
+		if w.Code != tc.ExpectedStatusCode {
+			t.Errorf("'%s' failed. Expected status code %d, received %d", name, tc.ExpectedStatusCode, w.Code)
+			t.Logf("Response: %s", w.Body.String())
+			continue
+		}
+		if tc.Expected == "" {
+			continue
+		}
+
+		// The recipe generates exactly the noise of its query
+		expected := httptest.NewRecorder()
+		r, _ = http.NewRequest(http.MethodGet, fmt.Sprintf("/noise?%s", tc.Expected), nil)
+		tghttp.HandleNoise()(expected, r, nil)
+
+		result, original := noise.Noise{}, noise.Noise{}
+		json.NewDecoder(w.Body).Decode(&result)
+		json.NewDecoder(expected.Body).Decode(&original)
+		if !result.IsEqual(&original) {
+			t.Errorf("'%s' failed. Expected the noise of %s, received %v", name, tc.Expected, result.Values)
+		}
+	}
+}
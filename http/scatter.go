@@ -0,0 +1,158 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/bcokert/terragen/log"
+	tgmath "github.com/bcokert/terragen/math"
+	"github.com/bcokert/terragen/noise"
+	"github.com/bcokert/terragen/scatter"
+	"github.com/julienschmidt/httprouter"
+)
+
+// maxScatterInstances limits how many instances HandleScatter could place, judged from the area and minimum distance
+const maxScatterInstances = 1 << 18
+
+// maxScatterCellSize limits the cells of HandleScatter, since whole cells are placed however small the requested area is
+const maxScatterCellSize = 256
+
+// HandleScatter places instances such as trees and rocks over 2D noise with the given params, and responds with their positions,
+// rotations and scales. It is an idempotent call, and requests for neighbouring areas agree at their borders
+func HandleScatter() httprouter.Handle {
+	return Handle(func(response http.ResponseWriter, request *http.Request, _ httprouter.Params) (interface{}, int) {
+		log.Info("Request Started: %s %s", request.Method, request.URL.String())
+
+		// Validate the params, and get the related data
+		params, err := validateNoiseParams(request.URL.Query())
+		if err != nil {
+			return fmt.Errorf("Invalid param: (%s)", err.Error()), http.StatusBadRequest
+		}
+
+		scatterParams, err := validateScatterParams(request.URL.Query(), params)
+		if err != nil {
+			return fmt.Errorf("Invalid param: (%s)", err.Error()), http.StatusBadRequest
+		}
+
+		// The density is generated from its own seed, and shifted from the presets' roughly [-1, 1] range to [0, 1]
+		var density noise.Function
+		if scatterParams.densityPreset != nil {
+			densityFn := scatterParams.densityPreset(tgmath.NewDefaultSource(params.seed+3), presetFrequencies)
+			density = func(t []float64) float64 {
+				return (densityFn(t) + 1) / 2
+			}
+		}
+
+		from := [2]float64{float64(params.from[0]), float64(params.from[1])}
+		to := [2]float64{float64(params.to[0]), float64(params.to[1])}
+		instances, err := scatter.Scatter(params.seed, from, to, density, noiseFunction(params), scatterParams.options)
+		if err != nil {
+			return fmt.Errorf("Failed to scatter instances: (%s)", err.Error()), http.StatusInternalServerError
+		}
+
+		return scatterResponse{Instances: instances}, http.StatusOK
+	})
+}
+
+type scatterResponse struct {
+	Instances []scatter.Instance `json:"instances"`
+}
+
+type scatterParams struct {
+	options       scatter.Options
+	densityPreset noise.Preset
+}
+
+func validateScatterParams(params url.Values, noiseParams queryParams) (response scatterParams, err error) {
+	densityFunction := params.Get("densityFunction")
+
+	if len(noiseParams.from) != 2 {
+		return scatterParams{}, errors.New("Scatter requires a 2 dimensional From and To")
+	}
+
+	// Masks and grid presets are fitted to From and To, so they'd give neighbouring areas different heights at their borders
+	if noiseParams.mask != nil {
+		return scatterParams{}, errors.New("Scatter can't use a mask, since masks depend on From and To")
+	}
+	if noiseParams.gridPreset != nil {
+		return scatterParams{}, errors.New("Scatter can't use a grid preset, since grid presets depend on From and To")
+	}
+
+	// Validate the spacing and scale, each part of which is an optional number
+	response.options = scatter.NewDefaultOptions()
+	optionParams := []struct {
+		name  string
+		value *float64
+		err   string
+	}{
+		{"minDistance", &response.options.MinDistance, "MinDistance must be a number"},
+		{"maxDistance", &response.options.MaxDistance, "MaxDistance must be a number"},
+		{"cellSize", &response.options.CellSize, "CellSize must be a number"},
+		{"minScale", &response.options.MinScale, "MinScale must be a number"},
+		{"maxScale", &response.options.MaxScale, "MaxScale must be a number"},
+	}
+	for _, optionParam := range optionParams {
+		if value := params.Get(optionParam.name); value != "" {
+			if *optionParam.value, err = strconv.ParseFloat(value, 64); err != nil {
+				return scatterParams{}, errors.New(optionParam.err)
+			}
+		}
+	}
+
+	// A rule is added for height or slope when either of its bounds is given
+	ruleParams := []struct {
+		min, max string
+		rule     scatter.Rule
+		err      string
+	}{
+		{"minHeight", "maxHeight", scatter.Rule{Property: scatter.Height, Min: math.Inf(-1), Max: math.Inf(1)}, "MinHeight and MaxHeight must be numbers"},
+		{"minSlope", "maxSlope", scatter.Rule{Property: scatter.Slope, Min: 0, Max: 90}, "MinSlope and MaxSlope must be numbers of degrees"},
+	}
+	for _, ruleParam := range ruleParams {
+		min, max := params.Get(ruleParam.min), params.Get(ruleParam.max)
+		if min == "" && max == "" {
+			continue
+		}
+
+		rule := ruleParam.rule
+		if min != "" {
+			if rule.Min, err = strconv.ParseFloat(min, 64); err != nil {
+				return scatterParams{}, errors.New(ruleParam.err)
+			}
+		}
+		if max != "" {
+			if rule.Max, err = strconv.ParseFloat(max, 64); err != nil {
+				return scatterParams{}, errors.New(ruleParam.err)
+			}
+		}
+		response.options.Rules = append(response.options.Rules, rule)
+	}
+
+	if err = response.options.Validate(); err != nil {
+		return scatterParams{}, err
+	}
+
+	if response.options.CellSize > maxScatterCellSize {
+		return scatterParams{}, fmt.Errorf("CellSize must be at most %d", maxScatterCellSize)
+	}
+
+	// Every cell overlapping the range is placed, along with its neighbours, so the instances are judged from the area they cover
+	from := [2]float64{float64(noiseParams.from[0]), float64(noiseParams.from[1])}
+	to := [2]float64{float64(noiseParams.to[0]), float64(noiseParams.to[1])}
+	area := scatter.CoveredArea(from, to, response.options.CellSize)
+	if area/(response.options.MinDistance*response.options.MinDistance) > maxScatterInstances {
+		return scatterParams{}, fmt.Errorf("Scatter is limited to about %d instances, so reduce the range or CellSize, or increase MinDistance", maxScatterInstances)
+	}
+
+	if densityFunction != "" {
+		if response.densityPreset = searchPresets(densityFunction); response.densityPreset == nil {
+			return scatterParams{}, errors.New("DensityFunction must be a valid preset")
+		}
+	}
+
+	return response, nil
+}
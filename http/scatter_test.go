@@ -0,0 +1,146 @@
+package http_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	tghttp "github.com/bcokert/terragen/http"
+)
+
+func TestHandleScatter(t *testing.T) {
+	testCases := map[string]struct {
+		Query              string
+		ExpectedStatusCode int
+		ExpectedErrorBody  string
+		Allowed            func(position [3]float64) bool
+	}{
+		"defaults": {
+			Query:              "seed=42",
+			ExpectedStatusCode: http.StatusOK,
+			Allowed:            func(position [3]float64) bool { return position[0] < 5 && position[1] < 5 },
+		},
+		"density": {
+			Query:              "from=2,2&to=4,4&seed=42&densityFunction=pink&minDistance=0.1&maxDistance=0.5&cellSize=2",
+			ExpectedStatusCode: http.StatusOK,
+			Allowed:            func(position [3]float64) bool { return position[0] >= 2 && position[1] >= 2 },
+		},
+		"height band": {
+			Query:              "seed=42&minHeight=0&maxHeight=0.5&maxSlope=60&minScale=1&maxScale=1",
+			ExpectedStatusCode: http.StatusOK,
+			Allowed:            func(position [3]float64) bool { return position[2] >= 0 && position[2] <= 0.5 },
+		},
+		"1d": {
+			Query:              "from=0&to=2&seed=42",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (Scatter requires a 2 dimensional From and To)"}`,
+		},
+		"mask": {
+			Query:              "seed=42&mask=radial",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (Scatter can't use a mask, since masks depend on From and To)"}`,
+		},
+		"grid preset": {
+			Query:              "seed=42&noiseFunction=diamondSquare",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (Scatter can't use a grid preset, since grid presets depend on From and To)"}`,
+		},
+		"invalid min distance": {
+			Query:              "seed=42&minDistance=close",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (MinDistance must be a number)"}`,
+		},
+		"max below min": {
+			Query:              "seed=42&minDistance=1&maxDistance=0.5",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (MaxDistance must be at least MinDistance)"}`,
+		},
+		"invalid height": {
+			Query:              "seed=42&minHeight=low",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (MinHeight and MaxHeight must be numbers)"}`,
+		},
+		"invalid slope": {
+			Query:              "seed=42&maxSlope=steep",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (MinSlope and MaxSlope must be numbers of degrees)"}`,
+		},
+		"inverted slope": {
+			Query:              "seed=42&minSlope=30&maxSlope=10",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (Rules must have a min no more than their max)"}`,
+		},
+		"too many instances": {
+			Query:              "from=0,0&to=100,100&seed=42&minDistance=0.01&maxDistance=0.01",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (Scatter is limited to about 262144 instances, so reduce the range or CellSize, or increase MinDistance)"}`,
+		},
+		"huge cells": {
+			Query:              "from=0,0&to=1,1&seed=42&cellSize=2000",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (CellSize must be at most 256)"}`,
+		},
+		"many cells": {
+			Query:              "from=0,0&to=1,1&seed=42&cellSize=256&minDistance=0.25&maxDistance=1",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (Scatter is limited to about 262144 instances, so reduce the range or CellSize, or increase MinDistance)"}`,
+		},
+		"nan distance": {
+			Query:              "seed=42&minDistance=NaN",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (MinDistance must be a positive number)"}`,
+		},
+		"far max distance": {
+			Query:              "seed=42&minDistance=0.01&maxDistance=1",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (MaxDistance must be at most 16 times MinDistance)"}`,
+		},
+		"invalid density function": {
+			Query:              "seed=42&densityFunction=plaid",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (DensityFunction must be a valid preset)"}`,
+		},
+	}
+
+	for name, tc := range testCases {
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest(http.MethodGet, fmt.Sprintf("/scatter?%s", tc.Query), nil)
+		tghttp.HandleScatter()(w, r, nil)
+
+		if w.Code != tc.ExpectedStatusCode {
+			t.Errorf("'%s' failed. Expected status code %d, received %d", name, tc.ExpectedStatusCode, w.Code)
+			t.Logf("Response: %s", w.Body.String())
+			continue
+		}
+
+		if tc.ExpectedErrorBody != "" {
+			if w.Body.String() != tc.ExpectedErrorBody {
+				t.Errorf("'%s' failed. Expected error response '%s', received '%s'", name, tc.ExpectedErrorBody, w.Body.String())
+			}
+			continue
+		}
+
+		var result struct {
+			Instances []struct {
+				Position [3]float64 `json:"position"`
+				Rotation float64    `json:"rotation"`
+				Scale    float64    `json:"scale"`
+			} `json:"instances"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+			t.Errorf("'%s' failed. Expected a scatter response, received '%s'", name, w.Body.String())
+			continue
+		}
+
+		if len(result.Instances) == 0 {
+			t.Errorf("'%s' failed. Expected some instances", name)
+		}
+		for _, instance := range result.Instances {
+			if !tc.Allowed(instance.Position) {
+				t.Errorf("'%s' failed. Expected no instance at %v", name, instance.Position)
+			}
+		}
+	}
+}
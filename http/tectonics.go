@@ -0,0 +1,112 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/bcokert/terragen/log"
+	"github.com/bcokert/terragen/math"
+	"github.com/bcokert/terragen/noise"
+	"github.com/bcokert/terragen/tectonics"
+	"github.com/julienschmidt/httprouter"
+)
+
+// HandleTectonics generates 2D terrain from plate tectonics, with the noise of the given params added as detail. It is an idempotent call
+// It responds with the terrain, along with the plates and the plate, boundary type and uplift of each sample
+func HandleTectonics() httprouter.Handle {
+	return Handle(func(response http.ResponseWriter, request *http.Request, _ httprouter.Params) (interface{}, int) {
+		log.Info("Request Started: %s %s", request.Method, request.URL.String())
+
+		// Validate the params, and get the related data
+		params, err := validateNoiseParams(request.URL.Query())
+		if err != nil {
+			return fmt.Errorf("Invalid param: (%s)", err.Error()), http.StatusBadRequest
+		}
+
+		tectonicsParams, err := validateTectonicsParams(request.URL.Query(), params)
+		if err != nil {
+			return fmt.Errorf("Invalid param: (%s)", err.Error()), http.StatusBadRequest
+		}
+
+		// The plates are seeded from their own seed, so they don't follow the detail noise
+		from := [2]float64{float64(params.from[0]), float64(params.from[1])}
+		to := [2]float64{float64(params.to[0]), float64(params.to[1])}
+		plates, err := tectonics.NewPlates(math.NewDefaultSource(params.seed+4), from, to, tectonicsParams.plates, tectonicsParams.oceanicFraction)
+		if err != nil {
+			return fmt.Errorf("Invalid param: (%s)", err.Error()), http.StatusBadRequest
+		}
+		model, err := tectonics.NewModel(plates, tectonicsParams.options)
+		if err != nil {
+			return fmt.Errorf("Invalid param: (%s)", err.Error()), http.StatusBadRequest
+		}
+
+		detail := presetFunction(params)
+		terrain := generateNoiseFrom(params, tectonics.WithDetail(model.Function(), detail, tectonicsParams.detail))
+		layers, err := model.Layers(terrain)
+		if err != nil {
+			return fmt.Errorf("Failed to sample tectonics: (%s)", err.Error()), http.StatusInternalServerError
+		}
+
+		return tectonicsResponse{Noise: terrain, Plates: plates, Layers: layers}, http.StatusOK
+	})
+}
+
+type tectonicsResponse struct {
+	Noise  *noise.Noise      `json:"noise"`
+	Plates []tectonics.Plate `json:"plates"`
+	Layers *tectonics.Layers `json:"layers"`
+}
+
+// maxPlates limits the plates of HandleTectonics, since every pair of plates has a boundary and each sample is compared to every plate
+const maxPlates = 256
+
+type tectonicsParams struct {
+	plates          int
+	oceanicFraction float64
+	detail          float64
+	options         tectonics.Options
+}
+
+func validateTectonicsParams(params url.Values, noiseParams queryParams) (response tectonicsParams, err error) {
+	plates := params.Get("plates")
+	oceanicFraction := params.Get("oceanicFraction")
+	detail := params.Get("detail")
+	width := params.Get("width")
+
+	if len(noiseParams.from) != 2 {
+		return tectonicsParams{}, errors.New("Tectonics requires a 2 dimensional From and To")
+	}
+
+	response.plates = 12
+	if plates != "" {
+		if response.plates, err = strconv.Atoi(plates); err != nil || response.plates < 2 || response.plates > maxPlates {
+			return tectonicsParams{}, fmt.Errorf("Plates must be an integer between 2 and %d", maxPlates)
+		}
+	}
+
+	response.oceanicFraction = 0.6
+	if oceanicFraction != "" {
+		if response.oceanicFraction, err = strconv.ParseFloat(oceanicFraction, 64); err != nil || response.oceanicFraction < 0 || response.oceanicFraction > 1 {
+			return tectonicsParams{}, errors.New("OceanicFraction must be a number between 0 and 1")
+		}
+	}
+
+	response.detail = 0.25
+	if detail != "" {
+		if response.detail, err = strconv.ParseFloat(detail, 64); err != nil || response.detail < 0 {
+			return tectonicsParams{}, errors.New("Detail must be a non negative number")
+		}
+	}
+
+	response.options = tectonics.NewDefaultOptions()
+	if width != "" {
+		if response.options.Width, err = strconv.ParseFloat(width, 64); err != nil || response.options.Width <= 0 {
+			return tectonicsParams{}, errors.New("Width must be a positive number")
+		}
+	}
+
+	return response, nil
+}
@@ -0,0 +1,115 @@
+package http_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	tghttp "github.com/bcokert/terragen/http"
+)
+
+func TestHandleTectonics(t *testing.T) {
+	testCases := map[string]struct {
+		Query              string
+		ExpectedStatusCode int
+		ExpectedErrorBody  string
+		ExpectedSamples    int
+		ExpectedPlates     int
+	}{
+		"defaults": {
+			Query:              "seed=42",
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedSamples:    10000,
+			ExpectedPlates:     12,
+		},
+		"custom": {
+			Query:              "from=0,0&to=4,2&resolution=5&seed=42&plates=5&oceanicFraction=0.3&detail=0&width=0.25",
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedSamples:    200,
+			ExpectedPlates:     5,
+		},
+		"1d": {
+			Query:              "from=0&to=2&seed=42",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (Tectonics requires a 2 dimensional From and To)"}`,
+		},
+		"one plate": {
+			Query:              "seed=42&plates=1",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (Plates must be an integer between 2 and 256)"}`,
+		},
+		"too many plates": {
+			Query:              "seed=42&plates=1000000",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (Plates must be an integer between 2 and 256)"}`,
+		},
+		"invalid oceanic fraction": {
+			Query:              "seed=42&oceanicFraction=2",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (OceanicFraction must be a number between 0 and 1)"}`,
+		},
+		"negative detail": {
+			Query:              "seed=42&detail=-1",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (Detail must be a non negative number)"}`,
+		},
+		"invalid width": {
+			Query:              "seed=42&width=0",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (Width must be a positive number)"}`,
+		},
+	}
+
+	for name, tc := range testCases {
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest(http.MethodGet, fmt.Sprintf("/tectonics?%s", tc.Query), nil)
+		tghttp.HandleTectonics()(w, r, nil)
+
+		if w.Code != tc.ExpectedStatusCode {
+			t.Errorf("'%s' failed. Expected status code %d, received %d", name, tc.ExpectedStatusCode, w.Code)
+			t.Logf("Response: %s", w.Body.String())
+			continue
+		}
+
+		if tc.ExpectedErrorBody != "" {
+			if w.Body.String() != tc.ExpectedErrorBody {
+				t.Errorf("'%s' failed. Expected error response '%s', received '%s'", name, tc.ExpectedErrorBody, w.Body.String())
+			}
+			continue
+		}
+
+		var result struct {
+			Noise struct {
+				Values []float64 `json:"values"`
+			} `json:"noise"`
+			Plates []struct {
+				ID int `json:"id"`
+			} `json:"plates"`
+			Layers struct {
+				Plates     []int     `json:"plates"`
+				Boundaries []string  `json:"boundaries"`
+				Uplift     []float64 `json:"uplift"`
+			} `json:"layers"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+			t.Errorf("'%s' failed. Expected a tectonics response, received '%s'", name, w.Body.String())
+			continue
+		}
+
+		if len(result.Plates) != tc.ExpectedPlates {
+			t.Errorf("'%s' failed. Expected %d plates, received %d", name, tc.ExpectedPlates, len(result.Plates))
+		}
+		for channel, length := range map[string]int{
+			"values":     len(result.Noise.Values),
+			"plates":     len(result.Layers.Plates),
+			"boundaries": len(result.Layers.Boundaries),
+			"uplift":     len(result.Layers.Uplift),
+		} {
+			if length != tc.ExpectedSamples {
+				t.Errorf("'%s' failed. Expected %d %s, received %d", name, tc.ExpectedSamples, channel, length)
+			}
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package http
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/bcokert/terragen/log"
+	"github.com/bcokert/terragen/math"
+	"github.com/bcokert/terragen/mesh"
+	"github.com/bcokert/terragen/noise"
+	"github.com/julienschmidt/httprouter"
+)
+
+// HandleTile generates one tile of a level of detail pyramid over the world, at the zoom level and position in the path. It is an idempotent call
+// Tiles are returned as JSON by default, or as a mesh with an optional skirt to hide cracks between neighbouring levels of detail
+// Tiles from separate requests share their edge samples, so only spectral presets are allowed, since their value depends purely on position and the seed
+// Lattice presets draw their gradients in the order they're visited, so neighbouring tiles would disagree at their edges
+func HandleTile() httprouter.Handle {
+	return Handle(func(response http.ResponseWriter, request *http.Request, p httprouter.Params) (interface{}, int) {
+		log.Info("Request Started: %s %s", request.Method, request.URL.String())
+
+		// Validate the params, and get the related data
+		params, err := validateTileParams(request.URL.Query(), p)
+		if err != nil {
+			return fmt.Errorf("Invalid param: (%s)", err.Error()), http.StatusBadRequest
+		}
+
+		log.Info("Generating tile with the following params: %+v", params)
+		tile := noise.NewTile(params.presetName, params.zoom, params.x, params.y)
+		tile.Generate(params.rootSize, params.samples, params.preset(math.NewDefaultSource(params.seed), presetFrequencies))
+
+		if params.format == nil {
+			return tile, http.StatusOK
+		}
+
+		terrain, err := mesh.FromHeightfield(mesh.Heightfield{
+			Values:  tile.Values,
+			Width:   tile.Samples,
+			Height:  tile.Samples,
+			Origin:  math.Vec2{tile.From[0], tile.From[1]},
+			Spacing: tile.Spacing(),
+		}, params.options)
+		if err != nil {
+			return fmt.Errorf("Failed to build mesh: (%s)", err.Error()), http.StatusBadRequest
+		}
+
+		buffer := &bytes.Buffer{}
+		if err := params.format.write(buffer, terrain); err != nil {
+			return fmt.Errorf("Failed to encode mesh: (%s)", err.Error()), http.StatusInternalServerError
+		}
+
+		response.Header().Add("Content-Type", params.format.contentType)
+		response.Write(buffer.Bytes())
+		return nil, http.StatusOK
+	})
+}
+
+type tileParams struct {
+	zoom, x, y int
+	rootSize   float64
+	samples    int
+	presetName string
+	preset     noise.Preset
+	seed       int64
+	format     *meshFormat
+	options    mesh.Options
+}
+
+// maxTileZoom keeps tile coordinates, and the sample indices derived from them, well within integer range
+const maxTileZoom = 24
+
+func validateTileParams(params url.Values, pathParams httprouter.Params) (response tileParams, err error) {
+	rootSize := params.Get("rootSize")
+	samples := params.Get("samples")
+	noiseFunction := params.Get("noiseFunction")
+	seed := params.Get("seed")
+	format := params.Get("format")
+
+	// Validate the tile's position in the pyramid
+	if response.zoom, err = strconv.Atoi(pathParams.ByName("z")); err != nil || response.zoom < 0 || response.zoom > maxTileZoom {
+		return tileParams{}, fmt.Errorf("Z must be an integer between 0 and %d", maxTileZoom)
+	}
+	tilesPerSide := 1 << uint(response.zoom)
+	if response.x, err = strconv.Atoi(pathParams.ByName("x")); err != nil || response.x < 0 || response.x >= tilesPerSide {
+		return tileParams{}, errors.New("X must be an integer between 0 and 2^Z - 1")
+	}
+	if response.y, err = strconv.Atoi(pathParams.ByName("y")); err != nil || response.y < 0 || response.y >= tilesPerSide {
+		return tileParams{}, errors.New("Y must be an integer between 0 and 2^Z - 1")
+	}
+
+	// Validate the size of the world and the samples per tile
+	response.rootSize = 64
+	if rootSize != "" {
+		if response.rootSize, err = strconv.ParseFloat(rootSize, 64); err != nil || response.rootSize <= 0 {
+			return tileParams{}, errors.New("RootSize must be a positive number")
+		}
+	}
+	response.samples = 65
+	if samples != "" {
+		if response.samples, err = strconv.Atoi(samples); err != nil || response.samples < 2 || response.samples > 1025 {
+			return tileParams{}, errors.New("Samples must be an integer between 2 and 1025")
+		}
+	}
+
+	if response.presetName, response.preset, response.seed, err = validatePresetParams(noiseFunction, seed); err != nil {
+		return tileParams{}, err
+	}
+	if _, isSpectral := noise.SpectralPresets[response.presetName]; !isSpectral {
+		return tileParams{}, errors.New("Tiles require a spectral preset, so that neighbouring tiles agree at their edges")
+	}
+
+	// Validate the mesh format and options, if the tile is to be meshed
+	if format != "" && format != "json" {
+		meshFormat, ok := meshFormats[format]
+		if !ok {
+			return tileParams{}, errors.New("Format must be one of json, obj, stl or glb")
+		}
+		response.format = &meshFormat
+	}
+	if response.options, err = validateMeshOptions(params); err != nil {
+		return tileParams{}, err
+	}
+
+	return response, nil
+}
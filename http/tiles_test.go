@@ -0,0 +1,148 @@
+package http_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	tghttp "github.com/bcokert/terragen/http"
+	"github.com/bcokert/terragen/math"
+	"github.com/bcokert/terragen/noise"
+	"github.com/julienschmidt/httprouter"
+)
+
+func TestHandleTile(t *testing.T) {
+	testCases := map[string]struct {
+		Z, X, Y             string
+		Query               string
+		ExpectedStatusCode  int
+		ExpectedErrorBody   string
+		ExpectedContentType string
+		ExpectedTile        func() *noise.Tile
+	}{
+		"defaults": {
+			Z: "0", X: "0", Y: "0", Query: "seed=42&samples=5",
+			ExpectedStatusCode:  http.StatusOK,
+			ExpectedContentType: "application/json",
+			ExpectedTile: func() *noise.Tile {
+				tile := noise.NewTile("red", 0, 0, 0)
+				tile.Generate(64, 5, noise.Red(math.NewDefaultSource(42), []float64{1, 2, 4, 8, 16, 32, 64}))
+				return tile
+			},
+		},
+		"zoomed": {
+			Z: "5", X: "17", Y: "3", Query: "seed=7&noiseFunction=pink&rootSize=16&samples=9",
+			ExpectedStatusCode:  http.StatusOK,
+			ExpectedContentType: "application/json",
+			ExpectedTile: func() *noise.Tile {
+				tile := noise.NewTile("pink", 5, 17, 3)
+				tile.Generate(16, 9, noise.Pink(math.NewDefaultSource(7), []float64{1, 2, 4, 8, 16, 32, 64}))
+				return tile
+			},
+		},
+		"mesh with skirt": {
+			Z: "2", X: "1", Y: "3", Query: "seed=7&samples=9&format=glb&skirt=0.5&maxError=0.01",
+			ExpectedStatusCode:  http.StatusOK,
+			ExpectedContentType: "model/gltf-binary",
+		},
+		"negative zoom": {
+			Z: "-1", X: "0", Y: "0", Query: "seed=42",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (Z must be an integer between 0 and 24)"}`,
+		},
+		"x out of range": {
+			Z: "2", X: "4", Y: "0", Query: "seed=42",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (X must be an integer between 0 and 2^Z - 1)"}`,
+		},
+		"invalid y": {
+			Z: "2", X: "0", Y: "banana", Query: "seed=42",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (Y must be an integer between 0 and 2^Z - 1)"}`,
+		},
+		"invalid root size": {
+			Z: "0", X: "0", Y: "0", Query: "seed=42&rootSize=0",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (RootSize must be a positive number)"}`,
+		},
+		"too few samples": {
+			Z: "0", X: "0", Y: "0", Query: "seed=42&samples=1",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (Samples must be an integer between 2 and 1025)"}`,
+		},
+		"invalid preset": {
+			Z: "0", X: "0", Y: "0", Query: "seed=42&noiseFunction=banana",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (NoiseFunction must be a valid preset)"}`,
+		},
+		"lattice preset": {
+			Z: "0", X: "0", Y: "0", Query: "seed=42&noiseFunction=rawPerlin",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (Tiles require a spectral preset, so that neighbouring tiles agree at their edges)"}`,
+		},
+		"invalid format": {
+			Z: "0", X: "0", Y: "0", Query: "seed=42&format=png",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (Format must be one of json, obj, stl or glb)"}`,
+		},
+		"invalid skirt": {
+			Z: "0", X: "0", Y: "0", Query: "seed=42&format=obj&skirt=deep",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (Skirt must be a non negative number)"}`,
+		},
+	}
+
+	for name, tc := range testCases {
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest(http.MethodGet, fmt.Sprintf("/tiles/%s/%s/%s?%s", tc.Z, tc.X, tc.Y, tc.Query), nil)
+		params := httprouter.Params{{Key: "z", Value: tc.Z}, {Key: "x", Value: tc.X}, {Key: "y", Value: tc.Y}}
+		tghttp.HandleTile()(w, r, params)
+
+		if w.Code != tc.ExpectedStatusCode {
+			t.Errorf("'%s' failed. Expected status code %d, received %d", name, tc.ExpectedStatusCode, w.Code)
+			t.Logf("Response: %s", w.Body.String())
+			continue
+		}
+
+		if tc.ExpectedErrorBody != "" {
+			if w.Body.String() != tc.ExpectedErrorBody {
+				t.Errorf("'%s' failed. Expected error response '%s', received '%s'", name, tc.ExpectedErrorBody, w.Body.String())
+			}
+			continue
+		}
+
+		if contentType := w.Header().Get("Content-Type"); contentType != tc.ExpectedContentType {
+			t.Errorf("'%s' failed. Expected content type %s, received %s", name, tc.ExpectedContentType, contentType)
+		}
+
+		if tc.ExpectedTile == nil {
+			if !strings.HasPrefix(w.Body.String(), "glTF") {
+				t.Errorf("'%s' failed. Expected a binary glTF body", name)
+			}
+			continue
+		}
+
+		expected := tc.ExpectedTile()
+		responseObject := noise.Tile{}
+		if err := json.NewDecoder(w.Body).Decode(&responseObject); err != nil {
+			t.Errorf("'%s' failed. Failed to decode response: %s", name, err.Error())
+			continue
+		}
+		if responseObject.Zoom != expected.Zoom || responseObject.X != expected.X || responseObject.Y != expected.Y || responseObject.Samples != expected.Samples {
+			t.Errorf("'%s' failed. Expected tile %+v, received %+v", name, expected, responseObject)
+		}
+		if len(responseObject.Values) != len(expected.Values) {
+			t.Errorf("'%s' failed. Expected %d values, received %d", name, len(expected.Values), len(responseObject.Values))
+			continue
+		}
+		for i := range expected.Values {
+			if !math.IsFloatEqual(responseObject.Values[i], expected.Values[i]) {
+				t.Errorf("'%s' failed. Expected value %d to be %v, received %v", name, i, expected.Values[i], responseObject.Values[i])
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package http
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	stdImage "image"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/bcokert/terragen/image"
+	"github.com/bcokert/terragen/log"
+	tgmath "github.com/bcokert/terragen/math"
+	"github.com/bcokert/terragen/noise"
+	"github.com/julienschmidt/httprouter"
+)
+
+// maxImportBytes limits the size of heightmaps posted to HandleUpsample
+const maxImportBytes = 32 << 20
+
+// maxImportSamples limits the number of samples of heightmaps posted to HandleUpsample, since small PNGs can decompress to huge images
+const maxImportSamples = 1 << 22
+
+// HandleUpsample upsamples a posted heightmap over the range and resolution of the params, and responds with it as noise
+// The heightmap is stretched over the range and interpolated bicubically, and then the params' noise is added as detail, scaled by the local slope
+// The heightmap is posted as the body, either as a PNG or as raw little endian float32s
+func HandleUpsample() httprouter.Handle {
+	return Handle(func(response http.ResponseWriter, request *http.Request, _ httprouter.Params) (interface{}, int) {
+		log.Info("Request Started: %s %s", request.Method, request.URL.String())
+
+		// Validate the params, and get the related data
+		params, err := validateNoiseParams(request.URL.Query())
+		if err != nil {
+			return fmt.Errorf("Invalid param: (%s)", err.Error()), http.StatusBadRequest
+		}
+
+		upsampleParams, err := validateUpsampleParams(request.URL.Query(), params)
+		if err != nil {
+			return fmt.Errorf("Invalid param: (%s)", err.Error()), http.StatusBadRequest
+		}
+
+		body, err := ioutil.ReadAll(http.MaxBytesReader(response, request.Body, maxImportBytes))
+		if err != nil {
+			return fmt.Errorf("Invalid body: (%s)", err.Error()), http.StatusBadRequest
+		}
+		raster, err := decodeUpsampleBody(body, upsampleParams)
+		if err != nil {
+			return fmt.Errorf("Invalid body: (%s)", err.Error()), http.StatusBadRequest
+		}
+
+		from := [2]float64{float64(params.from[0]), float64(params.from[1])}
+		to := [2]float64{float64(params.to[0]), float64(params.to[1])}
+		heights := raster.Function(from, to, tgmath.CatmullRom)
+		base := func(t []float64) float64 {
+			return heights(t) * upsampleParams.heightScale
+		}
+
+		step := 1 / float64(params.resolution)
+		return generateNoiseFrom(params, noise.SlopeDetail(base, presetFunction(params), upsampleParams.detail, upsampleParams.steepness, step)), http.StatusOK
+	})
+}
+
+// decodeUpsampleBody decodes the posted heightmap in the params' format
+func decodeUpsampleBody(body []byte, upsampleParams upsampleParams) (*noise.Raster, error) {
+	if upsampleParams.format == "raw" {
+		return image.DecodeRawHeightmap(bytes.NewReader(body), upsampleParams.width, upsampleParams.height)
+	}
+
+	config, _, err := stdImage.DecodeConfig(bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	if config.Width*config.Height > maxImportSamples {
+		return nil, fmt.Errorf("Heightmaps are limited to %d pixels", maxImportSamples)
+	}
+	return image.DecodeHeightmap(bytes.NewReader(body))
+}
+
+type upsampleParams struct {
+	format      string
+	width       int
+	height      int
+	heightScale float64
+	detail      float64
+	steepness   float64
+}
+
+func validateUpsampleParams(params url.Values, noiseParams queryParams) (response upsampleParams, err error) {
+	format := params.Get("format")
+	width := params.Get("width")
+	height := params.Get("height")
+	heightScale := params.Get("heightScale")
+	detail := params.Get("detail")
+	steepness := params.Get("steepness")
+
+	if len(noiseParams.from) != 2 {
+		return upsampleParams{}, errors.New("Upsampling requires a 2 dimensional From and To")
+	}
+
+	response.format = "png"
+	if format != "" {
+		if format != "png" && format != "raw" {
+			return upsampleParams{}, errors.New("Format must be one of png or raw")
+		}
+		response.format = format
+	}
+
+	// Raw heightmaps don't record their size, so it has to be given
+	if response.format == "raw" {
+		response.width, err = strconv.Atoi(width)
+		if err != nil || response.width < 1 {
+			return upsampleParams{}, errors.New("Width and Height must be positive integers for raw heightmaps")
+		}
+		response.height, err = strconv.Atoi(height)
+		if err != nil || response.height < 1 {
+			return upsampleParams{}, errors.New("Width and Height must be positive integers for raw heightmaps")
+		}
+		// Dividing rather than multiplying keeps huge sizes from overflowing past the limit
+		if response.width > maxImportSamples/response.height {
+			return upsampleParams{}, fmt.Errorf("Heightmaps are limited to %d pixels", maxImportSamples)
+		}
+	}
+
+	response.heightScale = 1
+	if heightScale != "" {
+		if response.heightScale, err = strconv.ParseFloat(heightScale, 64); err != nil {
+			return upsampleParams{}, errors.New("HeightScale must be a number")
+		}
+	}
+
+	response.detail = 0.05
+	if detail != "" {
+		if response.detail, err = strconv.ParseFloat(detail, 64); err != nil || response.detail < 0 {
+			return upsampleParams{}, errors.New("Detail must be a non negative number")
+		}
+	}
+
+	response.steepness = 1
+	if steepness != "" {
+		if response.steepness, err = strconv.ParseFloat(steepness, 64); err != nil || response.steepness <= 0 {
+			return upsampleParams{}, errors.New("Steepness must be a positive number")
+		}
+	}
+
+	return response, nil
+}
@@ -0,0 +1,157 @@
+package http_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	stdImage "image"
+	"image/color"
+	"image/png"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	tghttp "github.com/bcokert/terragen/http"
+	"github.com/bcokert/terragen/noise"
+)
+
+func TestHandleUpsample(t *testing.T) {
+	// A 4x4 ramp that rises along x, as a PNG and as raw floats
+	img := stdImage.NewGray16(stdImage.Rect(0, 0, 4, 4))
+	raw := &bytes.Buffer{}
+	for y := 0; y < 4; y++ {
+		for x := 0; x < 4; x++ {
+			img.SetGray16(x, y, color.Gray16{Y: uint16(x * 65535 / 3)})
+			binary.Write(raw, binary.LittleEndian, float32(x)/3)
+		}
+	}
+	encoded := &bytes.Buffer{}
+	png.Encode(encoded, img)
+
+	testCases := map[string]struct {
+		Query              string
+		Body               []byte
+		ExpectedStatusCode int
+		ExpectedErrorBody  string
+		ExpectedSamples    int
+	}{
+		"png": {
+			Query:              "from=0,0&to=2,2&resolution=8&seed=42&detail=0",
+			Body:               encoded.Bytes(),
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedSamples:    256,
+		},
+		"raw": {
+			Query:              "from=0,0&to=2,2&resolution=8&seed=42&detail=0&format=raw&width=4&height=4",
+			Body:               raw.Bytes(),
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedSamples:    256,
+		},
+		"detail": {
+			Query:              "from=0,0&to=3,1&resolution=4&seed=42&detail=0.2&steepness=0.5&heightScale=2",
+			Body:               encoded.Bytes(),
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedSamples:    48,
+		},
+		"invalid png": {
+			Query:              "seed=42",
+			Body:               []byte("not a png"),
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid body: (image: unknown format)"}`,
+		},
+		"wrong raw size": {
+			Query:              "seed=42&format=raw&width=3&height=4",
+			Body:               raw.Bytes(),
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid body: (Raw heightmaps must have a little endian float32 for each of their width * height samples)"}`,
+		},
+		"overflowing raw size": {
+			Query:              "seed=42&format=raw&width=4294967296&height=4294967296",
+			Body:               []byte{},
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (Heightmaps are limited to 4194304 pixels)"}`,
+		},
+		"missing raw size": {
+			Query:              "seed=42&format=raw",
+			Body:               raw.Bytes(),
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (Width and Height must be positive integers for raw heightmaps)"}`,
+		},
+		"invalid format": {
+			Query:              "seed=42&format=tiff",
+			Body:               encoded.Bytes(),
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (Format must be one of png or raw)"}`,
+		},
+		"invalid steepness": {
+			Query:              "seed=42&steepness=0",
+			Body:               encoded.Bytes(),
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (Steepness must be a positive number)"}`,
+		},
+		"1d": {
+			Query:              "from=0&to=2&seed=42",
+			Body:               encoded.Bytes(),
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (Upsampling requires a 2 dimensional From and To)"}`,
+		},
+	}
+
+	for name, tc := range testCases {
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest(http.MethodPost, fmt.Sprintf("/upsample?%s", tc.Query), bytes.NewReader(tc.Body))
+		tghttp.HandleUpsample()(w, r, nil)
+
+		if w.Code != tc.ExpectedStatusCode {
+			t.Errorf("'%s' failed. Expected status code %d, received %d", name, tc.ExpectedStatusCode, w.Code)
+			t.Logf("Response: %s", w.Body.String())
+			continue
+		}
+
+		if tc.ExpectedErrorBody != "" {
+			if w.Body.String() != tc.ExpectedErrorBody {
+				t.Errorf("'%s' failed. Expected error response '%s', received '%s'", name, tc.ExpectedErrorBody, w.Body.String())
+			}
+			continue
+		}
+
+		result := noise.Noise{}
+		if err := json.NewDecoder(w.Body).Decode(&result); err != nil {
+			t.Errorf("'%s' failed. Failed to decode response: %s", name, w.Body.String())
+			continue
+		}
+		if len(result.Values) != tc.ExpectedSamples {
+			t.Errorf("'%s' failed. Expected %d samples, received %d", name, tc.ExpectedSamples, len(result.Values))
+		}
+	}
+}
+
+func TestHandleUpsample_Ramp(t *testing.T) {
+	// Without detail, a ramp is upsampled into the same ramp, stretched over the range
+	raw := &bytes.Buffer{}
+	for y := 0; y < 3; y++ {
+		for x := 0; x < 3; x++ {
+			binary.Write(raw, binary.LittleEndian, float32(x))
+		}
+	}
+
+	w := httptest.NewRecorder()
+	r, _ := http.NewRequest(http.MethodPost, "/upsample?from=0,0&to=4,4&resolution=2&seed=42&detail=0&format=raw&width=3&height=3", raw)
+	tghttp.HandleUpsample()(w, r, nil)
+
+	result := noise.Noise{}
+	if err := json.NewDecoder(w.Body).Decode(&result); err != nil {
+		t.Fatalf("Failed to decode response: %s", w.Body.String())
+	}
+
+	for x := 0; x < 8; x++ {
+		for y := 0; y < 8; y++ {
+			expected := float64(x) / 2 / 2
+			if value := result.Values[x*8+y]; math.Abs(value-expected) > 1e-9 {
+				t.Errorf("Expected %v at %d, %d, received %v", expected, x, y, value)
+			}
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package http
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/bcokert/terragen/log"
+	"github.com/bcokert/terragen/volume"
+	"github.com/julienschmidt/httprouter"
+)
+
+// maxVolumeSamples limits the size of the grid HandleVolume samples, since it grows with the cube of the resolution
+const maxVolumeSamples = 1 << 21
+
+// HandleVolume generates 3D density from noise and a height gradient, and responds with its surface as a downloadable mesh. It is an idempotent call
+// Unlike a heightfield, the surface can have caves and overhangs
+func HandleVolume() httprouter.Handle {
+	return Handle(func(response http.ResponseWriter, request *http.Request, _ httprouter.Params) (interface{}, int) {
+		log.Info("Request Started: %s %s", request.Method, request.URL.String())
+
+		// Validate the params, and get the related data
+		params, err := validateNoiseParams(request.URL.Query())
+		if err != nil {
+			return fmt.Errorf("Invalid param: (%s)", err.Error()), http.StatusBadRequest
+		}
+
+		volumeParams, err := validateVolumeParams(request.URL.Query(), params)
+		if err != nil {
+			return fmt.Errorf("Invalid param: (%s)", err.Error()), http.StatusBadRequest
+		}
+
+		log.Info("Generating volume with the following params: %+v", params)
+		density := volume.HeightDensity(presetFunction(params), volumeParams.groundLevel, volumeParams.gradient)
+		from := [3]int{params.from[0], params.from[1], params.from[2]}
+		to := [3]int{params.to[0], params.to[1], params.to[2]}
+		grid, err := volume.NewGrid(density, from, to, params.resolution)
+		if err != nil {
+			return fmt.Errorf("Failed to sample volume: (%s)", err.Error()), http.StatusBadRequest
+		}
+
+		surface, err := volume.Extract(grid, volumeParams.options)
+		if err != nil {
+			return fmt.Errorf("Failed to build mesh: (%s)", err.Error()), http.StatusBadRequest
+		}
+
+		buffer := &bytes.Buffer{}
+		if err := volumeParams.format.write(buffer, surface); err != nil {
+			return fmt.Errorf("Failed to encode mesh: (%s)", err.Error()), http.StatusInternalServerError
+		}
+
+		response.Header().Add("Content-Type", volumeParams.format.contentType)
+		response.Header().Add("Content-Disposition", fmt.Sprintf(`attachment; filename="volume.%s"`, volumeParams.format.extension))
+		response.Write(buffer.Bytes())
+		return nil, http.StatusOK
+	})
+}
+
+type volumeParams struct {
+	format      meshFormat
+	groundLevel float64
+	gradient    float64
+	options     volume.Options
+}
+
+func validateVolumeParams(params url.Values, noiseParams queryParams) (response volumeParams, err error) {
+	format := params.Get("format")
+	groundLevel := params.Get("groundLevel")
+	gradient := params.Get("gradient")
+	isoLevel := params.Get("isoLevel")
+	closed := params.Get("closed")
+
+	if len(noiseParams.from) != 3 {
+		return volumeParams{}, errors.New("Volumes require a 3 dimensional From and To")
+	}
+
+	samples := 1
+	for i := range noiseParams.from {
+		samples *= (noiseParams.to[i]-noiseParams.from[i])*noiseParams.resolution + 1
+	}
+	if samples > maxVolumeSamples {
+		return volumeParams{}, fmt.Errorf("Volumes are limited to %d samples, so reduce the range or resolution", maxVolumeSamples)
+	}
+
+	response.format = meshFormats["glb"]
+	if format != "" {
+		var ok bool
+		if response.format, ok = meshFormats[format]; !ok {
+			return volumeParams{}, errors.New("Format must be one of obj, stl or glb")
+		}
+	}
+
+	// The ground defaults to halfway up the volume, so there is room for both caves and overhangs
+	response.groundLevel = float64(noiseParams.from[2]+noiseParams.to[2]) / 2
+	if groundLevel != "" {
+		if response.groundLevel, err = strconv.ParseFloat(groundLevel, 64); err != nil {
+			return volumeParams{}, errors.New("GroundLevel must be a number")
+		}
+	}
+
+	response.gradient = 1
+	if gradient != "" {
+		if response.gradient, err = strconv.ParseFloat(gradient, 64); err != nil || response.gradient < 0 {
+			return volumeParams{}, errors.New("Gradient must be a non negative number")
+		}
+	}
+
+	response.options = volume.NewDefaultOptions()
+	if isoLevel != "" {
+		if response.options.IsoLevel, err = strconv.ParseFloat(isoLevel, 64); err != nil {
+			return volumeParams{}, errors.New("IsoLevel must be a number")
+		}
+	}
+	if closed != "" {
+		if response.options.Closed, err = strconv.ParseBool(closed); err != nil {
+			return volumeParams{}, errors.New("Closed must be true or false")
+		}
+	}
+
+	return response, nil
+}
@@ -0,0 +1,108 @@
+package http_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	tghttp "github.com/bcokert/terragen/http"
+)
+
+func TestHandleVolume(t *testing.T) {
+	testCases := map[string]struct {
+		Query               string
+		ExpectedStatusCode  int
+		ExpectedErrorBody   string
+		ExpectedContentType string
+		ExpectedFilename    string
+		ExpectedPrefix      string
+	}{
+		"defaults": {
+			Query:               "from=0,0,0&to=2,2,2&resolution=4&seed=42",
+			ExpectedStatusCode:  http.StatusOK,
+			ExpectedContentType: "model/gltf-binary",
+			ExpectedFilename:    "volume.glb",
+			ExpectedPrefix:      "glTF",
+		},
+		"obj": {
+			Query:               "from=0,0,0&to=2,2,2&resolution=4&seed=42&format=obj&groundLevel=0.5&gradient=0.5&isoLevel=0.1&closed=false",
+			ExpectedStatusCode:  http.StatusOK,
+			ExpectedContentType: "model/obj",
+			ExpectedFilename:    "volume.obj",
+			ExpectedPrefix:      "# terragen",
+		},
+		"stl": {
+			Query:               "from=0,0,0&to=1,1,1&resolution=4&seed=42&format=stl",
+			ExpectedStatusCode:  http.StatusOK,
+			ExpectedContentType: "model/stl",
+			ExpectedFilename:    "volume.stl",
+			ExpectedPrefix:      "terragen",
+		},
+		"2d": {
+			Query:              "from=0,0&to=2,2&seed=42",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (Volumes require a 3 dimensional From and To)"}`,
+		},
+		"too many samples": {
+			Query:              "from=0,0,0&to=10,10,10&resolution=20&seed=42",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (Volumes are limited to 2097152 samples, so reduce the range or resolution)"}`,
+		},
+		"invalid format": {
+			Query:              "from=0,0,0&to=1,1,1&seed=42&format=fbx",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (Format must be one of obj, stl or glb)"}`,
+		},
+		"invalid ground level": {
+			Query:              "from=0,0,0&to=1,1,1&seed=42&groundLevel=low",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (GroundLevel must be a number)"}`,
+		},
+		"negative gradient": {
+			Query:              "from=0,0,0&to=1,1,1&seed=42&gradient=-1",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (Gradient must be a non negative number)"}`,
+		},
+		"invalid iso level": {
+			Query:              "from=0,0,0&to=1,1,1&seed=42&isoLevel=surface",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (IsoLevel must be a number)"}`,
+		},
+		"invalid closed": {
+			Query:              "from=0,0,0&to=1,1,1&seed=42&closed=banana",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (Closed must be true or false)"}`,
+		},
+	}
+
+	for name, tc := range testCases {
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest(http.MethodGet, fmt.Sprintf("/volume?%s", tc.Query), nil)
+		tghttp.HandleVolume()(w, r, nil)
+
+		if w.Code != tc.ExpectedStatusCode {
+			t.Errorf("'%s' failed. Expected status code %d, received %d", name, tc.ExpectedStatusCode, w.Code)
+			t.Logf("Response: %s", w.Body.String())
+			continue
+		}
+
+		if tc.ExpectedErrorBody != "" {
+			if w.Body.String() != tc.ExpectedErrorBody {
+				t.Errorf("'%s' failed. Expected error response '%s', received '%s'", name, tc.ExpectedErrorBody, w.Body.String())
+			}
+			continue
+		}
+
+		if contentType := w.Header().Get("Content-Type"); contentType != tc.ExpectedContentType {
+			t.Errorf("'%s' failed. Expected content type %s, received %s", name, tc.ExpectedContentType, contentType)
+		}
+		if disposition := w.Header().Get("Content-Disposition"); !strings.Contains(disposition, tc.ExpectedFilename) {
+			t.Errorf("'%s' failed. Expected a download named %s, received %s", name, tc.ExpectedFilename, disposition)
+		}
+		if !strings.HasPrefix(w.Body.String(), tc.ExpectedPrefix) {
+			t.Errorf("'%s' failed. Expected the body to start with %q", name, tc.ExpectedPrefix)
+		}
+	}
+}
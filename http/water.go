@@ -0,0 +1,70 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/bcokert/terragen/hydrology"
+	"github.com/bcokert/terragen/log"
+	"github.com/bcokert/terragen/noise"
+	"github.com/julienschmidt/httprouter"
+)
+
+// HandleWater generates 2D noise with the given params, and responds with it along with its ocean, lakes and coastlines. It is an idempotent call
+func HandleWater() httprouter.Handle {
+	return Handle(func(response http.ResponseWriter, request *http.Request, _ httprouter.Params) (interface{}, int) {
+		log.Info("Request Started: %s %s", request.Method, request.URL.String())
+
+		// Validate the params, and get the related data
+		params, err := validateNoiseParams(request.URL.Query())
+		if err != nil {
+			return fmt.Errorf("Invalid param: (%s)", err.Error()), http.StatusBadRequest
+		}
+
+		options, err := validateWaterParams(request.URL.Query(), params)
+		if err != nil {
+			return fmt.Errorf("Invalid param: (%s)", err.Error()), http.StatusBadRequest
+		}
+
+		noise := generateNoise(params)
+		water, err := hydrology.FindWater(noise, options)
+		if err != nil {
+			return fmt.Errorf("Failed to find water: (%s)", err.Error()), http.StatusInternalServerError
+		}
+
+		return waterResponse{Noise: noise, Water: water}, http.StatusOK
+	})
+}
+
+type waterResponse struct {
+	Noise *noise.Noise     `json:"noise"`
+	Water *hydrology.Water `json:"water"`
+}
+
+func validateWaterParams(params url.Values, noiseParams queryParams) (options hydrology.WaterOptions, err error) {
+	seaLevel := params.Get("seaLevel")
+	minLakeDepth := params.Get("minLakeDepth")
+
+	if len(noiseParams.from) != 2 {
+		return hydrology.WaterOptions{}, errors.New("Water requires a 2 dimensional From and To")
+	}
+
+	options = hydrology.NewDefaultWaterOptions()
+
+	if seaLevel != "" {
+		if options.SeaLevel, err = strconv.ParseFloat(seaLevel, 64); err != nil {
+			return hydrology.WaterOptions{}, errors.New("SeaLevel must be a number")
+		}
+	}
+
+	if minLakeDepth != "" {
+		if options.MinLakeDepth, err = strconv.ParseFloat(minLakeDepth, 64); err != nil || options.MinLakeDepth < 0 {
+			return hydrology.WaterOptions{}, errors.New("MinLakeDepth must be a non negative number")
+		}
+	}
+
+	return options, nil
+}
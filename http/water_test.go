@@ -0,0 +1,87 @@
+package http_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	tghttp "github.com/bcokert/terragen/http"
+)
+
+func TestHandleWater(t *testing.T) {
+	testCases := map[string]struct {
+		Query              string
+		ExpectedStatusCode int
+		ExpectedErrorBody  string
+		ExpectedSamples    int
+	}{
+		"defaults": {
+			Query:              "seed=42",
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedSamples:    10000,
+		},
+		"island": {
+			Query:              "from=0,0&to=4,4&resolution=8&seed=42&mask=radial&maskMode=subtract&seaLevel=-0.2&minLakeDepth=0.01",
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedSamples:    1024,
+		},
+		"1d": {
+			Query:              "from=0&to=2&seed=42",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (Water requires a 2 dimensional From and To)"}`,
+		},
+		"invalid sea level": {
+			Query:              "seed=42&seaLevel=high",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (SeaLevel must be a number)"}`,
+		},
+		"negative lake depth": {
+			Query:              "seed=42&minLakeDepth=-1",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (MinLakeDepth must be a non negative number)"}`,
+		},
+	}
+
+	for name, tc := range testCases {
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest(http.MethodGet, fmt.Sprintf("/water?%s", tc.Query), nil)
+		tghttp.HandleWater()(w, r, nil)
+
+		if w.Code != tc.ExpectedStatusCode {
+			t.Errorf("'%s' failed. Expected status code %d, received %d", name, tc.ExpectedStatusCode, w.Code)
+			t.Logf("Response: %s", w.Body.String())
+			continue
+		}
+
+		if tc.ExpectedErrorBody != "" {
+			if w.Body.String() != tc.ExpectedErrorBody {
+				t.Errorf("'%s' failed. Expected error response '%s', received '%s'", name, tc.ExpectedErrorBody, w.Body.String())
+			}
+			continue
+		}
+
+		var result struct {
+			Water struct {
+				OceanMask  []float64 `json:"oceanMask"`
+				LakeMask   []float64 `json:"lakeMask"`
+				LakeLabels []int     `json:"lakeLabels"`
+				Coastlines struct {
+					Type string `json:"type"`
+				} `json:"coastlines"`
+			} `json:"water"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+			t.Errorf("'%s' failed. Expected a water response, received '%s'", name, w.Body.String())
+			continue
+		}
+
+		if len(result.Water.OceanMask) != tc.ExpectedSamples || len(result.Water.LakeMask) != tc.ExpectedSamples || len(result.Water.LakeLabels) != tc.ExpectedSamples {
+			t.Errorf("'%s' failed. Expected %d samples in each channel", name, tc.ExpectedSamples)
+		}
+		if result.Water.Coastlines.Type != "FeatureCollection" {
+			t.Errorf("'%s' failed. Expected coastlines to be a FeatureCollection, received '%s'", name, result.Water.Coastlines.Type)
+		}
+	}
+}
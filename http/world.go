@@ -0,0 +1,173 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/bcokert/terragen/brush"
+	"github.com/bcokert/terragen/log"
+	"github.com/bcokert/terragen/world"
+	"github.com/julienschmidt/httprouter"
+)
+
+// maxStrokeBytes limits the size of strokes posted to HandleAddStroke
+const maxStrokeBytes = 1 << 16
+
+// queryOverrides are the params of a saved query that can be changed when generating it, so it can be viewed at any range and resolution
+var queryOverrides = []string{"from", "to", "resolution"}
+
+// HandleCreateWorld saves the noise params as a new world, with no strokes, and responds with it
+// Worlds are painted on a plane, so they must be 2 dimensional
+func HandleCreateWorld(store world.Store) httprouter.Handle {
+	return Handle(func(response http.ResponseWriter, request *http.Request, _ httprouter.Params) (interface{}, int) {
+		log.Info("Request Started: %s %s", request.Method, request.URL.String())
+
+		// Validate the params, so only worlds that can be generated are saved
+		params, err := validateWorldParams(request.URL.Query())
+		if err != nil {
+			return fmt.Errorf("Invalid param: (%s)", err.Error()), http.StatusBadRequest
+		}
+
+		// The seed is saved even if it was generated, so the world is the same each time it's generated
+		query := request.URL.Query()
+		query.Set("seed", strconv.FormatInt(params.seed, 10))
+
+		created, err := store.Create(query.Encode())
+		if err != nil {
+			return fmt.Errorf("Failed to create world: (%s)", err.Error()), http.StatusInternalServerError
+		}
+		return created, http.StatusCreated
+	})
+}
+
+// HandleWorld responds with the world with the id in the path. It is an idempotent call
+func HandleWorld(store world.Store) httprouter.Handle {
+	return Handle(func(response http.ResponseWriter, request *http.Request, p httprouter.Params) (interface{}, int) {
+		log.Info("Request Started: %s %s", request.Method, request.URL.String())
+
+		saved, err := store.Get(p.ByName("id"))
+		if err != nil {
+			return worldError(err)
+		}
+		return saved, http.StatusOK
+	})
+}
+
+// HandleStrokes responds with the strokes of the world with the id in the path, in the order they are applied. It is an idempotent call
+func HandleStrokes(store world.Store) httprouter.Handle {
+	return Handle(func(response http.ResponseWriter, request *http.Request, p httprouter.Params) (interface{}, int) {
+		log.Info("Request Started: %s %s", request.Method, request.URL.String())
+
+		saved, err := store.Get(p.ByName("id"))
+		if err != nil {
+			return worldError(err)
+		}
+		return saved.Strokes, http.StatusOK
+	})
+}
+
+// HandleAddStroke adds the stroke posted as the JSON body on top of the strokes of the world with the id in the path, and responds with the world
+func HandleAddStroke(store world.Store) httprouter.Handle {
+	return Handle(func(response http.ResponseWriter, request *http.Request, p httprouter.Params) (interface{}, int) {
+		log.Info("Request Started: %s %s", request.Method, request.URL.String())
+
+		body, err := ioutil.ReadAll(http.MaxBytesReader(response, request.Body, maxStrokeBytes))
+		if err != nil {
+			return fmt.Errorf("Invalid body: (%s)", err.Error()), http.StatusBadRequest
+		}
+		stroke := brush.Stroke{}
+		if err := json.Unmarshal(body, &stroke); err != nil {
+			return fmt.Errorf("Invalid body: (%s)", err.Error()), http.StatusBadRequest
+		}
+		if err := stroke.Validate(); err != nil {
+			return fmt.Errorf("Invalid body: (%s)", err.Error()), http.StatusBadRequest
+		}
+
+		updated, err := store.AddStroke(p.ByName("id"), stroke)
+		if err != nil {
+			return worldError(err)
+		}
+		return updated, http.StatusOK
+	})
+}
+
+// HandleUndoStroke removes the most recent stroke of the world with the id in the path, and responds with the world
+func HandleUndoStroke(store world.Store) httprouter.Handle {
+	return Handle(func(response http.ResponseWriter, request *http.Request, p httprouter.Params) (interface{}, int) {
+		log.Info("Request Started: %s %s", request.Method, request.URL.String())
+
+		updated, err := store.Undo(p.ByName("id"))
+		if err != nil {
+			return worldError(err)
+		}
+		return updated, http.StatusOK
+	})
+}
+
+// HandleWorldNoise generates the world with the id in the path, with its strokes applied on top of its preset. It is an idempotent call
+// The from, to and resolution params override the world's own, and any other params are ignored
+// Strokes wrap the preset's function, so they are the same at any range and resolution
+func HandleWorldNoise(store world.Store) httprouter.Handle {
+	return Handle(func(response http.ResponseWriter, request *http.Request, p httprouter.Params) (interface{}, int) {
+		log.Info("Request Started: %s %s", request.Method, request.URL.String())
+
+		saved, err := store.Get(p.ByName("id"))
+		if err != nil {
+			return worldError(err)
+		}
+
+		query, err := overrideQuery(saved.Query, request.URL.Query())
+		if err != nil {
+			return fmt.Errorf("Failed to read world: (%s)", err.Error()), http.StatusInternalServerError
+		}
+
+		params, err := validateWorldParams(query)
+		if err != nil {
+			return fmt.Errorf("Invalid param: (%s)", err.Error()), http.StatusBadRequest
+		}
+
+		return generateNoiseFrom(params, saved.Strokes.Apply(presetFunction(params))), http.StatusOK
+	})
+}
+
+// overrideQuery decodes a saved query, and replaces its range and resolution with any that are in the params
+func overrideQuery(saved string, params url.Values) (url.Values, error) {
+	query, err := url.ParseQuery(saved)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range queryOverrides {
+		if value := params.Get(name); value != "" {
+			query.Set(name, value)
+		}
+	}
+	return query, nil
+}
+
+// worldError responds with a not found for missing worlds, a bad request for changes the world can't take, and an internal error if the store failed
+func worldError(err error) (interface{}, int) {
+	switch err {
+	case world.ErrNotFound:
+		return err, http.StatusNotFound
+	case world.ErrNothingToUndo, world.ErrTooManyStrokes:
+		return err, http.StatusBadRequest
+	}
+	return fmt.Errorf("Failed to access worlds: (%s)", err.Error()), http.StatusInternalServerError
+}
+
+// validateWorldParams validates the noise params of a world, which must be 2 dimensional since strokes are painted on a plane
+func validateWorldParams(params url.Values) (queryParams, error) {
+	noiseParams, err := validateNoiseParams(params)
+	if err != nil {
+		return queryParams{}, err
+	}
+	if len(noiseParams.from) != 2 {
+		return queryParams{}, errors.New("Worlds require a 2 dimensional From and To")
+	}
+	return noiseParams, nil
+}
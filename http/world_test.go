@@ -0,0 +1,248 @@
+package http_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/bcokert/terragen/brush"
+	tghttp "github.com/bcokert/terragen/http"
+	"github.com/bcokert/terragen/noise"
+	"github.com/bcokert/terragen/world"
+	"github.com/julienschmidt/httprouter"
+)
+
+func TestHandleCreateWorld(t *testing.T) {
+	testCases := map[string]struct {
+		Query              string
+		ExpectedStatusCode int
+		ExpectedErrorBody  string
+	}{
+		"seeded": {
+			Query:              "from=0,0&to=2,2&resolution=4&seed=42&noiseFunction=red",
+			ExpectedStatusCode: http.StatusCreated,
+		},
+		"unseeded": {
+			Query:              "from=0,0&to=2,2&resolution=4",
+			ExpectedStatusCode: http.StatusCreated,
+		},
+		"1d": {
+			Query:              "from=0&to=2&seed=42",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (Worlds require a 2 dimensional From and To)"}`,
+		},
+		"invalid preset": {
+			Query:              "seed=42&noiseFunction=nope",
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedErrorBody:  `{"error": "Invalid param: (NoiseFunction must be a valid preset)"}`,
+		},
+	}
+
+	for name, tc := range testCases {
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest(http.MethodPost, fmt.Sprintf("/worlds?%s", tc.Query), nil)
+		tghttp.HandleCreateWorld(world.NewMemoryStore())(w, r, nil)
+
+		if w.Code != tc.ExpectedStatusCode {
+			t.Errorf("'%s' failed. Expected status code %d, received %d", name, tc.ExpectedStatusCode, w.Code)
+			t.Logf("Response: %s", w.Body.String())
+			continue
+		}
+
+		if tc.ExpectedErrorBody != "" {
+			if w.Body.String() != tc.ExpectedErrorBody {
+				t.Errorf("'%s' failed. Expected error response '%s', received '%s'", name, tc.ExpectedErrorBody, w.Body.String())
+			}
+			continue
+		}
+
+		created := world.World{}
+		if err := json.NewDecoder(w.Body).Decode(&created); err != nil {
+			t.Errorf("'%s' failed. Failed to decode response: %s", name, w.Body.String())
+			continue
+		}
+		// Generated seeds are saved with the world
+		if query, _ := url.ParseQuery(created.Query); created.ID == "" || query.Get("seed") == "" || len(created.Strokes) != 0 {
+			t.Errorf("'%s' failed. Expected a new seeded world with no strokes, received %+v", name, created)
+		}
+	}
+}
+
+func TestHandleWorlds_Strokes(t *testing.T) {
+	store := world.NewMemoryStore()
+	created, _ := store.Create("from=0,0&to=2,2&resolution=4&seed=42")
+	params := httprouter.Params{{Key: "id", Value: created.ID}}
+	missing := httprouter.Params{{Key: "id", Value: "missing"}}
+
+	testCases := []struct {
+		Name               string
+		Handler            httprouter.Handle
+		Params             httprouter.Params
+		Body               string
+		ExpectedStatusCode int
+		ExpectedBody       string
+	}{
+		{
+			Name:               "add",
+			Handler:            tghttp.HandleAddStroke(store),
+			Params:             params,
+			Body:               `{"position": [1, 1], "radius": 1, "falloff": 0.5, "operation": "raise", "strength": 2}`,
+			ExpectedStatusCode: http.StatusOK,
+		},
+		{
+			Name:               "add another",
+			Handler:            tghttp.HandleAddStroke(store),
+			Params:             params,
+			Body:               `{"position": [0, 1], "radius": 2, "operation": "flatten", "strength": 0.5}`,
+			ExpectedStatusCode: http.StatusOK,
+		},
+		{
+			Name:               "list",
+			Handler:            tghttp.HandleStrokes(store),
+			Params:             params,
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedBody:       `[{"position":[1,1],"radius":1,"falloff":0.5,"operation":"raise","strength":2},{"position":[0,1],"radius":2,"falloff":0,"operation":"flatten","strength":0.5}]`,
+		},
+		{
+			Name:               "undo",
+			Handler:            tghttp.HandleUndoStroke(store),
+			Params:             params,
+			ExpectedStatusCode: http.StatusOK,
+		},
+		{
+			Name:               "get",
+			Handler:            tghttp.HandleWorld(store),
+			Params:             params,
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedBody:       fmt.Sprintf(`{"id":"%s","query":"from=0,0\u0026to=2,2\u0026resolution=4\u0026seed=42","strokes":[{"position":[1,1],"radius":1,"falloff":0.5,"operation":"raise","strength":2}]}`, created.ID),
+		},
+		{
+			Name:               "invalid stroke",
+			Handler:            tghttp.HandleAddStroke(store),
+			Params:             params,
+			Body:               `{"position": [1, 1], "radius": 1, "operation": "carve", "strength": 2}`,
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedBody:       `{"error": "Invalid body: (Operation must be one of raise, lower, flatten or smooth)"}`,
+		},
+		{
+			Name:               "malformed stroke",
+			Handler:            tghttp.HandleAddStroke(store),
+			Params:             params,
+			Body:               `{"position": "here"}`,
+			ExpectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			Name:               "undo the last",
+			Handler:            tghttp.HandleUndoStroke(store),
+			Params:             params,
+			ExpectedStatusCode: http.StatusOK,
+		},
+		{
+			Name:               "undo nothing",
+			Handler:            tghttp.HandleUndoStroke(store),
+			Params:             params,
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedBody:       `{"error": "World has no strokes to undo"}`,
+		},
+		{
+			Name:               "missing",
+			Handler:            tghttp.HandleStrokes(store),
+			Params:             missing,
+			ExpectedStatusCode: http.StatusNotFound,
+			ExpectedBody:       `{"error": "World not found"}`,
+		},
+		{
+			Name:               "add to missing",
+			Handler:            tghttp.HandleAddStroke(store),
+			Params:             missing,
+			Body:               `{"position": [1, 1], "radius": 1, "operation": "raise", "strength": 2}`,
+			ExpectedStatusCode: http.StatusNotFound,
+			ExpectedBody:       `{"error": "World not found"}`,
+		},
+	}
+
+	// The steps share a store, so they're run in order
+	for _, tc := range testCases {
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest(http.MethodPost, "/worlds", bytes.NewBufferString(tc.Body))
+		tc.Handler(w, r, tc.Params)
+
+		if w.Code != tc.ExpectedStatusCode {
+			t.Errorf("'%s' failed. Expected status code %d, received %d", tc.Name, tc.ExpectedStatusCode, w.Code)
+			t.Logf("Response: %s", w.Body.String())
+			continue
+		}
+		if tc.ExpectedBody != "" && w.Body.String() != tc.ExpectedBody {
+			t.Errorf("'%s' failed. Expected response '%s', received '%s'", tc.Name, tc.ExpectedBody, w.Body.String())
+		}
+	}
+}
+
+func TestHandleWorldNoise(t *testing.T) {
+	store := world.NewMemoryStore()
+	created, _ := store.Create("from=0,0&to=4,4&resolution=2&seed=42&noiseFunction=red")
+	store.AddStroke(created.ID, brush.Stroke{Position: [2]float64{2, 2}, Radius: 0.5, Operation: brush.Raise, Strength: 10})
+	params := httprouter.Params{{Key: "id", Value: created.ID}}
+
+	generate := func(query string) *noise.Noise {
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest(http.MethodGet, fmt.Sprintf("/worlds/%s/noise?%s", created.ID, query), nil)
+		tghttp.HandleWorldNoise(store)(w, r, params)
+
+		result := noise.Noise{}
+		if err := json.NewDecoder(w.Body).Decode(&result); err != nil {
+			t.Fatalf("Failed to decode response for '%s': %s", query, w.Body.String())
+		}
+		return &result
+	}
+
+	// The same world without its stroke, to compare against
+	w := httptest.NewRecorder()
+	r, _ := http.NewRequest(http.MethodGet, "/noise?from=0,0&to=4,4&resolution=2&seed=42&noiseFunction=red", nil)
+	tghttp.HandleNoise()(w, r, nil)
+	original := noise.Noise{}
+	json.NewDecoder(w.Body).Decode(&original)
+
+	painted := generate("")
+	if len(painted.Values) != len(original.Values) {
+		t.Fatalf("Expected %d samples, received %d", len(original.Values), len(painted.Values))
+	}
+	for i := range painted.Values {
+		x, y := float64(i/8)/2, float64(i%8)/2
+		expected := original.Values[i]
+		if math.Hypot(x-2, y-2) < 0.5 {
+			expected += 10
+		}
+		if math.Abs(painted.Values[i]-expected) > 1e-9 {
+			t.Errorf("Expected sample (%v, %v) to be %v, received %v", x, y, expected, painted.Values[i])
+		}
+	}
+
+	// Zooming in keeps the stroke where it was painted, while other params like the seed can't be overridden
+	zoomed := generate("from=2,2&to=3,3&resolution=4&seed=7")
+	if len(zoomed.Values) != 16 {
+		t.Fatalf("Expected 16 samples, received %d", len(zoomed.Values))
+	}
+	if math.Abs(zoomed.Values[0]-painted.Values[4*8+4]) > 1e-9 {
+		t.Errorf("Expected the zoomed stroke to match the original at (2, 2), received %v and %v", zoomed.Values[0], painted.Values[4*8+4])
+	}
+
+	w = httptest.NewRecorder()
+	r, _ = http.NewRequest(http.MethodGet, "/worlds/missing/noise", nil)
+	tghttp.HandleWorldNoise(store)(w, r, httprouter.Params{{Key: "id", Value: "missing"}})
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected a missing world to not be found, received %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	r, _ = http.NewRequest(http.MethodGet, "/worlds/1/noise?from=0&to=1", nil)
+	tghttp.HandleWorldNoise(store)(w, r, params)
+	if w.Code != http.StatusBadRequest || w.Body.String() != `{"error": "Invalid param: (Worlds require a 2 dimensional From and To)"}` {
+		t.Errorf("Expected an invalid override to be rejected, received %d %s", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,109 @@
+package hydrology
+
+import (
+	"container/heap"
+	"math"
+)
+
+// neighborOffsets are the (x, y) offsets of the 8 neighbours of a sample, counter-clockwise from east
+var neighborOffsets = [8][2]int{{1, 0}, {1, 1}, {0, 1}, {-1, 1}, {-1, 0}, {-1, -1}, {0, -1}, {1, -1}}
+
+// Fill fills the depressions of a width x height heightfield with Priority-Flood, raising every sample that can't drain off an edge
+// up to the height it would spill at
+// Filled samples are raised the smallest representable amount above the sample they spill into, so every sample away from the edges
+// has a strictly lower neighbour and flow is defined across what would otherwise be flat lakes
+func Fill(heights []float64, width, height int) []float64 {
+	return priorityFlood(heights, width, height, neighborOffsets[:], func(spill float64) float64 {
+		return math.Nextafter(spill, math.Inf(1))
+	})
+}
+
+// SpillLevels returns the level water would pond at over each sample of a width x height heightfield, if it could only drain off an edge
+// Samples that aren't in a depression keep their own height, and every sample of a depression gets the height of the lowest point on its rim
+// Water only connects orthogonally, like the lakes of FindWater, so it can't spill diagonally between two samples of land
+func SpillLevels(heights []float64, width, height int) []float64 {
+	return priorityFlood(heights, width, height, orthogonalOffsets[:], func(spill float64) float64 {
+		return spill
+	})
+}
+
+// priorityFlood floods a heightfield inwards from its edges, always from the lowest sample reached so far, to the neighbours at the offsets
+// Samples reached from a sample at or above them are in a depression, and are raised to raise(the height they spill at)
+func priorityFlood(heights []float64, width, height int, offsets [][2]int, raise func(spill float64) float64) []float64 {
+	filled := append([]float64{}, heights...)
+	closed := make([]bool, len(heights))
+	open := &floodQueue{}
+
+	// The flood starts from the edges, which drain off the heightfield
+	for x := 0; x < width; x++ {
+		for y := 0; y < height; y++ {
+			if x == 0 || y == 0 || x == width-1 || y == height-1 {
+				index := x*height + y
+				closed[index] = true
+				heap.Push(open, floodCell{index: index, height: filled[index], order: open.pushed})
+			}
+		}
+	}
+
+	for open.Len() > 0 {
+		cell := heap.Pop(open).(floodCell)
+		x, y := cell.index/height, cell.index%height
+
+		for _, offset := range offsets {
+			nx, ny := x+offset[0], y+offset[1]
+			if nx < 0 || nx >= width || ny < 0 || ny >= height {
+				continue
+			}
+			neighbor := nx*height + ny
+			if closed[neighbor] {
+				continue
+			}
+			closed[neighbor] = true
+			if filled[neighbor] <= filled[cell.index] {
+				filled[neighbor] = raise(filled[cell.index])
+			}
+			heap.Push(open, floodCell{index: neighbor, height: filled[neighbor], order: open.pushed})
+		}
+	}
+
+	return filled
+}
+
+// floodCell is a sample waiting to be flooded from. order breaks ties between equal heights, so the flood is deterministic
+type floodCell struct {
+	index  int
+	height float64
+	order  int
+}
+
+// floodQueue is a min heap of cells by height, and implements heap.Interface
+type floodQueue struct {
+	cells  []floodCell
+	pushed int
+}
+
+func (queue *floodQueue) Len() int {
+	return len(queue.cells)
+}
+
+func (queue *floodQueue) Less(i, j int) bool {
+	if queue.cells[i].height != queue.cells[j].height {
+		return queue.cells[i].height < queue.cells[j].height
+	}
+	return queue.cells[i].order < queue.cells[j].order
+}
+
+func (queue *floodQueue) Swap(i, j int) {
+	queue.cells[i], queue.cells[j] = queue.cells[j], queue.cells[i]
+}
+
+func (queue *floodQueue) Push(cell interface{}) {
+	queue.cells = append(queue.cells, cell.(floodCell))
+	queue.pushed++
+}
+
+func (queue *floodQueue) Pop() interface{} {
+	cell := queue.cells[len(queue.cells)-1]
+	queue.cells = queue.cells[:len(queue.cells)-1]
+	return cell
+}
@@ -0,0 +1,149 @@
+package hydrology_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/bcokert/terragen/hydrology"
+)
+
+func TestFill(t *testing.T) {
+	testCases := map[string]struct {
+		Heights       []float64
+		Width, Height int
+		ExpectedFloor []float64
+	}{
+		"single pit": {
+			Heights: []float64{
+				5, 5, 5,
+				5, 1, 5,
+				5, 4, 5,
+			},
+			Width: 3, Height: 3,
+			ExpectedFloor: []float64{
+				5, 5, 5,
+				5, 4, 5,
+				5, 4, 5,
+			},
+		},
+		"draining": {
+			Heights: []float64{
+				5, 5, 5,
+				5, 3, 5,
+				5, 1, 5,
+			},
+			Width: 3, Height: 3,
+			ExpectedFloor: []float64{
+				5, 5, 5,
+				5, 3, 5,
+				5, 1, 5,
+			},
+		},
+		"wide lake": {
+			Heights: []float64{
+				9, 9, 9, 9,
+				9, 1, 2, 9,
+				9, 2, 1, 9,
+				9, 9, 6, 9,
+			},
+			Width: 4, Height: 4,
+			ExpectedFloor: []float64{
+				9, 9, 9, 9,
+				9, 6, 6, 9,
+				9, 6, 6, 9,
+				9, 9, 6, 9,
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		original := append([]float64{}, testCase.Heights...)
+		filled := hydrology.Fill(testCase.Heights, testCase.Width, testCase.Height)
+
+		for i := range filled {
+			// Filled samples sit a tiny amount above their spill height, so that they can drain
+			if filled[i] < testCase.ExpectedFloor[i] || filled[i]-testCase.ExpectedFloor[i] > 1e-9 {
+				t.Errorf("'%s' failed. Expected %v at %d, received %v", name, testCase.ExpectedFloor[i], i, filled[i])
+			}
+		}
+		for i := range original {
+			if testCase.Heights[i] != original[i] {
+				t.Errorf("'%s' failed. Expected the heights to be left unchanged", name)
+				break
+			}
+		}
+	}
+}
+
+func TestFill_AlwaysDrains(t *testing.T) {
+	width, height := 12, 9
+	heights := make([]float64, width*height)
+	for x := 0; x < width; x++ {
+		for y := 0; y < height; y++ {
+			heights[x*height+y] = math.Sin(float64(x)*1.3) * math.Cos(float64(y)*0.7)
+		}
+	}
+
+	filled := hydrology.Fill(heights, width, height)
+
+	for x := 1; x < width-1; x++ {
+		for y := 1; y < height-1; y++ {
+			lower := false
+			for dx := -1; dx <= 1; dx++ {
+				for dy := -1; dy <= 1; dy++ {
+					if filled[(x+dx)*height+y+dy] < filled[x*height+y] {
+						lower = true
+					}
+				}
+			}
+			if !lower {
+				t.Errorf("Expected (%d, %d) to have a strictly lower neighbour after filling", x, y)
+			}
+		}
+	}
+}
+
+func TestSpillLevels(t *testing.T) {
+	testCases := map[string]struct {
+		Heights  []float64
+		Expected []float64
+	}{
+		"depression": {
+			Heights: []float64{
+				9, 9, 9, 9,
+				9, 1, 2, 9,
+				9, 2, 1, 9,
+				9, 9, 6, 9,
+			},
+			Expected: []float64{
+				9, 9, 9, 9,
+				9, 6, 6, 9,
+				9, 6, 6, 9,
+				9, 9, 6, 9,
+			},
+		},
+		"diagonal gap": {
+			Heights: []float64{
+				2, 9, 9, 9,
+				9, 1, 9, 9,
+				9, 9, 9, 9,
+				9, 9, 9, 9,
+			},
+			Expected: []float64{
+				2, 9, 9, 9,
+				9, 9, 9, 9,
+				9, 9, 9, 9,
+				9, 9, 9, 9,
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		levels := hydrology.SpillLevels(testCase.Heights, 4, 4)
+		for i := range testCase.Expected {
+			if levels[i] != testCase.Expected[i] {
+				t.Errorf("'%s' failed. Expected %v at %d, received %v", name, testCase.Expected[i], i, levels[i])
+			}
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package hydrology
+
+import (
+	"math"
+	"sort"
+)
+
+// A FlowMethod decides how water leaves a sample for its lower neighbours
+type FlowMethod int
+
+const (
+	// D8 sends all of a sample's water to its single steepest downhill neighbour
+	D8 FlowMethod = iota
+	// DInfinity points flow in any direction down the steepest of the 8 triangular facets around a sample, splitting water between
+	// the two neighbours on either side of it (Tarboton, 1997)
+	DInfinity
+)
+
+// receivers are the up to two lower neighbours a sample drains into, and the fraction of its water each gets
+// Samples on the edges are outlets, and drain off the heightfield with no receivers
+// The dominant receiver, which gets the most water, is always first
+type receivers struct {
+	to       [2]int
+	fraction [2]float64
+}
+
+// none is the receivers of an outlet
+var none = receivers{to: [2]int{-1, -1}}
+
+// facets are the 8 triangular facets of D-infinity, as pairs of an orthogonal and a diagonal neighbour offset index
+var facets = [8][2]int{{0, 1}, {2, 1}, {2, 3}, {4, 3}, {4, 5}, {6, 5}, {6, 7}, {0, 7}}
+
+// flowDirections finds where each sample of a filled heightfield drains, using the given method
+// Filled heightfields always have a strictly lower neighbour for every sample that isn't an outlet
+func flowDirections(filled []float64, width, height int, spacing float64, method FlowMethod) []receivers {
+	directions := make([]receivers, len(filled))
+
+	for x := 0; x < width; x++ {
+		for y := 0; y < height; y++ {
+			index := x*height + y
+			if x == 0 || y == 0 || x == width-1 || y == height-1 {
+				directions[index] = none
+				continue
+			}
+
+			neighbor := func(offset int) int {
+				return (x+neighborOffsets[offset][0])*height + y + neighborOffsets[offset][1]
+			}
+
+			if method == DInfinity {
+				directions[index] = dInfinityDirection(filled, index, neighbor, spacing)
+			} else {
+				directions[index] = d8Direction(filled, index, neighbor, spacing)
+			}
+		}
+	}
+
+	return directions
+}
+
+// d8Direction returns the steepest downhill neighbour of an interior sample
+func d8Direction(filled []float64, index int, neighbor func(offset int) int, spacing float64) receivers {
+	direction := none
+	steepest := 0.0
+	for offset := range neighborOffsets {
+		distance := spacing
+		if offset%2 == 1 {
+			distance *= math.Sqrt2
+		}
+		if slope := (filled[index] - filled[neighbor(offset)]) / distance; slope > steepest {
+			steepest = slope
+			direction = receivers{to: [2]int{neighbor(offset), -1}, fraction: [2]float64{1, 0}}
+		}
+	}
+	return direction
+}
+
+// dInfinityDirection returns the neighbours on either side of the steepest facet of an interior sample, with their share of its water
+func dInfinityDirection(filled []float64, index int, neighbor func(offset int) int, spacing float64) receivers {
+	direction := none
+	steepest := 0.0
+	for _, facet := range facets {
+		orthogonal, diagonal := neighbor(facet[0]), neighbor(facet[1])
+		s1 := (filled[index] - filled[orthogonal]) / spacing
+		s2 := (filled[orthogonal] - filled[diagonal]) / spacing
+
+		// The angle of steepest descent within the facet, from the orthogonal towards the diagonal, clamped to the facet's edges
+		angle, slope := math.Atan2(s2, s1), math.Hypot(s1, s2)
+		if angle < 0 {
+			angle, slope = 0, s1
+		} else if angle > math.Pi/4 {
+			angle, slope = math.Pi/4, (filled[index]-filled[diagonal])/(spacing*math.Sqrt2)
+		}
+
+		if slope <= steepest {
+			continue
+		}
+		steepest = slope
+
+		toDiagonal := angle / (math.Pi / 4)
+		switch {
+		case toDiagonal == 0:
+			direction = receivers{to: [2]int{orthogonal, -1}, fraction: [2]float64{1, 0}}
+		case toDiagonal == 1:
+			direction = receivers{to: [2]int{diagonal, -1}, fraction: [2]float64{1, 0}}
+		case toDiagonal > 0.5:
+			direction = receivers{to: [2]int{diagonal, orthogonal}, fraction: [2]float64{toDiagonal, 1 - toDiagonal}}
+		default:
+			direction = receivers{to: [2]int{orthogonal, diagonal}, fraction: [2]float64{1 - toDiagonal, toDiagonal}}
+		}
+	}
+	return direction
+}
+
+// flowAccumulation returns the upstream area draining through each sample, including its own cellArea
+// Samples are visited from highest to lowest, so each has received all of its water before passing it on
+func flowAccumulation(filled []float64, directions []receivers, cellArea float64) []float64 {
+	order := make([]int, len(filled))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return filled[order[i]] > filled[order[j]]
+	})
+
+	accumulation := make([]float64, len(filled))
+	for i := range accumulation {
+		accumulation[i] = cellArea
+	}
+	for _, index := range order {
+		for i, to := range directions[index].to {
+			if to >= 0 {
+				accumulation[to] += accumulation[index] * directions[index].fraction[i]
+			}
+		}
+	}
+
+	return accumulation
+}
@@ -0,0 +1,139 @@
+package hydrology
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/bcokert/terragen/geojson"
+	"github.com/bcokert/terragen/noise"
+)
+
+// Options control how drainage is analysed
+// RiverThreshold is the upstream area, in square world units, a sample must drain before it is part of a river
+type Options struct {
+	Method         FlowMethod
+	RiverThreshold float64
+}
+
+// NewDefaultOptions creates Options for D8 flow, with rivers draining at least a tenth of a square unit
+func NewDefaultOptions() Options {
+	return Options{Method: D8, RiverThreshold: 0.1}
+}
+
+// Drainage is the result of analysing how water flows over a heightfield. Every channel is laid out the same way as the noise values
+// Filled is the heightfield with its depressions filled, so the depth of lakes is the difference between it and the original
+// Accumulation is the upstream area, in square world units, draining through each sample
+// Watersheds labels each sample with the basin it drains into, numbered from 0 to Basins - 1
+// RiverMask is 1 for samples whose accumulation is above the river threshold, and 0 otherwise
+// Rivers traces the river samples into lines running downstream in world coordinates, split at each confluence
+type Drainage struct {
+	Filled       []float64                  `json:"filled"`
+	Accumulation []float64                  `json:"accumulation"`
+	Watersheds   []int                      `json:"watersheds"`
+	Basins       int                        `json:"basins"`
+	RiverMask    []float64                  `json:"riverMask"`
+	Rivers       *geojson.FeatureCollection `json:"rivers"`
+}
+
+// Analyze fills the depressions of 2D noise, then computes how water flows over it, the basins it drains into, and the rivers it forms
+func Analyze(noise *noise.Noise, options Options) (*Drainage, error) {
+	shape := noise.Shape()
+	if len(shape) != 2 {
+		return nil, errors.New("Hydrology can only be computed from 2 dimensional noise")
+	}
+	width, height := shape[0], shape[1]
+	spacing := 1 / float64(noise.Resolution)
+
+	filled := Fill(noise.Values, width, height)
+	directions := flowDirections(filled, width, height, spacing, options.Method)
+	accumulation := flowAccumulation(filled, directions, spacing*spacing)
+	watersheds, basins := labelWatersheds(filled, directions)
+
+	riverMask := make([]float64, len(accumulation))
+	for i, area := range accumulation {
+		if area >= options.RiverThreshold {
+			riverMask[i] = 1
+		}
+	}
+
+	position := func(index int) [2]float64 {
+		return [2]float64{
+			float64(noise.From[0]) + float64(index/height)*spacing,
+			float64(noise.From[1]) + float64(index%height)*spacing,
+		}
+	}
+
+	return &Drainage{
+		Filled:       filled,
+		Accumulation: accumulation,
+		Watersheds:   watersheds,
+		Basins:       basins,
+		RiverMask:    riverMask,
+		Rivers:       traceRivers(riverMask, directions, accumulation, watersheds, position),
+	}, nil
+}
+
+// labelWatersheds labels every sample with the outlet its dominant flow ends at, visiting samples from lowest to highest
+// so that every sample's receiver is labelled before it is
+func labelWatersheds(filled []float64, directions []receivers) (watersheds []int, basins int) {
+	order := make([]int, len(filled))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return filled[order[i]] < filled[order[j]]
+	})
+
+	watersheds = make([]int, len(filled))
+	for _, index := range order {
+		if to := directions[index].to[0]; to >= 0 {
+			watersheds[index] = watersheds[to]
+		} else {
+			watersheds[index] = basins
+			basins++
+		}
+	}
+
+	return watersheds, basins
+}
+
+// traceRivers follows the dominant flow of the river samples, splitting the rivers into a line between each pair of heads, confluences and outlets
+func traceRivers(riverMask []float64, directions []receivers, accumulation []float64, watersheds []int, position func(index int) [2]float64) *geojson.FeatureCollection {
+	tributaries := make([]int, len(riverMask))
+	for index, river := range riverMask {
+		if to := directions[index].to[0]; river == 1 && to >= 0 && riverMask[to] == 1 {
+			tributaries[to]++
+		}
+	}
+
+	// Lines start at the head of each river and at each confluence, and end at the next confluence or where the river leaves the mask
+	rivers := geojson.NewFeatureCollection()
+	for start, river := range riverMask {
+		if river != 1 || tributaries[start] == 1 {
+			continue
+		}
+
+		line := [][2]float64{position(start)}
+		index := start
+		for {
+			to := directions[index].to[0]
+			if to < 0 || riverMask[to] != 1 {
+				break
+			}
+			index = to
+			line = append(line, position(index))
+			if tributaries[index] > 1 {
+				break
+			}
+		}
+
+		if len(line) > 1 {
+			rivers.Add(geojson.NewLineString(line), map[string]interface{}{
+				"accumulation": accumulation[index],
+				"watershed":    watersheds[index],
+			})
+		}
+	}
+
+	return rivers
+}
@@ -0,0 +1,126 @@
+package hydrology_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/bcokert/terragen/hydrology"
+	"github.com/bcokert/terragen/noise"
+)
+
+// valley generates a v shaped valley along y = 2, which descends towards x = 0
+func valley() *noise.Noise {
+	valley := noise.NewNoise("valley")
+	valley.Generate([]int{0, 0}, []int{4, 4}, 4, func(t []float64) float64 {
+		return math.Abs(t[1]-2) + 0.2*t[0]
+	})
+	return valley
+}
+
+func TestAnalyze(t *testing.T) {
+	testCases := map[string]struct {
+		Method hydrology.FlowMethod
+	}{
+		"d8":         {Method: hydrology.D8},
+		"d infinity": {Method: hydrology.DInfinity},
+	}
+
+	for name, testCase := range testCases {
+		terrain := valley()
+		shape := terrain.Shape()
+		cellArea := 1 / float64(terrain.Resolution*terrain.Resolution)
+
+		drainage, err := hydrology.Analyze(terrain, hydrology.Options{Method: testCase.Method, RiverThreshold: 1})
+		if err != nil {
+			t.Errorf("'%s' failed. Unexpected error: %s", name, err.Error())
+			continue
+		}
+
+		// All of the water ends up at an outlet on the edges
+		outflow := 0.0
+		for x := 0; x < shape[0]; x++ {
+			for y := 0; y < shape[1]; y++ {
+				if x == 0 || y == 0 || x == shape[0]-1 || y == shape[1]-1 {
+					outflow += drainage.Accumulation[x*shape[1]+y]
+				}
+			}
+		}
+		if total := cellArea * float64(len(terrain.Values)); math.Abs(outflow-total) > 1e-9 {
+			t.Errorf("'%s' failed. Expected %v to flow out of the edges, received %v", name, total, outflow)
+		}
+
+		// The valley floor collects the water, and drains out at x = 0
+		mouth := 0*shape[1] + 8
+		if drainage.RiverMask[mouth] != 1 {
+			t.Errorf("'%s' failed. Expected the mouth of the valley to be a river, received accumulation %v", name, drainage.Accumulation[mouth])
+		}
+		for y := 0; y < shape[1]; y++ {
+			if y != 8 && drainage.Accumulation[1*shape[1]+y] > drainage.Accumulation[1*shape[1]+8] {
+				t.Errorf("'%s' failed. Expected the valley floor to collect the most water, received %v at y index %d", name, drainage.Accumulation[1*shape[1]+y], y)
+			}
+		}
+
+		if len(drainage.Rivers.Features) != 1 {
+			t.Errorf("'%s' failed. Expected 1 river, received %d", name, len(drainage.Rivers.Features))
+			continue
+		}
+		river := drainage.Rivers.Features[0].Geometry.Coordinates.([][2]float64)
+		if end := river[len(river)-1]; end != [2]float64{0, 2} {
+			t.Errorf("'%s' failed. Expected the river to end at the mouth of the valley, received %v", name, end)
+		}
+		for i := 1; i < len(river); i++ {
+			if river[i][0] >= river[i-1][0] {
+				t.Errorf("'%s' failed. Expected the river to run down the valley, received %v", name, river)
+				break
+			}
+		}
+
+		if drainage.Watersheds[mouth+1*shape[1]] != drainage.Watersheds[mouth] {
+			t.Errorf("'%s' failed. Expected the valley floor to drain into the basin of its mouth", name)
+		}
+		if drainage.Basins < 1 || drainage.Basins > 2*(shape[0]+shape[1]) {
+			t.Errorf("'%s' failed. Expected a basin per outlet at most, received %d", name, drainage.Basins)
+		}
+	}
+}
+
+func TestAnalyze_Dimensions(t *testing.T) {
+	line := noise.NewNoise("line")
+	line.Generate([]int{0}, []int{4}, 4, func(t []float64) float64 { return t[0] })
+
+	expected := "Hydrology can only be computed from 2 dimensional noise"
+	if _, err := hydrology.Analyze(line, hydrology.NewDefaultOptions()); err == nil || err.Error() != expected {
+		t.Errorf("Expected error '%s', received %v", expected, err)
+	}
+}
+
+func TestAnalyze_Confluence(t *testing.T) {
+	// Two valleys meet at (2, 2), and continue as one valley that descends towards x = 0
+	fork := noise.NewNoise("fork")
+	fork.Generate([]int{0, 0}, []int{4, 4}, 4, func(t []float64) float64 {
+		distance := math.Abs(t[1] - 2)
+		if t[0] > 2 {
+			distance = math.Min(math.Abs(t[1]-t[0]), math.Abs(t[1]-4+t[0]))
+		}
+		return distance + 0.2*t[0]
+	})
+
+	drainage, err := hydrology.Analyze(fork, hydrology.Options{Method: hydrology.D8, RiverThreshold: 0.5})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err.Error())
+	}
+
+	// Each branch ends at the confluence, where the main river starts
+	if len(drainage.Rivers.Features) != 3 {
+		t.Fatalf("Expected 3 rivers, received %d", len(drainage.Rivers.Features))
+	}
+	starts, ends := map[[2]float64]int{}, map[[2]float64]int{}
+	for _, feature := range drainage.Rivers.Features {
+		river := feature.Geometry.Coordinates.([][2]float64)
+		starts[river[0]]++
+		ends[river[len(river)-1]]++
+	}
+	if ends[[2]float64{2, 2}] != 2 || starts[[2]float64{2, 2}] != 1 || ends[[2]float64{0, 2}] != 1 {
+		t.Errorf("Expected both branches to end at the confluence, and the main river to run from it to the mouth, received starts %v and ends %v", starts, ends)
+	}
+}
@@ -0,0 +1,158 @@
+package hydrology
+
+import (
+	"errors"
+	"math"
+
+	"github.com/bcokert/terragen/contour"
+	"github.com/bcokert/terragen/geojson"
+	"github.com/bcokert/terragen/noise"
+)
+
+// WaterOptions control how standing water is found
+// Samples below SeaLevel that connect to the edge of the heightfield are ocean
+// Depressions anywhere else are lakes, if their deepest sample is deeper than MinLakeDepth when filled to their spill level
+type WaterOptions struct {
+	SeaLevel     float64
+	MinLakeDepth float64
+}
+
+// NewDefaultWaterOptions creates WaterOptions with the sea at 0, and a lake in every depression
+func NewDefaultWaterOptions() WaterOptions {
+	return WaterOptions{SeaLevel: 0, MinLakeDepth: 0}
+}
+
+// A Lake is a filled depression. Its Level is the height it spills over its rim at, and Depth how far below that its lowest sample is
+type Lake struct {
+	ID      int     `json:"id"`
+	Level   float64 `json:"level"`
+	Depth   float64 `json:"depth"`
+	Samples int     `json:"samples"`
+	Area    float64 `json:"area"`
+}
+
+// Water is the standing water over a heightfield. Every channel is laid out the same way as the noise values
+// OceanMask and LakeMask are 1 for samples under the ocean or a lake, and 0 otherwise
+// LakeLabels gives the id of the lake over each sample, or -1 for samples that aren't under a lake
+// Coastlines are the shores of the ocean in world coordinates, traced at sea level so they run between samples
+type Water struct {
+	OceanMask  []float64                  `json:"oceanMask"`
+	LakeMask   []float64                  `json:"lakeMask"`
+	LakeLabels []int                      `json:"lakeLabels"`
+	Lakes      []Lake                     `json:"lakes"`
+	Coastlines *geojson.FeatureCollection `json:"coastlines"`
+}
+
+// orthogonalOffsets are the (x, y) offsets of the 4 orthogonal neighbours of a sample. Water only connects orthogonally,
+// so it can't leak diagonally between two samples of land
+var orthogonalOffsets = [4][2]int{{1, 0}, {0, 1}, {-1, 0}, {0, -1}}
+
+// FindWater floods 2D noise with an ocean from its edges up to sea level, fills its remaining depressions with lakes,
+// and traces the coastlines of the ocean
+func FindWater(noise *noise.Noise, options WaterOptions) (*Water, error) {
+	shape := noise.Shape()
+	if len(shape) != 2 {
+		return nil, errors.New("Water can only be found in 2 dimensional noise")
+	}
+	width, height := shape[0], shape[1]
+	heights := noise.Values
+
+	water := &Water{
+		OceanMask:  make([]float64, len(heights)),
+		LakeMask:   make([]float64, len(heights)),
+		LakeLabels: make([]int, len(heights)),
+		Lakes:      []Lake{},
+	}
+
+	// The ocean is every sample below sea level that can be reached from an edge without crossing land
+	queue := []int{}
+	for x := 0; x < width; x++ {
+		for y := 0; y < height; y++ {
+			index := x*height + y
+			if (x == 0 || y == 0 || x == width-1 || y == height-1) && heights[index] < options.SeaLevel {
+				water.OceanMask[index] = 1
+				queue = append(queue, index)
+			}
+		}
+	}
+	eachNeighbor := func(index int, fn func(neighbor int)) {
+		x, y := index/height, index%height
+		for _, offset := range orthogonalOffsets {
+			nx, ny := x+offset[0], y+offset[1]
+			if nx >= 0 && nx < width && ny >= 0 && ny < height {
+				fn(nx*height + ny)
+			}
+		}
+	}
+	for len(queue) > 0 {
+		index := queue[0]
+		queue = queue[1:]
+		eachNeighbor(index, func(neighbor int) {
+			if water.OceanMask[neighbor] == 0 && heights[neighbor] < options.SeaLevel {
+				water.OceanMask[neighbor] = 1
+				queue = append(queue, neighbor)
+			}
+		})
+	}
+
+	// Lakes are the depressions left on land, each labelled by flooding through its connected samples
+	// A depression is kept whole if any of it is deeper than MinLakeDepth, so shallow shores stay part of their lake
+	levels := SpillLevels(heights, width, height)
+	isSubmerged := func(index int) bool {
+		return water.OceanMask[index] == 0 && levels[index] > heights[index]
+	}
+	for i := range water.LakeLabels {
+		water.LakeLabels[i] = -1
+	}
+	visited := make([]bool, len(heights))
+	cellArea := 1 / float64(noise.Resolution*noise.Resolution)
+	for start := range heights {
+		if visited[start] || !isSubmerged(start) {
+			continue
+		}
+
+		lake := Lake{ID: len(water.Lakes), Level: math.Inf(-1)}
+		visited[start] = true
+		samples := []int{start}
+		for next := 0; next < len(samples); next++ {
+			index := samples[next]
+			lake.Level = math.Max(lake.Level, levels[index])
+			lake.Depth = math.Max(lake.Depth, levels[index]-heights[index])
+
+			eachNeighbor(index, func(neighbor int) {
+				if !visited[neighbor] && isSubmerged(neighbor) {
+					visited[neighbor] = true
+					samples = append(samples, neighbor)
+				}
+			})
+		}
+		if lake.Depth <= options.MinLakeDepth {
+			continue
+		}
+
+		for _, index := range samples {
+			water.LakeLabels[index] = lake.ID
+			water.LakeMask[index] = 1
+		}
+		lake.Samples = len(samples)
+		lake.Area = float64(lake.Samples) * cellArea
+		water.Lakes = append(water.Lakes, lake)
+	}
+
+	// Coastlines are traced through the real heights of the ocean, with the land around it (including any lakes below sea level) held just above it
+	above := math.Nextafter(options.SeaLevel, math.Inf(1))
+	coast := make([]float64, len(heights))
+	for i, value := range heights {
+		coast[i] = value
+		if water.OceanMask[i] == 0 {
+			coast[i] = math.Max(value, above)
+		}
+	}
+	origin := [2]float64{float64(noise.From[0]), float64(noise.From[1])}
+	water.Coastlines = geojson.NewFeatureCollection()
+	for _, line := range contour.Isolines(coast, width, height, options.SeaLevel) {
+		water.Coastlines.Add(geojson.NewLineString(line.Transform(origin, 1/float64(noise.Resolution))), map[string]interface{}{"closed": line.IsClosed()})
+	}
+
+	return water, nil
+}
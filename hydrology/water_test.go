@@ -0,0 +1,148 @@
+package hydrology_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/bcokert/terragen/hydrology"
+	"github.com/bcokert/terragen/noise"
+)
+
+// island generates a square island in the middle of an ocean, with a crater lake at its center and a smaller pond off to one side
+func island() *noise.Noise {
+	island := noise.NewNoise("island")
+	island.Generate([]int{0, 0}, []int{9, 9}, 1, func(t []float64) float64 {
+		switch {
+		case t[0] == 4 && t[1] == 4:
+			return 0.2
+		case t[0] == 3 && t[1] == 5:
+			return 0.9
+		case t[0] >= 2 && t[0] <= 6 && t[1] >= 2 && t[1] <= 6:
+			return 1
+		default:
+			return -1
+		}
+	})
+	return island
+}
+
+func TestFindWater(t *testing.T) {
+	testCases := map[string]struct {
+		Options        hydrology.WaterOptions
+		ExpectedOcean  int
+		ExpectedLakes  []hydrology.Lake
+		ExpectedCoasts int
+	}{
+		"defaults": {
+			Options:       hydrology.NewDefaultWaterOptions(),
+			ExpectedOcean: 81 - 25,
+			ExpectedLakes: []hydrology.Lake{
+				{ID: 0, Level: 1, Depth: 0.1, Samples: 1, Area: 1},
+				{ID: 1, Level: 1, Depth: 0.8, Samples: 1, Area: 1},
+			},
+			ExpectedCoasts: 1,
+		},
+		"deep lakes only": {
+			Options:       hydrology.WaterOptions{SeaLevel: 0, MinLakeDepth: 0.5},
+			ExpectedOcean: 81 - 25,
+			ExpectedLakes: []hydrology.Lake{
+				{ID: 0, Level: 1, Depth: 0.8, Samples: 1, Area: 1},
+			},
+			ExpectedCoasts: 1,
+		},
+		"drowned": {
+			Options:        hydrology.WaterOptions{SeaLevel: 2, MinLakeDepth: 0},
+			ExpectedOcean:  81,
+			ExpectedLakes:  []hydrology.Lake{},
+			ExpectedCoasts: 0,
+		},
+	}
+
+	for name, testCase := range testCases {
+		terrain := island()
+		water, err := hydrology.FindWater(terrain, testCase.Options)
+		if err != nil {
+			t.Errorf("'%s' failed. Unexpected error: %s", name, err.Error())
+			continue
+		}
+
+		ocean := 0
+		for i, value := range water.OceanMask {
+			ocean += int(value)
+			if value == 1 && water.LakeMask[i] == 1 {
+				t.Errorf("'%s' failed. Expected sample %d to be ocean or lake, not both", name, i)
+			}
+		}
+		if ocean != testCase.ExpectedOcean {
+			t.Errorf("'%s' failed. Expected %d ocean samples, received %d", name, testCase.ExpectedOcean, ocean)
+		}
+
+		if len(water.Lakes) != len(testCase.ExpectedLakes) {
+			t.Errorf("'%s' failed. Expected lakes %v, received %v", name, testCase.ExpectedLakes, water.Lakes)
+			continue
+		}
+		for i, expected := range testCase.ExpectedLakes {
+			lake := water.Lakes[i]
+			if lake.ID != expected.ID || lake.Samples != expected.Samples || lake.Area != expected.Area ||
+				math.Abs(lake.Level-expected.Level) > 1e-9 || math.Abs(lake.Depth-expected.Depth) > 1e-9 {
+				t.Errorf("'%s' failed. Expected lake %v, received %v", name, expected, lake)
+			}
+		}
+
+		if len(water.Coastlines.Features) != testCase.ExpectedCoasts {
+			t.Errorf("'%s' failed. Expected %d coastlines, received %d", name, testCase.ExpectedCoasts, len(water.Coastlines.Features))
+			continue
+		}
+
+		// The coast runs halfway between the ocean at -1 and the island at 1, around the outside of the island
+		for _, feature := range water.Coastlines.Features {
+			for _, point := range feature.Geometry.Coordinates.([][2]float64) {
+				if point[0] < 1.5 || point[0] > 6.5 || point[1] < 1.5 || point[1] > 6.5 || (point[0] > 1.5 && point[0] < 6.5 && point[1] > 1.5 && point[1] < 6.5) {
+					t.Errorf("'%s' failed. Expected the coast to run around the island, received %v", name, point)
+					break
+				}
+			}
+			if feature.Properties["closed"] != true {
+				t.Errorf("'%s' failed. Expected the coast around an island to be closed", name)
+			}
+		}
+	}
+}
+
+func TestFindWater_ShallowShores(t *testing.T) {
+	// A crater whose center is deep, surrounded by shallow shores, is one lake however shallow the shores are
+	crater := noise.NewNoise("crater")
+	crater.Generate([]int{0, 0}, []int{5, 5}, 1, func(t []float64) float64 {
+		switch math.Abs(t[0]-2) + math.Abs(t[1]-2) {
+		case 0:
+			return 0.2
+		case 1:
+			return 0.8
+		default:
+			return 1
+		}
+	})
+
+	water, err := hydrology.FindWater(crater, hydrology.WaterOptions{SeaLevel: 0, MinLakeDepth: 0.5})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err.Error())
+	}
+	if len(water.Lakes) != 1 || water.Lakes[0].Samples != 5 || math.Abs(water.Lakes[0].Depth-0.8) > 1e-9 {
+		t.Errorf("Expected one lake of 5 samples and depth 0.8, received %v", water.Lakes)
+	}
+	for i, label := range water.LakeLabels {
+		if (label == 0) != (water.LakeMask[i] == 1) {
+			t.Errorf("Expected sample %d to be labelled only if it's under the lake, received %d", i, label)
+		}
+	}
+}
+
+func TestFindWater_Dimensions(t *testing.T) {
+	line := noise.NewNoise("line")
+	line.Generate([]int{0}, []int{4}, 4, func(t []float64) float64 { return t[0] })
+
+	expected := "Water can only be found in 2 dimensional noise"
+	if _, err := hydrology.FindWater(line, hydrology.NewDefaultWaterOptions()); err == nil || err.Error() != expected {
+		t.Errorf("Expected error '%s', received %v", expected, err)
+	}
+}
@@ -0,0 +1,63 @@
+package image
+
+import (
+	"encoding/binary"
+	"errors"
+	stdImage "image"
+	"image/color"
+	"io"
+	"io/ioutil"
+	"math"
+
+	"github.com/bcokert/terragen/noise"
+)
+
+// DecodeHeightmap reads a grayscale PNG heightmap, such as one written by EncodeHeightmap, into a raster of heights in [0, 1]
+// Colour images are converted to their luminance. The x axis of the image runs along the first dimension of the raster, like Heightmap
+func DecodeHeightmap(r io.Reader) (*noise.Raster, error) {
+	img, _, err := stdImage.Decode(r)
+	if err != nil {
+		return nil, err
+	}
+
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	values := make([]float64, 0, width*height)
+	for x := bounds.Min.X; x < bounds.Max.X; x++ {
+		for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+			values = append(values, float64(color.Gray16Model.Convert(img.At(x, y)).(color.Gray16).Y)/65535)
+		}
+	}
+
+	return noise.NewRaster(values, width, height)
+}
+
+// DecodeRawHeightmap reads a raw heightmap of little endian float32 heights into a raster, keeping the heights as they are
+// Raw heightmaps are stored a row at a time, so the first width values are the top row of the image
+func DecodeRawHeightmap(r io.Reader, width, height int) (*noise.Raster, error) {
+	if width < 1 || height < 1 {
+		return nil, errors.New("Raw heightmaps must have a positive width and height")
+	}
+
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	// The width is checked against the data before multiplying, so huge sizes can't overflow to match it
+	if width > len(data)/4/height || len(data) != width*height*4 {
+		return nil, errors.New("Raw heightmaps must have a little endian float32 for each of their width * height samples")
+	}
+
+	values := make([]float64, width*height)
+	for row := 0; row < height; row++ {
+		for column := 0; column < width; column++ {
+			value := float64(math.Float32frombits(binary.LittleEndian.Uint32(data[(row*width+column)*4:])))
+			if math.IsNaN(value) || math.IsInf(value, 0) {
+				return nil, errors.New("Raw heightmaps must only have finite heights")
+			}
+			values[column*height+row] = value
+		}
+	}
+
+	return noise.NewRaster(values, width, height)
+}
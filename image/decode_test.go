@@ -0,0 +1,110 @@
+package image_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/bcokert/terragen/image"
+	"github.com/bcokert/terragen/noise"
+)
+
+func TestDecodeHeightmap(t *testing.T) {
+	testCases := map[string]struct {
+		Depth image.BitDepth
+	}{
+		"8 bit":  {Depth: image.Gray8},
+		"16 bit": {Depth: image.Gray16},
+	}
+
+	for name, testCase := range testCases {
+		// Heightmaps decode back to the values they were encoded from, normalized to [0, 1]
+		n := &noise.Noise{Values: []float64{0, 1, 0.2, 0.6, 0.4, 0.8}, From: []int{0, 0}, To: []int{3, 2}, Resolution: 1}
+		buffer := &bytes.Buffer{}
+		if err := image.EncodeHeightmap(buffer, n, image.NewRangeNormalizer(0, 1), testCase.Depth); err != nil {
+			t.Errorf("'%s' failed. An unexpected error occurred: %v", name, err.Error())
+			continue
+		}
+
+		raster, err := image.DecodeHeightmap(buffer)
+		if err != nil {
+			t.Errorf("'%s' failed. An unexpected error occurred: %v", name, err.Error())
+			continue
+		}
+		if raster.Width != 3 || raster.Height != 2 {
+			t.Errorf("'%s' failed. Expected a 3x2 raster, received %dx%d", name, raster.Width, raster.Height)
+			continue
+		}
+		for i := range raster.Values {
+			if math.Abs(raster.Values[i]-n.Values[i]) > 0.5/255 {
+				t.Errorf("'%s' failed. Expected %v, received %v", name, n.Values, raster.Values)
+				break
+			}
+		}
+	}
+
+	if _, err := image.DecodeHeightmap(strings.NewReader("not a png")); err == nil {
+		t.Errorf("Expected an error decoding an invalid png")
+	}
+}
+
+func TestDecodeRawHeightmap(t *testing.T) {
+	raw := func(values ...float32) []byte {
+		buffer := &bytes.Buffer{}
+		binary.Write(buffer, binary.LittleEndian, values)
+		return buffer.Bytes()
+	}
+
+	testCases := map[string]struct {
+		Data          []byte
+		Width, Height int
+		Expected      []float64
+		ExpectedError string
+	}{
+		"rows": {
+			// The top row is 1, 2, 3, and the bottom row is 4, 5, 6
+			Data: raw(1, 2, 3, 4, 5, 6), Width: 3, Height: 2,
+			Expected: []float64{1, 4, 2, 5, 3, 6},
+		},
+		"negative heights": {
+			Data: raw(-1.5, 250), Width: 1, Height: 2,
+			Expected: []float64{-1.5, 250},
+		},
+		"wrong size": {
+			Data: raw(1, 2, 3), Width: 2, Height: 2,
+			ExpectedError: "Raw heightmaps must have a little endian float32 for each of their width * height samples",
+		},
+		"overflowing size": {
+			Data: raw(), Width: 1 << 32, Height: 1 << 32,
+			ExpectedError: "Raw heightmaps must have a little endian float32 for each of their width * height samples",
+		},
+		"no size": {
+			Data: raw(), Width: 0, Height: 2,
+			ExpectedError: "Raw heightmaps must have a positive width and height",
+		},
+		"not finite": {
+			Data: raw(1, float32(math.NaN())), Width: 2, Height: 1,
+			ExpectedError: "Raw heightmaps must only have finite heights",
+		},
+	}
+
+	for name, testCase := range testCases {
+		raster, err := image.DecodeRawHeightmap(bytes.NewReader(testCase.Data), testCase.Width, testCase.Height)
+		if testCase.ExpectedError != "" {
+			if err == nil || err.Error() != testCase.ExpectedError {
+				t.Errorf("'%s' failed. Expected error %s, received %v", name, testCase.ExpectedError, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("'%s' failed. An unexpected error occurred: %v", name, err.Error())
+			continue
+		}
+		if !reflect.DeepEqual(raster.Values, testCase.Expected) {
+			t.Errorf("'%s' failed. Expected %v, received %v", name, testCase.Expected, raster.Values)
+		}
+	}
+}
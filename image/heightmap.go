@@ -0,0 +1,63 @@
+package image
+
+import (
+	"errors"
+	stdImage "image"
+	"image/color"
+	"image/png"
+	"io"
+
+	"github.com/bcokert/terragen/noise"
+)
+
+// BitDepth is the number of bits used for each grayscale pixel of a heightmap
+type BitDepth int
+
+// Supported heightmap bit depths
+const (
+	Gray8  BitDepth = 8
+	Gray16 BitDepth = 16
+)
+
+// Heightmap converts 2D noise into a grayscale image, using the normalizer to map each value into the full range of the bit depth
+// The first dimension of the noise runs along the x axis of the image, and the second along the y axis
+func Heightmap(noise *noise.Noise, normalizer Normalizer, depth BitDepth) (stdImage.Image, error) {
+	shape := noise.Shape()
+	if len(shape) != 2 {
+		return nil, errors.New("Heightmaps can only be created from 2 dimensional noise")
+	}
+
+	width, height := shape[0], shape[1]
+	bounds := stdImage.Rect(0, 0, width, height)
+
+	switch depth {
+	case Gray8:
+		img := stdImage.NewGray(bounds)
+		for x := 0; x < width; x++ {
+			for y := 0; y < height; y++ {
+				img.SetGray(x, y, color.Gray{Y: uint8(normalizer(noise.Values[x*height+y])*255 + 0.5)})
+			}
+		}
+		return img, nil
+	case Gray16:
+		img := stdImage.NewGray16(bounds)
+		for x := 0; x < width; x++ {
+			for y := 0; y < height; y++ {
+				img.SetGray16(x, y, color.Gray16{Y: uint16(normalizer(noise.Values[x*height+y])*65535 + 0.5)})
+			}
+		}
+		return img, nil
+	default:
+		return nil, errors.New("Heightmaps must have a bit depth of 8 or 16")
+	}
+}
+
+// EncodeHeightmap writes 2D noise to w as a grayscale PNG heightmap
+func EncodeHeightmap(w io.Writer, noise *noise.Noise, normalizer Normalizer, depth BitDepth) error {
+	img, err := Heightmap(noise, normalizer, depth)
+	if err != nil {
+		return err
+	}
+
+	return png.Encode(w, img)
+}
@@ -0,0 +1,105 @@
+package image_test
+
+import (
+	"bytes"
+	stdImage "image"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"github.com/bcokert/terragen/image"
+	"github.com/bcokert/terragen/noise"
+)
+
+func TestHeightmap(t *testing.T) {
+	testCases := map[string]struct {
+		Noise            *noise.Noise
+		Depth            image.BitDepth
+		ExpectedPixels   map[[2]int]uint16
+		ExpectedErrorMsg string
+	}{
+		"8 bit": {
+			Noise:          &noise.Noise{Values: []float64{0, 0.5, 1, 0.25}, From: []int{0, 0}, To: []int{2, 2}, Resolution: 1},
+			Depth:          image.Gray8,
+			ExpectedPixels: map[[2]int]uint16{{0, 0}: 0, {0, 1}: 128, {1, 0}: 255, {1, 1}: 64},
+		},
+		"16 bit": {
+			Noise:          &noise.Noise{Values: []float64{0, 0.5, 1, 0.25}, From: []int{0, 0}, To: []int{2, 2}, Resolution: 1},
+			Depth:          image.Gray16,
+			ExpectedPixels: map[[2]int]uint16{{0, 0}: 0, {0, 1}: 32768, {1, 0}: 65535, {1, 1}: 16384},
+		},
+		"non square": {
+			Noise:          &noise.Noise{Values: []float64{0, 1, 0, 1, 0, 1}, From: []int{0, 0}, To: []int{3, 2}, Resolution: 1},
+			Depth:          image.Gray8,
+			ExpectedPixels: map[[2]int]uint16{{0, 0}: 0, {0, 1}: 255, {2, 0}: 0, {2, 1}: 255},
+		},
+		"1d noise": {
+			Noise:            &noise.Noise{Values: []float64{0, 1}, From: []int{0}, To: []int{2}, Resolution: 1},
+			Depth:            image.Gray8,
+			ExpectedErrorMsg: "Heightmaps can only be created from 2 dimensional noise",
+		},
+		"invalid depth": {
+			Noise:            &noise.Noise{Values: []float64{0, 1, 0, 1}, From: []int{0, 0}, To: []int{2, 2}, Resolution: 1},
+			Depth:            image.BitDepth(12),
+			ExpectedErrorMsg: "Heightmaps must have a bit depth of 8 or 16",
+		},
+	}
+
+	for name, testCase := range testCases {
+		img, err := image.Heightmap(testCase.Noise, image.NewRangeNormalizer(0, 1), testCase.Depth)
+		if testCase.ExpectedErrorMsg != "" {
+			if err == nil || err.Error() != testCase.ExpectedErrorMsg {
+				t.Errorf("'%s' failed. Expected error '%v', received '%v'", name, testCase.ExpectedErrorMsg, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("'%s' failed. An unexpected error occurred: %v", name, err.Error())
+			continue
+		}
+
+		for pixel, expected := range testCase.ExpectedPixels {
+			var result uint16
+			switch typed := img.(type) {
+			case *stdImage.Gray:
+				result = uint16(typed.GrayAt(pixel[0], pixel[1]).Y)
+			case *stdImage.Gray16:
+				result = typed.Gray16At(pixel[0], pixel[1]).Y
+			}
+			if result != expected {
+				t.Errorf("'%s' failed at pixel %v. Expected %v, received %v", name, pixel, expected, result)
+			}
+		}
+	}
+}
+
+func TestEncodeHeightmap(t *testing.T) {
+	testCases := map[string]struct {
+		Depth         image.BitDepth
+		ExpectedModel color.Model
+	}{
+		"8 bit":  {Depth: image.Gray8, ExpectedModel: color.GrayModel},
+		"16 bit": {Depth: image.Gray16, ExpectedModel: color.Gray16Model},
+	}
+
+	for name, testCase := range testCases {
+		n := &noise.Noise{Values: []float64{0, 1, 2, 3, 4, 5}, From: []int{0, 0}, To: []int{3, 2}, Resolution: 1}
+		buffer := &bytes.Buffer{}
+		if err := image.EncodeHeightmap(buffer, n, image.NewMinMaxNormalizer(n.Values), testCase.Depth); err != nil {
+			t.Errorf("'%s' failed. An unexpected error occurred: %v", name, err.Error())
+			continue
+		}
+
+		decoded, err := png.Decode(buffer)
+		if err != nil {
+			t.Errorf("'%s' failed. Could not decode png: %v", name, err.Error())
+			continue
+		}
+		if decoded.ColorModel() != testCase.ExpectedModel {
+			t.Errorf("'%s' failed. Expected color model %v, received %v", name, testCase.ExpectedModel, decoded.ColorModel())
+		}
+		if bounds := decoded.Bounds(); bounds.Dx() != 3 || bounds.Dy() != 2 {
+			t.Errorf("'%s' failed. Expected bounds 3x2, received %v", name, bounds)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package image
+
+import (
+	"math"
+
+	"github.com/bcokert/terragen/noise"
+)
+
+// A Hillshade lights a heightfield with a single distant sun, using Lambertian reflectance
+// Azimuth is the compass direction of the sun in degrees, clockwise from the top of the image
+// Altitude is the angle of the sun above the horizon in degrees
+// ZFactor exaggerates (or flattens) the noise values relative to the distance between samples
+type Hillshade struct {
+	Azimuth  float64
+	Altitude float64
+	ZFactor  float64
+}
+
+// NewDefaultHillshade creates a Hillshade with the conventional cartographic sun, from the north west at 45 degrees
+func NewDefaultHillshade() Hillshade {
+	return Hillshade{Azimuth: 315, Altitude: 45, ZFactor: 1}
+}
+
+// Shade computes the illumination of each sample of 2D noise, in the range [0, 1]
+// The result is laid out the same way as the noise values
+func (hillshade Hillshade) Shade(noise *noise.Noise) []float64 {
+	shape := noise.Shape()
+	width, height := shape[0], shape[1]
+	spacing := 1 / float64(noise.Resolution)
+
+	azimuth := hillshade.Azimuth * math.Pi / 180
+	altitude := hillshade.Altitude * math.Pi / 180
+	light := [3]float64{
+		math.Sin(azimuth) * math.Cos(altitude),
+		-math.Cos(azimuth) * math.Cos(altitude),
+		math.Sin(altitude),
+	}
+
+	at := func(x, y int) float64 {
+		x = clampIndex(x, width)
+		y = clampIndex(y, height)
+		return noise.Values[x*height+y] * hillshade.ZFactor
+	}
+
+	shades := make([]float64, len(noise.Values))
+	for x := 0; x < width; x++ {
+		for y := 0; y < height; y++ {
+			dzdx := (at(x+1, y) - at(x-1, y)) / (float64(clampIndex(x+1, width)-clampIndex(x-1, width)) * spacing)
+			dzdy := (at(x, y+1) - at(x, y-1)) / (float64(clampIndex(y+1, height)-clampIndex(y-1, height)) * spacing)
+			if math.IsNaN(dzdx) {
+				dzdx = 0
+			}
+			if math.IsNaN(dzdy) {
+				dzdy = 0
+			}
+
+			length := math.Sqrt(dzdx*dzdx + dzdy*dzdy + 1)
+			shade := (-dzdx*light[0] - dzdy*light[1] + light[2]) / length
+			shades[x*height+y] = math.Max(0, shade)
+		}
+	}
+
+	return shades
+}
+
+func clampIndex(i, length int) int {
+	if i < 0 {
+		return 0
+	}
+	if i >= length {
+		return length - 1
+	}
+	return i
+}
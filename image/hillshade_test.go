@@ -0,0 +1,68 @@
+package image_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/bcokert/terragen/image"
+	tgmath "github.com/bcokert/terragen/math"
+	"github.com/bcokert/terragen/noise"
+)
+
+func TestHillshade_Shade(t *testing.T) {
+	plane := func(dx, dy float64) *noise.Noise {
+		n := &noise.Noise{}
+		n.Generate([]int{0, 0}, []int{2, 2}, 2, func(t []float64) float64 {
+			return dx*t[0] + dy*t[1]
+		})
+		return n
+	}
+
+	testCases := map[string]struct {
+		Noise     *noise.Noise
+		Hillshade image.Hillshade
+		Expected  float64
+	}{
+		"flat": {
+			Noise:     plane(0, 0),
+			Hillshade: image.Hillshade{Azimuth: 315, Altitude: 30, ZFactor: 1},
+			Expected:  math.Sin(math.Pi / 6),
+		},
+		"facing the sun": {
+			Noise:     plane(1, 0),
+			Hillshade: image.Hillshade{Azimuth: 270, Altitude: 45, ZFactor: 1},
+			Expected:  1,
+		},
+		"facing away from the sun": {
+			Noise:     plane(1, 0),
+			Hillshade: image.Hillshade{Azimuth: 90, Altitude: 45, ZFactor: 1},
+			Expected:  0,
+		},
+		"overhead sun": {
+			Noise:     plane(0, 1),
+			Hillshade: image.Hillshade{Azimuth: 0, Altitude: 90, ZFactor: 1},
+			Expected:  1 / math.Sqrt(2),
+		},
+		"flattened by zfactor": {
+			Noise:     plane(3, 3),
+			Hillshade: image.Hillshade{Azimuth: 123, Altitude: 60, ZFactor: 0},
+			Expected:  math.Sin(math.Pi / 3),
+		},
+	}
+
+	for name, testCase := range testCases {
+		for i, result := range testCase.Hillshade.Shade(testCase.Noise) {
+			if math.Abs(result-testCase.Expected) > 0.0000001 {
+				t.Errorf("'%s' failed at sample %d. Expected %v, received %v", name, i, testCase.Expected, result)
+				break
+			}
+		}
+	}
+}
+
+func TestNewDefaultHillshade(t *testing.T) {
+	hillshade := image.NewDefaultHillshade()
+	if !tgmath.IsFloatEqual(hillshade.Azimuth, 315) || !tgmath.IsFloatEqual(hillshade.Altitude, 45) || !tgmath.IsFloatEqual(hillshade.ZFactor, 1) {
+		t.Errorf("Expected a north west sun at 45 degrees, received %+v", hillshade)
+	}
+}
@@ -0,0 +1,48 @@
+package image
+
+import (
+	"math"
+	"sort"
+)
+
+// A Normalizer maps a raw noise value into the range [0, 1], clamping values that fall outside of its range
+type Normalizer func(value float64) float64
+
+// NewRangeNormalizer creates a Normalizer that maps the fixed range [min, max] onto [0, 1]
+func NewRangeNormalizer(min, max float64) Normalizer {
+	return func(value float64) float64 {
+		if max <= min {
+			return 0
+		}
+		return math.Max(0, math.Min(1, (value-min)/(max-min)))
+	}
+}
+
+// NewMinMaxNormalizer creates a Normalizer that maps the smallest of the given values to 0, and the largest to 1
+func NewMinMaxNormalizer(values []float64) Normalizer {
+	return NewPercentileNormalizer(values, 0, 1)
+}
+
+// NewPercentileNormalizer creates a Normalizer that maps the low percentile of the given values to 0, and the high percentile to 1
+// Percentiles are given in the range [0, 1], and are linearly interpolated between samples
+// Clipping the extreme tails of the distribution keeps a few outlying peaks from flattening the rest of the map
+func NewPercentileNormalizer(values []float64, low, high float64) Normalizer {
+	if len(values) == 0 {
+		return NewRangeNormalizer(0, 0)
+	}
+
+	sorted := make([]float64, len(values))
+	copy(sorted, values)
+	sort.Float64s(sorted)
+
+	return NewRangeNormalizer(percentile(sorted, low), percentile(sorted, high))
+}
+
+// percentile finds the value at the given percentile [0, 1] of an already sorted list
+func percentile(sorted []float64, p float64) float64 {
+	p = math.Max(0, math.Min(1, p))
+	position := p * float64(len(sorted)-1)
+	lower := int(math.Floor(position))
+	upper := int(math.Ceil(position))
+	return sorted[lower] + (sorted[upper]-sorted[lower])*(position-float64(lower))
+}
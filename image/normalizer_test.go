@@ -0,0 +1,118 @@
+package image_test
+
+import (
+	"testing"
+
+	"github.com/bcokert/terragen/image"
+	"github.com/bcokert/terragen/math"
+)
+
+func TestNewRangeNormalizer(t *testing.T) {
+	testCases := map[string]struct {
+		Min, Max float64
+		Inputs   []float64
+		Expected []float64
+	}{
+		"unit range": {
+			Min:      0,
+			Max:      1,
+			Inputs:   []float64{0, 0.25, 1},
+			Expected: []float64{0, 0.25, 1},
+		},
+		"negative range": {
+			Min:      -2,
+			Max:      2,
+			Inputs:   []float64{-2, 0, 1},
+			Expected: []float64{0, 0.5, 0.75},
+		},
+		"clamps": {
+			Min:      0,
+			Max:      10,
+			Inputs:   []float64{-5, 15},
+			Expected: []float64{0, 1},
+		},
+		"empty range": {
+			Min:      3,
+			Max:      3,
+			Inputs:   []float64{2, 3, 4},
+			Expected: []float64{0, 0, 0},
+		},
+	}
+
+	for name, testCase := range testCases {
+		normalizer := image.NewRangeNormalizer(testCase.Min, testCase.Max)
+		for i, input := range testCase.Inputs {
+			if result := normalizer(input); !math.IsFloatEqual(result, testCase.Expected[i]) {
+				t.Errorf("'%s' failed on input %v. Expected %v, received %v", name, input, testCase.Expected[i], result)
+			}
+		}
+	}
+}
+
+func TestNewMinMaxNormalizer(t *testing.T) {
+	testCases := map[string]struct {
+		Values   []float64
+		Inputs   []float64
+		Expected []float64
+	}{
+		"unsorted": {
+			Values:   []float64{3, -1, 7, 1},
+			Inputs:   []float64{-1, 3, 7},
+			Expected: []float64{0, 0.5, 1},
+		},
+		"no values": {
+			Values:   []float64{},
+			Inputs:   []float64{5},
+			Expected: []float64{0},
+		},
+	}
+
+	for name, testCase := range testCases {
+		normalizer := image.NewMinMaxNormalizer(testCase.Values)
+		for i, input := range testCase.Inputs {
+			if result := normalizer(input); !math.IsFloatEqual(result, testCase.Expected[i]) {
+				t.Errorf("'%s' failed on input %v. Expected %v, received %v", name, input, testCase.Expected[i], result)
+			}
+		}
+	}
+}
+
+func TestNewPercentileNormalizer(t *testing.T) {
+	testCases := map[string]struct {
+		Values    []float64
+		Low, High float64
+		Inputs    []float64
+		Expected  []float64
+	}{
+		"full range": {
+			Values:   []float64{0, 1, 2, 3, 4},
+			Low:      0,
+			High:     1,
+			Inputs:   []float64{0, 2, 4},
+			Expected: []float64{0, 0.5, 1},
+		},
+		"clipped tails": {
+			Values:   []float64{0, 1, 2, 3, 4},
+			Low:      0.25,
+			High:     0.75,
+			Inputs:   []float64{0, 1, 2, 3, 4},
+			Expected: []float64{0, 0, 0.5, 1, 1},
+		},
+		"interpolated percentile": {
+			Values:   []float64{0, 10},
+			Low:      0.1,
+			High:     0.9,
+			Inputs:   []float64{1, 5, 9},
+			Expected: []float64{0, 0.5, 1},
+		},
+	}
+
+	for name, testCase := range testCases {
+		normalizer := image.NewPercentileNormalizer(testCase.Values, testCase.Low, testCase.High)
+		for i, input := range testCase.Inputs {
+			if result := normalizer(input); !math.IsFloatEqual(result, testCase.Expected[i]) {
+				t.Errorf("'%s' failed on input %v. Expected %v, received %v", name, input, testCase.Expected[i], result)
+			}
+		}
+	}
+}
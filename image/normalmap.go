@@ -0,0 +1,45 @@
+package image
+
+import (
+	stdImage "image"
+	"image/color"
+	"image/png"
+	"io"
+
+	"github.com/bcokert/terragen/analysis"
+	"github.com/bcokert/terragen/noise"
+)
+
+// NormalMap converts 2D noise into a tangent space normal map, with red pointing right, green pointing up the image and blue out of it
+// This is the OpenGL convention, where flat ground is (128, 128, 255)
+// ZFactor exaggerates (or flattens) the noise values relative to the distance between samples
+func NormalMap(noise *noise.Noise, zFactor float64) (stdImage.Image, error) {
+	derivatives, err := analysis.NewDerivatives(noise, zFactor)
+	if err != nil {
+		return nil, err
+	}
+
+	width, height := derivatives.Width, derivatives.Height
+	img := stdImage.NewRGBA(stdImage.Rect(0, 0, width, height))
+
+	encode := func(component float64) uint8 {
+		return uint8((component+1)/2*255 + 0.5)
+	}
+
+	// The y axis of the noise runs down the image, so it is flipped to point green up
+	for i, normal := range derivatives.Normals() {
+		img.SetRGBA(i/height, i%height, color.RGBA{R: encode(normal[0]), G: encode(-normal[1]), B: encode(normal[2]), A: 255})
+	}
+
+	return img, nil
+}
+
+// EncodeNormalMap writes 2D noise to w as a PNG normal map
+func EncodeNormalMap(w io.Writer, noise *noise.Noise, zFactor float64) error {
+	img, err := NormalMap(noise, zFactor)
+	if err != nil {
+		return err
+	}
+
+	return png.Encode(w, img)
+}
@@ -0,0 +1,85 @@
+package image_test
+
+import (
+	"bytes"
+	stdImage "image"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"github.com/bcokert/terragen/image"
+	"github.com/bcokert/terragen/noise"
+)
+
+func TestNormalMap(t *testing.T) {
+	testCases := map[string]struct {
+		Noise            *noise.Noise
+		ZFactor          float64
+		Expected         color.RGBA
+		ExpectedErrorMsg string
+	}{
+		"flat": {
+			Noise:    &noise.Noise{Values: []float64{1, 1, 1, 1}, From: []int{0, 0}, To: []int{2, 2}, Resolution: 1},
+			ZFactor:  1,
+			Expected: color.RGBA{128, 128, 255, 255},
+		},
+		"rising right": {
+			Noise:    &noise.Noise{Values: []float64{0, 0, 1, 1}, From: []int{0, 0}, To: []int{2, 2}, Resolution: 1},
+			ZFactor:  1,
+			Expected: color.RGBA{37, 128, 218, 255},
+		},
+		"rising down the image": {
+			Noise:    &noise.Noise{Values: []float64{0, 1, 0, 1}, From: []int{0, 0}, To: []int{2, 2}, Resolution: 1},
+			ZFactor:  1,
+			Expected: color.RGBA{128, 218, 218, 255},
+		},
+		"flattened": {
+			Noise:    &noise.Noise{Values: []float64{0, 1, 0, 1}, From: []int{0, 0}, To: []int{2, 2}, Resolution: 1},
+			ZFactor:  0,
+			Expected: color.RGBA{128, 128, 255, 255},
+		},
+		"1d noise": {
+			Noise:            &noise.Noise{Values: []float64{0, 1}, From: []int{0}, To: []int{2}, Resolution: 1},
+			ExpectedErrorMsg: "Derivatives can only be computed from 2 dimensional noise",
+		},
+	}
+
+	for name, testCase := range testCases {
+		img, err := image.NormalMap(testCase.Noise, testCase.ZFactor)
+		if testCase.ExpectedErrorMsg != "" {
+			if err == nil || err.Error() != testCase.ExpectedErrorMsg {
+				t.Errorf("'%s' failed. Expected error '%v', received '%v'", name, testCase.ExpectedErrorMsg, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("'%s' failed. An unexpected error occurred: %v", name, err.Error())
+			continue
+		}
+
+		bounds := img.Bounds()
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+				if result := img.(*stdImage.RGBA).RGBAAt(x, y); result != testCase.Expected {
+					t.Errorf("'%s' failed at pixel (%d, %d). Expected %v, received %v", name, x, y, testCase.Expected, result)
+				}
+			}
+		}
+	}
+}
+
+func TestEncodeNormalMap(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	terrain := &noise.Noise{Values: []float64{0, 1, 2, 3, 4, 5}, From: []int{0, 0}, To: []int{3, 2}, Resolution: 1}
+	if err := image.EncodeNormalMap(buffer, terrain, 1); err != nil {
+		t.Fatalf("Unexpected error: %s", err.Error())
+	}
+
+	decoded, err := png.Decode(buffer)
+	if err != nil {
+		t.Fatalf("Expected a valid PNG, received error: %s", err.Error())
+	}
+	if bounds := decoded.Bounds(); bounds.Dx() != 3 || bounds.Dy() != 2 {
+		t.Errorf("Expected a 3x2 image, received %dx%d", bounds.Dx(), bounds.Dy())
+	}
+}
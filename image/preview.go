@@ -0,0 +1,56 @@
+package image
+
+import (
+	"errors"
+	stdImage "image"
+	"image/color"
+	"image/png"
+	"io"
+
+	"github.com/bcokert/terragen/noise"
+)
+
+// Preview converts 2D noise into a color image, tinting each sample by its normalized height along the ramp
+// If a hillshade is given, each color is darkened by how much light its slope receives
+func Preview(noise *noise.Noise, normalizer Normalizer, ramp ColorRamp, hillshade *Hillshade) (stdImage.Image, error) {
+	shape := noise.Shape()
+	if len(shape) != 2 {
+		return nil, errors.New("Previews can only be created from 2 dimensional noise")
+	}
+
+	width, height := shape[0], shape[1]
+	img := stdImage.NewRGBA(stdImage.Rect(0, 0, width, height))
+
+	var shades []float64
+	if hillshade != nil {
+		shades = hillshade.Shade(noise)
+	}
+
+	for x := 0; x < width; x++ {
+		for y := 0; y < height; y++ {
+			tint := ramp.At(normalizer(noise.Values[x*height+y]))
+			if shades != nil {
+				shade := shades[x*height+y]
+				tint = color.RGBA{
+					R: uint8(float64(tint.R)*shade + 0.5),
+					G: uint8(float64(tint.G)*shade + 0.5),
+					B: uint8(float64(tint.B)*shade + 0.5),
+					A: tint.A,
+				}
+			}
+			img.SetRGBA(x, y, tint)
+		}
+	}
+
+	return img, nil
+}
+
+// EncodePreview writes 2D noise to w as a color PNG preview
+func EncodePreview(w io.Writer, noise *noise.Noise, normalizer Normalizer, ramp ColorRamp, hillshade *Hillshade) error {
+	img, err := Preview(noise, normalizer, ramp, hillshade)
+	if err != nil {
+		return err
+	}
+
+	return png.Encode(w, img)
+}
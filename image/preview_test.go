@@ -0,0 +1,78 @@
+package image_test
+
+import (
+	"bytes"
+	stdImage "image"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"github.com/bcokert/terragen/image"
+	"github.com/bcokert/terragen/noise"
+)
+
+func TestPreview(t *testing.T) {
+	ramp := image.ColorRamp{
+		{Position: 0, Color: color.RGBA{0, 0, 200, 255}},
+		{Position: 1, Color: color.RGBA{200, 0, 0, 255}},
+	}
+	flat := &image.Hillshade{Azimuth: 0, Altitude: 90, ZFactor: 0}
+
+	testCases := map[string]struct {
+		Noise            *noise.Noise
+		Hillshade        *image.Hillshade
+		ExpectedPixels   map[[2]int]color.RGBA
+		ExpectedErrorMsg string
+	}{
+		"unshaded": {
+			Noise:          &noise.Noise{Values: []float64{0, 0.5, 1, 0.5}, From: []int{0, 0}, To: []int{2, 2}, Resolution: 1},
+			ExpectedPixels: map[[2]int]color.RGBA{{0, 0}: {0, 0, 200, 255}, {0, 1}: {100, 0, 100, 255}, {1, 0}: {200, 0, 0, 255}},
+		},
+		"fully lit": {
+			Noise:          &noise.Noise{Values: []float64{0, 0.5, 1, 0.5}, From: []int{0, 0}, To: []int{2, 2}, Resolution: 1},
+			Hillshade:      flat,
+			ExpectedPixels: map[[2]int]color.RGBA{{0, 0}: {0, 0, 200, 255}, {1, 0}: {200, 0, 0, 255}},
+		},
+		"1d noise": {
+			Noise:            &noise.Noise{Values: []float64{0, 1}, From: []int{0}, To: []int{2}, Resolution: 1},
+			ExpectedErrorMsg: "Previews can only be created from 2 dimensional noise",
+		},
+	}
+
+	for name, testCase := range testCases {
+		img, err := image.Preview(testCase.Noise, image.NewRangeNormalizer(0, 1), ramp, testCase.Hillshade)
+		if testCase.ExpectedErrorMsg != "" {
+			if err == nil || err.Error() != testCase.ExpectedErrorMsg {
+				t.Errorf("'%s' failed. Expected error '%v', received '%v'", name, testCase.ExpectedErrorMsg, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("'%s' failed. An unexpected error occurred: %v", name, err.Error())
+			continue
+		}
+
+		for pixel, expected := range testCase.ExpectedPixels {
+			if result := img.(*stdImage.RGBA).RGBAAt(pixel[0], pixel[1]); result != expected {
+				t.Errorf("'%s' failed at pixel %v. Expected %v, received %v", name, pixel, expected, result)
+			}
+		}
+	}
+}
+
+func TestEncodePreview(t *testing.T) {
+	n := &noise.Noise{Values: []float64{0, 1, 2, 3, 4, 5}, From: []int{0, 0}, To: []int{3, 2}, Resolution: 1}
+	hillshade := image.NewDefaultHillshade()
+	buffer := &bytes.Buffer{}
+	if err := image.EncodePreview(buffer, n, image.NewMinMaxNormalizer(n.Values), image.ColorRamps["terrain"], &hillshade); err != nil {
+		t.Fatalf("An unexpected error occurred: %v", err.Error())
+	}
+
+	decoded, err := png.Decode(buffer)
+	if err != nil {
+		t.Fatalf("Could not decode png: %v", err.Error())
+	}
+	if bounds := decoded.Bounds(); bounds.Dx() != 3 || bounds.Dy() != 2 {
+		t.Errorf("Expected bounds 3x2, received %v", bounds)
+	}
+}
@@ -0,0 +1,111 @@
+package image
+
+import (
+	"encoding/hex"
+	"errors"
+	"image/color"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// A ColorStop pins a color to a position in the range [0, 1] of a ColorRamp
+type ColorStop struct {
+	Position float64
+	Color    color.RGBA
+}
+
+// A ColorRamp is a gradient of colors, defined by stops sorted by position
+type ColorRamp []ColorStop
+
+// ColorRamps is a map from ramp names to predefined ColorRamps
+var ColorRamps = map[string]ColorRamp{
+	"grayscale": {
+		{Position: 0, Color: color.RGBA{0, 0, 0, 255}},
+		{Position: 1, Color: color.RGBA{255, 255, 255, 255}},
+	},
+	"terrain": {
+		{Position: 0, Color: color.RGBA{12, 44, 104, 255}},
+		{Position: 0.35, Color: color.RGBA{46, 118, 178, 255}},
+		{Position: 0.4, Color: color.RGBA{226, 210, 150, 255}},
+		{Position: 0.45, Color: color.RGBA{92, 150, 64, 255}},
+		{Position: 0.65, Color: color.RGBA{54, 100, 42, 255}},
+		{Position: 0.8, Color: color.RGBA{120, 108, 96, 255}},
+		{Position: 0.9, Color: color.RGBA{150, 144, 140, 255}},
+		{Position: 1, Color: color.RGBA{250, 250, 252, 255}},
+	},
+	"hypsometric": {
+		{Position: 0, Color: color.RGBA{80, 140, 90, 255}},
+		{Position: 0.25, Color: color.RGBA{160, 190, 110, 255}},
+		{Position: 0.5, Color: color.RGBA{230, 220, 150, 255}},
+		{Position: 0.75, Color: color.RGBA{190, 140, 90, 255}},
+		{Position: 0.9, Color: color.RGBA{160, 120, 100, 255}},
+		{Position: 1, Color: color.RGBA{255, 255, 255, 255}},
+	},
+}
+
+// At returns the color of the ramp at the given position, linearly interpolating between the surrounding stops
+// Positions before the first stop or after the last stop take the color of that stop
+func (ramp ColorRamp) At(position float64) color.RGBA {
+	if len(ramp) == 0 {
+		return color.RGBA{0, 0, 0, 255}
+	}
+
+	if position <= ramp[0].Position {
+		return ramp[0].Color
+	}
+
+	for i := 1; i < len(ramp); i++ {
+		if position <= ramp[i].Position {
+			low, high := ramp[i-1], ramp[i]
+			bias := (position - low.Position) / (high.Position - low.Position)
+			return color.RGBA{
+				R: lerpChannel(bias, low.Color.R, high.Color.R),
+				G: lerpChannel(bias, low.Color.G, high.Color.G),
+				B: lerpChannel(bias, low.Color.B, high.Color.B),
+				A: lerpChannel(bias, low.Color.A, high.Color.A),
+			}
+		}
+	}
+
+	return ramp[len(ramp)-1].Color
+}
+
+// ParseColorRamp parses a ramp of the form "position:rrggbb,position:rrggbb,..." into a ColorRamp
+// The stops may be given in any order, but there must be at least two of them
+func ParseColorRamp(v string) (ColorRamp, error) {
+	ramp := ColorRamp{}
+
+	for _, stop := range strings.Split(v, ",") {
+		parts := strings.Split(stop, ":")
+		if len(parts) != 2 {
+			return nil, errors.New("Color stops must be of the form position:rrggbb")
+		}
+
+		position, err := strconv.ParseFloat(parts[0], 64)
+		if err != nil || position < 0 || position > 1 {
+			return nil, errors.New("Color stop positions must be numbers between 0 and 1")
+		}
+
+		rgb, err := hex.DecodeString(strings.TrimPrefix(parts[1], "#"))
+		if err != nil || len(rgb) != 3 {
+			return nil, errors.New("Color stop colors must be 6 digit hex colors")
+		}
+
+		ramp = append(ramp, ColorStop{Position: position, Color: color.RGBA{rgb[0], rgb[1], rgb[2], 255}})
+	}
+
+	if len(ramp) < 2 {
+		return nil, errors.New("Color ramps must have at least two stops")
+	}
+
+	sort.SliceStable(ramp, func(i, j int) bool {
+		return ramp[i].Position < ramp[j].Position
+	})
+
+	return ramp, nil
+}
+
+func lerpChannel(bias float64, a, b uint8) uint8 {
+	return uint8(float64(a)*(1-bias) + float64(b)*bias + 0.5)
+}
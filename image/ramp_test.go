@@ -0,0 +1,87 @@
+package image_test
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/bcokert/terragen/image"
+)
+
+func TestColorRamp_At(t *testing.T) {
+	ramp := image.ColorRamp{
+		{Position: 0.2, Color: color.RGBA{0, 0, 0, 255}},
+		{Position: 0.6, Color: color.RGBA{200, 100, 40, 255}},
+		{Position: 1, Color: color.RGBA{255, 255, 255, 255}},
+	}
+
+	testCases := map[string]struct {
+		Ramp     image.ColorRamp
+		Position float64
+		Expected color.RGBA
+	}{
+		"before first stop": {Ramp: ramp, Position: 0, Expected: color.RGBA{0, 0, 0, 255}},
+		"on a stop":         {Ramp: ramp, Position: 0.6, Expected: color.RGBA{200, 100, 40, 255}},
+		"between stops":     {Ramp: ramp, Position: 0.4, Expected: color.RGBA{100, 50, 20, 255}},
+		"after last stop":   {Ramp: ramp, Position: 1.5, Expected: color.RGBA{255, 255, 255, 255}},
+		"empty ramp":        {Ramp: image.ColorRamp{}, Position: 0.5, Expected: color.RGBA{0, 0, 0, 255}},
+	}
+
+	for name, testCase := range testCases {
+		if result := testCase.Ramp.At(testCase.Position); result != testCase.Expected {
+			t.Errorf("'%s' failed. Expected %v, received %v", name, testCase.Expected, result)
+		}
+	}
+}
+
+func TestParseColorRamp(t *testing.T) {
+	testCases := map[string]struct {
+		Input            string
+		Expected         image.ColorRamp
+		ExpectedErrorMsg string
+	}{
+		"sorted": {
+			Input: "0:000000,1:ffffff",
+			Expected: image.ColorRamp{
+				{Position: 0, Color: color.RGBA{0, 0, 0, 255}},
+				{Position: 1, Color: color.RGBA{255, 255, 255, 255}},
+			},
+		},
+		"unsorted with hashes": {
+			Input: "1:#ff0000,0:#00ff00,0.5:#0000ff",
+			Expected: image.ColorRamp{
+				{Position: 0, Color: color.RGBA{0, 255, 0, 255}},
+				{Position: 0.5, Color: color.RGBA{0, 0, 255, 255}},
+				{Position: 1, Color: color.RGBA{255, 0, 0, 255}},
+			},
+		},
+		"missing color":     {Input: "0,1:ffffff", ExpectedErrorMsg: "Color stops must be of the form position:rrggbb"},
+		"invalid position":  {Input: "0:000000,2:ffffff", ExpectedErrorMsg: "Color stop positions must be numbers between 0 and 1"},
+		"invalid color":     {Input: "0:000000,1:fff", ExpectedErrorMsg: "Color stop colors must be 6 digit hex colors"},
+		"single stop":       {Input: "0:000000", ExpectedErrorMsg: "Color ramps must have at least two stops"},
+		"non numeric color": {Input: "0:banana,1:ffffff", ExpectedErrorMsg: "Color stop colors must be 6 digit hex colors"},
+	}
+
+	for name, testCase := range testCases {
+		result, err := image.ParseColorRamp(testCase.Input)
+		if testCase.ExpectedErrorMsg != "" {
+			if err == nil || err.Error() != testCase.ExpectedErrorMsg {
+				t.Errorf("'%s' failed. Expected error '%v', received '%v'", name, testCase.ExpectedErrorMsg, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("'%s' failed. An unexpected error occurred: %v", name, err.Error())
+			continue
+		}
+		if len(result) != len(testCase.Expected) {
+			t.Errorf("'%s' failed. Expected %v, received %v", name, testCase.Expected, result)
+			continue
+		}
+		for i := range result {
+			if result[i] != testCase.Expected[i] {
+				t.Errorf("'%s' failed. Expected %v, received %v", name, testCase.Expected, result)
+				break
+			}
+		}
+	}
+}
@@ -1,15 +1,27 @@
 package main
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
 	stdLog "log"
 
 	"os"
 
+	"github.com/bcokert/terragen/cache"
 	"github.com/bcokert/terragen/http"
 	"github.com/bcokert/terragen/log"
+	"github.com/bcokert/terragen/storage"
+	"github.com/bcokert/terragen/world"
 	"github.com/julienschmidt/httprouter"
 )
 
+// cacheBytes and cacheDiskBytes are the budgets of the result cache in memory and on disk
+const (
+	cacheBytes     = 256 << 20
+	cacheDiskBytes = 4 << 30
+)
+
 func main() {
 	port := os.Getenv("TERRAGEN_PORT")
 	if port == "" {
@@ -26,6 +38,36 @@ func main() {
 		stdLog.Fatal("No bundle file hash was specified for server. Please set the TERRAGEN_JAVASCRIPT_BUNDLE variable")
 	}
 
+	// Recipes and worlds are only kept between restarts if there is a database to keep them in
+	var recipes storage.Store = storage.NewMemoryStore()
+	var worlds world.Store = world.NewMemoryStore()
+	if databasePath := os.Getenv("TERRAGEN_DATABASE"); databasePath != "" {
+		boltStore, err := storage.OpenBoltStore(databasePath)
+		if err != nil {
+			stdLog.Fatalf("Failed to open the database at %s: %s", databasePath, err.Error())
+		}
+		if worlds, err = world.NewBoltStore(boltStore.DB()); err != nil {
+			boltStore.Close()
+			stdLog.Fatalf("Failed to open the worlds in the database at %s: %s", databasePath, err.Error())
+		}
+		recipes = boltStore
+	}
+
+	// Results are cached by a hash of the service itself, so the disk never serves results generated by an earlier build
+	buildVersion, err := hashExecutable()
+	if err != nil {
+		stdLog.Fatalf("Failed to hash the service executable: %s", err.Error())
+	}
+
+	// Results are only cached on disk if there is a directory to keep them in
+	var cacheDisk *cache.Disk
+	if cacheDirectory := os.Getenv("TERRAGEN_CACHE_DIRECTORY"); cacheDirectory != "" {
+		if cacheDisk, err = cache.OpenDisk(cacheDirectory, cacheDiskBytes); err != nil {
+			stdLog.Fatalf("Failed to open the cache directory %s: %s", cacheDirectory, err.Error())
+		}
+	}
+	results := cache.New(cacheBytes, cacheDisk)
+
 	router := httprouter.New()
 
 	router.GET("/static/*path", http.HandleStatic(assetsDir))
@@ -34,9 +76,82 @@ func main() {
 
 	router.GET("/amiup", http.TimedRequest(http.HandleStatus(), "Amiup"))
 
-	router.GET("/noise", http.TimedRequest(http.HandleNoise(), "Noise"))
+	router.GET("/noise", http.TimedRequest(http.CachedRequest(http.HandleNoise(), results, buildVersion), "Noise"))
+
+	router.GET("/heightmap", http.TimedRequest(http.CachedRequest(http.HandleHeightmap(), results, buildVersion), "Heightmap"))
+
+	router.POST("/upsample", http.TimedRequest(http.HandleUpsample(), "Upsample"))
+
+	router.GET("/preview", http.TimedRequest(http.CachedRequest(http.HandlePreview(), results, buildVersion), "Preview"))
+
+	router.GET("/mesh", http.TimedRequest(http.CachedRequest(http.HandleMesh(), results, buildVersion), "Mesh"))
+
+	router.GET("/tiles/:z/:x/:y", http.TimedRequest(http.HandleTile(), "Tile"))
+
+	router.GET("/volume", http.TimedRequest(http.HandleVolume(), "Volume"))
+
+	router.GET("/hydrology", http.TimedRequest(http.HandleHydrology(), "Hydrology"))
+
+	router.GET("/water", http.TimedRequest(http.HandleWater(), "Water"))
+
+	router.GET("/tectonics", http.TimedRequest(http.HandleTectonics(), "Tectonics"))
+
+	router.GET("/climate", http.TimedRequest(http.HandleClimate(), "Climate"))
+
+	router.GET("/contours", http.TimedRequest(http.HandleContours(), "Contours"))
+
+	router.GET("/scatter", http.TimedRequest(http.HandleScatter(), "Scatter"))
+
+	router.GET("/derivatives", http.TimedRequest(http.HandleDerivatives(), "Derivatives"))
+
+	router.GET("/normalmap", http.TimedRequest(http.CachedRequest(http.HandleNormalMap(), results, buildVersion), "NormalMap"))
+
+	router.GET("/statistics", http.TimedRequest(http.HandleStatistics(), "Statistics"))
+
+	router.GET("/biomes", http.TimedRequest(http.HandleBiomes(), "Biomes"))
+	router.POST("/biomes", http.TimedRequest(http.HandleBiomes(), "Biomes"))
+
+	router.POST("/worlds", http.TimedRequest(http.HandleCreateWorld(worlds), "CreateWorld"))
+	router.GET("/worlds/:id", http.TimedRequest(http.HandleWorld(worlds), "World"))
+	router.GET("/worlds/:id/strokes", http.TimedRequest(http.HandleStrokes(worlds), "Strokes"))
+	router.POST("/worlds/:id/strokes", http.TimedRequest(http.HandleAddStroke(worlds), "AddStroke"))
+	router.POST("/worlds/:id/undo", http.TimedRequest(http.HandleUndoStroke(worlds), "UndoStroke"))
+	router.GET("/worlds/:id/noise", http.TimedRequest(http.HandleWorldNoise(worlds), "WorldNoise"))
+
+	router.GET("/recipes", http.TimedRequest(http.HandleRecipes(recipes), "Recipes"))
+	router.POST("/recipes", http.TimedRequest(http.HandleCreateRecipe(recipes), "CreateRecipe"))
+	router.GET("/recipes/:id", http.TimedRequest(http.HandleRecipe(recipes), "Recipe"))
+	router.PUT("/recipes/:id", http.TimedRequest(http.HandleUpdateRecipe(recipes), "UpdateRecipe"))
+	router.DELETE("/recipes/:id", http.TimedRequest(http.HandleDeleteRecipe(recipes), "DeleteRecipe"))
+	router.GET("/recipes/:id/versions", http.TimedRequest(http.HandleRecipeVersions(recipes), "RecipeVersions"))
+	router.GET("/recipes/:id/noise", http.TimedRequest(http.HandleRecipeNoise(recipes), "RecipeNoise"))
 
 	log.Info("Starting Terragen Service on port %s and asset directory %s", port, assetsDir)
 
-	stdLog.Fatal(http.ListenAndServe(":"+port, router))
+	// The database is closed before exiting, since Fatal exits without running deferred calls
+	err = http.ListenAndServe(":"+port, router)
+	recipes.Close()
+	if err != nil {
+		stdLog.Fatal(err)
+	}
+	log.Info("Stopped Terragen Service")
+}
+
+// hashExecutable hashes the running executable, which changes whenever the service is rebuilt with different code
+func hashExecutable() (string, error) {
+	path, err := os.Executable()
+	if err != nil {
+		return "", err
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
 }
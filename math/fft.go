@@ -0,0 +1,156 @@
+package math
+
+import (
+	"errors"
+	"math"
+	"math/cmplx"
+)
+
+// FFT returns the discrete Fourier transform of the values, which can be any length
+// Lengths that are powers of 2 use the radix 2 Cooley-Tukey algorithm, and other lengths use Bluestein's algorithm, so both are O(n log n)
+func FFT(values []complex128) []complex128 {
+	return transform(values, false)
+}
+
+// InverseFFT returns the inverse discrete Fourier transform of the values, scaled by 1/n so that it undoes FFT
+func InverseFFT(values []complex128) []complex128 {
+	result := transform(values, true)
+	scale := complex(1/float64(len(result)), 0)
+	for i := range result {
+		result[i] *= scale
+	}
+	return result
+}
+
+// FFT2D returns the 2 dimensional discrete Fourier transform of the values, which are laid out with the first dimension outermost like noise
+func FFT2D(values []complex128, shape [2]int) ([]complex128, error) {
+	return transform2D(values, shape, false)
+}
+
+// InverseFFT2D returns the 2 dimensional inverse discrete Fourier transform of the values, scaled so that it undoes FFT2D
+func InverseFFT2D(values []complex128, shape [2]int) ([]complex128, error) {
+	result, err := transform2D(values, shape, true)
+	if err != nil {
+		return nil, err
+	}
+	scale := complex(1/float64(len(result)), 0)
+	for i := range result {
+		result[i] *= scale
+	}
+	return result, nil
+}
+
+// transform2D transforms each row of the values, and then each column
+func transform2D(values []complex128, shape [2]int, inverse bool) ([]complex128, error) {
+	if shape[0] < 1 || shape[1] < 1 || len(values) != shape[0]*shape[1] {
+		return nil, errors.New("Values must have a positive shape, with one value for each element of it")
+	}
+
+	result := make([]complex128, len(values))
+	for x := 0; x < shape[0]; x++ {
+		copy(result[x*shape[1]:(x+1)*shape[1]], transform(values[x*shape[1]:(x+1)*shape[1]], inverse))
+	}
+
+	column := make([]complex128, shape[0])
+	for y := 0; y < shape[1]; y++ {
+		for x := range column {
+			column[x] = result[x*shape[1]+y]
+		}
+		for x, value := range transform(column, inverse) {
+			result[x*shape[1]+y] = value
+		}
+	}
+
+	return result, nil
+}
+
+// transform returns the unscaled transform of the values, with the sign of the exponent flipped for the inverse
+func transform(values []complex128, inverse bool) []complex128 {
+	result := make([]complex128, len(values))
+	copy(result, values)
+
+	if len(result) < 2 {
+		return result
+	}
+	if len(result)&(len(result)-1) == 0 {
+		radix2(result, inverse)
+		return result
+	}
+	return bluestein(result, inverse)
+}
+
+// radix2 transforms the values in place, whose length must be a power of 2
+func radix2(values []complex128, inverse bool) {
+	n := len(values)
+
+	// Reorder the values by the bit reversal of their index, so each pass combines adjacent halves
+	for i, j := 0, 0; i < n; i++ {
+		if i < j {
+			values[i], values[j] = values[j], values[i]
+		}
+		bit := n >> 1
+		for ; j&bit != 0; bit >>= 1 {
+			j ^= bit
+		}
+		j |= bit
+	}
+
+	sign := -1.0
+	if inverse {
+		sign = 1
+	}
+
+	for size := 2; size <= n; size <<= 1 {
+		half := size / 2
+		for k := 0; k < half; k++ {
+			twiddle := cmplx.Rect(1, sign*2*math.Pi*float64(k)/float64(size))
+			for start := 0; start < n; start += size {
+				even, odd := values[start+k], values[start+k+half]*twiddle
+				values[start+k], values[start+k+half] = even+odd, even-odd
+			}
+		}
+	}
+}
+
+// bluestein transforms values of any length by rewriting the transform as a convolution with a chirp, which is done with power of 2 transforms
+func bluestein(values []complex128, inverse bool) []complex128 {
+	n := len(values)
+
+	sign := -1.0
+	if inverse {
+		sign = 1
+	}
+
+	// The chirp is exp(±iπk²/n), where k² is reduced mod 2n to keep the angle precise for long inputs
+	chirp := make([]complex128, n)
+	for k := range chirp {
+		chirp[k] = cmplx.Rect(1, sign*math.Pi*float64((k*k)%(2*n))/float64(n))
+	}
+
+	size := 1
+	for size < 2*n-1 {
+		size <<= 1
+	}
+
+	a, b := make([]complex128, size), make([]complex128, size)
+	for k := 0; k < n; k++ {
+		a[k] = values[k] * chirp[k]
+		b[k] = cmplx.Conj(chirp[k])
+		if k > 0 {
+			b[size-k] = cmplx.Conj(chirp[k])
+		}
+	}
+
+	radix2(a, false)
+	radix2(b, false)
+	for i := range a {
+		a[i] *= b[i]
+	}
+	radix2(a, true)
+
+	result := make([]complex128, n)
+	for k := range result {
+		result[k] = a[k] * chirp[k] / complex(float64(size), 0)
+	}
+	return result
+}
@@ -0,0 +1,144 @@
+package math_test
+
+import (
+	"math"
+	"math/cmplx"
+	"testing"
+
+	tgmath "github.com/bcokert/terragen/math"
+)
+
+// dft is the direct O(n²) transform that the FFT is checked against
+func dft(values []complex128) []complex128 {
+	result := make([]complex128, len(values))
+	for k := range result {
+		for j, value := range values {
+			result[k] += value * cmplx.Rect(1, -2*math.Pi*float64(j*k)/float64(len(values)))
+		}
+	}
+	return result
+}
+
+func isComplexSliceEqual(a, b []complex128) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if cmplx.Abs(a[i]-b[i]) > 0.000000001 {
+			return false
+		}
+	}
+	return true
+}
+
+func TestFFT(t *testing.T) {
+	testCases := map[string]struct {
+		Values   []complex128
+		Expected []complex128
+	}{
+		"empty": {
+			Values:   []complex128{},
+			Expected: []complex128{},
+		},
+		"single": {
+			Values:   []complex128{3 + 1i},
+			Expected: []complex128{3 + 1i},
+		},
+		"impulse": {
+			Values:   []complex128{1, 0, 0, 0},
+			Expected: []complex128{1, 1, 1, 1},
+		},
+		"constant": {
+			Values:   []complex128{2, 2, 2, 2, 2, 2, 2, 2},
+			Expected: []complex128{16, 0, 0, 0, 0, 0, 0, 0},
+		},
+		"cosine": {
+			Values:   []complex128{1, 0, -1, 0},
+			Expected: []complex128{0, 2, 0, 2},
+		},
+		"odd length impulse": {
+			Values:   []complex128{1, 0, 0, 0, 0},
+			Expected: []complex128{1, 1, 1, 1, 1},
+		},
+		"odd length constant": {
+			Values:   []complex128{1, 1, 1},
+			Expected: []complex128{3, 0, 0},
+		},
+	}
+
+	for name, testCase := range testCases {
+		if result := tgmath.FFT(testCase.Values); !isComplexSliceEqual(result, testCase.Expected) {
+			t.Errorf("'%s' failed. Expected %v, received %v", name, testCase.Expected, result)
+		}
+	}
+}
+
+func TestFFT_MatchesDFT(t *testing.T) {
+	source := tgmath.NewDefaultSource(42)
+
+	for _, length := range []int{2, 3, 7, 8, 12, 16, 100, 128} {
+		values := make([]complex128, length)
+		for i := range values {
+			values[i] = complex(source.Float64()*2-1, source.Float64()*2-1)
+		}
+
+		expected := dft(values)
+		if result := tgmath.FFT(values); !isComplexSliceEqual(result, expected) {
+			t.Errorf("'%d values' failed. Expected %v, received %v", length, expected, result)
+		}
+		if result := tgmath.InverseFFT(expected); !isComplexSliceEqual(result, values) {
+			t.Errorf("'%d values inverse' failed. Expected %v, received %v", length, values, result)
+		}
+	}
+}
+
+func TestFFT2D(t *testing.T) {
+	testCases := map[string]struct {
+		Values        []complex128
+		Shape         [2]int
+		Expected      []complex128
+		ExpectedError string
+	}{
+		"impulse": {
+			Values:   []complex128{1, 0, 0, 0, 0, 0},
+			Shape:    [2]int{2, 3},
+			Expected: []complex128{1, 1, 1, 1, 1, 1},
+		},
+		"constant": {
+			Values:   []complex128{1, 1, 1, 1, 1, 1},
+			Shape:    [2]int{3, 2},
+			Expected: []complex128{6, 0, 0, 0, 0, 0},
+		},
+		"alternating rows": {
+			Values:   []complex128{1, 1, -1, -1},
+			Shape:    [2]int{2, 2},
+			Expected: []complex128{0, 0, 4, 0},
+		},
+		"wrong shape": {
+			Values:        []complex128{1, 1, 1},
+			Shape:         [2]int{2, 2},
+			ExpectedError: "Values must have a positive shape, with one value for each element of it",
+		},
+	}
+
+	for name, testCase := range testCases {
+		result, err := tgmath.FFT2D(testCase.Values, testCase.Shape)
+		if testCase.ExpectedError != "" {
+			if err == nil || err.Error() != testCase.ExpectedError {
+				t.Errorf("'%s' failed. Expected error %s, received %v", name, testCase.ExpectedError, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("'%s' failed. Expected no error, received %s", name, err.Error())
+			continue
+		}
+
+		if !isComplexSliceEqual(result, testCase.Expected) {
+			t.Errorf("'%s' failed. Expected %v, received %v", name, testCase.Expected, result)
+		}
+		if inverse, _ := tgmath.InverseFFT2D(result, testCase.Shape); !isComplexSliceEqual(inverse, testCase.Values) {
+			t.Errorf("'%s' failed. Expected the inverse to be %v, received %v", name, testCase.Values, inverse)
+		}
+	}
+}
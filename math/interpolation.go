@@ -30,3 +30,19 @@ func NewInterpolator(easingFunc EasingFunction) Interpolator {
 		return a*(1-delta) + b*delta
 	}
 }
+
+// A CubicInterpolator interpolates between b and c, using their outer neighbours a and d to keep the slope continuous across samples
+type CubicInterpolator func(percentage, a, b, c, d float64) float64
+
+// CatmullRom interpolates with a Catmull-Rom spline, which passes through every sample with the slope between its neighbours
+// Percentages outside of [0, 1] are clamped, like other Interpolators
+func CatmullRom(percentage, a, b, c, d float64) float64 {
+	t := percentage
+	if t > 1 {
+		t = 1
+	}
+	if t < 0 {
+		t = 0
+	}
+	return b + 0.5*t*(c-a+t*(2*a-5*b+4*c-d+t*(3*(b-c)+d-a)))
+}
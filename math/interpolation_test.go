@@ -133,3 +133,25 @@ func TestNewInterpolator(t *testing.T) {
 		}
 	}
 }
+
+func TestCatmullRom(t *testing.T) {
+	testCases := map[string]struct {
+		Percentage float64
+		A, B, C, D float64
+		Expected   float64
+	}{
+		"start":           {Percentage: 0, A: 9, B: 2, C: 4, D: -3, Expected: 2},
+		"end":             {Percentage: 1, A: 9, B: 2, C: 4, D: -3, Expected: 4},
+		"line":            {Percentage: 0.25, A: 0, B: 1, C: 2, D: 3, Expected: 1.25},
+		"parabola":        {Percentage: 0.5, A: 1, B: 0, C: 1, D: 4, Expected: 0.25},
+		"flat neighbours": {Percentage: 0.5, A: 0, B: 0, C: 1, D: 1, Expected: 0.5},
+		"clamped":         {Percentage: 1.5, A: 9, B: 2, C: 4, D: -3, Expected: 4},
+	}
+
+	for name, testCase := range testCases {
+		result := math.CatmullRom(testCase.Percentage, testCase.A, testCase.B, testCase.C, testCase.D)
+		if !math.IsFloatEqual(result, testCase.Expected) {
+			t.Errorf("'%s' failed. Expected %v, received %v", name, testCase.Expected, result)
+		}
+	}
+}
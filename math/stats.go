@@ -0,0 +1,86 @@
+package math
+
+import (
+	"errors"
+	"math"
+)
+
+// Mean returns the average of the values, or 0 if there are none
+func Mean(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sum := 0.0
+	for _, value := range values {
+		sum += value
+	}
+	return sum / float64(len(values))
+}
+
+// Variance returns the population variance of the values, or 0 if there are none
+func Variance(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	mean, sum := Mean(values), 0.0
+	for _, value := range values {
+		sum += (value - mean) * (value - mean)
+	}
+	return sum / float64(len(values))
+}
+
+// MinMax returns the smallest and largest of the values, or 0 for both if there are none
+func MinMax(values []float64) (min, max float64) {
+	if len(values) == 0 {
+		return 0, 0
+	}
+	min, max = values[0], values[0]
+	for _, value := range values[1:] {
+		min, max = math.Min(min, value), math.Max(max, value)
+	}
+	return min, max
+}
+
+// Histogram counts the values in each of the given number of equal bins between min and max
+// Values equal to max are counted in the last bin, and values outside of [min, max] aren't counted
+func Histogram(values []float64, bins int, min, max float64) ([]int, error) {
+	if bins < 1 {
+		return nil, errors.New("Histograms require at least 1 bin")
+	}
+	if max < min {
+		return nil, errors.New("Histograms require a min no more than their max")
+	}
+
+	counts := make([]int, bins)
+	for _, value := range values {
+		if value < min || value > max {
+			continue
+		}
+		bin := bins - 1
+		if max > min {
+			bin = int(math.Min(float64(bins-1), (value-min)/(max-min)*float64(bins)))
+		}
+		counts[bin]++
+	}
+	return counts, nil
+}
+
+// LinearRegression returns the slope and intercept of the least squares line through the points
+func LinearRegression(xs, ys []float64) (slope, intercept float64, err error) {
+	if len(xs) != len(ys) {
+		return 0, 0, errors.New("Linear regressions require the same number of xs and ys")
+	}
+
+	meanX, meanY := Mean(xs), Mean(ys)
+	covariance, variance := 0.0, 0.0
+	for i := range xs {
+		covariance += (xs[i] - meanX) * (ys[i] - meanY)
+		variance += (xs[i] - meanX) * (xs[i] - meanX)
+	}
+	if variance == 0 {
+		return 0, 0, errors.New("Linear regressions require at least 2 distinct xs")
+	}
+
+	slope = covariance / variance
+	return slope, meanY - slope*meanX, nil
+}
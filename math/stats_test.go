@@ -0,0 +1,92 @@
+package math_test
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	tgmath "github.com/bcokert/terragen/math"
+)
+
+func TestMeanVarianceMinMax(t *testing.T) {
+	testCases := map[string]struct {
+		Values           []float64
+		ExpectedMean     float64
+		ExpectedVariance float64
+		ExpectedMin      float64
+		ExpectedMax      float64
+	}{
+		"empty":    {Values: []float64{}, ExpectedMean: 0, ExpectedVariance: 0, ExpectedMin: 0, ExpectedMax: 0},
+		"single":   {Values: []float64{-3}, ExpectedMean: -3, ExpectedVariance: 0, ExpectedMin: -3, ExpectedMax: -3},
+		"several":  {Values: []float64{2, 4, 4, 4, 5, 5, 7, 9}, ExpectedMean: 5, ExpectedVariance: 4, ExpectedMin: 2, ExpectedMax: 9},
+		"negative": {Values: []float64{-1, 1}, ExpectedMean: 0, ExpectedVariance: 1, ExpectedMin: -1, ExpectedMax: 1},
+	}
+
+	for name, testCase := range testCases {
+		if result := tgmath.Mean(testCase.Values); !tgmath.IsFloatEqual(result, testCase.ExpectedMean) {
+			t.Errorf("'%s' failed. Expected mean %v, received %v", name, testCase.ExpectedMean, result)
+		}
+		if result := tgmath.Variance(testCase.Values); !tgmath.IsFloatEqual(result, testCase.ExpectedVariance) {
+			t.Errorf("'%s' failed. Expected variance %v, received %v", name, testCase.ExpectedVariance, result)
+		}
+		if min, max := tgmath.MinMax(testCase.Values); min != testCase.ExpectedMin || max != testCase.ExpectedMax {
+			t.Errorf("'%s' failed. Expected min and max %v, %v, received %v, %v", name, testCase.ExpectedMin, testCase.ExpectedMax, min, max)
+		}
+	}
+}
+
+func TestHistogram(t *testing.T) {
+	testCases := map[string]struct {
+		Values        []float64
+		Bins          int
+		Min, Max      float64
+		Expected      []int
+		ExpectedError string
+	}{
+		"even":           {Values: []float64{0, 0.1, 0.5, 0.6, 0.99, 1}, Bins: 2, Min: 0, Max: 1, Expected: []int{2, 4}},
+		"outside":        {Values: []float64{-1, 0.2, 2}, Bins: 4, Min: 0, Max: 1, Expected: []int{1, 0, 0, 0}},
+		"single value":   {Values: []float64{3, 3}, Bins: 3, Min: 3, Max: 3, Expected: []int{0, 0, 2}},
+		"no bins":        {Values: []float64{1}, Bins: 0, Min: 0, Max: 1, ExpectedError: "Histograms require at least 1 bin"},
+		"inverted range": {Values: []float64{1}, Bins: 2, Min: 1, Max: 0, ExpectedError: "Histograms require a min no more than their max"},
+	}
+
+	for name, testCase := range testCases {
+		result, err := tgmath.Histogram(testCase.Values, testCase.Bins, testCase.Min, testCase.Max)
+		if testCase.ExpectedError != "" {
+			if err == nil || err.Error() != testCase.ExpectedError {
+				t.Errorf("'%s' failed. Expected error %s, received %v", name, testCase.ExpectedError, err)
+			}
+			continue
+		}
+		if !reflect.DeepEqual(result, testCase.Expected) {
+			t.Errorf("'%s' failed. Expected %v, received %v", name, testCase.Expected, result)
+		}
+	}
+}
+
+func TestLinearRegression(t *testing.T) {
+	testCases := map[string]struct {
+		Xs, Ys            []float64
+		ExpectedSlope     float64
+		ExpectedIntercept float64
+		ExpectedError     string
+	}{
+		"line":      {Xs: []float64{0, 1, 2}, Ys: []float64{1, 3, 5}, ExpectedSlope: 2, ExpectedIntercept: 1},
+		"scattered": {Xs: []float64{0, 0, 2, 2}, Ys: []float64{0, 2, 2, 4}, ExpectedSlope: 1, ExpectedIntercept: 1},
+		"lengths":   {Xs: []float64{0, 1}, Ys: []float64{1}, ExpectedError: "Linear regressions require the same number of xs and ys"},
+		"vertical":  {Xs: []float64{1, 1}, Ys: []float64{1, 2}, ExpectedError: "Linear regressions require at least 2 distinct xs"},
+	}
+
+	for name, testCase := range testCases {
+		slope, intercept, err := tgmath.LinearRegression(testCase.Xs, testCase.Ys)
+		if testCase.ExpectedError != "" {
+			if err == nil || err.Error() != testCase.ExpectedError {
+				t.Errorf("'%s' failed. Expected error %s, received %v", name, testCase.ExpectedError, err)
+			}
+			continue
+		}
+		if math.Abs(slope-testCase.ExpectedSlope) > 1e-12 || math.Abs(intercept-testCase.ExpectedIntercept) > 1e-12 {
+			t.Errorf("'%s' failed. Expected %v, %v, received %v, %v", name, testCase.ExpectedSlope, testCase.ExpectedIntercept, slope, intercept)
+		}
+	}
+}
@@ -55,3 +55,65 @@ func (vec *Vec2) Normalize() error {
 func (vec *Vec2) Dot(other Vec2) float64 {
 	return vec[0]*other[0] + vec[1]*other[1]
 }
+
+// Vec3 Represents a 3d vector with simple operations
+type Vec3 [3]float64
+
+// IsEqual returns true if the two vectors are equal
+func (vec Vec3) IsEqual(other Vec3) bool {
+	for i := range vec {
+		if math.Abs(vec[i]-other[i]) > 0.00000000000001 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Length returns the length of a Vec3
+func (vec Vec3) Length() float64 {
+	return math.Sqrt(vec[0]*vec[0] + vec[1]*vec[1] + vec[2]*vec[2])
+}
+
+// Normalize mutates a Vec3 so that it is normalized. It will fail if the vector has length zero
+func (vec *Vec3) Normalize() error {
+	length := vec.Length()
+	if length == 0.0 {
+		return fmt.Errorf("Tried to normalize a vector with length zero: %v", *vec)
+	}
+
+	vec[0] = vec[0] / length
+	vec[1] = vec[1] / length
+	vec[2] = vec[2] / length
+
+	return nil
+}
+
+// Add returns the sum of this vector and another
+func (vec Vec3) Add(other Vec3) Vec3 {
+	return Vec3{vec[0] + other[0], vec[1] + other[1], vec[2] + other[2]}
+}
+
+// Sub returns the difference of this vector and another
+func (vec Vec3) Sub(other Vec3) Vec3 {
+	return Vec3{vec[0] - other[0], vec[1] - other[1], vec[2] - other[2]}
+}
+
+// Scale returns this vector with each component multiplied by the scalar
+func (vec Vec3) Scale(scalar float64) Vec3 {
+	return Vec3{vec[0] * scalar, vec[1] * scalar, vec[2] * scalar}
+}
+
+// Dot takes another vector and returns the dot product between the two
+func (vec Vec3) Dot(other Vec3) float64 {
+	return vec[0]*other[0] + vec[1]*other[1] + vec[2]*other[2]
+}
+
+// Cross takes another vector and returns the cross product between the two
+func (vec Vec3) Cross(other Vec3) Vec3 {
+	return Vec3{
+		vec[1]*other[2] - vec[2]*other[1],
+		vec[2]*other[0] - vec[0]*other[2],
+		vec[0]*other[1] - vec[1]*other[0],
+	}
+}
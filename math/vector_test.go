@@ -170,3 +170,143 @@ func TestVec2_Dot(t *testing.T) {
 		}
 	}
 }
+
+func TestVec3_IsEqual(t *testing.T) {
+	testCases := map[string]struct {
+		Vec1, Vec2 tgmath.Vec3
+		Expected   bool
+	}{
+		"zero": {
+			Vec1:     tgmath.Vec3{0, 0, 0},
+			Vec2:     tgmath.Vec3{0, 0, 0},
+			Expected: true,
+		},
+		"equal": {
+			Vec1:     tgmath.Vec3{0.0956276, -4532926.2364, 3},
+			Vec2:     tgmath.Vec3{0.0956276, -4532926.2364, 3},
+			Expected: true,
+		},
+		"almost equal": {
+			Vec1:     tgmath.Vec3{0.0956276, -4532926.2364, 3},
+			Vec2:     tgmath.Vec3{0.0956276, -4532926.2364, 3.0000001},
+			Expected: false,
+		},
+	}
+
+	for name, testCase := range testCases {
+		result := testCase.Vec1.IsEqual(testCase.Vec2)
+		if result != testCase.Expected {
+			t.Errorf("'%s' failed. Expected %v, received %v", name, testCase.Expected, result)
+		}
+	}
+}
+
+func TestVec3_Length(t *testing.T) {
+	testCases := map[string]struct {
+		Vec      tgmath.Vec3
+		Expected float64
+	}{
+		"zero": {
+			Vec:      tgmath.Vec3{0, 0, 0},
+			Expected: 0,
+		},
+		"orthogonal": {
+			Vec:      tgmath.Vec3{0, 0, 5},
+			Expected: 5,
+		},
+		"negatives": {
+			Vec:      tgmath.Vec3{-2, 3, -6},
+			Expected: 7,
+		},
+	}
+
+	for name, testCase := range testCases {
+		result := testCase.Vec.Length()
+		if result != testCase.Expected {
+			t.Errorf("'%s' failed. Expected %v, received %v", name, testCase.Expected, result)
+		}
+	}
+}
+
+func TestVec3_Normalize(t *testing.T) {
+	testCases := map[string]struct {
+		Vec              tgmath.Vec3
+		Expected         tgmath.Vec3
+		ExpectedErrorMsg string
+	}{
+		"zero": {
+			Vec:              tgmath.Vec3{0, 0, 0},
+			ExpectedErrorMsg: "Tried to normalize a vector with length zero: [0 0 0]",
+		},
+		"orthogonal": {
+			Vec:      tgmath.Vec3{0, 5, 0},
+			Expected: tgmath.Vec3{0, 1, 0},
+		},
+		"regular": {
+			Vec:      tgmath.Vec3{-2, 3, -6},
+			Expected: tgmath.Vec3{-2.0 / 7, 3.0 / 7, -6.0 / 7},
+		},
+	}
+
+	for name, testCase := range testCases {
+		err := testCase.Vec.Normalize()
+		if testCase.ExpectedErrorMsg == "" {
+			if err != nil {
+				t.Errorf("'%s' failed. An unexpected error occurred: %v", name, err.Error())
+			}
+			if !testCase.Vec.IsEqual(testCase.Expected) {
+				t.Errorf("'%s' failed. Expected %v, received %v", name, testCase.Expected, testCase.Vec)
+			}
+		} else {
+			if err == nil || testCase.ExpectedErrorMsg != err.Error() {
+				t.Errorf("'%s' failed. Expected error '%v', received '%v'", name, testCase.ExpectedErrorMsg, err)
+			}
+		}
+	}
+}
+
+func TestVec3_Arithmetic(t *testing.T) {
+	a, b := tgmath.Vec3{1, 2, 3}, tgmath.Vec3{-4, 0.5, 2}
+
+	testCases := map[string]struct {
+		Result   tgmath.Vec3
+		Expected tgmath.Vec3
+	}{
+		"add":         {Result: a.Add(b), Expected: tgmath.Vec3{-3, 2.5, 5}},
+		"sub":         {Result: a.Sub(b), Expected: tgmath.Vec3{5, 1.5, 1}},
+		"scale":       {Result: a.Scale(-2), Expected: tgmath.Vec3{-2, -4, -6}},
+		"cross":       {Result: tgmath.Vec3{1, 0, 0}.Cross(tgmath.Vec3{0, 1, 0}), Expected: tgmath.Vec3{0, 0, 1}},
+		"cross order": {Result: tgmath.Vec3{0, 1, 0}.Cross(tgmath.Vec3{1, 0, 0}), Expected: tgmath.Vec3{0, 0, -1}},
+	}
+
+	for name, testCase := range testCases {
+		if !testCase.Result.IsEqual(testCase.Expected) {
+			t.Errorf("'%s' failed. Expected %v, received %v", name, testCase.Expected, testCase.Result)
+		}
+	}
+}
+
+func TestVec3_Dot(t *testing.T) {
+	testCases := map[string]struct {
+		Vec1, Vec2 tgmath.Vec3
+		Expected   float64
+	}{
+		"orthogonal": {
+			Vec1:     tgmath.Vec3{1, 0, 0},
+			Vec2:     tgmath.Vec3{0, 0, 1},
+			Expected: 0,
+		},
+		"complex": {
+			Vec1:     tgmath.Vec3{-3, 4, 2},
+			Vec2:     tgmath.Vec3{1, 2, -1},
+			Expected: -3 + 8 - 2,
+		},
+	}
+
+	for name, testCase := range testCases {
+		result := testCase.Vec1.Dot(testCase.Vec2)
+		if result != testCase.Expected {
+			t.Errorf("'%s' failed. Expected %v, received %v", name, testCase.Expected, result)
+		}
+	}
+}
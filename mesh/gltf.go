@@ -0,0 +1,163 @@
+package mesh
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"math"
+)
+
+// glTF constants, from the 2.0 specification
+const (
+	glbMagic           = 0x46546C67
+	glbVersion         = 2
+	glbChunkJSON       = 0x4E4F534A
+	glbChunkBIN        = 0x004E4942
+	gltfFloat          = 5126
+	gltfUnsignedInt    = 5125
+	gltfArrayBuffer    = 34962
+	gltfElementBuffer  = 34963
+	gltfModeTriangles  = 4
+	gltfGeneratorLabel = "terragen"
+)
+
+type gltfDocument struct {
+	Asset       gltfAsset        `json:"asset"`
+	Scene       int              `json:"scene"`
+	Scenes      []gltfScene      `json:"scenes"`
+	Nodes       []gltfNode       `json:"nodes"`
+	Meshes      []gltfMesh       `json:"meshes"`
+	Buffers     []gltfBuffer     `json:"buffers"`
+	BufferViews []gltfBufferView `json:"bufferViews"`
+	Accessors   []gltfAccessor   `json:"accessors"`
+}
+
+type gltfAsset struct {
+	Version   string `json:"version"`
+	Generator string `json:"generator"`
+}
+
+type gltfScene struct {
+	Nodes []int `json:"nodes"`
+}
+
+type gltfNode struct {
+	Mesh int `json:"mesh"`
+}
+
+type gltfMesh struct {
+	Primitives []gltfPrimitive `json:"primitives"`
+}
+
+type gltfPrimitive struct {
+	Attributes map[string]int `json:"attributes"`
+	Indices    int            `json:"indices"`
+	Mode       int            `json:"mode"`
+}
+
+type gltfBuffer struct {
+	ByteLength int `json:"byteLength"`
+}
+
+type gltfBufferView struct {
+	Buffer     int `json:"buffer"`
+	ByteOffset int `json:"byteOffset"`
+	ByteLength int `json:"byteLength"`
+	Target     int `json:"target"`
+}
+
+type gltfAccessor struct {
+	BufferView    int       `json:"bufferView"`
+	ComponentType int       `json:"componentType"`
+	Count         int       `json:"count"`
+	Type          string    `json:"type"`
+	Min           []float64 `json:"min,omitempty"`
+	Max           []float64 `json:"max,omitempty"`
+}
+
+// WriteGLB writes the mesh to w as a binary glTF 2.0 file, with positions, normals, texture coordinates and indices
+// Positions and normals are converted to y-up, as required by glTF
+func WriteGLB(w io.Writer, mesh *Mesh) error {
+	bin := &bytes.Buffer{}
+	doc := gltfDocument{
+		Asset:   gltfAsset{Version: "2.0", Generator: gltfGeneratorLabel},
+		Scene:   0,
+		Scenes:  []gltfScene{{Nodes: []int{0}}},
+		Nodes:   []gltfNode{{Mesh: 0}},
+		Meshes:  []gltfMesh{{Primitives: []gltfPrimitive{{Attributes: map[string]int{"POSITION": 0, "NORMAL": 1, "TEXCOORD_0": 2}, Indices: 3, Mode: gltfModeTriangles}}}},
+		Buffers: []gltfBuffer{{}},
+	}
+
+	// addView appends the values to the binary chunk as a new buffer view, and describes them with an accessor
+	// every component is 4 bytes, so views are always aligned as the spec requires
+	addView := func(values interface{}, count int, componentType int, accessorType string, target int) {
+		offset := bin.Len()
+		binary.Write(bin, binary.LittleEndian, values)
+		doc.BufferViews = append(doc.BufferViews, gltfBufferView{Buffer: 0, ByteOffset: offset, ByteLength: bin.Len() - offset, Target: target})
+		doc.Accessors = append(doc.Accessors, gltfAccessor{BufferView: len(doc.BufferViews) - 1, ComponentType: componentType, Count: count, Type: accessorType})
+	}
+
+	positions := make([]float32, 0, len(mesh.Positions)*3)
+	min := []float64{math.Inf(1), math.Inf(1), math.Inf(1)}
+	max := []float64{math.Inf(-1), math.Inf(-1), math.Inf(-1)}
+	for _, position := range mesh.Positions {
+		p := yUp(position)
+		for i := range p {
+			// The bounds must match the stored single precision values exactly
+			component := float32(p[i])
+			positions = append(positions, component)
+			min[i] = math.Min(min[i], float64(component))
+			max[i] = math.Max(max[i], float64(component))
+		}
+	}
+	addView(positions, len(mesh.Positions), gltfFloat, "VEC3", gltfArrayBuffer)
+	if len(mesh.Positions) > 0 {
+		doc.Accessors[0].Min, doc.Accessors[0].Max = min, max
+	}
+
+	normals := make([]float32, 0, len(mesh.Normals)*3)
+	for _, normal := range mesh.Normals {
+		n := yUp(normal)
+		normals = append(normals, float32(n[0]), float32(n[1]), float32(n[2]))
+	}
+	addView(normals, len(mesh.Normals), gltfFloat, "VEC3", gltfArrayBuffer)
+
+	// glTF texture coordinates start at the top left, rather than the bottom left
+	uvs := make([]float32, 0, len(mesh.UVs)*2)
+	for _, uv := range mesh.UVs {
+		uvs = append(uvs, float32(uv[0]), float32(1-uv[1]))
+	}
+	addView(uvs, len(mesh.UVs), gltfFloat, "VEC2", gltfArrayBuffer)
+
+	indices := make([]uint32, len(mesh.Indices))
+	for i, index := range mesh.Indices {
+		indices[i] = uint32(index)
+	}
+	addView(indices, len(mesh.Indices), gltfUnsignedInt, "SCALAR", gltfElementBuffer)
+
+	doc.Buffers[0].ByteLength = bin.Len()
+
+	jsonChunk, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+
+	// Chunks must be 4 byte aligned, with JSON padded by spaces and binary by zeros
+	for len(jsonChunk)%4 != 0 {
+		jsonChunk = append(jsonChunk, ' ')
+	}
+	for bin.Len()%4 != 0 {
+		bin.WriteByte(0)
+	}
+
+	output := &bytes.Buffer{}
+	binary.Write(output, binary.LittleEndian, []uint32{glbMagic, glbVersion, uint32(12 + 8 + len(jsonChunk) + 8 + bin.Len())})
+	binary.Write(output, binary.LittleEndian, []uint32{uint32(len(jsonChunk)), glbChunkJSON})
+	output.Write(jsonChunk)
+	binary.Write(output, binary.LittleEndian, []uint32{uint32(bin.Len()), glbChunkBIN})
+	output.Write(bin.Bytes())
+
+	_, err = w.Write(output.Bytes())
+	return err
+}
@@ -0,0 +1,81 @@
+package mesh_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"testing"
+
+	"github.com/bcokert/terragen/mesh"
+)
+
+func TestWriteGLB(t *testing.T) {
+	quad := unitQuad()
+	quad.ComputeNormals()
+
+	testCases := map[string]struct {
+		Mesh              *mesh.Mesh
+		ExpectedBinLength int
+		ExpectedMin       []float64
+		ExpectedMax       []float64
+	}{
+		"quad": {
+			Mesh:              quad,
+			ExpectedBinLength: 4*3*4 + 4*3*4 + 4*2*4 + 6*4,
+			ExpectedMin:       []float64{0, 0, -1},
+			ExpectedMax:       []float64{1, 0, 0},
+		},
+	}
+
+	for name, testCase := range testCases {
+		buffer := &bytes.Buffer{}
+		if err := mesh.WriteGLB(buffer, testCase.Mesh); err != nil {
+			t.Errorf("'%s' failed. An unexpected error occurred: %v", name, err.Error())
+			continue
+		}
+		output := buffer.Bytes()
+
+		if magic := binary.LittleEndian.Uint32(output[0:]); magic != 0x46546C67 {
+			t.Errorf("'%s' failed. Expected glTF magic, received %x", name, magic)
+		}
+		if version := binary.LittleEndian.Uint32(output[4:]); version != 2 {
+			t.Errorf("'%s' failed. Expected version 2, received %d", name, version)
+		}
+		if length := binary.LittleEndian.Uint32(output[8:]); int(length) != len(output) {
+			t.Errorf("'%s' failed. Expected header length %d, received %d", name, len(output), length)
+		}
+
+		jsonLength := int(binary.LittleEndian.Uint32(output[12:]))
+		if jsonLength%4 != 0 {
+			t.Errorf("'%s' failed. Expected the JSON chunk to be 4 byte aligned, received length %d", name, jsonLength)
+		}
+		doc := struct {
+			Buffers   []struct{ ByteLength int }
+			Accessors []struct {
+				Count    int
+				Min, Max []float64
+			}
+		}{}
+		if err := json.Unmarshal(output[20:20+jsonLength], &doc); err != nil {
+			t.Errorf("'%s' failed. Could not decode JSON chunk: %v", name, err.Error())
+			continue
+		}
+
+		if len(doc.Buffers) != 1 || doc.Buffers[0].ByteLength != testCase.ExpectedBinLength {
+			t.Errorf("'%s' failed. Expected a single buffer of %d bytes, received %+v", name, testCase.ExpectedBinLength, doc.Buffers)
+		}
+		if binLength := int(binary.LittleEndian.Uint32(output[20+jsonLength:])); binLength != testCase.ExpectedBinLength {
+			t.Errorf("'%s' failed. Expected a binary chunk of %d bytes, received %d", name, testCase.ExpectedBinLength, binLength)
+		}
+		if len(doc.Accessors) != 4 || doc.Accessors[3].Count != len(testCase.Mesh.Indices) {
+			t.Errorf("'%s' failed. Expected 4 accessors with %d indices, received %+v", name, len(testCase.Mesh.Indices), doc.Accessors)
+			continue
+		}
+		for i := range testCase.ExpectedMin {
+			if doc.Accessors[0].Min[i] != testCase.ExpectedMin[i] || doc.Accessors[0].Max[i] != testCase.ExpectedMax[i] {
+				t.Errorf("'%s' failed. Expected position bounds %v %v, received %v %v", name, testCase.ExpectedMin, testCase.ExpectedMax, doc.Accessors[0].Min, doc.Accessors[0].Max)
+				break
+			}
+		}
+	}
+}
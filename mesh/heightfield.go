@@ -0,0 +1,184 @@
+package mesh
+
+import (
+	"errors"
+	"math"
+
+	tgmath "github.com/bcokert/terragen/math"
+	"github.com/bcokert/terragen/noise"
+)
+
+// Options control how a heightfield is turned into a mesh
+// VerticalScale multiplies each noise value to get the height of its vertex
+// Skirt is how far below the lowest point of the terrain to extend walls around its border, or 0 for no walls
+// Closed caps the bottom of the walls with a flat base, so the mesh is a watertight solid suitable for 3D printing
+// MaxError simplifies the mesh to as few triangles as possible, while keeping every sample within MaxError of the surface
+// A MaxError of 0 keeps the full regular grid
+type Options struct {
+	VerticalScale float64
+	Skirt         float64
+	Closed        bool
+	MaxError      float64
+}
+
+// NewDefaultOptions creates Options for an open surface at the natural scale of the noise
+func NewDefaultOptions() Options {
+	return Options{VerticalScale: 1}
+}
+
+// A Heightfield is a regular grid of heights anchored in world space, laid out the same way as noise values
+// The sample at (x, y) sits at Origin + (x, y) * Spacing
+type Heightfield struct {
+	Values  []float64
+	Width   int
+	Height  int
+	Origin  tgmath.Vec2
+	Spacing float64
+}
+
+// FromNoise triangulates 2D noise into a mesh, placing vertices at the world coordinates of their samples
+// so that meshes from neighbouring ranges line up
+func FromNoise(noise *noise.Noise, options Options) (*Mesh, error) {
+	shape := noise.Shape()
+	if len(shape) != 2 {
+		return nil, errors.New("Meshes can only be created from 2 dimensional noise")
+	}
+
+	return FromHeightfield(Heightfield{
+		Values:  noise.Values,
+		Width:   shape[0],
+		Height:  shape[1],
+		Origin:  tgmath.Vec2{float64(noise.From[0]), float64(noise.From[1])},
+		Spacing: 1 / float64(noise.Resolution),
+	}, options)
+}
+
+// FromHeightfield triangulates a heightfield into a regular grid mesh, with one vertex per sample and two triangles per grid cell
+// If options has a MaxError, the grid is instead simplified to the fewest triangles that stay within it
+func FromHeightfield(field Heightfield, options Options) (*Mesh, error) {
+	width, height := field.Width, field.Height
+	if width < 2 || height < 2 {
+		return nil, errors.New("Meshes require at least 2 samples in each dimension")
+	}
+
+	mesh := &Mesh{}
+
+	heights := make([]float64, len(field.Values))
+	for i, value := range field.Values {
+		heights[i] = value * options.VerticalScale
+	}
+
+	addSample := func(x, y int) int {
+		return mesh.AddVertex(
+			tgmath.Vec3{field.Origin[0] + float64(x)*field.Spacing, field.Origin[1] + float64(y)*field.Spacing, heights[x*height+y]},
+			tgmath.Vec2{float64(x) / float64(width-1), float64(y) / float64(height-1)},
+		)
+	}
+
+	var border []int
+	if options.MaxError > 0 {
+		triangulation := newGreedyTriangulation(heights, width, height)
+		triangulation.run(options.MaxError)
+
+		for i := 0; i < len(triangulation.coords); i += 2 {
+			addSample(triangulation.coords[i], triangulation.coords[i+1])
+		}
+
+		// The triangulation winds its triangles the other way, so they're reversed to face up
+		for i := 0; i < len(triangulation.triangles); i += 3 {
+			mesh.AddTriangle(triangulation.triangles[i], triangulation.triangles[i+2], triangulation.triangles[i+1])
+		}
+
+		border = triangulation.border()
+		for i, j := 0, len(border)-1; i < j; i, j = i+1, j-1 {
+			border[i], border[j] = border[j], border[i]
+		}
+	} else {
+		for x := 0; x < width; x++ {
+			for y := 0; y < height; y++ {
+				addSample(x, y)
+			}
+		}
+
+		for x := 0; x < width-1; x++ {
+			for y := 0; y < height-1; y++ {
+				a, b, c, d := x*height+y, (x+1)*height+y, (x+1)*height+y+1, x*height+y+1
+				mesh.AddTriangle(a, b, c)
+				mesh.AddTriangle(a, c, d)
+			}
+		}
+
+		border = gridBorder(width, height)
+	}
+
+	if options.Skirt > 0 || options.Closed {
+		addSkirt(mesh, border, options)
+	}
+
+	mesh.ComputeNormals()
+
+	return mesh, nil
+}
+
+// gridBorder returns the indices of the vertices around the border of a width x height grid, counter-clockwise when viewed from above
+func gridBorder(width, height int) []int {
+	border := make([]int, 0, 2*(width+height))
+	for x := 0; x < width-1; x++ {
+		border = append(border, x*height)
+	}
+	for y := 0; y < height-1; y++ {
+		border = append(border, (width-1)*height+y)
+	}
+	for x := width - 1; x > 0; x-- {
+		border = append(border, x*height+height-1)
+	}
+	for y := height - 1; y > 0; y-- {
+		border = append(border, y)
+	}
+	return border
+}
+
+// addSkirt hangs walls from the given counter-clockwise border loop down to below the lowest point of the mesh, optionally capping them with a base
+// The walls and base get their own vertices, so their normals stay sharp where they meet the terrain
+func addSkirt(mesh *Mesh, border []int, options Options) {
+	min, _ := mesh.Bounds()
+	bottom := min[2] - math.Max(0, options.Skirt)
+
+	down := func(vertex int) tgmath.Vec3 {
+		return tgmath.Vec3{mesh.Positions[vertex][0], mesh.Positions[vertex][1], bottom}
+	}
+
+	for i := range border {
+		p, q := border[i], border[(i+1)%len(border)]
+		pTop := mesh.AddVertex(mesh.Positions[p], mesh.UVs[p])
+		qTop := mesh.AddVertex(mesh.Positions[q], mesh.UVs[q])
+		pBottom := mesh.AddVertex(down(p), mesh.UVs[p])
+		qBottom := mesh.AddVertex(down(q), mesh.UVs[q])
+		mesh.AddTriangle(pTop, pBottom, qBottom)
+		mesh.AddTriangle(pTop, qBottom, qTop)
+	}
+
+	if !options.Closed {
+		return
+	}
+
+	// Fan the base from its centroid. Heightfield borders are always star shaped around it, so the fan never overlaps itself
+	centroid := tgmath.Vec3{}
+	uvCentroid := tgmath.Vec2{}
+	for _, vertex := range border {
+		centroid = centroid.Add(down(vertex))
+		uvCentroid[0] += mesh.UVs[vertex][0]
+		uvCentroid[1] += mesh.UVs[vertex][1]
+	}
+	centroid = centroid.Scale(1 / float64(len(border)))
+	uvCentroid[0] /= float64(len(border))
+	uvCentroid[1] /= float64(len(border))
+
+	center := mesh.AddVertex(centroid, uvCentroid)
+	for i := range border {
+		p, q := border[i], border[(i+1)%len(border)]
+		pBottom := mesh.AddVertex(down(p), mesh.UVs[p])
+		qBottom := mesh.AddVertex(down(q), mesh.UVs[q])
+		mesh.AddTriangle(center, qBottom, pBottom)
+	}
+}
@@ -0,0 +1,133 @@
+package mesh_test
+
+import (
+	"math"
+	"testing"
+
+	tgmath "github.com/bcokert/terragen/math"
+	"github.com/bcokert/terragen/mesh"
+	"github.com/bcokert/terragen/noise"
+)
+
+const sqrt2 = 1.4142135623730951
+
+func bumpyNoise() *noise.Noise {
+	n := &noise.Noise{}
+	n.Generate([]int{-1, 2}, []int{2, 4}, 3, func(t []float64) float64 {
+		return math.Sin(t[0]) * math.Cos(t[1])
+	})
+	return n
+}
+
+// isWatertight returns true if every edge of the mesh is shared by exactly two triangles, in opposite directions
+// Vertices are compared by position, since skirts and bases have their own vertices
+func isWatertight(m *mesh.Mesh) bool {
+	edges := map[[2]tgmath.Vec3]int{}
+	for i := 0; i < len(m.Indices); i += 3 {
+		for j := 0; j < 3; j++ {
+			a, b := m.Positions[m.Indices[i+j]], m.Positions[m.Indices[i+(j+1)%3]]
+			edges[[2]tgmath.Vec3{a, b}]++
+		}
+	}
+	for edge, count := range edges {
+		if count != 1 || edges[[2]tgmath.Vec3{edge[1], edge[0]}] != 1 {
+			return false
+		}
+	}
+	return true
+}
+
+// signedVolume is positive for a closed mesh whose triangles all face outwards
+func signedVolume(m *mesh.Mesh) float64 {
+	volume := 0.0
+	for i := 0; i < len(m.Indices); i += 3 {
+		a, b, c := m.Positions[m.Indices[i]], m.Positions[m.Indices[i+1]], m.Positions[m.Indices[i+2]]
+		volume += a.Dot(b.Cross(c)) / 6
+	}
+	return volume
+}
+
+func TestFromNoise(t *testing.T) {
+	testCases := map[string]struct {
+		Noise             *noise.Noise
+		Options           mesh.Options
+		ExpectedVertices  int
+		ExpectedTriangles int
+		ExpectedClosed    bool
+		ExpectedErrorMsg  string
+	}{
+		"open surface": {
+			Noise:             bumpyNoise(),
+			Options:           mesh.NewDefaultOptions(),
+			ExpectedVertices:  9 * 6,
+			ExpectedTriangles: 8 * 5 * 2,
+		},
+		"skirt": {
+			Noise:             bumpyNoise(),
+			Options:           mesh.Options{VerticalScale: 2, Skirt: 0.5},
+			ExpectedVertices:  9*6 + 26*4,
+			ExpectedTriangles: 8*5*2 + 26*2,
+		},
+		"closed solid": {
+			Noise:             bumpyNoise(),
+			Options:           mesh.Options{VerticalScale: 3, Skirt: 1, Closed: true},
+			ExpectedVertices:  9*6 + 26*4 + 1 + 26*2,
+			ExpectedTriangles: 8*5*2 + 26*2 + 26,
+			ExpectedClosed:    true,
+		},
+		"1d noise": {
+			Noise:            &noise.Noise{Values: []float64{0, 1}, From: []int{0}, To: []int{2}, Resolution: 1},
+			Options:          mesh.NewDefaultOptions(),
+			ExpectedErrorMsg: "Meshes can only be created from 2 dimensional noise",
+		},
+		"too few samples": {
+			Noise:            &noise.Noise{Values: []float64{0, 1}, From: []int{0, 0}, To: []int{1, 2}, Resolution: 1},
+			Options:          mesh.NewDefaultOptions(),
+			ExpectedErrorMsg: "Meshes require at least 2 samples in each dimension",
+		},
+	}
+
+	for name, testCase := range testCases {
+		result, err := mesh.FromNoise(testCase.Noise, testCase.Options)
+		if testCase.ExpectedErrorMsg != "" {
+			if err == nil || err.Error() != testCase.ExpectedErrorMsg {
+				t.Errorf("'%s' failed. Expected error '%v', received '%v'", name, testCase.ExpectedErrorMsg, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("'%s' failed. An unexpected error occurred: %v", name, err.Error())
+			continue
+		}
+
+		if len(result.Positions) != testCase.ExpectedVertices || len(result.Normals) != testCase.ExpectedVertices || len(result.UVs) != testCase.ExpectedVertices {
+			t.Errorf("'%s' failed. Expected %d vertices, received %d positions, %d normals, %d uvs", name, testCase.ExpectedVertices, len(result.Positions), len(result.Normals), len(result.UVs))
+		}
+		if result.NumTriangles() != testCase.ExpectedTriangles {
+			t.Errorf("'%s' failed. Expected %d triangles, received %d", name, testCase.ExpectedTriangles, result.NumTriangles())
+		}
+
+		// The first grid vertex sits at the world coordinate of the first sample
+		expectedFirst := tgmath.Vec3{-1, 2, testCase.Noise.Values[0] * testCase.Options.VerticalScale}
+		if !result.Positions[0].IsEqual(expectedFirst) {
+			t.Errorf("'%s' failed. Expected first vertex %v, received %v", name, expectedFirst, result.Positions[0])
+		}
+
+		// Every terrain triangle should face up
+		for i := 0; i < 8*5*2; i++ {
+			if normal := result.FaceNormal(i); normal[2] <= 0 {
+				t.Errorf("'%s' failed. Expected triangle %d to face up, received normal %v", name, i, normal)
+				break
+			}
+		}
+
+		if testCase.ExpectedClosed {
+			if !isWatertight(result) {
+				t.Errorf("'%s' failed. Expected a watertight mesh", name)
+			}
+			if volume := signedVolume(result); volume <= 0 {
+				t.Errorf("'%s' failed. Expected a positive volume, received %v", name, volume)
+			}
+		}
+	}
+}
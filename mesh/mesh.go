@@ -0,0 +1,95 @@
+package mesh
+
+import (
+	tgmath "github.com/bcokert/terragen/math"
+)
+
+// A Mesh is an indexed triangle mesh, with a normal and texture coordinate for each vertex
+// Positions are z-up, and each triple of Indices is a triangle wound counter-clockwise when viewed from outside the surface
+type Mesh struct {
+	Positions []tgmath.Vec3
+	Normals   []tgmath.Vec3
+	UVs       []tgmath.Vec2
+	Indices   []int
+}
+
+// AddVertex appends a vertex to the mesh, returning its index. Its normal is left empty until ComputeNormals is called
+func (mesh *Mesh) AddVertex(position tgmath.Vec3, uv tgmath.Vec2) int {
+	mesh.Positions = append(mesh.Positions, position)
+	mesh.Normals = append(mesh.Normals, tgmath.Vec3{})
+	mesh.UVs = append(mesh.UVs, uv)
+	return len(mesh.Positions) - 1
+}
+
+// AddTriangle appends a triangle between the three vertex indices, which should be counter-clockwise when viewed from outside
+func (mesh *Mesh) AddTriangle(a, b, c int) {
+	mesh.Indices = append(mesh.Indices, a, b, c)
+}
+
+// NumTriangles returns the number of triangles in the mesh
+func (mesh *Mesh) NumTriangles() int {
+	return len(mesh.Indices) / 3
+}
+
+// FaceNormal returns the unit normal of the given triangle, or the zero vector if the triangle is degenerate
+func (mesh *Mesh) FaceNormal(triangle int) tgmath.Vec3 {
+	a := mesh.Positions[mesh.Indices[triangle*3]]
+	b := mesh.Positions[mesh.Indices[triangle*3+1]]
+	c := mesh.Positions[mesh.Indices[triangle*3+2]]
+
+	normal := b.Sub(a).Cross(c.Sub(a))
+	if err := normal.Normalize(); err != nil {
+		return tgmath.Vec3{}
+	}
+	return normal
+}
+
+// ComputeNormals sets the normal of each vertex to the area weighted average of the normals of the triangles that use it
+// Vertices that aren't part of any non degenerate triangle are given an up facing normal
+func (mesh *Mesh) ComputeNormals() {
+	normals := make([]tgmath.Vec3, len(mesh.Positions))
+
+	for i := 0; i+2 < len(mesh.Indices); i += 3 {
+		a, b, c := mesh.Indices[i], mesh.Indices[i+1], mesh.Indices[i+2]
+
+		// The cross product's length is twice the triangle's area, which gives the weighting for free
+		weighted := mesh.Positions[b].Sub(mesh.Positions[a]).Cross(mesh.Positions[c].Sub(mesh.Positions[a]))
+		normals[a] = normals[a].Add(weighted)
+		normals[b] = normals[b].Add(weighted)
+		normals[c] = normals[c].Add(weighted)
+	}
+
+	for i := range normals {
+		if err := normals[i].Normalize(); err != nil {
+			normals[i] = tgmath.Vec3{0, 0, 1}
+		}
+	}
+
+	mesh.Normals = normals
+}
+
+// Bounds returns the smallest and largest value of each component of the mesh's positions
+func (mesh *Mesh) Bounds() (min, max tgmath.Vec3) {
+	if len(mesh.Positions) == 0 {
+		return min, max
+	}
+
+	min, max = mesh.Positions[0], mesh.Positions[0]
+	for _, position := range mesh.Positions {
+		for i := range position {
+			if position[i] < min[i] {
+				min[i] = position[i]
+			}
+			if position[i] > max[i] {
+				max[i] = position[i]
+			}
+		}
+	}
+
+	return min, max
+}
+
+// yUp converts a z-up position or normal to the y-up convention used by most modelling tools and engines
+func yUp(vec tgmath.Vec3) tgmath.Vec3 {
+	return tgmath.Vec3{vec[0], vec[2], -vec[1]}
+}
@@ -0,0 +1,127 @@
+package mesh_test
+
+import (
+	"testing"
+
+	tgmath "github.com/bcokert/terragen/math"
+	"github.com/bcokert/terragen/mesh"
+)
+
+// unitQuad is a flat square made of two triangles, facing up
+func unitQuad() *mesh.Mesh {
+	m := &mesh.Mesh{}
+	a := m.AddVertex(tgmath.Vec3{0, 0, 0}, tgmath.Vec2{0, 0})
+	b := m.AddVertex(tgmath.Vec3{1, 0, 0}, tgmath.Vec2{1, 0})
+	c := m.AddVertex(tgmath.Vec3{1, 1, 0}, tgmath.Vec2{1, 1})
+	d := m.AddVertex(tgmath.Vec3{0, 1, 0}, tgmath.Vec2{0, 1})
+	m.AddTriangle(a, b, c)
+	m.AddTriangle(a, c, d)
+	return m
+}
+
+func TestMesh_FaceNormal(t *testing.T) {
+	testCases := map[string]struct {
+		Mesh     *mesh.Mesh
+		Triangle int
+		Expected tgmath.Vec3
+	}{
+		"up facing": {
+			Mesh:     unitQuad(),
+			Triangle: 1,
+			Expected: tgmath.Vec3{0, 0, 1},
+		},
+		"degenerate": {
+			Mesh: &mesh.Mesh{
+				Positions: []tgmath.Vec3{{0, 0, 0}, {1, 0, 0}, {2, 0, 0}},
+				Indices:   []int{0, 1, 2},
+			},
+			Triangle: 0,
+			Expected: tgmath.Vec3{0, 0, 0},
+		},
+		"clockwise faces down": {
+			Mesh: &mesh.Mesh{
+				Positions: []tgmath.Vec3{{0, 0, 0}, {1, 0, 0}, {0, 1, 0}},
+				Indices:   []int{0, 2, 1},
+			},
+			Triangle: 0,
+			Expected: tgmath.Vec3{0, 0, -1},
+		},
+	}
+
+	for name, testCase := range testCases {
+		if result := testCase.Mesh.FaceNormal(testCase.Triangle); !result.IsEqual(testCase.Expected) {
+			t.Errorf("'%s' failed. Expected %v, received %v", name, testCase.Expected, result)
+		}
+	}
+}
+
+func TestMesh_ComputeNormals(t *testing.T) {
+	testCases := map[string]struct {
+		Mesh     *mesh.Mesh
+		Expected []tgmath.Vec3
+	}{
+		"flat": {
+			Mesh:     unitQuad(),
+			Expected: []tgmath.Vec3{{0, 0, 1}, {0, 0, 1}, {0, 0, 1}, {0, 0, 1}},
+		},
+		"unused vertex": {
+			Mesh: &mesh.Mesh{
+				Positions: []tgmath.Vec3{{0, 0, 0}, {1, 0, 0}, {0, 1, 0}, {5, 5, 5}},
+				Indices:   []int{0, 1, 2},
+			},
+			Expected: []tgmath.Vec3{{0, 0, 1}, {0, 0, 1}, {0, 0, 1}, {0, 0, 1}},
+		},
+		"ridge": {
+			Mesh: &mesh.Mesh{
+				Positions: []tgmath.Vec3{{0, 0, 0}, {1, 0, 1}, {1, 1, 1}, {0, 1, 0}, {2, 0, 0}, {2, 1, 0}},
+				Indices:   []int{0, 1, 2, 0, 2, 3, 1, 4, 2, 2, 4, 5},
+			},
+			Expected: []tgmath.Vec3{
+				{-1 / sqrt2, 0, 1 / sqrt2},
+				{0, 0, 1},
+				{0, 0, 1},
+				{-1 / sqrt2, 0, 1 / sqrt2},
+				{1 / sqrt2, 0, 1 / sqrt2},
+				{1 / sqrt2, 0, 1 / sqrt2},
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase.Mesh.ComputeNormals()
+		for i, expected := range testCase.Expected {
+			if result := testCase.Mesh.Normals[i]; !result.IsEqual(expected) {
+				t.Errorf("'%s' failed at vertex %d. Expected %v, received %v", name, i, expected, result)
+			}
+		}
+	}
+}
+
+func TestMesh_Bounds(t *testing.T) {
+	testCases := map[string]struct {
+		Mesh        *mesh.Mesh
+		ExpectedMin tgmath.Vec3
+		ExpectedMax tgmath.Vec3
+	}{
+		"empty": {
+			Mesh: &mesh.Mesh{},
+		},
+		"quad": {
+			Mesh:        unitQuad(),
+			ExpectedMin: tgmath.Vec3{0, 0, 0},
+			ExpectedMax: tgmath.Vec3{1, 1, 0},
+		},
+		"scattered": {
+			Mesh:        &mesh.Mesh{Positions: []tgmath.Vec3{{3, -1, 2}, {-4, 5, 0}, {1, 1, -7}}},
+			ExpectedMin: tgmath.Vec3{-4, -1, -7},
+			ExpectedMax: tgmath.Vec3{3, 5, 2},
+		},
+	}
+
+	for name, testCase := range testCases {
+		min, max := testCase.Mesh.Bounds()
+		if !min.IsEqual(testCase.ExpectedMin) || !max.IsEqual(testCase.ExpectedMax) {
+			t.Errorf("'%s' failed. Expected %v %v, received %v %v", name, testCase.ExpectedMin, testCase.ExpectedMax, min, max)
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package mesh
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+)
+
+// WriteOBJ writes the mesh to w as a Wavefront OBJ file
+// Positions and normals are converted to y-up, which is what most tools expect when importing OBJ
+func WriteOBJ(w io.Writer, mesh *Mesh) error {
+	buffered := bufio.NewWriter(w)
+
+	fmt.Fprintf(buffered, "# terragen heightfield, %d vertices, %d triangles\n", len(mesh.Positions), mesh.NumTriangles())
+	for _, position := range mesh.Positions {
+		p := yUp(position)
+		fmt.Fprintf(buffered, "v %g %g %g\n", p[0], p[1], p[2])
+	}
+	for _, uv := range mesh.UVs {
+		fmt.Fprintf(buffered, "vt %g %g\n", uv[0], uv[1])
+	}
+	for _, normal := range mesh.Normals {
+		n := yUp(normal)
+		fmt.Fprintf(buffered, "vn %g %g %g\n", n[0], n[1], n[2])
+	}
+
+	// OBJ indices are 1 based
+	for i := 0; i+2 < len(mesh.Indices); i += 3 {
+		a, b, c := mesh.Indices[i]+1, mesh.Indices[i+1]+1, mesh.Indices[i+2]+1
+		fmt.Fprintf(buffered, "f %d/%d/%d %d/%d/%d %d/%d/%d\n", a, a, a, b, b, b, c, c, c)
+	}
+
+	return buffered.Flush()
+}
@@ -0,0 +1,56 @@
+package mesh_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/bcokert/terragen/mesh"
+)
+
+func TestWriteOBJ(t *testing.T) {
+	quad := unitQuad()
+	quad.ComputeNormals()
+
+	testCases := map[string]struct {
+		Mesh          *mesh.Mesh
+		ExpectedLines []string
+	}{
+		"quad": {
+			Mesh: quad,
+			ExpectedLines: []string{
+				"# terragen heightfield, 4 vertices, 2 triangles",
+				"v 0 0 -0",
+				"v 1 0 -0",
+				"v 1 0 -1",
+				"v 0 0 -1",
+				"vt 0 0",
+				"vt 1 0",
+				"vt 1 1",
+				"vt 0 1",
+				"vn 0 1 -0",
+				"vn 0 1 -0",
+				"vn 0 1 -0",
+				"vn 0 1 -0",
+				"f 1/1/1 2/2/2 3/3/3",
+				"f 1/1/1 3/3/3 4/4/4",
+				"",
+			},
+		},
+		"empty": {
+			Mesh:          &mesh.Mesh{},
+			ExpectedLines: []string{"# terragen heightfield, 0 vertices, 0 triangles", ""},
+		},
+	}
+
+	for name, testCase := range testCases {
+		buffer := &bytes.Buffer{}
+		if err := mesh.WriteOBJ(buffer, testCase.Mesh); err != nil {
+			t.Errorf("'%s' failed. An unexpected error occurred: %v", name, err.Error())
+			continue
+		}
+		if expected := strings.Join(testCase.ExpectedLines, "\n"); buffer.String() != expected {
+			t.Errorf("'%s' failed. Expected\n%s\nreceived\n%s", name, expected, buffer.String())
+		}
+	}
+}
@@ -0,0 +1,409 @@
+package mesh
+
+import (
+	"errors"
+	"math"
+)
+
+// greedyTriangulation incrementally builds a Delaunay triangulation of a heightfield grid, by repeatedly inserting the sample
+// with the largest vertical error into the triangle that contains it (Garland and Heckbert's greedy insertion)
+// Triangles are stored as triples of vertex indices, with a matching halfedge array linking each edge to its twin in the
+// neighbouring triangle, or -1 on the border of the grid. Each triangle is positively oriented in grid coordinates
+type greedyTriangulation struct {
+	width, height int
+	heights       []float64
+
+	coords    []int
+	triangles []int
+	halfedges []int
+
+	// per triangle metadata: the sample with the largest error, and where the triangle sits in the queue
+	candidates   []int
+	queueIndices []int
+
+	// a max heap of triangles, ordered by their largest error
+	queue  []int
+	errors []float64
+
+	// triangles that have been added, but whose candidates haven't been found yet
+	pending []int
+}
+
+// newGreedyTriangulation creates a triangulation of the grid made of two triangles covering its corners
+// heights holds width*height values, laid out the same way as noise values
+func newGreedyTriangulation(heights []float64, width, height int) *greedyTriangulation {
+	g := &greedyTriangulation{width: width, height: height, heights: heights}
+
+	p0 := g.addPoint(0, 0)
+	p1 := g.addPoint(width-1, 0)
+	p2 := g.addPoint(0, height-1)
+	p3 := g.addPoint(width-1, height-1)
+
+	t0 := g.addTriangle(p3, p0, p2, -1, -1, -1, len(g.triangles))
+	g.addTriangle(p0, p3, p1, t0, -1, -1, len(g.triangles))
+	g.flush()
+
+	return g
+}
+
+// run inserts points until no sample is further than maxError from the surface
+func (g *greedyTriangulation) run(maxError float64) {
+	for len(g.queue) > 0 && g.errors[0] > maxError {
+		g.step()
+		g.flush()
+	}
+}
+
+func (g *greedyTriangulation) heightAt(x, y int) float64 {
+	return g.heights[x*g.height+y]
+}
+
+func (g *greedyTriangulation) addPoint(x, y int) int {
+	g.coords = append(g.coords, x, y)
+	return len(g.coords)/2 - 1
+}
+
+// addTriangle writes a triangle at the halfedge index e, which is either a reused slot or the end of the arrays
+// ab, bc and ca are the twins of the new triangle's edges, which are linked back to it
+func (g *greedyTriangulation) addTriangle(a, b, c, ab, bc, ca, e int) int {
+	t := e / 3
+	if e == len(g.triangles) {
+		g.triangles = append(g.triangles, a, b, c)
+		g.halfedges = append(g.halfedges, ab, bc, ca)
+		g.candidates = append(g.candidates, 0, 0)
+		g.queueIndices = append(g.queueIndices, -1)
+	} else {
+		g.triangles[e], g.triangles[e+1], g.triangles[e+2] = a, b, c
+		g.halfedges[e], g.halfedges[e+1], g.halfedges[e+2] = ab, bc, ca
+		g.candidates[2*t], g.candidates[2*t+1] = 0, 0
+		g.queueIndices[t] = -1
+	}
+
+	if ab >= 0 {
+		g.halfedges[ab] = e
+	}
+	if bc >= 0 {
+		g.halfedges[bc] = e + 1
+	}
+	if ca >= 0 {
+		g.halfedges[ca] = e + 2
+	}
+
+	g.pending = append(g.pending, t)
+	return e
+}
+
+// flush finds the candidate of each pending triangle and queues it
+func (g *greedyTriangulation) flush() {
+	for _, t := range g.pending {
+		g.findCandidate(t)
+	}
+	g.pending = g.pending[:0]
+}
+
+// findCandidate rasterizes the triangle over the grid to find the sample furthest from its plane
+func (g *greedyTriangulation) findCandidate(t int) {
+	p0x, p0y := g.coords[2*g.triangles[t*3]], g.coords[2*g.triangles[t*3]+1]
+	p1x, p1y := g.coords[2*g.triangles[t*3+1]], g.coords[2*g.triangles[t*3+1]+1]
+	p2x, p2y := g.coords[2*g.triangles[t*3+2]], g.coords[2*g.triangles[t*3+2]+1]
+
+	minX, maxX := minInt(p0x, p1x, p2x), maxInt(p0x, p1x, p2x)
+	minY, maxY := minInt(p0y, p1y, p2y), maxInt(p0y, p1y, p2y)
+
+	// Barycentric weights at the corner of the bounding box, and how they change with each step in x and y
+	w00 := orient(p1x, p1y, p2x, p2y, minX, minY)
+	w01 := orient(p2x, p2y, p0x, p0y, minX, minY)
+	w02 := orient(p0x, p0y, p1x, p1y, minX, minY)
+	a01, b01 := p1y-p0y, p0x-p1x
+	a12, b12 := p2y-p1y, p1x-p2x
+	a20, b20 := p0y-p2y, p2x-p0x
+
+	area := float64(orient(p0x, p0y, p1x, p1y, p2x, p2y))
+	z0 := g.heightAt(p0x, p0y) / area
+	z1 := g.heightAt(p1x, p1y) / area
+	z2 := g.heightAt(p2x, p2y) / area
+
+	maxError := 0.0
+	mx, my := p0x, p0y
+	for y := minY; y <= maxY; y++ {
+		w0, w1, w2 := w00, w01, w02
+		for x := minX; x <= maxX; x++ {
+			if w0 >= 0 && w1 >= 0 && w2 >= 0 {
+				z := z0*float64(w0) + z1*float64(w1) + z2*float64(w2)
+				if dz := math.Abs(z - g.heightAt(x, y)); dz > maxError {
+					maxError, mx, my = dz, x, y
+				}
+			}
+			w0 += a12
+			w1 += a20
+			w2 += a01
+		}
+		w00 += b12
+		w01 += b20
+		w02 += b01
+	}
+
+	// A sample on one of the corners can only differ by rounding, and must never be inserted twice
+	if (mx == p0x && my == p0y) || (mx == p1x && my == p1y) || (mx == p2x && my == p2y) {
+		maxError = 0
+	}
+
+	g.candidates[2*t], g.candidates[2*t+1] = mx, my
+	g.queuePush(t, maxError)
+}
+
+// step splits the triangle with the largest error at its candidate
+func (g *greedyTriangulation) step() {
+	t := g.queuePop()
+
+	e0, e1, e2 := t*3, t*3+1, t*3+2
+	p0, p1, p2 := g.triangles[e0], g.triangles[e1], g.triangles[e2]
+	ax, ay := g.coords[2*p0], g.coords[2*p0+1]
+	bx, by := g.coords[2*p1], g.coords[2*p1+1]
+	cx, cy := g.coords[2*p2], g.coords[2*p2+1]
+	px, py := g.candidates[2*t], g.candidates[2*t+1]
+
+	pn := g.addPoint(px, py)
+
+	switch {
+	case orient(ax, ay, bx, by, px, py) == 0:
+		g.handleCollinear(pn, e0)
+	case orient(bx, by, cx, cy, px, py) == 0:
+		g.handleCollinear(pn, e1)
+	case orient(cx, cy, ax, ay, px, py) == 0:
+		g.handleCollinear(pn, e2)
+	default:
+		h0, h1, h2 := g.halfedges[e0], g.halfedges[e1], g.halfedges[e2]
+		t0 := g.addTriangle(p0, p1, pn, h0, -1, -1, e0)
+		t1 := g.addTriangle(p1, p2, pn, h1, -1, t0+1, len(g.triangles))
+		t2 := g.addTriangle(p2, p0, pn, h2, t0+2, t1+1, len(g.triangles))
+		g.legalize(t0)
+		g.legalize(t1)
+		g.legalize(t2)
+	}
+}
+
+// legalize flips the edge a with its twin if the pair of triangles doesn't satisfy the Delaunay condition,
+// then recursively checks the edges of the flipped pair
+//
+//	      pl                    pl
+//	     /||\                  /  \
+//	  al/ || \bl            al/    \a
+//	   /  ||  \              /      \
+//	  /  a||b  \    flip    /___ar___\
+//	p0\   ||   /p1   =>   p0\---bl---/p1
+//	   \  ||  /              \      /
+//	  ar\ || /br             b\    /br
+//	     \||/                  \  /
+//	      pr                    pr
+func (g *greedyTriangulation) legalize(a int) {
+	b := g.halfedges[a]
+	if b < 0 {
+		return
+	}
+
+	a0, b0 := a-a%3, b-b%3
+	al, ar := a0+(a+1)%3, a0+(a+2)%3
+	bl, br := b0+(b+2)%3, b0+(b+1)%3
+	p0, pr, pl, p1 := g.triangles[ar], g.triangles[a], g.triangles[al], g.triangles[bl]
+
+	if !inCircle(
+		g.coords[2*p0], g.coords[2*p0+1],
+		g.coords[2*pr], g.coords[2*pr+1],
+		g.coords[2*pl], g.coords[2*pl+1],
+		g.coords[2*p1], g.coords[2*p1+1]) {
+		return
+	}
+
+	hal, har := g.halfedges[al], g.halfedges[ar]
+	hbl, hbr := g.halfedges[bl], g.halfedges[br]
+
+	g.queueRemove(a0 / 3)
+	g.queueRemove(b0 / 3)
+
+	t0 := g.addTriangle(p0, p1, pl, -1, hbl, hal, a0)
+	t1 := g.addTriangle(p1, p0, pr, t0, har, hbr, b0)
+
+	g.legalize(t0 + 1)
+	g.legalize(t1 + 2)
+}
+
+// handleCollinear inserts pn on the edge a, splitting the triangles on either side of it
+func (g *greedyTriangulation) handleCollinear(pn, a int) {
+	a0 := a - a%3
+	al, ar := a0+(a+1)%3, a0+(a+2)%3
+	p0, pr, pl := g.triangles[ar], g.triangles[a], g.triangles[al]
+	hal, har := g.halfedges[al], g.halfedges[ar]
+
+	b := g.halfedges[a]
+	if b < 0 {
+		t0 := g.addTriangle(pn, p0, pr, -1, har, -1, a0)
+		t1 := g.addTriangle(p0, pn, pl, t0, -1, hal, len(g.triangles))
+		g.legalize(t0 + 1)
+		g.legalize(t1 + 2)
+		return
+	}
+
+	b0 := b - b%3
+	bl, br := b0+(b+2)%3, b0+(b+1)%3
+	p1 := g.triangles[bl]
+	hbl, hbr := g.halfedges[bl], g.halfedges[br]
+
+	g.queueRemove(b0 / 3)
+
+	t0 := g.addTriangle(p0, pr, pn, har, -1, -1, a0)
+	t1 := g.addTriangle(pr, p1, pn, hbr, -1, t0+1, b0)
+	t2 := g.addTriangle(p1, pl, pn, hbl, -1, t1+1, len(g.triangles))
+	t3 := g.addTriangle(pl, p0, pn, hal, t0+2, t2+1, len(g.triangles))
+
+	g.legalize(t0)
+	g.legalize(t1)
+	g.legalize(t2)
+	g.legalize(t3)
+}
+
+func (g *greedyTriangulation) queuePush(t int, err float64) {
+	i := len(g.queue)
+	g.queueIndices[t] = i
+	g.queue = append(g.queue, t)
+	g.errors = append(g.errors, err)
+	g.queueUp(i)
+}
+
+func (g *greedyTriangulation) queuePop() int {
+	n := len(g.queue) - 1
+	g.queueSwap(0, n)
+	g.queueDown(0, n)
+	return g.queuePopBack()
+}
+
+func (g *greedyTriangulation) queuePopBack() int {
+	t := g.queue[len(g.queue)-1]
+	g.queue = g.queue[:len(g.queue)-1]
+	g.errors = g.errors[:len(g.errors)-1]
+	g.queueIndices[t] = -1
+	return t
+}
+
+// queueRemove takes a triangle out of the queue, or out of the pending list if it hasn't been queued yet
+func (g *greedyTriangulation) queueRemove(t int) {
+	i := g.queueIndices[t]
+	if i < 0 {
+		for j, pending := range g.pending {
+			if pending == t {
+				g.pending[j] = g.pending[len(g.pending)-1]
+				g.pending = g.pending[:len(g.pending)-1]
+				return
+			}
+		}
+		panic(errors.New("Broken triangulation: removed a triangle that was neither queued nor pending"))
+	}
+
+	n := len(g.queue) - 1
+	if n != i {
+		g.queueSwap(i, n)
+		if !g.queueDown(i, n) {
+			g.queueUp(i)
+		}
+	}
+	g.queuePopBack()
+}
+
+func (g *greedyTriangulation) queueLess(i, j int) bool {
+	return g.errors[i] > g.errors[j]
+}
+
+func (g *greedyTriangulation) queueSwap(i, j int) {
+	pi, pj := g.queue[i], g.queue[j]
+	g.queue[i], g.queue[j] = pj, pi
+	g.errors[i], g.errors[j] = g.errors[j], g.errors[i]
+	g.queueIndices[pi] = j
+	g.queueIndices[pj] = i
+}
+
+func (g *greedyTriangulation) queueUp(j int) {
+	for j > 0 {
+		i := (j - 1) / 2
+		if i == j || !g.queueLess(j, i) {
+			break
+		}
+		g.queueSwap(i, j)
+		j = i
+	}
+}
+
+func (g *greedyTriangulation) queueDown(i0, n int) bool {
+	i := i0
+	for {
+		j1 := 2*i + 1
+		if j1 >= n || j1 < 0 {
+			break
+		}
+		j := j1
+		if j2 := j1 + 1; j2 < n && g.queueLess(j2, j1) {
+			j = j2
+		}
+		if !g.queueLess(j, i) {
+			break
+		}
+		g.queueSwap(i, j)
+		i = j
+	}
+	return i > i0
+}
+
+// border returns the vertices around the border of the triangulation, in the order its triangles traverse them
+func (g *greedyTriangulation) border() []int {
+	next := map[int]int{}
+	start := -1
+	for e, twin := range g.halfedges {
+		if twin < 0 {
+			from := g.triangles[e]
+			to := g.triangles[e-e%3+(e+1)%3]
+			next[from] = to
+			start = from
+		}
+	}
+
+	border := make([]int, 0, len(next))
+	for vertex := start; len(border) < len(next); vertex = next[vertex] {
+		border = append(border, vertex)
+	}
+	return border
+}
+
+func orient(ax, ay, bx, by, cx, cy int) int {
+	return (bx-cx)*(ay-cy) - (by-cy)*(ax-cx)
+}
+
+func inCircle(ax, ay, bx, by, cx, cy, px, py int) bool {
+	dx, dy := float64(ax-px), float64(ay-py)
+	ex, ey := float64(bx-px), float64(by-py)
+	fx, fy := float64(cx-px), float64(cy-py)
+
+	ap := dx*dx + dy*dy
+	bp := ex*ex + ey*ey
+	cp := fx*fx + fy*fy
+
+	return dx*(ey*cp-bp*fy)-dy*(ex*cp-bp*fx)+ap*(ex*fy-ey*fx) < 0
+}
+
+func minInt(values ...int) int {
+	min := values[0]
+	for _, v := range values[1:] {
+		if v < min {
+			min = v
+		}
+	}
+	return min
+}
+
+func maxInt(values ...int) int {
+	max := values[0]
+	for _, v := range values[1:] {
+		if v > max {
+			max = v
+		}
+	}
+	return max
+}
@@ -0,0 +1,141 @@
+package mesh_test
+
+import (
+	"math"
+	"testing"
+
+	tgmath "github.com/bcokert/terragen/math"
+	"github.com/bcokert/terragen/mesh"
+	"github.com/bcokert/terragen/noise"
+)
+
+// surfaceHeight finds the triangle of the mesh containing the point (x, y), and interpolates its height there
+func surfaceHeight(m *mesh.Mesh, x, y float64) (float64, bool) {
+	for i := 0; i < len(m.Indices); i += 3 {
+		a, b, c := m.Positions[m.Indices[i]], m.Positions[m.Indices[i+1]], m.Positions[m.Indices[i+2]]
+		area := (b[0]-a[0])*(c[1]-a[1]) - (b[1]-a[1])*(c[0]-a[0])
+		if area <= 0 {
+			continue
+		}
+		wa := ((b[0]-x)*(c[1]-y) - (b[1]-y)*(c[0]-x)) / area
+		wb := ((c[0]-x)*(a[1]-y) - (c[1]-y)*(a[0]-x)) / area
+		wc := 1 - wa - wb
+		if wa >= -1e-9 && wb >= -1e-9 && wc >= -1e-9 {
+			return wa*a[2] + wb*b[2] + wc*c[2], true
+		}
+	}
+	return 0, false
+}
+
+func TestFromNoise_MaxError(t *testing.T) {
+	hills := func(t []float64) float64 {
+		return math.Sin(t[0]*2)*math.Cos(t[1]*3) + 0.3*math.Sin(t[0]*t[1])
+	}
+
+	testCases := map[string]struct {
+		Function         noise.Function
+		From, To         []int
+		Resolution       int
+		Options          mesh.Options
+		ExpectedMaxCount int
+	}{
+		"flat": {
+			Function:         func(t []float64) float64 { return 2 },
+			From:             []int{0, 0},
+			To:               []int{4, 3},
+			Resolution:       4,
+			Options:          mesh.Options{VerticalScale: 1, MaxError: 0.01},
+			ExpectedMaxCount: 2,
+		},
+		"tilted plane": {
+			Function:         func(t []float64) float64 { return t[0] - 2*t[1] },
+			From:             []int{-2, 1},
+			To:               []int{1, 3},
+			Resolution:       5,
+			Options:          mesh.Options{VerticalScale: 1, MaxError: 0.001},
+			ExpectedMaxCount: 2,
+		},
+		// Hills are simplified to well under the 2 triangles per cell of the full grid
+		"coarse hills": {
+			Function:         hills,
+			From:             []int{0, 0},
+			To:               []int{4, 4},
+			Resolution:       8,
+			Options:          mesh.Options{VerticalScale: 1, MaxError: 0.1},
+			ExpectedMaxCount: 31 * 31,
+		},
+		"fine hills with scale": {
+			Function:         hills,
+			From:             []int{-1, 2},
+			To:               []int{2, 4},
+			Resolution:       10,
+			Options:          mesh.Options{VerticalScale: 5, MaxError: 0.05},
+			ExpectedMaxCount: 29 * 19 * 3 / 2,
+		},
+	}
+
+	for name, testCase := range testCases {
+		n := &noise.Noise{}
+		n.Generate(testCase.From, testCase.To, testCase.Resolution, testCase.Function)
+
+		result, err := mesh.FromNoise(n, testCase.Options)
+		if err != nil {
+			t.Errorf("'%s' failed. An unexpected error occurred: %v", name, err.Error())
+			continue
+		}
+
+		if result.NumTriangles() > testCase.ExpectedMaxCount {
+			t.Errorf("'%s' failed. Expected at most %d triangles, received %d", name, testCase.ExpectedMaxCount, result.NumTriangles())
+		}
+
+		for i := 0; i < result.NumTriangles(); i++ {
+			if normal := result.FaceNormal(i); normal[2] <= 0 {
+				t.Errorf("'%s' failed. Expected triangle %d to face up, received normal %v", name, i, normal)
+				break
+			}
+		}
+
+		shape := n.Shape()
+		spacing := 1 / float64(testCase.Resolution)
+		worstError := 0.0
+		for x := 0; x < shape[0]; x++ {
+			for y := 0; y < shape[1]; y++ {
+				px, py := float64(testCase.From[0])+float64(x)*spacing, float64(testCase.From[1])+float64(y)*spacing
+				height, ok := surfaceHeight(result, px, py)
+				if !ok {
+					t.Errorf("'%s' failed. Sample %d,%d is not covered by the mesh", name, x, y)
+					continue
+				}
+				worstError = math.Max(worstError, math.Abs(height-n.Values[x*shape[1]+y]*testCase.Options.VerticalScale))
+			}
+		}
+		if worstError > testCase.Options.MaxError {
+			t.Errorf("'%s' failed. Expected a max error under %v, received %v", name, testCase.Options.MaxError, worstError)
+		}
+	}
+}
+
+func TestFromNoise_MaxErrorClosed(t *testing.T) {
+	n := &noise.Noise{}
+	n.Generate([]int{0, 0}, []int{3, 2}, 8, func(t []float64) float64 {
+		return math.Sin(t[0]*3) * math.Cos(t[1]*2)
+	})
+
+	result, err := mesh.FromNoise(n, mesh.Options{VerticalScale: 1, Skirt: 0.5, Closed: true, MaxError: 0.05})
+	if err != nil {
+		t.Fatalf("An unexpected error occurred: %v", err.Error())
+	}
+
+	if !isWatertight(result) {
+		t.Errorf("Expected a watertight mesh")
+	}
+	if volume := signedVolume(result); volume <= 0 {
+		t.Errorf("Expected a positive volume, received %v", volume)
+	}
+
+	// Every border sample of the grid should still be reachable from the walls' top edges
+	min, max := result.Bounds()
+	if !(tgmath.Vec3{min[0], min[1], 0}).IsEqual(tgmath.Vec3{0, 0, 0}) || !(tgmath.Vec3{max[0], max[1], 0}).IsEqual(tgmath.Vec3{3 - 1.0/8, 2 - 1.0/8, 0}) {
+		t.Errorf("Expected the mesh to cover the full range, received bounds %v %v", min, max)
+	}
+}
@@ -0,0 +1,43 @@
+package mesh
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"math"
+)
+
+// WriteSTL writes the mesh to w as a binary STL file
+// STL is kept z-up, which is the convention slicers use for 3D printing
+func WriteSTL(w io.Writer, mesh *Mesh) error {
+	buffered := bufio.NewWriter(w)
+
+	header := make([]byte, 80)
+	copy(header, "terragen heightfield")
+	if _, err := buffered.Write(header); err != nil {
+		return err
+	}
+	if err := binary.Write(buffered, binary.LittleEndian, uint32(mesh.NumTriangles())); err != nil {
+		return err
+	}
+
+	// Each triangle is a normal, three vertices, and an unused attribute count, for 50 bytes
+	record := make([]byte, 50)
+	for triangle := 0; triangle < mesh.NumTriangles(); triangle++ {
+		normal := mesh.FaceNormal(triangle)
+		for i := 0; i < 3; i++ {
+			binary.LittleEndian.PutUint32(record[i*4:], math.Float32bits(float32(normal[i])))
+		}
+		for v := 0; v < 3; v++ {
+			position := mesh.Positions[mesh.Indices[triangle*3+v]]
+			for i := 0; i < 3; i++ {
+				binary.LittleEndian.PutUint32(record[12+v*12+i*4:], math.Float32bits(float32(position[i])))
+			}
+		}
+		if _, err := buffered.Write(record); err != nil {
+			return err
+		}
+	}
+
+	return buffered.Flush()
+}
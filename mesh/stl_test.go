@@ -0,0 +1,54 @@
+package mesh_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"testing"
+
+	"github.com/bcokert/terragen/mesh"
+)
+
+func TestWriteSTL(t *testing.T) {
+	testCases := map[string]struct {
+		Mesh              *mesh.Mesh
+		ExpectedTriangles uint32
+	}{
+		"quad":  {Mesh: unitQuad(), ExpectedTriangles: 2},
+		"empty": {Mesh: &mesh.Mesh{}, ExpectedTriangles: 0},
+	}
+
+	for name, testCase := range testCases {
+		buffer := &bytes.Buffer{}
+		if err := mesh.WriteSTL(buffer, testCase.Mesh); err != nil {
+			t.Errorf("'%s' failed. An unexpected error occurred: %v", name, err.Error())
+			continue
+		}
+
+		output := buffer.Bytes()
+		if expectedLength := 84 + 50*int(testCase.ExpectedTriangles); len(output) != expectedLength {
+			t.Errorf("'%s' failed. Expected %d bytes, received %d", name, expectedLength, len(output))
+			continue
+		}
+		if count := binary.LittleEndian.Uint32(output[80:]); count != testCase.ExpectedTriangles {
+			t.Errorf("'%s' failed. Expected %d triangles, received %d", name, testCase.ExpectedTriangles, count)
+		}
+
+		// Each triangle of the quad faces up, and its vertices match the mesh
+		for triangle := 0; triangle < int(testCase.ExpectedTriangles); triangle++ {
+			record := output[84+triangle*50:]
+			normalZ := math.Float32frombits(binary.LittleEndian.Uint32(record[8:]))
+			if normalZ != 1 {
+				t.Errorf("'%s' failed on triangle %d. Expected an up facing normal, received z %v", name, triangle, normalZ)
+			}
+			for v := 0; v < 3; v++ {
+				expected := testCase.Mesh.Positions[testCase.Mesh.Indices[triangle*3+v]]
+				x := math.Float32frombits(binary.LittleEndian.Uint32(record[12+v*12:]))
+				y := math.Float32frombits(binary.LittleEndian.Uint32(record[16+v*12:]))
+				if float64(x) != expected[0] || float64(y) != expected[1] {
+					t.Errorf("'%s' failed on triangle %d vertex %d. Expected %v, received %v,%v", name, triangle, v, expected, x, y)
+				}
+			}
+		}
+	}
+}
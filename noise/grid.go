@@ -0,0 +1,183 @@
+package noise
+
+import (
+	"errors"
+	"math"
+
+	tgmath "github.com/bcokert/terragen/math"
+)
+
+// A GridPreset generates every sample of a lattice at once, for algorithms that can't be evaluated point by point like a Preset
+// Shape is the number of samples in each dimension, and the values are returned laid out like Noise.Values
+// Roughness scales the detail added at each finer level, so 0 is smooth and 1 adds as much detail at every level
+type GridPreset func(source tgmath.Source, shape []int, roughness float64) ([]float64, error)
+
+// GridPresets is a map from preset names to Grid Presets
+// A Grid Preset repeatedly subdivides a lattice, setting each new sample to the average of its neighbours plus a random displacement
+// Both support 1 and 2 dimensions, where they are the same in 1 dimension
+var GridPresets = map[string]GridPreset{
+	"diamondSquare":        DiamondSquare,
+	"midpointDisplacement": MidpointDisplacement,
+}
+
+// GenerateGrid populates the noise like Generate, but with a grid preset that generates the whole lattice at once
+// Unlike Generate, the values depend on the range as well as the position, since the preset subdivides the whole lattice
+func (noise *Noise) GenerateGrid(from, to []int, resolution int, preset GridPreset, source tgmath.Source, roughness float64) error {
+	if roughness < 0 || roughness > 1 {
+		return errors.New("Roughness must be between 0 and 1")
+	}
+
+	noise.From = from
+	noise.To = to
+	noise.Resolution = resolution
+
+	values, err := preset(source, noise.Shape(), roughness)
+	if err != nil {
+		return err
+	}
+	noise.Values = values
+	return nil
+}
+
+// At returns the value of the sample nearest to t, clamped to the lattice, so generated noise can be used as a Function
+func (noise *Noise) At(t []float64) float64 {
+	shape := noise.Shape()
+	index := 0
+	for i := range shape {
+		sample := int(math.Round((t[i] - float64(noise.From[i])) * float64(noise.Resolution)))
+		sample = int(math.Max(0, math.Min(float64(shape[i]-1), float64(sample))))
+		index = index*shape[i] + sample
+	}
+	return noise.Values[index]
+}
+
+// SubdivisionBound returns the largest magnitude that DiamondSquare or MidpointDisplacement can reach over a shape
+// Averages never exceed their neighbours, so each level can only add its own displacement scale to the corners' bound of 1
+func SubdivisionBound(shape []int, roughness float64) float64 {
+	bound, scale := 1.0, roughness
+	for step := subdivisionSize(shape) - 1; step > 1; step /= 2 {
+		bound += scale
+		scale *= roughness
+	}
+	return bound
+}
+
+// SubdivisionSamples returns the number of samples in the lattice that DiamondSquare or MidpointDisplacement subdivide for a shape
+// The lattice is square, with a side big enough for the shape's longest dimension, so long thin shapes need many more samples than they keep
+func SubdivisionSamples(shape []int) int {
+	size := subdivisionSize(shape)
+	if len(shape) == 1 {
+		return size
+	}
+	return size * size
+}
+
+// DiamondSquare is a Grid Preset that generates fractal terrain with the diamond-square algorithm
+// Each level sets the center of every square from its corners, then the center of every diamond that leaves, which avoids the creases
+// of plain midpoint displacement. In 1 dimension it's midpoint displacement
+func DiamondSquare(source tgmath.Source, shape []int, roughness float64) ([]float64, error) {
+	return subdivide(source, shape, roughness, true)
+}
+
+// MidpointDisplacement is a Grid Preset that generates fractal terrain by midpoint displacement
+// In 2 dimensions each level sets the midpoint of every edge from its ends, and the center of every square from its corners
+func MidpointDisplacement(source tgmath.Source, shape []int, roughness float64) ([]float64, error) {
+	return subdivide(source, shape, roughness, false)
+}
+
+// subdivide displaces the midpoints of a lattice of 2^n + 1 samples a side, big enough to hold the shape, and crops it to the shape
+// Displacements are uniform in [-scale, scale], where the scale starts at 1 for the corners and is multiplied by roughness at each level
+func subdivide(source tgmath.Source, shape []int, roughness float64, diamond bool) ([]float64, error) {
+	if len(shape) != 1 && len(shape) != 2 {
+		return nil, errors.New("Grid presets only support 1 or 2 dimensions")
+	}
+
+	size := subdivisionSize(shape)
+
+	displace := func(average, scale float64) float64 {
+		return average + (source.Float64()*2-1)*scale
+	}
+
+	if len(shape) == 1 {
+		line := make([]float64, size)
+		line[0], line[size-1] = displace(0, 1), displace(0, 1)
+		scale := roughness
+		for step := size - 1; step > 1; step /= 2 {
+			for x := step / 2; x < size; x += step {
+				line[x] = displace((line[x-step/2]+line[x+step/2])/2, scale)
+			}
+			scale *= roughness
+		}
+		return line[:shape[0]], nil
+	}
+
+	grid := make([]float64, size*size)
+	at := func(x, y int) *float64 {
+		return &grid[x*size+y]
+	}
+
+	for _, corner := range [][2]int{{0, 0}, {size - 1, 0}, {0, size - 1}, {size - 1, size - 1}} {
+		*at(corner[0], corner[1]) = displace(0, 1)
+	}
+
+	scale := roughness
+	for step := size - 1; step > 1; step /= 2 {
+		half := step / 2
+
+		// Square centers, from the four corners of each square
+		for x := half; x < size; x += step {
+			for y := half; y < size; y += step {
+				*at(x, y) = displace((*at(x-half, y-half)+*at(x+half, y-half)+*at(x-half, y+half)+*at(x+half, y+half))/4, scale)
+			}
+		}
+
+		// Edge midpoints, which are diamond centers when every neighbour at half a step is used, or just the edge's ends otherwise
+		for x := 0; x < size; x += half {
+			for y := (x/half + 1) % 2 * half; y < size; y += step {
+				ends, centers := [][2]int{{x - half, y}, {x + half, y}}, [][2]int{{x, y - half}, {x, y + half}}
+				if x%step == 0 {
+					ends, centers = centers, ends
+				}
+				neighbours := ends
+				if diamond {
+					neighbours = append(neighbours, centers...)
+				}
+
+				sum, count := 0.0, 0
+
+				for _, neighbour := range neighbours {
+					if neighbour[0] >= 0 && neighbour[0] < size && neighbour[1] >= 0 && neighbour[1] < size {
+						sum += *at(neighbour[0], neighbour[1])
+						count++
+					}
+				}
+				*at(x, y) = displace(sum/float64(count), scale)
+			}
+		}
+
+		scale *= roughness
+	}
+
+	values := make([]float64, 0, shape[0]*shape[1])
+	for x := 0; x < shape[0]; x++ {
+		for y := 0; y < shape[1]; y++ {
+			values = append(values, *at(x, y))
+		}
+	}
+	return values, nil
+}
+
+// subdivisionSize returns the number of samples a side of the smallest lattice of 2^n + 1 samples that holds the shape
+func subdivisionSize(shape []int) int {
+	largest := 1
+	for _, count := range shape {
+		if count > largest {
+			largest = count
+		}
+	}
+	size := 2
+	for size < largest {
+		size = (size-1)*2 + 1
+	}
+	return size
+}
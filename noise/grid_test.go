@@ -0,0 +1,212 @@
+package noise_test
+
+import (
+	"math"
+	"testing"
+
+	tgmath "github.com/bcokert/terragen/math"
+	"github.com/bcokert/terragen/noise"
+)
+
+func TestGridPresets(t *testing.T) {
+	testCases := map[string]struct {
+		Preset    noise.GridPreset
+		Shape     []int
+		Roughness float64
+		Expected  []float64
+	}{
+		"1d midpoint displacement": {
+			Preset: noise.MidpointDisplacement, Shape: []int{3}, Roughness: 0.5,
+			Expected: []float64{1, 1.5, 1},
+		},
+		"1d diamond square": {
+			Preset: noise.DiamondSquare, Shape: []int{5}, Roughness: 1,
+			Expected: []float64{1, 2.5, 2, 2.5, 1},
+		},
+		"cropped": {
+			Preset: noise.MidpointDisplacement, Shape: []int{4}, Roughness: 1,
+			Expected: []float64{1, 2.5, 2, 2.5},
+		},
+		"2d midpoint displacement": {
+			Preset: noise.MidpointDisplacement, Shape: []int{3, 3}, Roughness: 0.5,
+			Expected: []float64{1, 1.5, 1, 1.5, 1.5, 1.5, 1, 1.5, 1},
+		},
+		"2d diamond square": {
+			Preset: noise.DiamondSquare, Shape: []int{3, 3}, Roughness: 0.5,
+			Expected: []float64{1, 7.0/6 + 0.5, 1, 7.0/6 + 0.5, 1.5, 7.0/6 + 0.5, 1, 7.0/6 + 0.5, 1},
+		},
+		"2d cropped": {
+			Preset: noise.MidpointDisplacement, Shape: []int{3, 2}, Roughness: 0.5,
+			Expected: []float64{1, 1.5, 1.5, 1.5, 1, 1.5},
+		},
+	}
+
+	for name, testCase := range testCases {
+		// Every displacement is the full scale, so each sample is the average of its neighbours plus the scale of its level
+		result, err := testCase.Preset(&tgmath.ConstantSourceMock{ConstantResult: 1}, testCase.Shape, testCase.Roughness)
+		if err != nil {
+			t.Errorf("'%s' failed. Expected no error, received %s", name, err.Error())
+			continue
+		}
+
+		if len(result) != len(testCase.Expected) {
+			t.Errorf("'%s' failed. Expected %v, received %v", name, testCase.Expected, result)
+			continue
+		}
+		for i := range result {
+			if math.Abs(result[i]-testCase.Expected[i]) > 0.00000000001 {
+				t.Errorf("'%s' failed. Expected %v, received %v", name, testCase.Expected, result)
+				break
+			}
+		}
+	}
+}
+
+func TestGridPresets_Smooth(t *testing.T) {
+	// Without roughness, midpoint displacement only displaces the corners, and interpolates them everywhere else
+	values, _ := noise.MidpointDisplacement(tgmath.NewDefaultSource(42), []int{9, 9}, 0)
+
+	at := func(x, y int) float64 {
+		return values[x*9+y]
+	}
+	for x := 0; x < 9; x++ {
+		for y := 0; y < 9; y++ {
+			u, v := float64(x)/8, float64(y)/8
+			expected := at(0, 0)*(1-u)*(1-v) + at(8, 0)*u*(1-v) + at(0, 8)*(1-u)*v + at(8, 8)*u*v
+			if math.Abs(at(x, y)-expected) > 0.00000000001 {
+				t.Errorf("Expected %v at %d, %d, received %v", expected, x, y, at(x, y))
+			}
+		}
+	}
+}
+
+func TestGridPresets_Roughness(t *testing.T) {
+	// roughness is the average difference between neighbouring samples
+	roughness := func(values []float64) (sum float64) {
+		for i := 1; i < len(values); i++ {
+			sum += math.Abs(values[i] - values[i-1])
+		}
+		return sum / float64(len(values)-1)
+	}
+
+	for name, preset := range noise.GridPresets {
+		smooth, _ := preset(tgmath.NewDefaultSource(7), []int{65, 65}, 0.3)
+		rough, _ := preset(tgmath.NewDefaultSource(7), []int{65, 65}, 0.8)
+		if roughness(rough) <= 2*roughness(smooth) {
+			t.Errorf("'%s' failed. Expected a higher roughness to be much rougher, received %v and %v", name, roughness(smooth), roughness(rough))
+		}
+
+		again, _ := preset(tgmath.NewDefaultSource(7), []int{65, 65}, 0.3)
+		for i := range smooth {
+			if smooth[i] != again[i] {
+				t.Errorf("'%s' failed. Expected the same seed to generate the same values", name)
+				break
+			}
+		}
+	}
+}
+
+func TestSubdivisionBound(t *testing.T) {
+	testCases := map[string]struct {
+		Shape     []int
+		Roughness float64
+		Expected  float64
+	}{
+		"corners":   {Shape: []int{2, 2}, Roughness: 0.5, Expected: 1},
+		"one level": {Shape: []int{3}, Roughness: 0.5, Expected: 1.5},
+		"cropped":   {Shape: []int{6, 9}, Roughness: 0.5, Expected: 1.875},
+		"rough":     {Shape: []int{9, 9}, Roughness: 1, Expected: 4},
+	}
+
+	for name, testCase := range testCases {
+		bound := noise.SubdivisionBound(testCase.Shape, testCase.Roughness)
+		if !tgmath.IsFloatEqual(bound, testCase.Expected) {
+			t.Errorf("'%s' failed. Expected %v, received %v", name, testCase.Expected, bound)
+		}
+
+		// Every value is within the bound, however the displacements fall
+		for name, preset := range noise.GridPresets {
+			values, _ := preset(tgmath.NewDefaultSource(42), testCase.Shape, testCase.Roughness)
+			for _, value := range values {
+				if math.Abs(value) > bound {
+					t.Errorf("'%s' failed. Expected every value to be within %v, received %v", name, bound, value)
+					break
+				}
+			}
+		}
+	}
+}
+
+func TestSubdivisionSamples(t *testing.T) {
+	testCases := map[string]struct {
+		Shape    []int
+		Expected int
+	}{
+		"1d":        {Shape: []int{6}, Expected: 9},
+		"square":    {Shape: []int{9, 9}, Expected: 81},
+		"long":      {Shape: []int{10, 2}, Expected: 17 * 17},
+		"tall":      {Shape: []int{2, 10}, Expected: 17 * 17},
+		"2 samples": {Shape: []int{2, 1}, Expected: 4},
+	}
+
+	for name, testCase := range testCases {
+		if samples := noise.SubdivisionSamples(testCase.Shape); samples != testCase.Expected {
+			t.Errorf("'%s' failed. Expected %v, received %v", name, testCase.Expected, samples)
+		}
+	}
+}
+
+func TestNoise_GenerateGrid(t *testing.T) {
+	testCases := map[string]struct {
+		From, To         []int
+		Resolution       int
+		Roughness        float64
+		ExpectedSamples  int
+		ExpectedErrorMsg string
+	}{
+		"2d":             {From: []int{0, 0}, To: []int{3, 2}, Resolution: 4, Roughness: 0.5, ExpectedSamples: 96},
+		"1d":             {From: []int{-2}, To: []int{2}, Resolution: 10, Roughness: 0.5, ExpectedSamples: 40},
+		"3d":             {From: []int{0, 0, 0}, To: []int{1, 1, 1}, Resolution: 2, Roughness: 0.5, ExpectedErrorMsg: "Grid presets only support 1 or 2 dimensions"},
+		"negative rough": {From: []int{0, 0}, To: []int{1, 1}, Resolution: 2, Roughness: -0.5, ExpectedErrorMsg: "Roughness must be between 0 and 1"},
+		"too much rough": {From: []int{0, 0}, To: []int{1, 1}, Resolution: 2, Roughness: 1.5, ExpectedErrorMsg: "Roughness must be between 0 and 1"},
+	}
+
+	for name, testCase := range testCases {
+		generated := noise.NewNoise("diamondSquare")
+		err := generated.GenerateGrid(testCase.From, testCase.To, testCase.Resolution, noise.DiamondSquare, tgmath.NewDefaultSource(1), testCase.Roughness)
+
+		if testCase.ExpectedErrorMsg != "" {
+			if err == nil || err.Error() != testCase.ExpectedErrorMsg {
+				t.Errorf("'%s' failed. Expected error '%s', received '%v'", name, testCase.ExpectedErrorMsg, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("'%s' failed. Expected no error, received %s", name, err.Error())
+			continue
+		}
+		if len(generated.Values) != testCase.ExpectedSamples {
+			t.Errorf("'%s' failed. Expected %d samples, received %d", name, testCase.ExpectedSamples, len(generated.Values))
+		}
+	}
+}
+
+func TestNoise_At(t *testing.T) {
+	lattice := &noise.Noise{Values: []float64{0, 1, 2, 10, 11, 12, 20, 21, 22}, From: []int{1, 0}, To: []int{2, 1}, Resolution: 3}
+
+	testCases := map[string]struct {
+		Point    []float64
+		Expected float64
+	}{
+		"first":   {Point: []float64{1, 0}, Expected: 0},
+		"exact":   {Point: []float64{1 + 1.0/3, 2.0 / 3}, Expected: 12},
+		"nearest": {Point: []float64{1.6, 0.4}, Expected: 21},
+		"clamped": {Point: []float64{5, -3}, Expected: 20},
+	}
+
+	for name, testCase := range testCases {
+		if result := lattice.At(testCase.Point); result != testCase.Expected {
+			t.Errorf("'%s' failed. Expected %v, received %v", name, testCase.Expected, result)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package noise
+
+import (
+	"math"
+
+	tgmath "github.com/bcokert/terragen/math"
+)
+
+// Masks are Functions with values in [0, 1], which shape other Functions by where they are in the world rather than by noise
+// They are 1 well inside their shape, and fall smoothly to 0 at its edge over a falloff distance in world units
+
+// RadialMask is a circle (or sphere, in higher dimensions) of the given radius around the center
+func RadialMask(center []float64, radius, falloff float64) Function {
+	return func(t []float64) float64 {
+		distance := 0.0
+		for i := range t {
+			distance += (t[i] - center[i]) * (t[i] - center[i])
+		}
+		return insetMask(radius-math.Sqrt(distance), falloff)
+	}
+}
+
+// RoundedRectMask is the 2D rectangle from..to, with its corners rounded off by cornerRadius
+func RoundedRectMask(from, to []float64, cornerRadius, falloff float64) Function {
+	center := [2]float64{(from[0] + to[0]) / 2, (from[1] + to[1]) / 2}
+	halfSize := [2]float64{(to[0] - from[0]) / 2, (to[1] - from[1]) / 2}
+	cornerRadius = math.Min(cornerRadius, math.Min(halfSize[0], halfSize[1]))
+
+	return func(t []float64) float64 {
+		// The signed distance to a rounded rectangle, which is negative inside it
+		qx := math.Abs(t[0]-center[0]) - halfSize[0] + cornerRadius
+		qy := math.Abs(t[1]-center[1]) - halfSize[1] + cornerRadius
+		distance := math.Hypot(math.Max(qx, 0), math.Max(qy, 0)) + math.Min(math.Max(qx, qy), 0) - cornerRadius
+		return insetMask(-distance, falloff)
+	}
+}
+
+// EdgeMask is the distance from t to the nearest edge of the box from..to in any number of dimensions, divided by falloff and clamped to [0, 1]
+// Unlike the other masks it rises linearly rather than smoothly, so it can be used as a raw distance field
+func EdgeMask(from, to []float64, falloff float64) Function {
+	return func(t []float64) float64 {
+		distance := math.Inf(1)
+		for i := range t {
+			distance = math.Min(distance, math.Min(t[i]-from[i], to[i]-t[i]))
+		}
+		if falloff <= 0 {
+			return step(distance)
+		}
+		return math.Max(0, math.Min(1, distance/falloff))
+	}
+}
+
+// PolygonMask is the inside of a simple 2D polygon, whose points may wind in either direction
+func PolygonMask(points [][2]float64, falloff float64) Function {
+	return func(t []float64) float64 {
+		inside := false
+		distance := math.Inf(1)
+		for i := range points {
+			a, b := points[i], points[(i+1)%len(points)]
+
+			// Even odd rule, by counting crossings of a ray towards +x
+			if (a[1] > t[1]) != (b[1] > t[1]) && t[0] < a[0]+(t[1]-a[1])*(b[0]-a[0])/(b[1]-a[1]) {
+				inside = !inside
+			}
+
+			distance = math.Min(distance, segmentDistance(t[0], t[1], a, b))
+		}
+
+		if !inside {
+			distance = -distance
+		}
+		return insetMask(distance, falloff)
+	}
+}
+
+// ProductMask combines masks by multiplying them, so the result is only 1 where all of them are
+func ProductMask(masks ...Function) Function {
+	return func(t []float64) float64 {
+		product := 1.0
+		for _, mask := range masks {
+			product *= mask(t)
+		}
+		return product
+	}
+}
+
+// MultiplyMask shapes a noise function by scaling it by the mask, flattening it to 0 wherever the mask is 0
+func MultiplyMask(fn, mask Function) Function {
+	return func(t []float64) float64 {
+		return fn(t) * mask(t)
+	}
+}
+
+// SubtractMask shapes a noise function by lowering it by up to depth wherever the mask falls below 1, then clamping it to be no lower than floor
+// Unlike MultiplyMask it keeps the detail of the noise near the edge of the mask, so coastlines stay rough
+func SubtractMask(fn, mask Function, depth, floor float64) Function {
+	return func(t []float64) float64 {
+		return math.Max(floor, fn(t)-depth*(1-mask(t)))
+	}
+}
+
+// insetMask eases from 0 at the edge of a shape to 1 at falloff inside it, given how far inside the shape a point is
+func insetMask(inset, falloff float64) float64 {
+	if falloff <= 0 {
+		return step(inset)
+	}
+	return tgmath.DampCubicEase(math.Max(0, math.Min(1, inset/falloff)))
+}
+
+// step is 1 inside a shape and 0 outside it, for masks without a falloff
+func step(inset float64) float64 {
+	if inset > 0 {
+		return 1
+	}
+	return 0
+}
+
+// segmentDistance is the distance from (x, y) to the closest point on the segment from a to b
+func segmentDistance(x, y float64, a, b [2]float64) float64 {
+	dx, dy := b[0]-a[0], b[1]-a[1]
+	along := 0.0
+	if length := dx*dx + dy*dy; length > 0 {
+		along = math.Max(0, math.Min(1, ((x-a[0])*dx+(y-a[1])*dy)/length))
+	}
+	return math.Hypot(x-a[0]-along*dx, y-a[1]-along*dy)
+}
@@ -0,0 +1,87 @@
+package noise_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/bcokert/terragen/noise"
+)
+
+func TestMasks(t *testing.T) {
+	square := [][2]float64{{0, 0}, {4, 0}, {4, 4}, {0, 4}}
+
+	testCases := map[string]struct {
+		Mask     noise.Function
+		Point    []float64
+		Expected float64
+	}{
+		"radial center":               {Mask: noise.RadialMask([]float64{0, 0}, 2, 1), Point: []float64{0, 0}, Expected: 1},
+		"radial inside falloff":       {Mask: noise.RadialMask([]float64{0, 0}, 2, 1), Point: []float64{0.5, 0}, Expected: 1},
+		"radial falloff midpoint":     {Mask: noise.RadialMask([]float64{0, 0}, 2, 1), Point: []float64{0, 1.5}, Expected: 0.5},
+		"radial edge":                 {Mask: noise.RadialMask([]float64{0, 0}, 2, 1), Point: []float64{2, 0}, Expected: 0},
+		"radial outside":              {Mask: noise.RadialMask([]float64{0, 0}, 2, 1), Point: []float64{3, 3}, Expected: 0},
+		"radial 1d":                   {Mask: noise.RadialMask([]float64{5}, 2, 2), Point: []float64{4}, Expected: 0.5},
+		"radial without falloff":      {Mask: noise.RadialMask([]float64{0, 0}, 2, 0), Point: []float64{1.9, 0}, Expected: 1},
+		"rounded rect center":         {Mask: noise.RoundedRectMask([]float64{0, 0}, []float64{4, 2}, 0.5, 0.5), Point: []float64{2, 1}, Expected: 1},
+		"rounded rect falloff":        {Mask: noise.RoundedRectMask([]float64{0, 0}, []float64{4, 2}, 0.5, 0.5), Point: []float64{2, 0.25}, Expected: 0.5},
+		"rounded rect rounded corner": {Mask: noise.RoundedRectMask([]float64{0, 0}, []float64{4, 2}, 1, 0.5), Point: []float64{0.1, 0.1}, Expected: 0},
+		"rounded rect outside":        {Mask: noise.RoundedRectMask([]float64{0, 0}, []float64{4, 2}, 0.5, 0.5), Point: []float64{5, 1}, Expected: 0},
+		"edge center":                 {Mask: noise.EdgeMask([]float64{0, 0}, []float64{4, 4}, 1), Point: []float64{2, 2}, Expected: 1},
+		"edge linear":                 {Mask: noise.EdgeMask([]float64{0, 0}, []float64{4, 4}, 1), Point: []float64{2, 0.25}, Expected: 0.25},
+		"edge 3d":                     {Mask: noise.EdgeMask([]float64{0, 0, 0}, []float64{4, 4, 4}, 2), Point: []float64{2, 2, 3}, Expected: 0.5},
+		"edge outside":                {Mask: noise.EdgeMask([]float64{0, 0}, []float64{4, 4}, 1), Point: []float64{-1, 2}, Expected: 0},
+		"polygon inside":              {Mask: noise.PolygonMask(square, 1), Point: []float64{2, 2}, Expected: 1},
+		"polygon falloff":             {Mask: noise.PolygonMask(square, 1), Point: []float64{2, 3.5}, Expected: 0.5},
+		"polygon outside":             {Mask: noise.PolygonMask(square, 1), Point: []float64{5, 2}, Expected: 0},
+		"polygon concave notch": {
+			Mask:     noise.PolygonMask([][2]float64{{0, 0}, {4, 0}, {4, 4}, {2, 1}, {0, 4}}, 0),
+			Point:    []float64{2, 3},
+			Expected: 0,
+		},
+		"product": {
+			Mask:     noise.ProductMask(noise.RadialMask([]float64{0, 0}, 2, 1), noise.EdgeMask([]float64{-2, -2}, []float64{2, 2}, 4)),
+			Point:    []float64{0, 1.5},
+			Expected: 0.5 * 0.125,
+		},
+	}
+
+	for name, testCase := range testCases {
+		if result := testCase.Mask(testCase.Point); math.Abs(result-testCase.Expected) > 1e-9 {
+			t.Errorf("'%s' failed. Expected %v, received %v", name, testCase.Expected, result)
+		}
+	}
+}
+
+func TestMultiplyMask(t *testing.T) {
+	fn := noise.Function(func(t []float64) float64 { return t[0] + 1 })
+	mask := noise.Function(func(t []float64) float64 { return 0.5 })
+
+	expected := noise.Function(func(t []float64) float64 { return (t[0] + 1) * 0.5 })
+	if !noise.MultiplyMask(fn, mask).IsEqual(expected, 2) {
+		t.Errorf("Expected the masked function to be scaled by the mask")
+	}
+}
+
+func TestSubtractMask(t *testing.T) {
+	testCases := map[string]struct {
+		Value    float64
+		Mask     float64
+		Expected float64
+	}{
+		"unmasked":      {Value: 0.5, Mask: 1, Expected: 0.5},
+		"half masked":   {Value: 0.5, Mask: 0.5, Expected: -0.5},
+		"fully masked":  {Value: 0.5, Mask: 0, Expected: -1},
+		"floor clamped": {Value: -0.5, Mask: 0, Expected: -1},
+	}
+
+	for name, testCase := range testCases {
+		fn := noise.SubtractMask(
+			func(t []float64) float64 { return testCase.Value },
+			func(t []float64) float64 { return testCase.Mask },
+			2, -1,
+		)
+		if result := fn([]float64{0, 0}); math.Abs(result-testCase.Expected) > 1e-9 {
+			t.Errorf("'%s' failed. Expected %v, received %v", name, testCase.Expected, result)
+		}
+	}
+}
@@ -11,6 +11,7 @@ type Noise struct {
 	To            []int     `json:"to"`
 	Resolution    int       `json:"resolution"`
 	NoiseFunction string    `json:"noiseFunction"`
+	Range         *Range    `json:"range,omitempty"` // The range the values were normalized to, if they were
 }
 
 // NewNoise creates a new Noise object
@@ -22,37 +23,60 @@ func NewNoise(noiseFunction string) *Noise {
 
 // Generate populates the RawNoise and related fields of this noise, by iterating over the range and calling the given noise function
 func (noise *Noise) Generate(from, to []int, resolution int, noiseFunction Function) {
-	noise.Values = []float64{}
-
-	// initialize storage for parameters and values
 	// for an n dimensional lattice, the number of points is range[dimension0]*resolution * range[dimension1]*resolution * ...
-	numParams := len(from)
-	numTotalSamples := 0
+	counts := make([]int, len(from))
 	for i := range from {
-		numSamplesInDimension := to[i] - from[i]
-		numTotalSamples *= numSamplesInDimension
+		counts[i] = (to[i] - from[i]) * resolution
+	}
+
+	noise.Values = sampleLattice(counts, func(dimension, index int) float64 {
+		return float64(from[dimension]+index/resolution) + float64(index%resolution)/float64(resolution)
+	}, noiseFunction)
+
+	noise.From = from
+	noise.To = to
+	noise.Resolution = resolution
+}
+
+// sampleLattice calls the noise function at every point of an n dimensional lattice, with counts[i] points in dimension i
+// position converts an index along a dimension into the coordinate passed to the noise function
+// The first dimension is outermost in the result
+func sampleLattice(counts []int, position func(dimension, index int) float64, noiseFunction Function) []float64 {
+	numTotalSamples := 1
+	for _, count := range counts {
+		if count < 0 {
+			count = 0
+		}
+		numTotalSamples *= count
 	}
-	numTotalSamples *= resolution
-	noise.Values = make([]float64, 0, numTotalSamples)
+	values := make([]float64, 0, numTotalSamples)
 
-	var eachSample func(point []float64, dimensionIndex int)
-	eachSample = func(point []float64, dimensionIndex int) {
-		if dimensionIndex == numParams {
-			noise.Values = append(noise.Values, noiseFunction(point))
+	point := make([]float64, len(counts))
+	var eachSample func(dimensionIndex int)
+	eachSample = func(dimensionIndex int) {
+		if dimensionIndex == len(counts) {
+			values = append(values, noiseFunction(point))
 		} else {
-			for i := from[dimensionIndex]; i < to[dimensionIndex]; i++ {
-				for j := 0; j < resolution; j++ {
-					eachSample(append(point[:], float64(i)+float64(j)/float64(resolution)), dimensionIndex+1)
-				}
+			for i := 0; i < counts[dimensionIndex]; i++ {
+				point[dimensionIndex] = position(dimensionIndex, i)
+				eachSample(dimensionIndex + 1)
 			}
 		}
 	}
 
-	eachSample([]float64{}, 0)
+	eachSample(0)
 
-	noise.From = from
-	noise.To = to
-	noise.Resolution = resolution
+	return values
+}
+
+// Shape returns the number of samples in each dimension of the noise
+// Values are stored with the first dimension outermost, so in 2D the value at sample (x, y) is Values[x*shape[1]+y]
+func (noise *Noise) Shape() []int {
+	shape := make([]int, len(noise.From))
+	for i := range noise.From {
+		shape[i] = (noise.To[i] - noise.From[i]) * noise.Resolution
+	}
+	return shape
 }
 
 // IsEqual returns true if the other Noise is equal to this one
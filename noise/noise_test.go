@@ -211,3 +211,37 @@ func TestNoise_IsEqual(t *testing.T) {
 		}
 	}
 }
+
+func TestNoise_Shape(t *testing.T) {
+	testCases := map[string]struct {
+		Noise    noise.Noise
+		Expected []int
+	}{
+		"empty": {
+			Noise:    noise.Noise{},
+			Expected: []int{},
+		},
+		"1d": {
+			Noise:    noise.Noise{From: []int{-1}, To: []int{3}, Resolution: 4},
+			Expected: []int{16},
+		},
+		"2d": {
+			Noise:    noise.Noise{From: []int{0, 3}, To: []int{2, 4}, Resolution: 5},
+			Expected: []int{10, 5},
+		},
+	}
+
+	for name, testCase := range testCases {
+		result := testCase.Noise.Shape()
+		if len(result) != len(testCase.Expected) {
+			t.Errorf("'%s' failed. Expected %v, received %v", name, testCase.Expected, result)
+			continue
+		}
+		for i := range result {
+			if result[i] != testCase.Expected[i] {
+				t.Errorf("'%s' failed. Expected %v, received %v", name, testCase.Expected, result)
+				break
+			}
+		}
+	}
+}
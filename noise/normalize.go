@@ -0,0 +1,138 @@
+package noise
+
+import (
+	"errors"
+	"math"
+	"sort"
+
+	tgmath "github.com/bcokert/terragen/math"
+)
+
+// Range is the range that noise was normalized to, so clients can plot differently normalized noise on consistent axes
+type Range struct {
+	Min float64 `json:"min"`
+	Max float64 `json:"max"`
+}
+
+// NormalizeBounds divides the values by a bound on the magnitude of the noise function, such as PresetBound, so they fall within [-1, 1]
+// Unlike the other normalizations it doesn't depend on the values, so noise generated over different ranges is normalized the same way
+// Masks and post processes can push values past the bound, so those are clamped to [-1, 1] to keep within the reported range
+func (noise *Noise) NormalizeBounds(bound float64) error {
+	if bound <= 0 || math.IsInf(bound, 0) || math.IsNaN(bound) {
+		return errors.New("Bound must be a positive number")
+	}
+
+	for i, value := range noise.Values {
+		noise.Values[i] = math.Max(-1, math.Min(1, value/bound))
+	}
+	noise.Range = &Range{Min: -1, Max: 1}
+	return nil
+}
+
+// NormalizeMinMax linearly remaps the values so the smallest is -1 and the largest is 1
+// Constant noise is remapped to 0
+func (noise *Noise) NormalizeMinMax() {
+	min, max := tgmath.MinMax(noise.Values)
+	for i, value := range noise.Values {
+		noise.Values[i] = 0
+		if max > min {
+			noise.Values[i] = (value-min)/(max-min)*2 - 1
+		}
+	}
+	noise.Range = &Range{Min: -1, Max: 1}
+}
+
+// NormalizeZScore remaps the values to their number of standard deviations from the mean
+// It has no fixed range, so the range is the smallest and largest of the remapped values. Constant noise is remapped to 0
+func (noise *Noise) NormalizeZScore() {
+	mean, deviation := tgmath.Mean(noise.Values), math.Sqrt(tgmath.Variance(noise.Values))
+	for i, value := range noise.Values {
+		noise.Values[i] = 0
+		if deviation > 0 {
+			noise.Values[i] = (value - mean) / deviation
+		}
+	}
+	min, max := tgmath.MinMax(noise.Values)
+	noise.Range = &Range{Min: min, Max: max}
+}
+
+// Equalize remaps the values by their rank, so they're spread evenly across [-1, 1] and the histogram is flat
+// Equal values share the average of their ranks, so they stay equal
+func (noise *Noise) Equalize() {
+	order := make([]int, len(noise.Values))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return noise.Values[order[a]] < noise.Values[order[b]]
+	})
+
+	ranks := make([]float64, len(noise.Values))
+	for start := 0; start < len(order); {
+		end := start + 1
+		for end < len(order) && noise.Values[order[end]] == noise.Values[order[start]] {
+			end++
+		}
+		for _, index := range order[start:end] {
+			ranks[index] = float64(start+end-1) / 2
+		}
+		start = end
+	}
+
+	for i, rank := range ranks {
+		noise.Values[i] = 0
+		if len(ranks) > 1 {
+			noise.Values[i] = rank/float64(len(ranks)-1)*2 - 1
+		}
+	}
+	noise.Range = &Range{Min: -1, Max: 1}
+}
+
+// A Curve is a piecewise linear remapping, through points with increasing xs
+// Inputs outside of the curve's xs take the value of its nearest end
+type Curve [][2]float64
+
+// NewCurve validates the points of a curve
+func NewCurve(points [][2]float64) (Curve, error) {
+	if len(points) < 2 {
+		return nil, errors.New("Curves require at least 2 points")
+	}
+	for i := 1; i < len(points); i++ {
+		if points[i][0] <= points[i-1][0] {
+			return nil, errors.New("Curves require points with increasing xs")
+		}
+	}
+	return Curve(points), nil
+}
+
+// At returns the value of the curve at x
+func (curve Curve) At(x float64) float64 {
+	if x <= curve[0][0] {
+		return curve[0][1]
+	}
+	for i := 1; i < len(curve); i++ {
+		if x <= curve[i][0] {
+			a, b := curve[i-1], curve[i]
+			return a[1] + (b[1]-a[1])*(x-a[0])/(b[0]-a[0])
+		}
+	}
+	return curve[len(curve)-1][1]
+}
+
+// ApplyCurve normalizes the values to [-1, 1] like NormalizeMinMax, and then remaps them through the curve
+// The range is the range of the curve over [-1, 1], which is the same however the noise was generated
+func (noise *Noise) ApplyCurve(curve Curve) {
+	noise.NormalizeMinMax()
+	for i, value := range noise.Values {
+		noise.Values[i] = curve.At(value)
+	}
+
+	// A piecewise linear curve is most extreme at the ends of the range, or at one of its points within it
+	min, max := tgmath.MinMax([]float64{curve.At(-1), curve.At(1)})
+	for _, point := range curve {
+		if point[0] > -1 && point[0] < 1 {
+			min, max = math.Min(min, point[1]), math.Max(max, point[1])
+		}
+	}
+	noise.Range = &Range{Min: min, Max: max}
+}
@@ -0,0 +1,133 @@
+package noise_test
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/bcokert/terragen/noise"
+)
+
+func isSliceAlmostEqual(a, b []float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if math.Abs(a[i]-b[i]) > 0.00000000001 {
+			return false
+		}
+	}
+	return true
+}
+
+func TestNoise_Normalize(t *testing.T) {
+	curve, _ := noise.NewCurve([][2]float64{{-1, 0}, {0, 0.25}, {1, 1}})
+	peaked, _ := noise.NewCurve([][2]float64{{-2, 0}, {0, 3}, {2, 0}})
+
+	testCases := map[string]struct {
+		Values         []float64
+		Normalize      func(noise *noise.Noise) error
+		ExpectedValues []float64
+		ExpectedRange  noise.Range
+	}{
+		"bounds": {
+			Values:         []float64{-1, 0.5, 2},
+			Normalize:      func(n *noise.Noise) error { return n.NormalizeBounds(4) },
+			ExpectedValues: []float64{-0.25, 0.125, 0.5},
+			ExpectedRange:  noise.Range{Min: -1, Max: 1},
+		},
+		"bounds exceeded": {
+			Values:         []float64{-6, 2, 5},
+			Normalize:      func(n *noise.Noise) error { return n.NormalizeBounds(4) },
+			ExpectedValues: []float64{-1, 0.5, 1},
+			ExpectedRange:  noise.Range{Min: -1, Max: 1},
+		},
+		"minmax": {
+			Values:         []float64{2, 4, 3, 6},
+			Normalize:      func(n *noise.Noise) error { n.NormalizeMinMax(); return nil },
+			ExpectedValues: []float64{-1, 0, -0.5, 1},
+			ExpectedRange:  noise.Range{Min: -1, Max: 1},
+		},
+		"constant minmax": {
+			Values:         []float64{3, 3},
+			Normalize:      func(n *noise.Noise) error { n.NormalizeMinMax(); return nil },
+			ExpectedValues: []float64{0, 0},
+			ExpectedRange:  noise.Range{Min: -1, Max: 1},
+		},
+		"zscore": {
+			Values:         []float64{2, 4, 4, 4, 5, 5, 7, 9},
+			Normalize:      func(n *noise.Noise) error { n.NormalizeZScore(); return nil },
+			ExpectedValues: []float64{-1.5, -0.5, -0.5, -0.5, 0, 0, 1, 2},
+			ExpectedRange:  noise.Range{Min: -1.5, Max: 2},
+		},
+		"equalize": {
+			Values:         []float64{10, -3, 0.5, 100, 7},
+			Normalize:      func(n *noise.Noise) error { n.Equalize(); return nil },
+			ExpectedValues: []float64{0.5, -1, -0.5, 1, 0},
+			ExpectedRange:  noise.Range{Min: -1, Max: 1},
+		},
+		"equalize ties": {
+			Values:         []float64{1, 2, 2, 3},
+			Normalize:      func(n *noise.Noise) error { n.Equalize(); return nil },
+			ExpectedValues: []float64{-1, 0, 0, 1},
+			ExpectedRange:  noise.Range{Min: -1, Max: 1},
+		},
+		"curve": {
+			Values:         []float64{0, 1, 2, 3, 4},
+			Normalize:      func(n *noise.Noise) error { n.ApplyCurve(curve); return nil },
+			ExpectedValues: []float64{0, 0.125, 0.25, 0.625, 1},
+			ExpectedRange:  noise.Range{Min: 0, Max: 1},
+		},
+		"curve past the range": {
+			Values:         []float64{0, 2, 4},
+			Normalize:      func(n *noise.Noise) error { n.ApplyCurve(peaked); return nil },
+			ExpectedValues: []float64{1.5, 3, 1.5},
+			ExpectedRange:  noise.Range{Min: 1.5, Max: 3},
+		},
+	}
+
+	for name, testCase := range testCases {
+		normalized := &noise.Noise{Values: testCase.Values}
+		if err := testCase.Normalize(normalized); err != nil {
+			t.Errorf("'%s' failed. Expected no error, received %s", name, err.Error())
+			continue
+		}
+
+		if !isSliceAlmostEqual(normalized.Values, testCase.ExpectedValues) {
+			t.Errorf("'%s' failed. Expected %v, received %v", name, testCase.ExpectedValues, normalized.Values)
+		}
+		if normalized.Range == nil || !reflect.DeepEqual(*normalized.Range, testCase.ExpectedRange) {
+			t.Errorf("'%s' failed. Expected range %+v, received %+v", name, testCase.ExpectedRange, normalized.Range)
+		}
+	}
+}
+
+func TestNoise_NormalizeBounds_Invalid(t *testing.T) {
+	for _, bound := range []float64{0, -1, math.Inf(1), math.NaN()} {
+		if err := (&noise.Noise{Values: []float64{1}}).NormalizeBounds(bound); err == nil || err.Error() != "Bound must be a positive number" {
+			t.Errorf("'%v' failed. Expected a bound error, received %v", bound, err)
+		}
+	}
+}
+
+func TestNewCurve(t *testing.T) {
+	testCases := map[string]struct {
+		Points        [][2]float64
+		ExpectedError string
+	}{
+		"valid":      {Points: [][2]float64{{0, 1}, {1, 0}}},
+		"one point":  {Points: [][2]float64{{0, 1}}, ExpectedError: "Curves require at least 2 points"},
+		"decreasing": {Points: [][2]float64{{0, 1}, {1, 0}, {0.5, 2}}, ExpectedError: "Curves require points with increasing xs"},
+		"repeated":   {Points: [][2]float64{{0, 1}, {0, 2}}, ExpectedError: "Curves require points with increasing xs"},
+	}
+
+	for name, testCase := range testCases {
+		_, err := noise.NewCurve(testCase.Points)
+		if testCase.ExpectedError == "" && err != nil {
+			t.Errorf("'%s' failed. Expected no error, received %s", name, err.Error())
+		}
+		if testCase.ExpectedError != "" && (err == nil || err.Error() != testCase.ExpectedError) {
+			t.Errorf("'%s' failed. Expected error %s, received %v", name, testCase.ExpectedError, err)
+		}
+	}
+}
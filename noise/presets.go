@@ -1,6 +1,7 @@
 package noise
 
 import (
+	"errors"
 	"math"
 
 	tgmath "github.com/bcokert/terragen/math"
@@ -21,29 +22,38 @@ var SpectralPresets = map[string]Preset{
 	"red":    Red,
 }
 
+// spectralExponents are the weight exponents of each Spectral Preset, which both build the presets and bound them
+var spectralExponents = map[string]float64{
+	"violet": 2,
+	"blue":   1,
+	"white":  0,
+	"pink":   -1,
+	"red":    -2,
+}
+
 // Violet is a Preset with heavy emphasis on high frequencies
 func Violet(source tgmath.Source, frequencies []float64) Function {
-	return spectral(source, frequencies, 2)
+	return spectral(source, frequencies, spectralExponents["violet"])
 }
 
 // Blue is a Preset with light emphasis on high frequencies
 func Blue(source tgmath.Source, frequencies []float64) Function {
-	return spectral(source, frequencies, 1)
+	return spectral(source, frequencies, spectralExponents["blue"])
 }
 
 // White is a Preset with equal emphasis on all frequencies
 func White(source tgmath.Source, frequencies []float64) Function {
-	return spectral(source, frequencies, 0)
+	return spectral(source, frequencies, spectralExponents["white"])
 }
 
 // Pink is a Preset with light emphasis on low frequencies
 func Pink(source tgmath.Source, frequencies []float64) Function {
-	return spectral(source, frequencies, -1)
+	return spectral(source, frequencies, spectralExponents["pink"])
 }
 
 // Red is a Preset with heavy emphasis on low frequencies
 func Red(source tgmath.Source, frequencies []float64) Function {
-	return spectral(source, frequencies, -2)
+	return spectral(source, frequencies, spectralExponents["red"])
 }
 
 func spectral(source tgmath.Source, frequencies []float64, weightExponent float64) Function {
@@ -73,3 +83,21 @@ func RawPerlin(source tgmath.Source, frequencies []float64) Function {
 	interpolator := tgmath.NewInterpolator(tgmath.DampCubicEase)
 	return Perlin(cache, interpolator)
 }
+
+// PresetBound returns the largest magnitude that the named Preset can reach with the given frequencies
+// Spectral Presets sum sinusoids, so they're bounded by the sum of their weights, and 2D Perlin noise with unit gradients is bounded by sqrt(2)/2
+func PresetBound(name string, frequencies []float64) (float64, error) {
+	if weightExponent, ok := spectralExponents[name]; ok {
+		bound := 0.0
+		for _, frequency := range frequencies {
+			bound += math.Pow(frequency, weightExponent)
+		}
+		return bound, nil
+	}
+
+	if name == "rawPerlin" {
+		return math.Sqrt2 / 2, nil
+	}
+
+	return 0, errors.New("Preset must be a spectral or lattice preset")
+}
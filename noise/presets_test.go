@@ -84,3 +84,45 @@ func TestRawPerlin(t *testing.T) {
 		}
 	}
 }
+
+func TestPresetBound(t *testing.T) {
+	testCases := map[string]struct {
+		Name          string
+		Frequencies   []float64
+		Expected      float64
+		ExpectedError string
+	}{
+		"red":       {Name: "red", Frequencies: []float64{1, 2, 4}, Expected: 1 + 0.25 + 0.0625},
+		"violet":    {Name: "violet", Frequencies: []float64{1, 2, 4}, Expected: 1 + 4 + 16},
+		"white":     {Name: "white", Frequencies: []float64{1, 2, 4, 8}, Expected: 4},
+		"rawPerlin": {Name: "rawPerlin", Frequencies: []float64{1}, Expected: math.Sqrt2 / 2},
+		"unknown":   {Name: "diamondSquare", Frequencies: []float64{1}, ExpectedError: "Preset must be a spectral or lattice preset"},
+	}
+
+	for name, testCase := range testCases {
+		bound, err := noise.PresetBound(testCase.Name, testCase.Frequencies)
+		if testCase.ExpectedError != "" {
+			if err == nil || err.Error() != testCase.ExpectedError {
+				t.Errorf("'%s' failed. Expected error %s, received %v", name, testCase.ExpectedError, err)
+			}
+			continue
+		}
+		if !tgmath.IsFloatEqual(bound, testCase.Expected) {
+			t.Errorf("'%s' failed. Expected %v, received %v", name, testCase.Expected, bound)
+		}
+
+		// Every sample is within the bound
+		preset, ok := noise.SpectralPresets[testCase.Name]
+		if !ok {
+			preset = noise.LatticePresets[testCase.Name]
+		}
+		sampled := noise.NewNoise(testCase.Name)
+		sampled.Generate([]int{0, 0}, []int{4, 4}, 8, preset(tgmath.NewDefaultSource(42), testCase.Frequencies))
+		for _, value := range sampled.Values {
+			if math.Abs(value) > bound {
+				t.Errorf("'%s' failed. Expected every value to be within %v, received %v", name, bound, value)
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package noise
+
+import (
+	"errors"
+	"math"
+
+	tgmath "github.com/bcokert/terragen/math"
+)
+
+// A Raster is a 2D grid of samples that don't come from a noise function, such as an imported heightmap
+// Values are laid out like Noise values, so the value at sample (x, y) is Values[x*Height+y]
+type Raster struct {
+	Values []float64
+	Width  int
+	Height int
+}
+
+// NewRaster validates the size of a raster
+func NewRaster(values []float64, width, height int) (*Raster, error) {
+	if width < 1 || height < 1 || len(values) != width*height {
+		return nil, errors.New("Rasters require a positive width and height, with a value for each sample")
+	}
+	return &Raster{Values: values, Width: width, Height: height}, nil
+}
+
+// Function stretches the raster over the rectangle from from to to, with its first and last samples on the rectangle's edges
+// It's interpolated bicubically between samples with the interpolator, so it can be sampled at any resolution
+// Points outside of the rectangle take the value of the nearest point on its edge
+func (raster *Raster) Function(from, to [2]float64, interpolator tgmath.CubicInterpolator) Function {
+	// Neighbours just past an edge are extrapolated linearly from the edge, so ramps stay straight right up to it
+	var at func(x, y int) float64
+	at = func(x, y int) float64 {
+		switch {
+		case x < 0 && raster.Width > 1:
+			return 2*at(0, y) - at(1, y)
+		case x >= raster.Width && raster.Width > 1:
+			return 2*at(raster.Width-1, y) - at(raster.Width-2, y)
+		case y < 0 && raster.Height > 1:
+			return 2*at(x, 0) - at(x, 1)
+		case y >= raster.Height && raster.Height > 1:
+			return 2*at(x, raster.Height-1) - at(x, raster.Height-2)
+		}
+		x = int(math.Max(0, math.Min(float64(raster.Width-1), float64(x))))
+		y = int(math.Max(0, math.Min(float64(raster.Height-1), float64(y))))
+		return raster.Values[x*raster.Height+y]
+	}
+
+	// cell returns the sample before a position along a dimension of count samples, and how far the position is past it
+	cell := func(position, from, to float64, count int) (int, float64) {
+		if count < 2 || to <= from {
+			return 0, 0
+		}
+		sample := math.Max(0, math.Min(float64(count-1), (position-from)/(to-from)*float64(count-1)))
+		index := int(math.Min(float64(count-2), math.Floor(sample)))
+		return index, sample - float64(index)
+	}
+
+	return func(t []float64) float64 {
+		x, u := cell(t[0], from[0], to[0], raster.Width)
+		y, v := cell(t[1], from[1], to[1], raster.Height)
+
+		var columns [4]float64
+		for i := range columns {
+			columns[i] = interpolator(v, at(x+i-1, y-1), at(x+i-1, y), at(x+i-1, y+1), at(x+i-1, y+2))
+		}
+		return interpolator(u, columns[0], columns[1], columns[2], columns[3])
+	}
+}
+
+// SlopeDetail adds detail noise to a base function, scaled by how steep the base is, so flat ground stays smooth and slopes get rough
+// The detail has the full amplitude where the base's gradient is at least steepness, and fades linearly to nothing where it's flat
+// The gradient is estimated with central differences of the given step
+func SlopeDetail(base, detail Function, amplitude, steepness, step float64) Function {
+	return func(t []float64) float64 {
+		height := base(t)
+
+		gradient := 0.0
+		offset := make([]float64, len(t))
+		for i := range t {
+			copy(offset, t)
+			offset[i] = t[i] + step
+			ahead := base(offset)
+			offset[i] = t[i] - step
+			behind := base(offset)
+			gradient += math.Pow((ahead-behind)/(2*step), 2)
+		}
+
+		return height + amplitude*math.Min(1, math.Sqrt(gradient)/steepness)*detail(t)
+	}
+}
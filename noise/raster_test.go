@@ -0,0 +1,92 @@
+package noise_test
+
+import (
+	"math"
+	"testing"
+
+	tgmath "github.com/bcokert/terragen/math"
+	"github.com/bcokert/terragen/noise"
+)
+
+func TestNewRaster(t *testing.T) {
+	testCases := map[string]struct {
+		Values        []float64
+		Width, Height int
+		ExpectedError string
+	}{
+		"valid":        {Values: []float64{1, 2, 3, 4, 5, 6}, Width: 2, Height: 3},
+		"empty":        {Values: []float64{}, Width: 0, Height: 0, ExpectedError: "Rasters require a positive width and height, with a value for each sample"},
+		"wrong length": {Values: []float64{1, 2, 3}, Width: 2, Height: 2, ExpectedError: "Rasters require a positive width and height, with a value for each sample"},
+	}
+
+	for name, testCase := range testCases {
+		_, err := noise.NewRaster(testCase.Values, testCase.Width, testCase.Height)
+		if testCase.ExpectedError == "" && err != nil {
+			t.Errorf("'%s' failed. Expected no error, received %s", name, err.Error())
+		}
+		if testCase.ExpectedError != "" && (err == nil || err.Error() != testCase.ExpectedError) {
+			t.Errorf("'%s' failed. Expected error %s, received %v", name, testCase.ExpectedError, err)
+		}
+	}
+}
+
+func TestRaster_Function(t *testing.T) {
+	// A 3x2 raster of a plane stretched over [0, 4] x [0, 2], so the samples are 2 apart in x and 2 apart in y
+	plane, _ := noise.NewRaster([]float64{0, 2, 1, 3, 2, 4}, 3, 2)
+	single, _ := noise.NewRaster([]float64{7}, 1, 1)
+
+	testCases := map[string]struct {
+		Raster   *noise.Raster
+		Point    []float64
+		Expected float64
+	}{
+		"first sample":  {Raster: plane, Point: []float64{0, 0}, Expected: 0},
+		"last sample":   {Raster: plane, Point: []float64{4, 2}, Expected: 4},
+		"between":       {Raster: plane, Point: []float64{1, 1}, Expected: 1.5},
+		"across a cell": {Raster: plane, Point: []float64{3, 0.5}, Expected: 2},
+		"clamped":       {Raster: plane, Point: []float64{-3, 9}, Expected: 2},
+		"single sample": {Raster: single, Point: []float64{1.3, 0.2}, Expected: 7},
+	}
+
+	for name, testCase := range testCases {
+		fn := testCase.Raster.Function([2]float64{0, 0}, [2]float64{4, 2}, tgmath.CatmullRom)
+		if result := fn(testCase.Point); !tgmath.IsFloatEqual(result, testCase.Expected) {
+			t.Errorf("'%s' failed. Expected %v, received %v", name, testCase.Expected, result)
+		}
+	}
+}
+
+func TestRaster_Function_Smooth(t *testing.T) {
+	// Bicubic interpolation is continuous across cells, unlike the nearest sample
+	bumpy, _ := noise.NewRaster([]float64{0, 1, 0, 1, 3, 1, 0, 1, 0, 2, 0, 2}, 4, 3)
+	fn := bumpy.Function([2]float64{0, 0}, [2]float64{3, 2}, tgmath.CatmullRom)
+
+	for _, x := range []float64{1, 2} {
+		before, after := fn([]float64{x - 0.0001, 0.7}), fn([]float64{x + 0.0001, 0.7})
+		if math.Abs(before-after) > 0.001 {
+			t.Errorf("Expected the raster to be continuous at x = %v, received %v and %v", x, before, after)
+		}
+	}
+}
+
+func TestSlopeDetail(t *testing.T) {
+	detail := func(t []float64) float64 { return 1 }
+
+	testCases := map[string]struct {
+		Base      noise.Function
+		Steepness float64
+		Expected  float64
+	}{
+		"flat":     {Base: func(t []float64) float64 { return 2 }, Steepness: 1, Expected: 2},
+		"gentle":   {Base: func(t []float64) float64 { return 0.3 * t[0] }, Steepness: 1, Expected: 0.3 + 0.5*0.3},
+		"steep":    {Base: func(t []float64) float64 { return 3 * t[0] }, Steepness: 1, Expected: 3 + 0.5},
+		"diagonal": {Base: func(t []float64) float64 { return 0.3*t[0] + 0.4*t[1] }, Steepness: 2, Expected: 0.7 + 0.5*0.25},
+	}
+
+	for name, testCase := range testCases {
+		fn := noise.SlopeDetail(testCase.Base, detail, 0.5, testCase.Steepness, 0.01)
+		if result := fn([]float64{1, 1}); math.Abs(result-testCase.Expected) > 1e-9 {
+			t.Errorf("'%s' failed. Expected %v, received %v", name, testCase.Expected, result)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package noise
+
+import (
+	"errors"
+	"math"
+	"math/cmplx"
+
+	tgmath "github.com/bcokert/terragen/math"
+)
+
+// SpectralSynthesis returns a Grid Preset that synthesizes noise whose power spectrum falls off as 1/f^beta, by the inverse FFT of a random spectrum
+// Beta can be any number, where 0 is white, 1 is pink, 2 is red, and negative betas emphasize high frequencies like blue and violet
+// The noise is periodic over its lattice, so it tiles seamlessly. Roughness is ignored, since beta decides how the detail scales
+func SpectralSynthesis(beta float64) GridPreset {
+	return func(source tgmath.Source, shape []int, roughness float64) ([]float64, error) {
+		if len(shape) != 1 && len(shape) != 2 {
+			return nil, errors.New("Grid presets only support 1 or 2 dimensions")
+		}
+
+		// 1 dimensional noise is a 2 dimensional grid with a single column
+		grid := [2]int{shape[0], 1}
+		if len(shape) == 2 {
+			grid[1] = shape[1]
+		}
+
+		// Each frequency gets a normally distributed complex amplitude, which is a uniformly random phase with a Rayleigh distributed magnitude
+		// The constant term is left at zero, so the noise is centered on zero
+		spectrum := make([]complex128, grid[0]*grid[1])
+		for x := 0; x < grid[0]; x++ {
+			for y := 0; y < grid[1]; y++ {
+				frequency := math.Hypot(signedFrequency(x, grid[0]), signedFrequency(y, grid[1]))
+				if frequency == 0 {
+					continue
+				}
+				magnitude := math.Sqrt(-2*math.Log(1-source.Float64())) * math.Pow(frequency, -beta/2)
+				spectrum[x*grid[1]+y] = cmplx.Rect(magnitude, 2*math.Pi*source.Float64())
+			}
+		}
+
+		heights, err := tgmath.InverseFFT2D(spectrum, grid)
+		if err != nil {
+			return nil, err
+		}
+
+		// The real part has the same spectrum as the complex noise. It's scaled so its largest magnitude is 1
+		values := make([]float64, len(heights))
+		largest := 0.0
+		for i, height := range heights {
+			values[i] = real(height)
+			largest = math.Max(largest, math.Abs(values[i]))
+		}
+		if largest > 0 {
+			for i := range values {
+				values[i] /= largest
+			}
+		}
+		return values, nil
+	}
+}
+
+// signedFrequency returns the frequency of the index of a transform of n values, in cycles over the n values
+// Indices past the middle are negative frequencies
+func signedFrequency(index, n int) float64 {
+	if index > n/2 {
+		return float64(index - n)
+	}
+	return float64(index)
+}
@@ -0,0 +1,107 @@
+package noise_test
+
+import (
+	"math"
+	"math/cmplx"
+	"testing"
+
+	tgmath "github.com/bcokert/terragen/math"
+	"github.com/bcokert/terragen/noise"
+)
+
+func TestSpectralSynthesis(t *testing.T) {
+	testCases := map[string]struct {
+		Shape            []int
+		ExpectedSamples  int
+		ExpectedErrorMsg string
+	}{
+		"1d":          {Shape: []int{50}, ExpectedSamples: 50},
+		"2d":          {Shape: []int{12, 7}, ExpectedSamples: 84},
+		"single":      {Shape: []int{1, 1}, ExpectedSamples: 1},
+		"3d":          {Shape: []int{4, 4, 4}, ExpectedErrorMsg: "Grid presets only support 1 or 2 dimensions"},
+		"0 dimension": {Shape: []int{}, ExpectedErrorMsg: "Grid presets only support 1 or 2 dimensions"},
+	}
+
+	for name, testCase := range testCases {
+		values, err := noise.SpectralSynthesis(2)(tgmath.NewDefaultSource(42), testCase.Shape, 0.5)
+		if testCase.ExpectedErrorMsg != "" {
+			if err == nil || err.Error() != testCase.ExpectedErrorMsg {
+				t.Errorf("'%s' failed. Expected error %s, received %v", name, testCase.ExpectedErrorMsg, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("'%s' failed. Expected no error, received %s", name, err.Error())
+			continue
+		}
+
+		if len(values) != testCase.ExpectedSamples {
+			t.Errorf("'%s' failed. Expected %d samples, received %d", name, testCase.ExpectedSamples, len(values))
+			continue
+		}
+
+		// Without any frequencies, there's nothing but the constant term, which is zero
+		largest := 0.0
+		for _, value := range values {
+			largest = math.Max(largest, math.Abs(value))
+		}
+		if expected := math.Min(1, float64(len(values)-1)); largest != expected {
+			t.Errorf("'%s' failed. Expected the largest magnitude to be %v, received %v", name, expected, largest)
+		}
+	}
+}
+
+func TestSpectralSynthesis_PowerSpectrum(t *testing.T) {
+	// Compensating each frequency's power by f^beta should flatten the spectrum, so low and high bands have the same average
+	band := func(spectrum []complex128, size int, beta, low, high float64) float64 {
+		sum, count := 0.0, 0
+		for x := 0; x < size; x++ {
+			for y := 0; y < size; y++ {
+				fx, fy := float64(x), float64(y)
+				if x > size/2 {
+					fx -= float64(size)
+				}
+				if y > size/2 {
+					fy -= float64(size)
+				}
+				if frequency := math.Hypot(fx, fy); frequency >= low && frequency < high {
+					sum += math.Pow(cmplx.Abs(spectrum[x*size+y]), 2) * math.Pow(frequency, beta)
+					count++
+				}
+			}
+		}
+		return sum / float64(count)
+	}
+
+	for _, beta := range []float64{0, 1, 1.6, 2, 3} {
+		values, _ := noise.SpectralSynthesis(beta)(tgmath.NewDefaultSource(7), []int{64, 64}, 0)
+
+		heights := make([]complex128, len(values))
+		for i, value := range values {
+			heights[i] = complex(value, 0)
+		}
+		spectrum, _ := tgmath.FFT2D(heights, [2]int{64, 64})
+
+		low, high := band(spectrum, 64, beta, 4, 8), band(spectrum, 64, beta, 16, 32)
+		if ratio := low / high; ratio < 0.7 || ratio > 1.4 {
+			t.Errorf("'beta %v' failed. Expected the compensated bands to match, received %v and %v", beta, low, high)
+		}
+	}
+}
+
+func TestSpectralSynthesis_Deterministic(t *testing.T) {
+	first, _ := noise.SpectralSynthesis(1.5)(tgmath.NewDefaultSource(3), []int{16, 16}, 0)
+	second, _ := noise.SpectralSynthesis(1.5)(tgmath.NewDefaultSource(3), []int{16, 16}, 0)
+	other, _ := noise.SpectralSynthesis(1.5)(tgmath.NewDefaultSource(4), []int{16, 16}, 0)
+
+	different := false
+	for i := range first {
+		if first[i] != second[i] {
+			t.Fatalf("Expected the same seed to generate the same values")
+		}
+		different = different || first[i] != other[i]
+	}
+	if !different {
+		t.Errorf("Expected different seeds to generate different values")
+	}
+}
@@ -0,0 +1,57 @@
+package noise
+
+import (
+	"math"
+)
+
+// A Tile is one square of a quadtree pyramid over a 2D world, identified by its zoom level and x, y position within that level
+// Zoom 0 is a single tile covering the root of the world, and each zoom level splits every tile into 4, sampled at double the resolution
+// Tiles include the samples on all four of their edges, so neighbouring tiles share identical edge samples and stitch without cracks
+type Tile struct {
+	Values        []float64 `json:"values"`
+	From          []float64 `json:"from"`
+	To            []float64 `json:"to"`
+	Zoom          int       `json:"zoom"`
+	X             int       `json:"x"`
+	Y             int       `json:"y"`
+	Samples       int       `json:"samples"`
+	NoiseFunction string    `json:"noiseFunction"`
+}
+
+// NewTile creates a new Tile object at the given zoom level and position
+func NewTile(noiseFunction string, zoom, x, y int) *Tile {
+	return &Tile{
+		NoiseFunction: noiseFunction,
+		Zoom:          zoom,
+		X:             x,
+		Y:             y,
+	}
+}
+
+// TileSize returns the width of a tile at the given zoom level, in world units
+func TileSize(rootSize float64, zoom int) float64 {
+	return rootSize / math.Pow(2, float64(zoom))
+}
+
+// Generate populates the tile with a samples x samples grid of the noise function, spanning the tile's fractional bounds within a
+// world of rootSize units
+// Sample coordinates are computed from their index across the whole zoom level, so a tile's last row is bit for bit its neighbour's first,
+// and every other sample lands exactly on a sample of the parent tile
+func (tile *Tile) Generate(rootSize float64, samples int, noiseFunction Function) {
+	size := TileSize(rootSize, tile.Zoom)
+	segments := samples - 1
+	offsets := []int{tile.X * segments, tile.Y * segments}
+
+	tile.Values = sampleLattice([]int{samples, samples}, func(dimension, index int) float64 {
+		return float64(offsets[dimension]+index) * size / float64(segments)
+	}, noiseFunction)
+
+	tile.From = []float64{float64(tile.X) * size, float64(tile.Y) * size}
+	tile.To = []float64{float64(tile.X+1) * size, float64(tile.Y+1) * size}
+	tile.Samples = samples
+}
+
+// Spacing returns the distance between neighbouring samples of the tile, in world units
+func (tile *Tile) Spacing() float64 {
+	return (tile.To[0] - tile.From[0]) / float64(tile.Samples-1)
+}
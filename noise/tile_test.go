@@ -0,0 +1,114 @@
+package noise_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/bcokert/terragen/noise"
+)
+
+func TestTileSize(t *testing.T) {
+	testCases := map[string]struct {
+		RootSize float64
+		Zoom     int
+		Expected float64
+	}{
+		"root":    {RootSize: 64, Zoom: 0, Expected: 64},
+		"zoomed":  {RootSize: 64, Zoom: 3, Expected: 8},
+		"sub one": {RootSize: 1, Zoom: 4, Expected: 0.0625},
+	}
+
+	for name, testCase := range testCases {
+		if result := noise.TileSize(testCase.RootSize, testCase.Zoom); result != testCase.Expected {
+			t.Errorf("'%s' failed. Expected %v, received %v", name, testCase.Expected, result)
+		}
+	}
+}
+
+func TestTile_Generate(t *testing.T) {
+	testCases := map[string]struct {
+		Zoom, X, Y     int
+		RootSize       float64
+		Samples        int
+		ExpectedFrom   []float64
+		ExpectedTo     []float64
+		ExpectedValues []float64
+	}{
+		"root": {
+			Zoom: 0, X: 0, Y: 0,
+			RootSize:       4,
+			Samples:        3,
+			ExpectedFrom:   []float64{0, 0},
+			ExpectedTo:     []float64{4, 4},
+			ExpectedValues: []float64{0, 20, 40, 2, 22, 42, 4, 24, 44},
+		},
+		"fractional": {
+			Zoom: 3, X: 5, Y: 2,
+			RootSize:       1,
+			Samples:        2,
+			ExpectedFrom:   []float64{0.625, 0.25},
+			ExpectedTo:     []float64{0.75, 0.375},
+			ExpectedValues: []float64{0.625 + 2.5, 0.625 + 3.75, 0.75 + 2.5, 0.75 + 3.75},
+		},
+	}
+
+	for name, testCase := range testCases {
+		tile := noise.NewTile("test", testCase.Zoom, testCase.X, testCase.Y)
+		tile.Generate(testCase.RootSize, testCase.Samples, func(t []float64) float64 {
+			return t[0] + 10*t[1]
+		})
+
+		for i := range testCase.ExpectedFrom {
+			if tile.From[i] != testCase.ExpectedFrom[i] || tile.To[i] != testCase.ExpectedTo[i] {
+				t.Errorf("'%s' failed. Expected bounds %v %v, received %v %v", name, testCase.ExpectedFrom, testCase.ExpectedTo, tile.From, tile.To)
+				break
+			}
+		}
+		if len(tile.Values) != len(testCase.ExpectedValues) {
+			t.Errorf("'%s' failed. Expected %v, received %v", name, testCase.ExpectedValues, tile.Values)
+			continue
+		}
+		for i := range tile.Values {
+			if math.Abs(tile.Values[i]-testCase.ExpectedValues[i]) > 0.00000000000001 {
+				t.Errorf("'%s' failed. Expected %v, received %v", name, testCase.ExpectedValues, tile.Values)
+				break
+			}
+		}
+	}
+}
+
+func TestTile_GenerateStitches(t *testing.T) {
+	// An irrational function, so any difference in sample coordinates shows up in the values
+	fn := func(t []float64) float64 {
+		return math.Sin(t[0]*math.Pi*1.7) * math.Cos(t[1]*math.E)
+	}
+	samples := 9
+
+	generate := func(zoom, x, y int) *noise.Tile {
+		tile := noise.NewTile("test", zoom, x, y)
+		tile.Generate(10, samples, fn)
+		return tile
+	}
+	at := func(tile *noise.Tile, x, y int) float64 {
+		return tile.Values[x*samples+y]
+	}
+
+	left, right, below := generate(4, 6, 9), generate(4, 7, 9), generate(4, 6, 10)
+	for i := 0; i < samples; i++ {
+		if at(left, samples-1, i) != at(right, 0, i) {
+			t.Errorf("Expected the shared x edge to match at %d: %v != %v", i, at(left, samples-1, i), at(right, 0, i))
+		}
+		if at(left, i, samples-1) != at(below, i, 0) {
+			t.Errorf("Expected the shared y edge to match at %d: %v != %v", i, at(left, i, samples-1), at(below, i, 0))
+		}
+	}
+
+	parent, child := generate(3, 3, 4), generate(4, 7, 9)
+	for x := 0; x < samples; x += 2 {
+		for y := 0; y < samples; y += 2 {
+			if at(child, x, y) != at(parent, (samples-1)/2+x/2, (samples-1)/2+y/2) {
+				t.Errorf("Expected child sample %d,%d to match its parent sample", x, y)
+			}
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package scatter
+
+import (
+	"math"
+
+	tgmath "github.com/bcokert/terragen/math"
+)
+
+// A placement is a point placed by Poisson, along with the distance it keeps from other points
+type placement struct {
+	position [2]float64
+	radius   float64
+}
+
+// poissonGrid buckets placements so that the neighbours of a point can be found without checking every placement
+type poissonGrid struct {
+	from     [2]float64
+	cellSize float64
+	reach    int
+	cells    map[[2]int][]placement
+}
+
+func newPoissonGrid(from [2]float64, minDistance, maxDistance float64) *poissonGrid {
+	return &poissonGrid{
+		from:     from,
+		cellSize: minDistance,
+		reach:    int(math.Ceil(maxDistance / minDistance)),
+		cells:    map[[2]int][]placement{},
+	}
+}
+
+func (grid *poissonGrid) cell(position [2]float64) [2]int {
+	return [2]int{int(math.Floor((position[0] - grid.from[0]) / grid.cellSize)), int(math.Floor((position[1] - grid.from[1]) / grid.cellSize))}
+}
+
+func (grid *poissonGrid) add(point placement) {
+	cell := grid.cell(point.position)
+	grid.cells[cell] = append(grid.cells[cell], point)
+}
+
+// fits returns whether a candidate is far enough from every placement, where each pair keeps the larger of their two distances
+func (grid *poissonGrid) fits(candidate placement) bool {
+	cell := grid.cell(candidate.position)
+	for x := cell[0] - grid.reach; x <= cell[0]+grid.reach; x++ {
+		for y := cell[1] - grid.reach; y <= cell[1]+grid.reach; y++ {
+			for _, other := range grid.cells[[2]int{x, y}] {
+				if tooClose(candidate, other) {
+					return false
+				}
+			}
+		}
+	}
+	return true
+}
+
+// tooClose returns whether two placements are within the larger of their distances
+func tooClose(a, b placement) bool {
+	radius := math.Max(a.radius, b.radius)
+	dx, dy := a.position[0]-b.position[0], a.position[1]-b.position[1]
+	return dx*dx+dy*dy < radius*radius
+}
+
+// poisson places points within the rectangle between from and to with Bridson's algorithm, so that no two are closer than the larger of their radii
+// Radius gives the distance to keep around each point, and must be between minDistance and maxDistance
+// Attempts is how many candidates are tried around each point before it is considered surrounded
+func poisson(source tgmath.Source, from, to [2]float64, minDistance, maxDistance float64, radius func(position [2]float64) float64, attempts int) []placement {
+	grid := newPoissonGrid(from, minDistance, maxDistance)
+	inside := func(position [2]float64) bool {
+		return position[0] >= from[0] && position[0] < to[0] && position[1] >= from[1] && position[1] < to[1]
+	}
+
+	first := [2]float64{from[0] + source.Float64()*(to[0]-from[0]), from[1] + source.Float64()*(to[1]-from[1])}
+	placements := []placement{{position: first, radius: radius(first)}}
+	grid.add(placements[0])
+	active := []int{0}
+
+	for len(active) > 0 {
+		i := int(source.Float64() * float64(len(active)))
+		if i == len(active) {
+			i--
+		}
+		center := placements[active[i]]
+
+		// Candidates are spread evenly over the annulus between one and two radii around the point
+		placed := false
+		for attempt := 0; attempt < attempts; attempt++ {
+			angle := source.Float64() * 2 * math.Pi
+			distance := center.radius * math.Sqrt(1+3*source.Float64())
+			position := [2]float64{center.position[0] + distance*math.Cos(angle), center.position[1] + distance*math.Sin(angle)}
+			if !inside(position) {
+				continue
+			}
+
+			candidate := placement{position: position, radius: radius(position)}
+			if grid.fits(candidate) {
+				placements = append(placements, candidate)
+				grid.add(candidate)
+				active = append(active, len(placements)-1)
+				placed = true
+				break
+			}
+		}
+
+		if !placed {
+			active[i] = active[len(active)-1]
+			active = active[:len(active)-1]
+		}
+	}
+
+	return placements
+}
@@ -0,0 +1,260 @@
+package scatter
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	tgmath "github.com/bcokert/terragen/math"
+	"github.com/bcokert/terragen/noise"
+)
+
+// An Instance is a placed object, such as a tree or rock
+type Instance struct {
+	Position tgmath.Vec3 `json:"position"`
+	Rotation float64     `json:"rotation"` // Radians counter-clockwise around the z axis
+	Scale    float64     `json:"scale"`
+}
+
+// A Property is a feature of the terrain under an instance, which rules filter on
+type Property int
+
+const (
+	// Height is the value of the height function, in world units
+	Height Property = iota
+	// Slope is the steepness of the height function, in degrees from horizontal
+	Slope
+)
+
+// A Rule only allows instances where a property of the terrain is between Min and Max
+type Rule struct {
+	Property Property
+	Min      float64
+	Max      float64
+}
+
+// allows returns whether the rule allows an instance on terrain with the given height and slope
+func (rule Rule) allows(height, slope float64) bool {
+	value := height
+	if rule.Property == Slope {
+		value = slope
+	}
+	return value >= rule.Min && value <= rule.Max
+}
+
+// Options controls how instances are scattered
+type Options struct {
+	MinDistance float64 // The spacing between instances where the density is 1
+	MaxDistance float64 // The spacing between instances where the density is 0
+	CellSize    float64 // The size of the cells the world is split into, each of which is placed independently
+	Attempts    int     // How many candidates are tried around each instance before it is considered surrounded
+	MinScale    float64
+	MaxScale    float64
+	Rules       []Rule // Instances are kept only where every rule allows them
+}
+
+// NewDefaultOptions returns options for evenly spaced instances of varying scale, with no rules
+func NewDefaultOptions() Options {
+	return Options{
+		MinDistance: 0.25,
+		MaxDistance: 1,
+		CellSize:    8,
+		Attempts:    30,
+		MinScale:    0.8,
+		MaxScale:    1.2,
+	}
+}
+
+// MaxDistanceRatio limits MaxDistance to a multiple of MinDistance, since placing each point searches a grid that many cells around it
+const MaxDistanceRatio = 16
+
+// Validate returns an error describing the first problem with the options, if any
+// The comparisons are negated so that NaNs fail them
+func (options Options) Validate() error {
+	if !(options.MinDistance > 0) || math.IsInf(options.MinDistance, 0) {
+		return errors.New("MinDistance must be a positive number")
+	}
+	if !(options.MaxDistance >= options.MinDistance) {
+		return errors.New("MaxDistance must be at least MinDistance")
+	}
+	if !(options.MaxDistance <= options.MinDistance*MaxDistanceRatio) {
+		return fmt.Errorf("MaxDistance must be at most %d times MinDistance", MaxDistanceRatio)
+	}
+	if !(options.CellSize >= options.MaxDistance) || math.IsInf(options.CellSize, 0) {
+		return errors.New("CellSize must be at least MaxDistance")
+	}
+	if options.Attempts < 1 {
+		return errors.New("Attempts must be a positive integer")
+	}
+	if options.MinScale <= 0 || options.MaxScale < options.MinScale {
+		return errors.New("Scales must be positive, with MinScale no more than MaxScale")
+	}
+	for _, rule := range options.Rules {
+		if rule.Max < rule.Min {
+			return errors.New("Rules must have a min no more than their max")
+		}
+	}
+	return nil
+}
+
+// Scatter places instances within the rectangle between from and to, spaced with Poisson-disc sampling
+// Density is clamped to [0, 1], and moves the spacing from MaxDistance where it's 0 to MinDistance where it's 1. A nil density is always 1
+// Height gives the terrain height under each instance, which the rules filter on. A nil height is flat at 0
+// The world is split into cells that are each seeded from the seed and their position, and points near the border of two cells are
+// resolved in favour of the earlier cell, so any rectangle gets the same instances as the rectangles it overlaps, and tiles agree at their borders
+func Scatter(seed int64, from, to [2]float64, density, height noise.Function, options Options) ([]Instance, error) {
+	if err := options.Validate(); err != nil {
+		return nil, err
+	}
+
+	scatterer := &scatterer{
+		seed:    seed,
+		density: density,
+		options: options,
+		cells:   map[[2]int][]candidate{},
+	}
+
+	first, last := coveredCells(from, to, options.CellSize)
+
+	instances := []Instance{}
+	for x := first[0]; x <= last[0]; x++ {
+		for y := first[1]; y <= last[1]; y++ {
+			for _, candidate := range scatterer.resolve([2]int{x, y}) {
+				position := candidate.position
+				if position[0] < from[0] || position[0] >= to[0] || position[1] < from[1] || position[1] >= to[1] {
+					continue
+				}
+
+				z, slope := terrain(height, position, options.MinDistance/4)
+				allowed := true
+				for _, rule := range options.Rules {
+					allowed = allowed && rule.allows(z, slope)
+				}
+				if !allowed {
+					continue
+				}
+
+				instances = append(instances, Instance{
+					Position: tgmath.Vec3{position[0], position[1], z},
+					Rotation: candidate.rotation,
+					Scale:    candidate.scale,
+				})
+			}
+		}
+	}
+
+	return instances, nil
+}
+
+// CoveredArea returns the area of every cell that Scatter places points in for the rectangle between from and to
+// Whole cells are placed, along with the cells around them that resolve their borders, so it can be much larger than the rectangle
+func CoveredArea(from, to [2]float64, cellSize float64) float64 {
+	first, last := coveredCells(from, to, cellSize)
+	return float64(last[0]-first[0]+3) * float64(last[1]-first[1]+3) * cellSize * cellSize
+}
+
+// coveredCells returns the first and last cells that overlap the rectangle between from and to
+func coveredCells(from, to [2]float64, cellSize float64) (first, last [2]int) {
+	first = [2]int{int(math.Floor(from[0] / cellSize)), int(math.Floor(from[1] / cellSize))}
+	last = [2]int{int(math.Ceil(to[0]/cellSize)) - 1, int(math.Ceil(to[1]/cellSize)) - 1}
+	return first, last
+}
+
+// A candidate is a placement that will become an instance if it survives the border between cells and the rules
+type candidate struct {
+	placement
+	rotation float64
+	scale    float64
+}
+
+// scatterer places and caches the candidates of each cell
+type scatterer struct {
+	seed    int64
+	density noise.Function
+	options Options
+	cells   map[[2]int][]candidate
+}
+
+// generate returns every candidate placed within a cell, ignoring its neighbours
+func (scatterer *scatterer) generate(cell [2]int) []candidate {
+	if candidates, ok := scatterer.cells[cell]; ok {
+		return candidates
+	}
+
+	options := scatterer.options
+	radius := func(position [2]float64) float64 {
+		if scatterer.density == nil {
+			return options.MinDistance
+		}
+		density := math.Min(1, math.Max(0, scatterer.density(position[:])))
+		return options.MaxDistance - (options.MaxDistance-options.MinDistance)*density
+	}
+
+	source := tgmath.NewDefaultSource(cellSeed(scatterer.seed, cell))
+	from := [2]float64{float64(cell[0]) * options.CellSize, float64(cell[1]) * options.CellSize}
+	to := [2]float64{from[0] + options.CellSize, from[1] + options.CellSize}
+	placements := poisson(source, from, to, options.MinDistance, options.MaxDistance, radius, options.Attempts)
+
+	// Rotations and scales are drawn after every point is placed, so they're as deterministic as the points
+	candidates := make([]candidate, len(placements))
+	for i, placement := range placements {
+		candidates[i] = candidate{
+			placement: placement,
+			rotation:  source.Float64() * 2 * math.Pi,
+			scale:     options.MinScale + (options.MaxScale-options.MinScale)*source.Float64(),
+		}
+	}
+
+	scatterer.cells[cell] = candidates
+	return candidates
+}
+
+// resolve returns the candidates of a cell that aren't too close to a candidate of an earlier neighbouring cell
+// Only the neighbours' own candidates are considered, rather than what survives of them, so the result never depends on cells further away
+func (scatterer *scatterer) resolve(cell [2]int) []candidate {
+	options := scatterer.options
+	earlier := newPoissonGrid([2]float64{}, options.MinDistance, options.MaxDistance)
+	for x := cell[0] - 1; x <= cell[0]+1; x++ {
+		for y := cell[1] - 1; y <= cell[1]+1; y++ {
+			if x < cell[0] || (x == cell[0] && y < cell[1]) {
+				for _, other := range scatterer.generate([2]int{x, y}) {
+					earlier.add(other.placement)
+				}
+			}
+		}
+	}
+
+	var resolved []candidate
+	for _, candidate := range scatterer.generate(cell) {
+		if earlier.fits(candidate.placement) {
+			resolved = append(resolved, candidate)
+		}
+	}
+	return resolved
+}
+
+// cellSeed mixes the seed with a cell's position, so that every cell has its own independent random sequence
+func cellSeed(seed int64, cell [2]int) int64 {
+	hash := uint64(seed) ^ uint64(int64(cell[0]))*0x9e3779b97f4a7c15 ^ uint64(int64(cell[1]))*0xc2b2ae3d27d4eb4f
+	hash ^= hash >> 33
+	hash *= 0xff51afd7ed558ccd
+	hash ^= hash >> 33
+	hash *= 0xc4ceb9fe1a85ec53
+	hash ^= hash >> 33
+	return int64(hash)
+}
+
+// terrain returns the height and slope of the height function at a position, with the slope from central differences of the given step
+func terrain(height noise.Function, position [2]float64, step float64) (z, slope float64) {
+	if height == nil {
+		return 0, 0
+	}
+
+	at := func(x, y float64) float64 {
+		return height([]float64{x, y})
+	}
+	z = at(position[0], position[1])
+	dx := (at(position[0]+step, position[1]) - at(position[0]-step, position[1])) / (2 * step)
+	dy := (at(position[0], position[1]+step) - at(position[0], position[1]-step)) / (2 * step)
+	return z, math.Atan(math.Sqrt(dx*dx+dy*dy)) * 180 / math.Pi
+}
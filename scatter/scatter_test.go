@@ -0,0 +1,211 @@
+package scatter_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/bcokert/terragen/scatter"
+)
+
+// closestPair returns the smallest distance between any two instances
+func closestPair(instances []scatter.Instance) float64 {
+	closest := math.Inf(1)
+	for i := range instances {
+		for j := i + 1; j < len(instances); j++ {
+			closest = math.Min(closest, instances[i].Position.Sub(instances[j].Position).Length())
+		}
+	}
+	return closest
+}
+
+func TestScatter_Spacing(t *testing.T) {
+	options := scatter.NewDefaultOptions()
+	options.MinDistance, options.MaxDistance, options.CellSize = 0.5, 0.5, 2
+
+	instances, err := scatter.Scatter(42, [2]float64{-3, -3}, [2]float64{5, 5}, nil, nil, options)
+	if err != nil {
+		t.Fatalf("Expected no error, received %s", err.Error())
+	}
+
+	if closest := closestPair(instances); closest < options.MinDistance {
+		t.Errorf("Expected no instances closer than %v, received %v", options.MinDistance, closest)
+	}
+
+	// Bridson fills 64 units at 0.5 spacing with around 200 points, and small cells leave gaps along their borders
+	if len(instances) < 120 {
+		t.Errorf("Expected the area to be well covered, received %d instances", len(instances))
+	}
+
+	for _, instance := range instances {
+		if instance.Position[0] < -3 || instance.Position[0] >= 5 || instance.Position[1] < -3 || instance.Position[1] >= 5 {
+			t.Errorf("Expected every instance to be within the rectangle, received %v", instance.Position)
+		}
+		if instance.Scale < options.MinScale || instance.Scale > options.MaxScale {
+			t.Errorf("Expected every scale to be between %v and %v, received %v", options.MinScale, options.MaxScale, instance.Scale)
+		}
+		if instance.Rotation < 0 || instance.Rotation >= 2*math.Pi {
+			t.Errorf("Expected every rotation to be an angle, received %v", instance.Rotation)
+		}
+	}
+}
+
+func TestScatter_Tiles(t *testing.T) {
+	options := scatter.NewDefaultOptions()
+	options.CellSize = 3
+
+	whole, _ := scatter.Scatter(7, [2]float64{0, 0}, [2]float64{10, 5}, nil, nil, options)
+	left, _ := scatter.Scatter(7, [2]float64{0, 0}, [2]float64{5, 5}, nil, nil, options)
+	right, _ := scatter.Scatter(7, [2]float64{5, 0}, [2]float64{10, 5}, nil, nil, options)
+
+	if len(left)+len(right) != len(whole) {
+		t.Fatalf("Expected the tiles to have %d instances between them, received %d", len(whole), len(left)+len(right))
+	}
+
+	found := map[scatter.Instance]bool{}
+	for _, instance := range whole {
+		found[instance] = true
+	}
+	for _, instance := range append(left, right...) {
+		if !found[instance] {
+			t.Errorf("Expected every instance of the tiles to be part of the whole, received %v", instance)
+		}
+	}
+
+	different, _ := scatter.Scatter(8, [2]float64{0, 0}, [2]float64{10, 5}, nil, nil, options)
+	if len(different) > 0 && len(whole) > 0 && different[0] == whole[0] {
+		t.Errorf("Expected a different seed to place different instances")
+	}
+}
+
+func TestScatter_Density(t *testing.T) {
+	options := scatter.NewDefaultOptions()
+	options.MinDistance, options.MaxDistance, options.CellSize = 0.25, 1, 2
+
+	// Dense on the left, sparse on the right
+	density := func(t []float64) float64 {
+		if t[0] < 4 {
+			return 1
+		}
+		return 0
+	}
+
+	instances, _ := scatter.Scatter(3, [2]float64{0, 0}, [2]float64{8, 4}, density, nil, options)
+
+	var dense, sparse int
+	for _, instance := range instances {
+		if instance.Position[0] < 4 {
+			dense++
+		} else {
+			sparse++
+		}
+	}
+
+	if dense < 8*sparse {
+		t.Errorf("Expected the dense half to have many times the instances of the sparse half, received %d and %d", dense, sparse)
+	}
+}
+
+func TestScatter_Rules(t *testing.T) {
+	// A ramp that's flat below x = 2, and a 45 degree slope above it
+	height := func(t []float64) float64 {
+		return math.Max(0, t[0]-2)
+	}
+
+	testCases := map[string]struct {
+		Rules   []scatter.Rule
+		Allowed func(instance scatter.Instance) bool
+	}{
+		"flat": {
+			Rules:   []scatter.Rule{{Property: scatter.Slope, Min: 0, Max: 10}},
+			Allowed: func(instance scatter.Instance) bool { return instance.Position[0] < 2.1 },
+		},
+		"steep": {
+			Rules:   []scatter.Rule{{Property: scatter.Slope, Min: 40, Max: 50}},
+			Allowed: func(instance scatter.Instance) bool { return instance.Position[0] > 1.9 },
+		},
+		"height band": {
+			Rules:   []scatter.Rule{{Property: scatter.Height, Min: 1, Max: 2}},
+			Allowed: func(instance scatter.Instance) bool { return instance.Position[2] >= 1 && instance.Position[2] <= 2 },
+		},
+		"both": {
+			Rules: []scatter.Rule{{Property: scatter.Height, Min: 0, Max: 1}, {Property: scatter.Slope, Min: 40, Max: 90}},
+			Allowed: func(instance scatter.Instance) bool {
+				return instance.Position[0] > 1.9 && instance.Position[0] <= 3
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		options := scatter.NewDefaultOptions()
+		options.Rules = testCase.Rules
+
+		instances, err := scatter.Scatter(11, [2]float64{0, 0}, [2]float64{6, 4}, nil, height, options)
+		if err != nil {
+			t.Errorf("'%s' failed. Expected no error, received %s", name, err.Error())
+			continue
+		}
+		if len(instances) == 0 {
+			t.Errorf("'%s' failed. Expected some instances to be allowed", name)
+		}
+		for _, instance := range instances {
+			if !testCase.Allowed(instance) {
+				t.Errorf("'%s' failed. Expected no instance at %v", name, instance.Position)
+			}
+			if instance.Position[2] != height(instance.Position[:2]) {
+				t.Errorf("'%s' failed. Expected the instance to be on the terrain, received %v", name, instance.Position)
+			}
+		}
+	}
+}
+
+func TestCoveredArea(t *testing.T) {
+	testCases := map[string]struct {
+		From, To [2]float64
+		CellSize float64
+		Expected float64
+	}{
+		"one cell":   {From: [2]float64{0, 0}, To: [2]float64{1, 1}, CellSize: 8, Expected: 9 * 64},
+		"huge cell":  {From: [2]float64{0, 0}, To: [2]float64{1, 1}, CellSize: 2000, Expected: 9 * 2000 * 2000},
+		"four cells": {From: [2]float64{-1, -1}, To: [2]float64{1, 1}, CellSize: 2, Expected: 16 * 4},
+	}
+
+	for name, testCase := range testCases {
+		if area := scatter.CoveredArea(testCase.From, testCase.To, testCase.CellSize); area != testCase.Expected {
+			t.Errorf("'%s' failed. Expected %v, received %v", name, testCase.Expected, area)
+		}
+	}
+}
+
+func TestOptions_Validate(t *testing.T) {
+	testCases := map[string]struct {
+		Modify   func(options *scatter.Options)
+		Expected string
+	}{
+		"defaults":       {Modify: func(options *scatter.Options) {}, Expected: ""},
+		"no distance":    {Modify: func(options *scatter.Options) { options.MinDistance = 0 }, Expected: "MinDistance must be a positive number"},
+		"nan distance":   {Modify: func(options *scatter.Options) { options.MinDistance = math.NaN() }, Expected: "MinDistance must be a positive number"},
+		"max below min":  {Modify: func(options *scatter.Options) { options.MaxDistance = 0.1 }, Expected: "MaxDistance must be at least MinDistance"},
+		"far max":        {Modify: func(options *scatter.Options) { options.MaxDistance = 5 }, Expected: "MaxDistance must be at most 16 times MinDistance"},
+		"nan cells":      {Modify: func(options *scatter.Options) { options.CellSize = math.NaN() }, Expected: "CellSize must be at least MaxDistance"},
+		"small cells":    {Modify: func(options *scatter.Options) { options.CellSize = 0.5 }, Expected: "CellSize must be at least MaxDistance"},
+		"no attempts":    {Modify: func(options *scatter.Options) { options.Attempts = 0 }, Expected: "Attempts must be a positive integer"},
+		"negative scale": {Modify: func(options *scatter.Options) { options.MinScale = -1 }, Expected: "Scales must be positive, with MinScale no more than MaxScale"},
+		"inverted scale": {Modify: func(options *scatter.Options) { options.MaxScale = 0.5 }, Expected: "Scales must be positive, with MinScale no more than MaxScale"},
+		"inverted rule": {
+			Modify: func(options *scatter.Options) {
+				options.Rules = []scatter.Rule{{Property: scatter.Height, Min: 1, Max: 0}}
+			},
+			Expected: "Rules must have a min no more than their max",
+		},
+	}
+
+	for name, testCase := range testCases {
+		options := scatter.NewDefaultOptions()
+		testCase.Modify(&options)
+
+		err := options.Validate()
+		if (err == nil && testCase.Expected != "") || (err != nil && err.Error() != testCase.Expected) {
+			t.Errorf("'%s' failed. Expected %q, received %v", name, testCase.Expected, err)
+		}
+	}
+}
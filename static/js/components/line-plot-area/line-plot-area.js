@@ -36,12 +36,16 @@ class LinePlotArea extends React.Component {
         }
 
         // Find the largest absolute value for scaling. We scale by the furthest value from the center
+        // Normalized noise is scaled by its range instead, so every plot of it has the same axes
         var maxAbs = values[0];
         for (var i = 0; i < values.length; i++) {
             if (Math.abs(values[i]) > maxAbs) {
                 maxAbs = Math.abs(values[i]);
             }
         }
+        if (this.props.range) {
+            maxAbs = Math.max(Math.abs(this.props.range.min), Math.abs(this.props.range.max));
+        }
 
         // Calculate graph sizes and scaling factors
         var logicalWidth = this.props.values.length;
@@ -86,6 +90,7 @@ LinePlotArea.displayName = "MeshPlotArea";
 
 LinePlotArea.propTypes = {
     height: React.PropTypes.number,
+    range: React.PropTypes.shape({min: React.PropTypes.number, max: React.PropTypes.number}),
     resolution: React.PropTypes.number,
     values: React.PropTypes.arrayOf(React.PropTypes.number),
     width: React.PropTypes.number
@@ -54,12 +54,16 @@ class MeshPlotArea extends React.Component {
         this.renderer.setSize(this.canvas.clientWidth, this.canvas.clientHeight);
 
         // Find the largest absolute value for scaling. We scale by the furthest value from the "ground" plane.
+        // Normalized noise is scaled by its range instead, so every plot of it has the same axes
         var maxAbs = this.props.values[0];
         for (var i = 0; i < this.props.values.length; i++) {
             if (Math.abs(this.props.values[i]) > maxAbs) {
                 maxAbs = Math.abs(this.props.values[i]);
             }
         }
+        if (this.props.range) {
+            maxAbs = Math.max(Math.abs(this.props.range.min), Math.abs(this.props.range.max));
+        }
 
         // Calculate the mesh from the parameters
         var aspectRatio = this.props.numx / this.props.numy;
@@ -87,6 +91,7 @@ MeshPlotArea.displayName = "MeshPlotArea";
 
 MeshPlotArea.propTypes = {
     height: React.PropTypes.number,
+    range: React.PropTypes.shape({min: React.PropTypes.number, max: React.PropTypes.number}),
     numx: React.PropTypes.number,
     numy: React.PropTypes.number,
     values: React.PropTypes.arrayOf(React.PropTypes.number),
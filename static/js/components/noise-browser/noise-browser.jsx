@@ -14,12 +14,13 @@ class NoiseBrowser extends React.Component {
 
         this.state = {
             value: [],
+            range: null,
 
-            from: [0,0,0].slice(0,props.dimension).join(","),
-            to: [3,3,2].slice(0,props.dimension).join(","),
-            resolution: String([40, 20][props.dimension-1]),
+            from: props.initialFrom || [0,0,0].slice(0,props.dimension).join(","),
+            to: props.initialTo || [3,3,2].slice(0,props.dimension).join(","),
+            resolution: props.initialResolution || String([40, 20][props.dimension-1]),
             noiseFunction: props.initialNoiseFunction,
-            seed: "",
+            seed: props.initialSeed,
 
             errors: []
         };
@@ -60,12 +61,14 @@ class NoiseBrowser extends React.Component {
                 to: this.state.to,
                 resolution: this.state.resolution,
                 noiseFunction: this.state.noiseFunction,
-                seed: this.state.seed
+                seed: this.state.seed,
+                normalize: this.props.normalize
             }
         }).then(response => {
             if (response.values) {
                 this.setState({
                     value: response.values || [],
+                    range: response.range || null,
                     errors: []
                 });
             } else {
@@ -106,6 +109,7 @@ class NoiseBrowser extends React.Component {
                     height={600}
                     numx={(this.state.to.split(",")[0] - this.state.from.split(",")[0]) * this.state.resolution}
                     numy={(this.state.to.split(",")[1] - this.state.from.split(",")[1]) * this.state.resolution}
+                    range={this.state.range}
                     values={this.state.value}
                     width={window.innerWidth - 40}
                 />
@@ -115,6 +119,7 @@ class NoiseBrowser extends React.Component {
                 <LinePlotArea
                     height={300}
                     resolution={parseInt(this.state.resolution, 10)}
+                    range={this.state.range}
                     values={this.state.value}
                     width={window.innerWidth - 40}
                 />
@@ -149,7 +154,17 @@ NoiseBrowser.propTypes = {
     dimension: React.PropTypes.number.isRequired,
     displayName: React.PropTypes.string.isRequired,
     endpoint: React.PropTypes.string.isRequired,
-    initialNoiseFunction: React.PropTypes.string.isRequired
+    initialFrom: React.PropTypes.string,
+    initialNoiseFunction: React.PropTypes.string.isRequired,
+    initialResolution: React.PropTypes.string,
+    initialSeed: React.PropTypes.string,
+    initialTo: React.PropTypes.string,
+    normalize: React.PropTypes.string
+};
+
+NoiseBrowser.defaultProps = {
+    initialSeed: "",
+    normalize: "bounds"
 };
 
 module.exports = NoiseBrowser;
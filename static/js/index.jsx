@@ -1,6 +1,8 @@
 "use strict";
 var React = require("react");
 var ReactDom = require("react-dom");
+var Ajax = require("./ajax/ajax");
+var NoiseBrowser = require("./components/noise-browser/noise-browser.jsx");
 var NoiseBrowserList = require("./components/noise-browser-list/noise-browser-list.jsx");
 var Button = require("./components/control/button/button.jsx");
 var WebGL = require("./components/webgl/webgl");
@@ -12,7 +14,9 @@ class App extends React.Component {
         super(props);
 
         this.state = {
-            page: "Main"
+            page: "Main",
+            recipe: null,
+            errors: []
         };
 
         this.changePage = this.changePage.bind(this);
@@ -23,9 +27,30 @@ class App extends React.Component {
     componentDidMount() {
         window.addEventListener("popstate", this.onPageReload);
         window.onload = this.onPageRefresh;
+
+        // Shared recipes are loaded from their id in the url, eg: /?recipe=a1B2c3D4
+        let recipeId = new URLSearchParams(window.location.search).get("recipe");
+        if (recipeId) {
+            this.loadRecipe(recipeId);
+        }
+    }
+
+    loadRecipe(recipeId) {
+        Ajax.request({
+            url: "/recipes/" + encodeURIComponent(recipeId),
+            method: "GET",
+            queryParams: {}
+        }).then(recipe => {
+            this.setState({page: "Recipe", recipe: recipe, errors: []});
+        }).catch(e => {
+            this.setState({errors: [e.message]});
+        });
     }
 
     onPageRefresh() {
+        if (this.state.page === "Recipe") {
+            return;
+        }
         this.setState({page: history.state && history.state.page ? history.state.page : "Main"});
     }
 
@@ -161,6 +186,28 @@ class App extends React.Component {
         );
     }
 
+    renderRecipe() {
+        let recipe = this.state.recipe;
+        let query = new URLSearchParams(recipe.query);
+        let from = query.get("from") || "0,0";
+        let to = query.get("to") || "5,5";
+
+        // The recipe endpoint keeps the recipe's own params, and only lets the browser change its range and resolution
+        return (
+            <NoiseBrowser
+                dimension={from.split(",").length}
+                displayName={recipe.name + " (version " + recipe.version + ")"}
+                endpoint={"/recipes/" + encodeURIComponent(recipe.id) + "/noise"}
+                initialFrom={from}
+                initialNoiseFunction={query.get("noiseFunction") || "red"}
+                initialResolution={query.get("resolution") || "20"}
+                initialSeed={query.get("seed") || ""}
+                initialTo={to}
+                normalize={query.get("normalize") || "bounds"}
+            />
+        );
+    }
+
     renderWebGLDemo() {
         return <WebGL />;
     }
@@ -183,12 +230,16 @@ class App extends React.Component {
             case "WebGLDemo":
                 page = this.renderWebGLDemo();
                 break;
+            case "Recipe":
+                page = this.state.recipe ? this.renderRecipe() : null;
+                break;
             default:
                 page = null;
         }
 
         return (
             <div className="App">
+                {this.state.errors.map(e => <span className="-error" key={e}>{e}</span>)}
                 {page}
             </div>
         );
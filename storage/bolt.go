@@ -0,0 +1,179 @@
+package storage
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// recipesBucket holds a nested bucket for each recipe, which holds each of its versions keyed by the version number
+var recipesBucket = []byte("recipes")
+
+// A BoltStore keeps recipes in a bbolt database file, so they're kept between restarts of the service
+type BoltStore struct {
+	db *bolt.DB
+}
+
+// OpenBoltStore opens the database at the path, creating it if it doesn't exist
+func OpenBoltStore(path string) (*BoltStore, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(recipesBucket)
+		return err
+	}); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &BoltStore{db: db}, nil
+}
+
+// Create saves the first version of a new recipe, with a new id
+func (store *BoltStore) Create(name, query string) (recipe Recipe, err error) {
+	err = store.db.Update(func(tx *bolt.Tx) error {
+		recipes := tx.Bucket(recipesBucket)
+
+		id, err := newID()
+		for err == nil && recipes.Bucket([]byte(id)) != nil {
+			id, err = newID()
+		}
+		if err != nil {
+			return err
+		}
+
+		versions, err := recipes.CreateBucket([]byte(id))
+		if err != nil {
+			return err
+		}
+
+		recipe = Recipe{ID: id, Version: 1, Name: name, Query: query, Created: time.Now().UTC()}
+		return putVersion(versions, recipe)
+	})
+	return recipe, err
+}
+
+// Get returns the latest version of a recipe
+func (store *BoltStore) Get(id string) (recipe Recipe, err error) {
+	err = store.db.View(func(tx *bolt.Tx) error {
+		versions := tx.Bucket(recipesBucket).Bucket([]byte(id))
+		if versions == nil {
+			return ErrNotFound
+		}
+		_, value := versions.Cursor().Last()
+		return json.Unmarshal(value, &recipe)
+	})
+	return recipe, err
+}
+
+// GetVersion returns a version of a recipe, starting at 1
+func (store *BoltStore) GetVersion(id string, version int) (recipe Recipe, err error) {
+	err = store.db.View(func(tx *bolt.Tx) error {
+		versions := tx.Bucket(recipesBucket).Bucket([]byte(id))
+		if versions == nil || version < 1 {
+			return ErrNotFound
+		}
+		value := versions.Get(versionKey(version))
+		if value == nil {
+			return ErrNotFound
+		}
+		return json.Unmarshal(value, &recipe)
+	})
+	return recipe, err
+}
+
+// Versions returns every version of a recipe, oldest first
+func (store *BoltStore) Versions(id string) (recipes []Recipe, err error) {
+	err = store.db.View(func(tx *bolt.Tx) error {
+		versions := tx.Bucket(recipesBucket).Bucket([]byte(id))
+		if versions == nil {
+			return ErrNotFound
+		}
+		return versions.ForEach(func(_, value []byte) error {
+			recipe := Recipe{}
+			if err := json.Unmarshal(value, &recipe); err != nil {
+				return err
+			}
+			recipes = append(recipes, recipe)
+			return nil
+		})
+	})
+	return recipes, err
+}
+
+// List returns the latest version of every recipe, ordered by id
+func (store *BoltStore) List() (recipes []Recipe, err error) {
+	recipes = []Recipe{}
+	err = store.db.View(func(tx *bolt.Tx) error {
+		all := tx.Bucket(recipesBucket)
+		return all.ForEach(func(id, _ []byte) error {
+			_, value := all.Bucket(id).Cursor().Last()
+			recipe := Recipe{}
+			if err := json.Unmarshal(value, &recipe); err != nil {
+				return err
+			}
+			recipes = append(recipes, recipe)
+			return nil
+		})
+	})
+	return recipes, err
+}
+
+// Update saves a new version of a recipe
+func (store *BoltStore) Update(id, name, query string) (recipe Recipe, err error) {
+	err = store.db.Update(func(tx *bolt.Tx) error {
+		versions := tx.Bucket(recipesBucket).Bucket([]byte(id))
+		if versions == nil {
+			return ErrNotFound
+		}
+		last, _ := versions.Cursor().Last()
+
+		recipe = Recipe{ID: id, Version: int(binary.BigEndian.Uint32(last)) + 1, Name: name, Query: query, Created: time.Now().UTC()}
+		return putVersion(versions, recipe)
+	})
+	return recipe, err
+}
+
+// Delete removes a recipe and all of its versions
+func (store *BoltStore) Delete(id string) error {
+	return store.db.Update(func(tx *bolt.Tx) error {
+		if err := tx.Bucket(recipesBucket).DeleteBucket([]byte(id)); err != nil {
+			if err == bolt.ErrBucketNotFound {
+				return ErrNotFound
+			}
+			return err
+		}
+		return nil
+	})
+}
+
+// DB returns the store's database, so other stores can keep their data in the same file
+func (store *BoltStore) DB() *bolt.DB {
+	return store.db
+}
+
+// Close closes the database file
+func (store *BoltStore) Close() error {
+	return store.db.Close()
+}
+
+// putVersion saves a version of a recipe in the recipe's bucket
+func putVersion(versions *bolt.Bucket, recipe Recipe) error {
+	value, err := json.Marshal(recipe)
+	if err != nil {
+		return err
+	}
+	return versions.Put(versionKey(recipe.Version), value)
+}
+
+// versionKey encodes a version as big endian, so versions are ordered numerically in their bucket
+func versionKey(version int) []byte {
+	key := make([]byte, 4)
+	binary.BigEndian.PutUint32(key, uint32(version))
+	return key
+}
@@ -0,0 +1,117 @@
+package storage
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// A MemoryStore keeps recipes in memory, so they're lost when the service stops
+type MemoryStore struct {
+	mutex   sync.RWMutex
+	recipes map[string][]Recipe
+}
+
+// NewMemoryStore creates an empty store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{recipes: map[string][]Recipe{}}
+}
+
+// Create saves the first version of a new recipe, with a new id
+func (store *MemoryStore) Create(name, query string) (Recipe, error) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	id, err := newID()
+	for err == nil && store.recipes[id] != nil {
+		id, err = newID()
+	}
+	if err != nil {
+		return Recipe{}, err
+	}
+
+	recipe := Recipe{ID: id, Version: 1, Name: name, Query: query, Created: time.Now().UTC()}
+	store.recipes[id] = []Recipe{recipe}
+	return recipe, nil
+}
+
+// Get returns the latest version of a recipe
+func (store *MemoryStore) Get(id string) (Recipe, error) {
+	store.mutex.RLock()
+	defer store.mutex.RUnlock()
+
+	versions, ok := store.recipes[id]
+	if !ok {
+		return Recipe{}, ErrNotFound
+	}
+	return versions[len(versions)-1], nil
+}
+
+// GetVersion returns a version of a recipe, starting at 1
+func (store *MemoryStore) GetVersion(id string, version int) (Recipe, error) {
+	store.mutex.RLock()
+	defer store.mutex.RUnlock()
+
+	versions, ok := store.recipes[id]
+	if !ok || version < 1 || version > len(versions) {
+		return Recipe{}, ErrNotFound
+	}
+	return versions[version-1], nil
+}
+
+// Versions returns every version of a recipe, oldest first
+func (store *MemoryStore) Versions(id string) ([]Recipe, error) {
+	store.mutex.RLock()
+	defer store.mutex.RUnlock()
+
+	versions, ok := store.recipes[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return append([]Recipe{}, versions...), nil
+}
+
+// List returns the latest version of every recipe, ordered by id
+func (store *MemoryStore) List() ([]Recipe, error) {
+	store.mutex.RLock()
+	defer store.mutex.RUnlock()
+
+	recipes := make([]Recipe, 0, len(store.recipes))
+	for _, versions := range store.recipes {
+		recipes = append(recipes, versions[len(versions)-1])
+	}
+	sort.Slice(recipes, func(i, j int) bool { return recipes[i].ID < recipes[j].ID })
+	return recipes, nil
+}
+
+// Update saves a new version of a recipe
+func (store *MemoryStore) Update(id, name, query string) (Recipe, error) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	versions, ok := store.recipes[id]
+	if !ok {
+		return Recipe{}, ErrNotFound
+	}
+
+	recipe := Recipe{ID: id, Version: len(versions) + 1, Name: name, Query: query, Created: time.Now().UTC()}
+	store.recipes[id] = append(versions, recipe)
+	return recipe, nil
+}
+
+// Delete removes a recipe and all of its versions
+func (store *MemoryStore) Delete(id string) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	if _, ok := store.recipes[id]; !ok {
+		return ErrNotFound
+	}
+	delete(store.recipes, id)
+	return nil
+}
+
+// Close does nothing, since the store has no resources to release
+func (store *MemoryStore) Close() error {
+	return nil
+}
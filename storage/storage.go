@@ -0,0 +1,61 @@
+package storage
+
+import (
+	"crypto/rand"
+	"errors"
+	"math/big"
+	"time"
+)
+
+// ErrNotFound is returned when there is no recipe, or recipe version, with an id
+var ErrNotFound = errors.New("Recipe not found")
+
+// idLength is the number of characters in a recipe id, which gives around 47 bits of randomness
+const idLength = 8
+
+// idAlphabet are the characters of recipe ids, which are all safe to use in a url
+const idAlphabet = "0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
+
+// A Recipe is a named noise query, such as the preset, seed, bounds and post processing, that can be shared by its id
+// Each update saves a new version of the recipe, so earlier versions can still be loaded
+type Recipe struct {
+	ID      string    `json:"id"`
+	Version int       `json:"version"`
+	Name    string    `json:"name"`
+	Query   string    `json:"query"`
+	Created time.Time `json:"created"`
+}
+
+// A Store saves recipes and their versions. Implementations are safe to use concurrently
+type Store interface {
+	// Create saves the first version of a new recipe, with a new id
+	Create(name, query string) (Recipe, error)
+	// Get returns the latest version of a recipe
+	Get(id string) (Recipe, error)
+	// GetVersion returns a version of a recipe, starting at 1
+	GetVersion(id string, version int) (Recipe, error)
+	// Versions returns every version of a recipe, oldest first
+	Versions(id string) ([]Recipe, error)
+	// List returns the latest version of every recipe, ordered by id
+	List() ([]Recipe, error)
+	// Update saves a new version of a recipe
+	Update(id, name, query string) (Recipe, error)
+	// Delete removes a recipe and all of its versions
+	Delete(id string) error
+	// Close releases the store's resources
+	Close() error
+}
+
+// newID returns a random short id for a recipe
+func newID() (string, error) {
+	id := make([]byte, idLength)
+	max := big.NewInt(int64(len(idAlphabet)))
+	for i := range id {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		id[i] = idAlphabet[n.Int64()]
+	}
+	return string(id), nil
+}
@@ -0,0 +1,129 @@
+package storage_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/bcokert/terragen/storage"
+)
+
+func TestMemoryStore(t *testing.T) {
+	testStore(t, storage.NewMemoryStore())
+}
+
+func TestBoltStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "terragen")
+	if err != nil {
+		t.Fatalf("Failed to create a temporary directory: %s", err.Error())
+	}
+	defer os.RemoveAll(dir)
+
+	store, err := storage.OpenBoltStore(filepath.Join(dir, "recipes.db"))
+	if err != nil {
+		t.Fatalf("Failed to open the store: %s", err.Error())
+	}
+	testStore(t, store)
+}
+
+func TestBoltStore_Reopen(t *testing.T) {
+	dir, err := ioutil.TempDir("", "terragen")
+	if err != nil {
+		t.Fatalf("Failed to create a temporary directory: %s", err.Error())
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "recipes.db")
+
+	store, _ := storage.OpenBoltStore(path)
+	created, _ := store.Create("hills", "seed=1")
+	store.Update(created.ID, "hills", "seed=2")
+	store.Close()
+
+	store, err = storage.OpenBoltStore(path)
+	if err != nil {
+		t.Fatalf("Failed to reopen the store: %s", err.Error())
+	}
+	defer store.Close()
+
+	if recipe, err := store.Get(created.ID); err != nil || recipe.Version != 2 || recipe.Query != "seed=2" {
+		t.Errorf("Expected the second version to be kept, received %+v and %v", recipe, err)
+	}
+}
+
+// testStore checks the behaviour every store shares, and closes the store
+func testStore(t *testing.T, store storage.Store) {
+	defer store.Close()
+
+	hills, err := store.Create("hills", "seed=1&noiseFunction=red")
+	if err != nil {
+		t.Fatalf("Expected no error creating a recipe, received %s", err.Error())
+	}
+	if !regexp.MustCompile(`^[0-9a-zA-Z]{8}$`).MatchString(hills.ID) || hills.Version != 1 || hills.Created.IsZero() {
+		t.Errorf("Expected a first version with a short id, received %+v", hills)
+	}
+	valleys, _ := store.Create("valleys", "seed=2")
+	if valleys.ID == hills.ID {
+		t.Errorf("Expected recipes to have unique ids, received %s twice", hills.ID)
+	}
+
+	updated, err := store.Update(hills.ID, "rolling hills", "seed=3&noiseFunction=red")
+	if err != nil || updated.Version != 2 || updated.Name != "rolling hills" {
+		t.Errorf("Expected a second version, received %+v and %v", updated, err)
+	}
+
+	testCases := map[string]struct {
+		Recipe          func() (storage.Recipe, error)
+		ExpectedVersion int
+		ExpectedQuery   string
+		ExpectedError   error
+	}{
+		"latest":          {Recipe: func() (storage.Recipe, error) { return store.Get(hills.ID) }, ExpectedVersion: 2, ExpectedQuery: "seed=3&noiseFunction=red"},
+		"first version":   {Recipe: func() (storage.Recipe, error) { return store.GetVersion(hills.ID, 1) }, ExpectedVersion: 1, ExpectedQuery: "seed=1&noiseFunction=red"},
+		"other recipe":    {Recipe: func() (storage.Recipe, error) { return store.Get(valleys.ID) }, ExpectedVersion: 1, ExpectedQuery: "seed=2"},
+		"missing":         {Recipe: func() (storage.Recipe, error) { return store.Get("missing") }, ExpectedError: storage.ErrNotFound},
+		"missing version": {Recipe: func() (storage.Recipe, error) { return store.GetVersion(hills.ID, 3) }, ExpectedError: storage.ErrNotFound},
+		"version zero":    {Recipe: func() (storage.Recipe, error) { return store.GetVersion(hills.ID, 0) }, ExpectedError: storage.ErrNotFound},
+		"update missing":  {Recipe: func() (storage.Recipe, error) { return store.Update("missing", "", "") }, ExpectedError: storage.ErrNotFound},
+	}
+
+	for name, testCase := range testCases {
+		recipe, err := testCase.Recipe()
+		if err != testCase.ExpectedError {
+			t.Errorf("'%s' failed. Expected error %v, received %v", name, testCase.ExpectedError, err)
+			continue
+		}
+		if err == nil && (recipe.Version != testCase.ExpectedVersion || recipe.Query != testCase.ExpectedQuery) {
+			t.Errorf("'%s' failed. Expected version %d of %s, received %+v", name, testCase.ExpectedVersion, testCase.ExpectedQuery, recipe)
+		}
+	}
+
+	versions, err := store.Versions(hills.ID)
+	if err != nil || len(versions) != 2 || versions[0].Version != 1 || versions[1].Version != 2 {
+		t.Errorf("Expected both versions oldest first, received %+v and %v", versions, err)
+	}
+
+	recipes, err := store.List()
+	if err != nil || len(recipes) != 2 || recipes[0].ID > recipes[1].ID {
+		t.Errorf("Expected the latest version of both recipes ordered by id, received %+v and %v", recipes, err)
+	}
+	for _, recipe := range recipes {
+		if recipe.ID == hills.ID && recipe.Version != 2 {
+			t.Errorf("Expected the latest version of %s to be listed, received %+v", hills.ID, recipe)
+		}
+	}
+
+	if err := store.Delete(hills.ID); err != nil {
+		t.Errorf("Expected no error deleting a recipe, received %s", err.Error())
+	}
+	if _, err := store.Versions(hills.ID); err != storage.ErrNotFound {
+		t.Errorf("Expected every version to be deleted, received %v", err)
+	}
+	if err := store.Delete(hills.ID); err != storage.ErrNotFound {
+		t.Errorf("Expected deleting a missing recipe to not find it, received %v", err)
+	}
+	if recipes, _ := store.List(); len(recipes) != 1 {
+		t.Errorf("Expected 1 recipe to be left, received %+v", recipes)
+	}
+}
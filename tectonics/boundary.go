@@ -0,0 +1,65 @@
+package tectonics
+
+import (
+	"math"
+)
+
+// A BoundaryType describes how two plates move relative to each other where they meet
+type BoundaryType int
+
+const (
+	// NoBoundary is used for samples that aren't near any boundary
+	NoBoundary BoundaryType = iota
+	// Convergent plates move towards each other, building mountains or subducting into trenches
+	Convergent
+	// Divergent plates move apart, opening rifts and ridges
+	Divergent
+	// Transform plates slide past each other
+	Transform
+)
+
+var boundaryTypeNames = map[BoundaryType]string{
+	NoBoundary: "none",
+	Convergent: "convergent",
+	Divergent:  "divergent",
+	Transform:  "transform",
+}
+
+// MarshalText encodes the boundary type as its name, so that it reads clearly in JSON
+func (boundaryType BoundaryType) MarshalText() ([]byte, error) {
+	return []byte(boundaryTypeNames[boundaryType]), nil
+}
+
+// A Boundary is where two plates meet
+type Boundary struct {
+	Type        BoundaryType `json:"type"`
+	Convergence float64      `json:"convergence"` // How quickly the plates approach each other, which is negative when they separate
+	Shear       float64      `json:"shear"`       // How quickly the plates slide past each other
+}
+
+// Classify returns the boundary between two plates, from their relative velocity along and across the line between their centers
+// Boundaries are transform when the plates slide past each other faster than they converge or separate
+func Classify(a, b Plate) Boundary {
+	normal := [2]float64{b.Center[0] - a.Center[0], b.Center[1] - a.Center[1]}
+	length := math.Hypot(normal[0], normal[1])
+	if length == 0 {
+		return Boundary{Type: Transform}
+	}
+	normal[0], normal[1] = normal[0]/length, normal[1]/length
+
+	relative := [2]float64{a.Velocity[0] - b.Velocity[0], a.Velocity[1] - b.Velocity[1]}
+	boundary := Boundary{
+		Convergence: relative[0]*normal[0] + relative[1]*normal[1],
+		Shear:       math.Abs(relative[0]*normal[1] - relative[1]*normal[0]),
+	}
+
+	switch {
+	case math.Abs(boundary.Convergence) <= boundary.Shear:
+		boundary.Type = Transform
+	case boundary.Convergence > 0:
+		boundary.Type = Convergent
+	default:
+		boundary.Type = Divergent
+	}
+	return boundary
+}
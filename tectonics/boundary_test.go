@@ -0,0 +1,49 @@
+package tectonics_test
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/bcokert/terragen/tectonics"
+)
+
+func TestClassify(t *testing.T) {
+	testCases := map[string]struct {
+		VelocityA, VelocityB [2]float64
+		Expected             tectonics.Boundary
+	}{
+		"head on":          {VelocityA: [2]float64{1, 0}, VelocityB: [2]float64{-1, 0}, Expected: tectonics.Boundary{Type: tectonics.Convergent, Convergence: 2, Shear: 0}},
+		"catching up":      {VelocityA: [2]float64{1, 0}, VelocityB: [2]float64{0.5, 0}, Expected: tectonics.Boundary{Type: tectonics.Convergent, Convergence: 0.5, Shear: 0}},
+		"apart":            {VelocityA: [2]float64{-0.5, 0}, VelocityB: [2]float64{0.5, 0}, Expected: tectonics.Boundary{Type: tectonics.Divergent, Convergence: -1, Shear: 0}},
+		"sliding":          {VelocityA: [2]float64{0, 1}, VelocityB: [2]float64{0, -1}, Expected: tectonics.Boundary{Type: tectonics.Transform, Convergence: 0, Shear: 2}},
+		"mostly sliding":   {VelocityA: [2]float64{0.2, 1}, VelocityB: [2]float64{0, 0}, Expected: tectonics.Boundary{Type: tectonics.Transform, Convergence: 0.2, Shear: 1}},
+		"mostly colliding": {VelocityA: [2]float64{1, 0.2}, VelocityB: [2]float64{0, 0}, Expected: tectonics.Boundary{Type: tectonics.Convergent, Convergence: 1, Shear: 0.2}},
+		"still":            {VelocityA: [2]float64{0, 0}, VelocityB: [2]float64{0, 0}, Expected: tectonics.Boundary{Type: tectonics.Transform, Convergence: 0, Shear: 0}},
+	}
+
+	for name, testCase := range testCases {
+		a := tectonics.Plate{ID: 0, Center: [2]float64{0, 0}, Velocity: testCase.VelocityA}
+		b := tectonics.Plate{ID: 1, Center: [2]float64{2, 0}, Velocity: testCase.VelocityB}
+
+		result := tectonics.Classify(a, b)
+		if result.Type != testCase.Expected.Type || math.Abs(result.Convergence-testCase.Expected.Convergence) > 0.00000000001 || math.Abs(result.Shear-testCase.Expected.Shear) > 0.00000000001 {
+			t.Errorf("'%s' failed. Expected %+v, received %+v", name, testCase.Expected, result)
+		}
+
+		// The boundary is the same from either side
+		if reversed := tectonics.Classify(b, a); reversed != result {
+			t.Errorf("'%s' failed. Expected the reversed boundary to match %+v, received %+v", name, result, reversed)
+		}
+	}
+}
+
+func TestBoundaryType_MarshalText(t *testing.T) {
+	result, err := json.Marshal([]tectonics.BoundaryType{tectonics.NoBoundary, tectonics.Convergent, tectonics.Divergent, tectonics.Transform})
+	if err != nil {
+		t.Fatalf("Expected no error, received %s", err.Error())
+	}
+	if expected := `["none","convergent","divergent","transform"]`; string(result) != expected {
+		t.Errorf("Expected %s, received %s", expected, string(result))
+	}
+}
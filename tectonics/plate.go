@@ -0,0 +1,56 @@
+package tectonics
+
+import (
+	"errors"
+	"math"
+
+	tgmath "github.com/bcokert/terragen/math"
+)
+
+// A Plate is one cell of a Voronoi diagram over the world, moving with a constant velocity
+type Plate struct {
+	ID       int        `json:"id"`
+	Center   [2]float64 `json:"center"`
+	Velocity [2]float64 `json:"velocity"` // In world units per unit of time, with a speed of at most 1
+	Oceanic  bool       `json:"oceanic"`  // Oceanic plates sit low and subduct under continental plates
+}
+
+// NewPlates seeds count plates at random positions within the rectangle between from and to, each moving in a random direction
+// Each plate is oceanic with a probability of oceanicFraction, and continental otherwise
+func NewPlates(source tgmath.Source, from, to [2]float64, count int, oceanicFraction float64) ([]Plate, error) {
+	if count < 2 {
+		return nil, errors.New("Tectonics requires at least 2 plates")
+	}
+	if oceanicFraction < 0 || oceanicFraction > 1 {
+		return nil, errors.New("OceanicFraction must be between 0 and 1")
+	}
+
+	plates := make([]Plate, count)
+	for i := range plates {
+		angle := source.Float64() * 2 * math.Pi
+		speed := source.Float64()
+		plates[i] = Plate{
+			ID:       i,
+			Center:   [2]float64{from[0] + source.Float64()*(to[0]-from[0]), from[1] + source.Float64()*(to[1]-from[1])},
+			Velocity: [2]float64{speed * math.Cos(angle), speed * math.Sin(angle)},
+			Oceanic:  source.Float64() < oceanicFraction,
+		}
+	}
+
+	return plates, nil
+}
+
+// bisectorDistance returns how far a point in the cell of plate a is from the boundary with plate b
+// The boundary is the perpendicular bisector of the two centers, so the distance is exact and grows linearly into the cell
+func bisectorDistance(point [2]float64, a, b Plate) float64 {
+	separation := math.Hypot(b.Center[0]-a.Center[0], b.Center[1]-a.Center[1])
+	if separation == 0 {
+		return math.Inf(1)
+	}
+	return (squaredDistance(point, b.Center) - squaredDistance(point, a.Center)) / (2 * separation)
+}
+
+func squaredDistance(a, b [2]float64) float64 {
+	dx, dy := a[0]-b[0], a[1]-b[1]
+	return dx*dx + dy*dy
+}
@@ -0,0 +1,63 @@
+package tectonics_test
+
+import (
+	"math"
+	"testing"
+
+	tgmath "github.com/bcokert/terragen/math"
+	"github.com/bcokert/terragen/tectonics"
+)
+
+func TestNewPlates(t *testing.T) {
+	plates, err := tectonics.NewPlates(tgmath.NewDefaultSource(42), [2]float64{-2, 1}, [2]float64{4, 3}, 50, 0.5)
+	if err != nil {
+		t.Fatalf("Expected no error, received %s", err.Error())
+	}
+	if len(plates) != 50 {
+		t.Fatalf("Expected 50 plates, received %d", len(plates))
+	}
+
+	oceanic := 0
+	for i, plate := range plates {
+		if plate.ID != i {
+			t.Errorf("Expected plate %d to have id %d, received %d", i, i, plate.ID)
+		}
+		if plate.Center[0] < -2 || plate.Center[0] > 4 || plate.Center[1] < 1 || plate.Center[1] > 3 {
+			t.Errorf("Expected plate %d to be within the rectangle, received %v", i, plate.Center)
+		}
+		if speed := math.Hypot(plate.Velocity[0], plate.Velocity[1]); speed > 1 {
+			t.Errorf("Expected plate %d to have a speed of at most 1, received %v", i, speed)
+		}
+		if plate.Oceanic {
+			oceanic++
+		}
+	}
+	if oceanic == 0 || oceanic == 50 {
+		t.Errorf("Expected a mix of oceanic and continental plates, received %d oceanic", oceanic)
+	}
+
+	again, _ := tectonics.NewPlates(tgmath.NewDefaultSource(42), [2]float64{-2, 1}, [2]float64{4, 3}, 50, 0.5)
+	for i := range plates {
+		if plates[i] != again[i] {
+			t.Errorf("Expected the same seed to produce the same plates, received %v and %v", plates[i], again[i])
+		}
+	}
+}
+
+func TestNewPlates_Errors(t *testing.T) {
+	testCases := map[string]struct {
+		Count           int
+		OceanicFraction float64
+		Expected        string
+	}{
+		"one plate":        {Count: 1, OceanicFraction: 0.5, Expected: "Tectonics requires at least 2 plates"},
+		"negative oceanic": {Count: 4, OceanicFraction: -0.1, Expected: "OceanicFraction must be between 0 and 1"},
+		"too oceanic":      {Count: 4, OceanicFraction: 1.1, Expected: "OceanicFraction must be between 0 and 1"},
+	}
+
+	for name, testCase := range testCases {
+		if _, err := tectonics.NewPlates(tgmath.NewDefaultSource(1), [2]float64{0, 0}, [2]float64{1, 1}, testCase.Count, testCase.OceanicFraction); err == nil || err.Error() != testCase.Expected {
+			t.Errorf("'%s' failed. Expected error %s, received %v", name, testCase.Expected, err)
+		}
+	}
+}
@@ -0,0 +1,216 @@
+package tectonics
+
+import (
+	"errors"
+	"math"
+
+	tgmath "github.com/bcokert/terragen/math"
+	"github.com/bcokert/terragen/noise"
+)
+
+// reach is how many widths from a boundary its effects are computed, beyond which they're negligible
+const reach = 4
+
+// Options controls the heights that plates and their boundaries produce
+// Heights at boundaries are per unit of convergence or separation, so faster plates build taller mountains
+type Options struct {
+	Width           float64 // How far the effects of a boundary spread into each plate
+	ContinentHeight float64 // The base height of continental plates
+	OceanDepth      float64 // The base depth of oceanic plates
+	MountainHeight  float64 // The height of mountains and volcanic arcs at convergent boundaries
+	TrenchDepth     float64 // The depth of trenches where oceanic plates subduct
+	RiftDepth       float64 // The depth of rift valleys where continental plates separate
+	RidgeHeight     float64 // The height of ridges where oceanic plates separate
+}
+
+// NewDefaultOptions returns options for continents that stand out of the ocean, with mountains that dwarf their trenches and rifts
+func NewDefaultOptions() Options {
+	return Options{
+		Width:           0.5,
+		ContinentHeight: 0.2,
+		OceanDepth:      0.5,
+		MountainHeight:  0.8,
+		TrenchDepth:     0.4,
+		RiftDepth:       0.2,
+		RidgeHeight:     0.15,
+	}
+}
+
+// A Model computes the uplift of a set of plates anywhere in the world
+type Model struct {
+	Plates     []Plate
+	Options    Options
+	boundaries map[[2]int]Boundary
+}
+
+// NewModel classifies the boundary between every pair of plates, ready to compute uplift
+func NewModel(plates []Plate, options Options) (*Model, error) {
+	if len(plates) == 0 {
+		return nil, errors.New("Tectonics requires at least 1 plate")
+	}
+	if options.Width <= 0 {
+		return nil, errors.New("Width must be a positive number")
+	}
+
+	model := &Model{
+		Plates:     plates,
+		Options:    options,
+		boundaries: map[[2]int]Boundary{},
+	}
+	for a := range plates {
+		for b := a + 1; b < len(plates); b++ {
+			model.boundaries[[2]int{a, b}] = Classify(plates[a], plates[b])
+		}
+	}
+
+	return model, nil
+}
+
+// nearest returns the index of the plate whose cell contains the point
+func (model *Model) nearest(point [2]float64) int {
+	nearest, nearestDistance := 0, math.Inf(1)
+	for i, plate := range model.Plates {
+		if distance := squaredDistance(point, plate.Center); distance < nearestDistance {
+			nearest, nearestDistance = i, distance
+		}
+	}
+	return nearest
+}
+
+// boundary returns the boundary between two plates by their index
+func (model *Model) boundary(a, b int) Boundary {
+	if a > b {
+		a, b = b, a
+	}
+	return model.boundaries[[2]int{a, b}]
+}
+
+// Uplift returns the height the plates produce at a point
+// Each boundary near the point adds its own profile, and base heights are blended across boundaries, so the field is continuous
+// everywhere except very close to where three plates meet
+func (model *Model) Uplift(point [2]float64) float64 {
+	a := model.nearest(point)
+	plate := model.Plates[a]
+
+	height := model.baseHeight(plate)
+	for b, other := range model.Plates {
+		if b == a {
+			continue
+		}
+		distance := bisectorDistance(point, plate, other)
+		if distance > reach*model.Options.Width {
+			continue
+		}
+
+		// Both plates blend to their average base height at the boundary
+		blend := tgmath.DampCubicEase(math.Min(1, distance/model.Options.Width))
+		height += (model.baseHeight(other) - model.baseHeight(plate)) / 2 * (1 - blend)
+		height += model.profile(a, b, distance/model.Options.Width)
+	}
+
+	return height
+}
+
+// Function returns the uplift as a 2D noise function, so it can be sampled and combined like any other noise
+func (model *Model) Function() noise.Function {
+	return func(t []float64) float64 {
+		return model.Uplift([2]float64{t[0], t[1]})
+	}
+}
+
+func (model *Model) baseHeight(plate Plate) float64 {
+	if plate.Oceanic {
+		return -model.Options.OceanDepth
+	}
+	return model.Options.ContinentHeight
+}
+
+// profile returns the height the boundary between plates a and b adds to a point in a's cell, the given number of widths from it
+// Profiles are functions of the signed distance from the boundary, so both plates agree on the height right at it
+func (model *Model) profile(a, b int, widths float64) float64 {
+	options := model.Options
+	plate, other := model.Plates[a], model.Plates[b]
+	boundary := model.boundary(a, b)
+
+	switch boundary.Type {
+	case Convergent:
+		if !plate.Oceanic && !other.Oceanic {
+			return boundary.Convergence * options.MountainHeight * bump(widths)
+		}
+
+		// The oceanic plate subducts under the other, leaving a trench on its side and raising a volcanic arc a width inland
+		// When both are oceanic, the first plate overrides
+		overriding := (other.Oceanic && !plate.Oceanic) || (plate.Oceanic == other.Oceanic && a < b)
+		inland := -widths
+		if overriding {
+			inland = widths
+		}
+		return boundary.Convergence * (options.MountainHeight*bump(inland-1) - options.TrenchDepth*bump(2*inland+1))
+	case Divergent:
+		if plate.Oceanic && other.Oceanic {
+			return -boundary.Convergence * options.RidgeHeight * bump(widths)
+		}
+		return boundary.Convergence * options.RiftDepth * bump(widths)
+	}
+
+	return 0
+}
+
+// bump is a bell curve that is 1 at 0, and negligible beyond a few units either side
+func bump(x float64) float64 {
+	return math.Exp(-x * x)
+}
+
+// WithDetail adds detail noise to an uplift field, so that its broad structure gets the texture of the noise
+func WithDetail(uplift, detail noise.Function, amplitude float64) noise.Function {
+	return func(t []float64) float64 {
+		return uplift(t) + amplitude*detail(t)
+	}
+}
+
+// Layers are the tectonic properties of each sample of a 2D noise lattice
+// Boundaries are the type of the nearest boundary to each sample, or NoBoundary when it's more than a width away
+type Layers struct {
+	Plates     []int          `json:"plates"`
+	Boundaries []BoundaryType `json:"boundaries"`
+	Uplift     []float64      `json:"uplift"`
+}
+
+// Layers samples the plates, boundaries and uplift over the same lattice as the given noise
+func (model *Model) Layers(noise *noise.Noise) (*Layers, error) {
+	if len(noise.From) != 2 {
+		return nil, errors.New("Tectonics can only be sampled over 2 dimensional noise")
+	}
+
+	shape := noise.Shape()
+	layers := &Layers{
+		Plates:     make([]int, shape[0]*shape[1]),
+		Boundaries: make([]BoundaryType, shape[0]*shape[1]),
+		Uplift:     make([]float64, shape[0]*shape[1]),
+	}
+
+	spacing := 1 / float64(noise.Resolution)
+	for x := 0; x < shape[0]; x++ {
+		for y := 0; y < shape[1]; y++ {
+			i := x*shape[1] + y
+			point := [2]float64{float64(noise.From[0]) + float64(x)*spacing, float64(noise.From[1]) + float64(y)*spacing}
+
+			a := model.nearest(point)
+			layers.Plates[i] = model.Plates[a].ID
+			layers.Uplift[i] = model.Uplift(point)
+
+			closest := model.Options.Width
+			for b, other := range model.Plates {
+				if b == a {
+					continue
+				}
+				if distance := bisectorDistance(point, model.Plates[a], other); distance <= closest {
+					closest = distance
+					layers.Boundaries[i] = model.boundary(a, b).Type
+				}
+			}
+		}
+	}
+
+	return layers, nil
+}
@@ -0,0 +1,195 @@
+package tectonics_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/bcokert/terragen/noise"
+	"github.com/bcokert/terragen/tectonics"
+)
+
+// pair returns two plates either side of the line x = 1, with the given velocities and types
+func pair(velocityA, velocityB [2]float64, oceanicA, oceanicB bool) []tectonics.Plate {
+	return []tectonics.Plate{
+		{ID: 0, Center: [2]float64{0, 0}, Velocity: velocityA, Oceanic: oceanicA},
+		{ID: 1, Center: [2]float64{2, 0}, Velocity: velocityB, Oceanic: oceanicB},
+	}
+}
+
+func TestModel_Uplift(t *testing.T) {
+	options := tectonics.NewDefaultOptions()
+	continent, ocean := options.ContinentHeight, -options.OceanDepth
+
+	testCases := map[string]struct {
+		Plates   []tectonics.Plate
+		Expected map[float64]float64 // Expected uplift at each x, along y = 0
+	}{
+		"colliding continents": {
+			Plates: pair([2]float64{0.5, 0}, [2]float64{-0.5, 0}, false, false),
+			Expected: map[float64]float64{
+				-2:  continent,
+				0.5: continent + options.MountainHeight*math.Exp(-1),
+				1:   continent + options.MountainHeight,
+				1.5: continent + options.MountainHeight*math.Exp(-1),
+				4:   continent,
+			},
+		},
+		"continental rift": {
+			Plates: pair([2]float64{-0.5, 0}, [2]float64{0.5, 0}, false, false),
+			Expected: map[float64]float64{
+				-2: continent,
+				1:  continent - options.RiftDepth,
+				4:  continent,
+			},
+		},
+		"mid ocean ridge": {
+			Plates: pair([2]float64{-0.25, 0}, [2]float64{0.25, 0}, true, true),
+			Expected: map[float64]float64{
+				-2: ocean,
+				1:  ocean + 0.5*options.RidgeHeight,
+				4:  ocean,
+			},
+		},
+		"transform": {
+			Plates: pair([2]float64{0, 1}, [2]float64{0, -1}, false, false),
+			Expected: map[float64]float64{
+				-2: continent,
+				1:  continent,
+				4:  continent,
+			},
+		},
+		"coast": {
+			Plates: pair([2]float64{0, 0}, [2]float64{0, 0}, true, false),
+			Expected: map[float64]float64{
+				-2:   ocean,
+				0.5:  ocean,
+				0.75: ocean + (continent-ocean)/2*0.5,
+				1:    (continent + ocean) / 2,
+				4:    continent,
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		model, err := tectonics.NewModel(testCase.Plates, options)
+		if err != nil {
+			t.Errorf("'%s' failed. Expected no error, received %s", name, err.Error())
+			continue
+		}
+
+		for x, expected := range testCase.Expected {
+			if result := model.Uplift([2]float64{x, 0}); math.Abs(result-expected) > 0.0000001 {
+				t.Errorf("'%s' failed. Expected %v at x = %v, received %v", name, expected, x, result)
+			}
+		}
+	}
+}
+
+func TestModel_UpliftSubduction(t *testing.T) {
+	// The ocean on the left dives under the continent on the right
+	model, _ := tectonics.NewModel(pair([2]float64{0.5, 0}, [2]float64{-0.5, 0}, true, false), tectonics.NewDefaultOptions())
+	at := func(x float64) float64 {
+		return model.Uplift([2]float64{x, 0})
+	}
+
+	trench, arc := math.Inf(1), math.Inf(-1)
+	var trenchX, arcX float64
+	for x := -1.0; x <= 3; x += 0.01 {
+		if height := at(x); height < trench {
+			trench, trenchX = height, x
+		}
+		if height := at(x); height > arc {
+			arc, arcX = height, x
+		}
+	}
+
+	if trenchX >= 1 || trench >= -tectonics.NewDefaultOptions().OceanDepth {
+		t.Errorf("Expected a trench deeper than the ocean on the oceanic side, received %v at %v", trench, trenchX)
+	}
+	if arcX <= 1 || arc <= tectonics.NewDefaultOptions().ContinentHeight {
+		t.Errorf("Expected an arc higher than the continent on the continental side, received %v at %v", arc, arcX)
+	}
+}
+
+func TestModel_UpliftContinuous(t *testing.T) {
+	plates := []tectonics.Plate{
+		{ID: 0, Center: [2]float64{0, 0}, Velocity: [2]float64{0.5, 0.2}, Oceanic: true},
+		{ID: 1, Center: [2]float64{3, 0.5}, Velocity: [2]float64{-0.4, 0.1}, Oceanic: false},
+		{ID: 2, Center: [2]float64{0.5, 4}, Velocity: [2]float64{0, -0.6}, Oceanic: false},
+	}
+	model, _ := tectonics.NewModel(plates, tectonics.NewDefaultOptions())
+
+	// Walk across the boundary between the first two plates, well away from where all three meet
+	step := 0.001
+	for x := 0.5; x < 2.5; x += step {
+		if jump := math.Abs(model.Uplift([2]float64{x + step, -1}) - model.Uplift([2]float64{x, -1})); jump > 0.01 {
+			t.Errorf("Expected the uplift to be continuous, but it jumped %v at x = %v", jump, x)
+		}
+	}
+}
+
+func TestNewModel_Errors(t *testing.T) {
+	testCases := map[string]struct {
+		Plates   []tectonics.Plate
+		Width    float64
+		Expected string
+	}{
+		"no plates": {Plates: nil, Width: 1, Expected: "Tectonics requires at least 1 plate"},
+		"no width":  {Plates: pair([2]float64{}, [2]float64{}, false, false), Width: 0, Expected: "Width must be a positive number"},
+	}
+
+	for name, testCase := range testCases {
+		options := tectonics.NewDefaultOptions()
+		options.Width = testCase.Width
+		if _, err := tectonics.NewModel(testCase.Plates, options); err == nil || err.Error() != testCase.Expected {
+			t.Errorf("'%s' failed. Expected error %s, received %v", name, testCase.Expected, err)
+		}
+	}
+}
+
+func TestModel_Layers(t *testing.T) {
+	model, _ := tectonics.NewModel(pair([2]float64{0.5, 0}, [2]float64{-0.5, 0}, false, false), tectonics.NewDefaultOptions())
+
+	// Samples at x = 0, 0.5, 1 and 1.5, where the boundary at x = 1 belongs to the first plate
+	lattice := &noise.Noise{From: []int{0, 0}, To: []int{2, 1}, Resolution: 2}
+	layers, err := model.Layers(lattice)
+	if err != nil {
+		t.Fatalf("Expected no error, received %s", err.Error())
+	}
+
+	expectedPlates := []int{0, 0, 0, 0, 0, 0, 1, 1}
+	expectedBoundaries := []tectonics.BoundaryType{
+		tectonics.NoBoundary, tectonics.NoBoundary,
+		tectonics.Convergent, tectonics.Convergent,
+		tectonics.Convergent, tectonics.Convergent,
+		tectonics.Convergent, tectonics.Convergent,
+	}
+	for i := range expectedPlates {
+		if layers.Plates[i] != expectedPlates[i] {
+			t.Errorf("Expected plates %v, received %v", expectedPlates, layers.Plates)
+			break
+		}
+	}
+	for i := range expectedBoundaries {
+		if layers.Boundaries[i] != expectedBoundaries[i] {
+			t.Errorf("Expected boundaries %v, received %v", expectedBoundaries, layers.Boundaries)
+			break
+		}
+	}
+	if expected := model.Uplift([2]float64{1, 0.5}); layers.Uplift[5] != expected {
+		t.Errorf("Expected the uplift at 1, 0.5 to be %v, received %v", expected, layers.Uplift[5])
+	}
+
+	if _, err := model.Layers(&noise.Noise{From: []int{0}, To: []int{1}, Resolution: 1}); err == nil || err.Error() != "Tectonics can only be sampled over 2 dimensional noise" {
+		t.Errorf("Expected a dimension error, received %v", err)
+	}
+}
+
+func TestWithDetail(t *testing.T) {
+	uplift := func(t []float64) float64 { return t[0] }
+	detail := func(t []float64) float64 { return t[1] }
+
+	if result := tectonics.WithDetail(uplift, detail, 0.5)([]float64{2, 3}); result != 3.5 {
+		t.Errorf("Expected 3.5, received %v", result)
+	}
+}
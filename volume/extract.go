@@ -0,0 +1,182 @@
+package volume
+
+import (
+	"errors"
+
+	tgmath "github.com/bcokert/terragen/math"
+	"github.com/bcokert/terragen/mesh"
+)
+
+// Options controls how a surface is extracted from a Grid
+type Options struct {
+	IsoLevel float64 // The density of the surface. Samples with a greater density are solid
+	Closed   bool    // Closed treats everything outside the grid as empty, which caps solids where the grid cuts through them
+}
+
+// NewDefaultOptions returns options that extract the zero density surface, closed at the grid's boundary
+func NewDefaultOptions() Options {
+	return Options{
+		IsoLevel: 0,
+		Closed:   true,
+	}
+}
+
+// cubeCorners are the offsets of the corners of a cube, where bit i of a corner's index is its offset in dimension i
+var cubeCorners = [8][3]int{
+	{0, 0, 0}, {1, 0, 0}, {0, 1, 0}, {1, 1, 0},
+	{0, 0, 1}, {1, 0, 1}, {0, 1, 1}, {1, 1, 1},
+}
+
+// cubeTetrahedra splits a cube into six tetrahedra that share its main diagonal, from corner 0 to corner 7
+// Every cube is split the same way, so neighbouring cubes split their shared face along the same diagonal
+var cubeTetrahedra = [6][4]int{
+	{0, 1, 3, 7}, {0, 3, 2, 7}, {0, 2, 6, 7},
+	{0, 6, 4, 7}, {0, 4, 5, 7}, {0, 5, 1, 7},
+}
+
+// Extract builds a mesh of the surface where the grid's density crosses the iso level, using marching cubes
+// Each cube is split into tetrahedra, which have no ambiguous cases, so the surface never has cracks between cubes
+// Surfaces are wound counter-clockwise when viewed from the empty side, and vertices on shared edges are shared between triangles
+func Extract(grid *Grid, options Options) (*mesh.Mesh, error) {
+	for _, size := range grid.Shape {
+		if size < 2 {
+			return nil, errors.New("Volumes require at least 2 samples in each dimension")
+		}
+	}
+
+	surface := &surface{
+		grid:     grid,
+		options:  options,
+		mesh:     &mesh.Mesh{},
+		vertices: map[[2]int]int{},
+	}
+
+	// Closed surfaces also march the cubes that straddle the boundary, whose outer corners are empty
+	first, last := 0, [3]int{grid.Shape[0] - 1, grid.Shape[1] - 1, grid.Shape[2] - 1}
+	if options.Closed {
+		first, last = -1, grid.Shape
+	}
+
+	var corners [8][3]int
+	for x := first; x < last[0]; x++ {
+		for y := first; y < last[1]; y++ {
+			for z := first; z < last[2]; z++ {
+				for i, offset := range cubeCorners {
+					corners[i] = [3]int{x + offset[0], y + offset[1], z + offset[2]}
+				}
+				for _, tetrahedron := range cubeTetrahedra {
+					surface.polygonize([4][3]int{corners[tetrahedron[0]], corners[tetrahedron[1]], corners[tetrahedron[2]], corners[tetrahedron[3]]})
+				}
+			}
+		}
+	}
+
+	surface.mesh.ComputeNormals()
+	return surface.mesh, nil
+}
+
+// surface accumulates the mesh of an isosurface as tetrahedra are polygonized
+type surface struct {
+	grid     *Grid
+	options  Options
+	mesh     *mesh.Mesh
+	vertices map[[2]int]int
+}
+
+// isSolid returns whether a sample is inside the surface. Samples outside the grid are always empty
+func (surface *surface) isSolid(sample [3]int) bool {
+	return surface.grid.inBounds(sample) && surface.grid.value(sample) > surface.options.IsoLevel
+}
+
+// polygonize adds the piece of the surface that passes through a tetrahedron, which is a triangle or a quad
+func (surface *surface) polygonize(tetrahedron [4][3]int) {
+	var solid, empty [][3]int
+	for _, corner := range tetrahedron {
+		if surface.isSolid(corner) {
+			solid = append(solid, corner)
+		} else {
+			empty = append(empty, corner)
+		}
+	}
+	if len(solid) == 0 || len(empty) == 0 {
+		return
+	}
+
+	// The surface is planar within a tetrahedron, and faces away from the solid corners
+	outward := surface.centroid(empty).Sub(surface.centroid(solid))
+
+	switch len(solid) {
+	case 1:
+		surface.addTriangle([3]edge{{solid[0], empty[0]}, {solid[0], empty[1]}, {solid[0], empty[2]}}, outward)
+	case 3:
+		surface.addTriangle([3]edge{{solid[0], empty[0]}, {solid[1], empty[0]}, {solid[2], empty[0]}}, outward)
+	case 2:
+		// The four crossed edges form a loop, where consecutive edges share a corner
+		a, b, c, d := edge{solid[0], empty[0]}, edge{solid[0], empty[1]}, edge{solid[1], empty[1]}, edge{solid[1], empty[0]}
+		surface.addTriangle([3]edge{a, b, c}, outward)
+		surface.addTriangle([3]edge{a, c, d}, outward)
+	}
+}
+
+// An edge runs from a solid sample to an empty one
+type edge [2][3]int
+
+// centroid returns the average world position of the samples
+func (surface *surface) centroid(samples [][3]int) (centroid tgmath.Vec3) {
+	for _, sample := range samples {
+		centroid = centroid.Add(surface.grid.position(sample))
+	}
+	return centroid.Scale(1 / float64(len(samples)))
+}
+
+// vertex returns the index of the vertex where the surface crosses the edge between a solid and an empty sample, adding it if needed
+// Edges to samples outside the grid are crossed right at the solid sample, which puts the caps of closed surfaces on the grid's boundary
+func (surface *surface) vertex(solid, empty [3]int) int {
+	grid := surface.grid
+
+	key := [2]int{grid.key(solid), grid.key(solid)}
+	position := grid.position(solid)
+	if grid.inBounds(empty) {
+		key[1] = grid.key(empty)
+		if key[1] < key[0] {
+			key[0], key[1] = key[1], key[0]
+		}
+
+		solidValue, emptyValue := grid.value(solid), grid.value(empty)
+		t := (solidValue - surface.options.IsoLevel) / (solidValue - emptyValue)
+		position = position.Add(grid.position(empty).Sub(position).Scale(t))
+	}
+
+	if index, ok := surface.vertices[key]; ok {
+		return index
+	}
+
+	// Textures are projected from above, across the whole grid
+	size := grid.position([3]int{grid.Shape[0] - 1, grid.Shape[1] - 1, 0}).Sub(grid.Origin)
+	uv := tgmath.Vec2{(position[0] - grid.Origin[0]) / size[0], (position[1] - grid.Origin[1]) / size[1]}
+
+	index := surface.mesh.AddVertex(position, uv)
+	surface.vertices[key] = index
+	return index
+}
+
+// addTriangle adds a triangle between where the surface crosses three edges, wound to face the outward direction
+// The winding is decided from the edges' midpoints rather than the vertices, since vertices can coincide when samples are exactly at the iso level
+// Triangles that collapsed onto a shared vertex are skipped
+func (surface *surface) addTriangle(edges [3]edge, outward tgmath.Vec3) {
+	var indices [3]int
+	var midpoints [3]tgmath.Vec3
+	for i, crossed := range edges {
+		indices[i] = surface.vertex(crossed[0], crossed[1])
+		midpoints[i] = surface.centroid(crossed[:])
+	}
+
+	a, b, c := indices[0], indices[1], indices[2]
+	if a == b || b == c || a == c {
+		return
+	}
+	if midpoints[1].Sub(midpoints[0]).Cross(midpoints[2].Sub(midpoints[0])).Dot(outward) < 0 {
+		b, c = c, b
+	}
+	surface.mesh.AddTriangle(a, b, c)
+}
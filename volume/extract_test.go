@@ -0,0 +1,153 @@
+package volume_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/bcokert/terragen/mesh"
+	"github.com/bcokert/terragen/volume"
+)
+
+// sphere is a density that is solid within radius of the center
+func sphere(center [3]float64, radius float64) func(t []float64) float64 {
+	return func(t []float64) float64 {
+		x, y, z := t[0]-center[0], t[1]-center[1], t[2]-center[2]
+		return radius - math.Sqrt(x*x+y*y+z*z)
+	}
+}
+
+// isClosed returns whether every edge of the mesh is used once in each direction, meaning it's watertight and consistently wound
+func isClosed(surface *mesh.Mesh) bool {
+	edges := map[[2]int]int{}
+	for i := 0; i+2 < len(surface.Indices); i += 3 {
+		for j := 0; j < 3; j++ {
+			edges[[2]int{surface.Indices[i+j], surface.Indices[i+(j+1)%3]}]++
+		}
+	}
+	for edge, count := range edges {
+		if count != 1 || edges[[2]int{edge[1], edge[0]}] != 1 {
+			return false
+		}
+	}
+	return true
+}
+
+// signedVolume returns the volume enclosed by a closed mesh, which is positive when its triangles face outward
+func signedVolume(surface *mesh.Mesh) (volume float64) {
+	for i := 0; i+2 < len(surface.Indices); i += 3 {
+		a, b, c := surface.Positions[surface.Indices[i]], surface.Positions[surface.Indices[i+1]], surface.Positions[surface.Indices[i+2]]
+		volume += a.Dot(b.Cross(c)) / 6
+	}
+	return volume
+}
+
+func TestExtract(t *testing.T) {
+	testCases := map[string]struct {
+		Density        func(t []float64) float64
+		From, To       [3]int
+		Resolution     int
+		Options        volume.Options
+		ExpectedVolume float64
+		Tolerance      float64
+	}{
+		"sphere": {
+			Density: sphere([3]float64{2, 2, 2}, 1.5),
+			From:    [3]int{0, 0, 0}, To: [3]int{4, 4, 4}, Resolution: 6,
+			Options:        volume.NewDefaultOptions(),
+			ExpectedVolume: 4.0 / 3.0 * math.Pi * 1.5 * 1.5 * 1.5,
+			Tolerance:      0.3,
+		},
+		"sphere cut by the grid": {
+			Density: sphere([3]float64{2, 2, 0}, 1.5),
+			From:    [3]int{0, 0, 0}, To: [3]int{4, 4, 2}, Resolution: 6,
+			Options:        volume.NewDefaultOptions(),
+			ExpectedVolume: 2.0 / 3.0 * math.Pi * 1.5 * 1.5 * 1.5,
+			Tolerance:      0.2,
+		},
+		"ground": {
+			Density: volume.HeightDensity(func(t []float64) float64 { return 0 }, 1.25, 1),
+			From:    [3]int{0, 0, 0}, To: [3]int{2, 3, 2}, Resolution: 2,
+			Options:        volume.NewDefaultOptions(),
+			ExpectedVolume: 2 * 3 * 1.25,
+			Tolerance:      0.00000000001,
+		},
+		"shifted iso level": {
+			Density: volume.HeightDensity(func(t []float64) float64 { return 0 }, 1.25, 1),
+			From:    [3]int{0, 0, 0}, To: [3]int{2, 3, 2}, Resolution: 2,
+			Options:        volume.Options{IsoLevel: -0.5, Closed: true},
+			ExpectedVolume: 2 * 3 * 1.75,
+			Tolerance:      0.00000000001,
+		},
+	}
+
+	for name, testCase := range testCases {
+		grid, err := volume.NewGrid(testCase.Density, testCase.From, testCase.To, testCase.Resolution)
+		if err != nil {
+			t.Errorf("'%s' failed. Expected no error, received %s", name, err.Error())
+			continue
+		}
+		surface, err := volume.Extract(grid, testCase.Options)
+		if err != nil {
+			t.Errorf("'%s' failed. Expected no error, received %s", name, err.Error())
+			continue
+		}
+
+		if !isClosed(surface) {
+			t.Errorf("'%s' failed. Expected a closed mesh", name)
+		}
+		if result := signedVolume(surface); math.Abs(result-testCase.ExpectedVolume) > testCase.Tolerance {
+			t.Errorf("'%s' failed. Expected a volume of %v, received %v", name, testCase.ExpectedVolume, result)
+		}
+	}
+}
+
+func TestExtract_Open(t *testing.T) {
+	grid, _ := volume.NewGrid(volume.HeightDensity(func(t []float64) float64 { return 0 }, 1.25, 1), [3]int{0, 0, 0}, [3]int{2, 2, 2}, 2)
+	surface, err := volume.Extract(grid, volume.Options{IsoLevel: 0, Closed: false})
+	if err != nil {
+		t.Fatalf("Expected no error, received %s", err.Error())
+	}
+
+	if surface.NumTriangles() == 0 {
+		t.Fatalf("Expected the ground to have a surface")
+	}
+	if isClosed(surface) {
+		t.Errorf("Expected an open surface, with no caps")
+	}
+	for i, position := range surface.Positions {
+		if math.Abs(position[2]-1.25) > 0.00000000001 {
+			t.Errorf("Expected every vertex to be at the ground level, received %v", position)
+		}
+		if surface.Normals[i][2] < 0.99999 {
+			t.Errorf("Expected every normal to face up, received %v", surface.Normals[i])
+		}
+	}
+}
+
+func TestExtract_Empty(t *testing.T) {
+	testCases := map[string]struct {
+		Density func(t []float64) float64
+	}{
+		"all empty":      {Density: func(t []float64) float64 { return -1 }},
+		"all solid open": {Density: func(t []float64) float64 { return 1 }},
+	}
+
+	for name, testCase := range testCases {
+		grid, _ := volume.NewGrid(testCase.Density, [3]int{0, 0, 0}, [3]int{1, 1, 1}, 2)
+		surface, err := volume.Extract(grid, volume.Options{Closed: false})
+		if err != nil {
+			t.Errorf("'%s' failed. Expected no error, received %s", name, err.Error())
+			continue
+		}
+		if surface.NumTriangles() != 0 {
+			t.Errorf("'%s' failed. Expected no triangles, received %d", name, surface.NumTriangles())
+		}
+	}
+}
+
+func TestExtract_TooSmall(t *testing.T) {
+	grid := &volume.Grid{Values: []float64{1, 1}, Shape: [3]int{1, 1, 2}, Spacing: 1}
+	if _, err := volume.Extract(grid, volume.NewDefaultOptions()); err == nil || err.Error() != "Volumes require at least 2 samples in each dimension" {
+		t.Errorf("Expected a too small error, received %v", err)
+	}
+}
@@ -0,0 +1,86 @@
+package volume
+
+import (
+	"errors"
+
+	tgmath "github.com/bcokert/terragen/math"
+	"github.com/bcokert/terragen/noise"
+)
+
+// HeightDensity builds a 3D density function from 3D noise, which is solid (positive) below groundLevel and empty (negative) above it
+// The noise is added to the height gradient, so it can carve caves and overhangs that a heightfield can't represent
+// Gradient is how quickly density falls with height, so smaller gradients give the noise more room to shape the terrain
+func HeightDensity(fn noise.Function, groundLevel, gradient float64) noise.Function {
+	return func(t []float64) float64 {
+		return fn(t) - (t[2]-groundLevel)*gradient
+	}
+}
+
+// A Grid is a 3D density function sampled at the corners of a voxel grid
+// Values are stored with x outermost, so the sample (x, y, z) is Values[(x*Shape[1]+y)*Shape[2]+z]
+type Grid struct {
+	Values  []float64
+	Shape   [3]int
+	Origin  tgmath.Vec3
+	Spacing float64
+}
+
+// NewGrid samples the density function over the box between from and to, with resolution voxels per unit
+// Samples are taken on both sides of the box, so grids that share a face have the same samples on it
+func NewGrid(fn noise.Function, from, to [3]int, resolution int) (*Grid, error) {
+	if resolution < 1 {
+		return nil, errors.New("Volumes require a positive resolution")
+	}
+	for i := range from {
+		if to[i] <= from[i] {
+			return nil, errors.New("Volumes require To to be greater than From in each dimension")
+		}
+	}
+
+	grid := &Grid{
+		Origin:  tgmath.Vec3{float64(from[0]), float64(from[1]), float64(from[2])},
+		Spacing: 1 / float64(resolution),
+	}
+	for i := range grid.Shape {
+		grid.Shape[i] = (to[i]-from[i])*resolution + 1
+	}
+
+	grid.Values = make([]float64, 0, grid.Shape[0]*grid.Shape[1]*grid.Shape[2])
+	point := make([]float64, 3)
+	for x := 0; x < grid.Shape[0]; x++ {
+		for y := 0; y < grid.Shape[1]; y++ {
+			for z := 0; z < grid.Shape[2]; z++ {
+				position := grid.position([3]int{x, y, z})
+				copy(point, position[:])
+				grid.Values = append(grid.Values, fn(point))
+			}
+		}
+	}
+
+	return grid, nil
+}
+
+// inBounds returns whether the sample is part of the grid
+func (grid *Grid) inBounds(sample [3]int) bool {
+	for i := range sample {
+		if sample[i] < 0 || sample[i] >= grid.Shape[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// value returns the density at a sample, which must be in bounds
+func (grid *Grid) value(sample [3]int) float64 {
+	return grid.Values[(sample[0]*grid.Shape[1]+sample[1])*grid.Shape[2]+sample[2]]
+}
+
+// position returns the world position of a sample, which may be outside the grid
+func (grid *Grid) position(sample [3]int) tgmath.Vec3 {
+	return grid.Origin.Add(tgmath.Vec3{float64(sample[0]), float64(sample[1]), float64(sample[2])}.Scale(grid.Spacing))
+}
+
+// key returns a unique id for a sample, including samples one step outside the grid
+func (grid *Grid) key(sample [3]int) int {
+	return ((sample[0]+1)*(grid.Shape[1]+2)+sample[1]+1)*(grid.Shape[2]+2) + sample[2] + 1
+}
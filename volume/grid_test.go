@@ -0,0 +1,82 @@
+package volume_test
+
+import (
+	"testing"
+
+	tgmath "github.com/bcokert/terragen/math"
+	"github.com/bcokert/terragen/volume"
+)
+
+func TestHeightDensity(t *testing.T) {
+	density := volume.HeightDensity(func(t []float64) float64 {
+		return t[0]
+	}, 2, 0.5)
+
+	testCases := map[string]struct {
+		Point    []float64
+		Expected float64
+	}{
+		"at ground": {Point: []float64{0, 0, 2}, Expected: 0},
+		"below":     {Point: []float64{0, 0, 0}, Expected: 1},
+		"above":     {Point: []float64{0, 0, 4}, Expected: -1},
+		"noise":     {Point: []float64{3, 0, 4}, Expected: 2},
+	}
+
+	for name, testCase := range testCases {
+		if result := density(testCase.Point); result != testCase.Expected {
+			t.Errorf("'%s' failed. Expected %v, received %v", name, testCase.Expected, result)
+		}
+	}
+}
+
+func TestNewGrid(t *testing.T) {
+	grid, err := volume.NewGrid(func(t []float64) float64 {
+		return t[0] + 10*t[1] + 100*t[2]
+	}, [3]int{1, 0, -1}, [3]int{2, 1, 0}, 2)
+	if err != nil {
+		t.Fatalf("Expected no error, received %s", err.Error())
+	}
+
+	if grid.Shape != [3]int{3, 3, 3} {
+		t.Errorf("Expected a shape of [3 3 3], received %v", grid.Shape)
+	}
+	if !grid.Origin.IsEqual(tgmath.Vec3{1, 0, -1}) || grid.Spacing != 0.5 {
+		t.Errorf("Expected an origin of [1 0 -1] and spacing of 0.5, received %v and %v", grid.Origin, grid.Spacing)
+	}
+
+	testCases := map[string]struct {
+		Sample   [3]int
+		Expected float64
+	}{
+		"origin":  {Sample: [3]int{0, 0, 0}, Expected: 1 - 100},
+		"far":     {Sample: [3]int{2, 2, 2}, Expected: 2 + 10},
+		"x":       {Sample: [3]int{1, 0, 0}, Expected: 1.5 - 100},
+		"y and z": {Sample: [3]int{0, 1, 1}, Expected: 1 + 5 - 50},
+	}
+
+	for name, testCase := range testCases {
+		index := (testCase.Sample[0]*grid.Shape[1]+testCase.Sample[1])*grid.Shape[2] + testCase.Sample[2]
+		if result := grid.Values[index]; result != testCase.Expected {
+			t.Errorf("'%s' failed. Expected %v, received %v", name, testCase.Expected, result)
+		}
+	}
+}
+
+func TestNewGrid_Errors(t *testing.T) {
+	fn := func(t []float64) float64 { return 0 }
+
+	testCases := map[string]struct {
+		From, To   [3]int
+		Resolution int
+		Expected   string
+	}{
+		"no resolution": {From: [3]int{0, 0, 0}, To: [3]int{1, 1, 1}, Resolution: 0, Expected: "Volumes require a positive resolution"},
+		"empty":         {From: [3]int{0, 0, 0}, To: [3]int{1, 0, 1}, Resolution: 1, Expected: "Volumes require To to be greater than From in each dimension"},
+	}
+
+	for name, testCase := range testCases {
+		if _, err := volume.NewGrid(fn, testCase.From, testCase.To, testCase.Resolution); err == nil || err.Error() != testCase.Expected {
+			t.Errorf("'%s' failed. Expected error %s, received %v", name, testCase.Expected, err)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package world
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"github.com/bcokert/terragen/brush"
+	bolt "go.etcd.io/bbolt"
+)
+
+// worldsBucket holds each world as JSON, keyed by its id
+var worldsBucket = []byte("worlds")
+
+// A BoltStore keeps worlds in a bbolt database, so they're kept between restarts of the service
+// The database is shared with other stores, so it's opened and closed by its owner rather than the BoltStore
+type BoltStore struct {
+	db *bolt.DB
+}
+
+// NewBoltStore keeps worlds in the database, creating their bucket if it doesn't exist
+func NewBoltStore(db *bolt.DB) (*BoltStore, error) {
+	if err := db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(worldsBucket)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	return &BoltStore{db: db}, nil
+}
+
+// Create saves a new world for the query, with no strokes
+// Ids count up from the bucket's sequence, like the ids of a MemoryStore
+func (store *BoltStore) Create(query string) (world World, err error) {
+	err = store.db.Update(func(tx *bolt.Tx) error {
+		worlds := tx.Bucket(worldsBucket)
+		sequence, err := worlds.NextSequence()
+		if err != nil {
+			return err
+		}
+
+		world = World{ID: strconv.FormatUint(sequence, 10), Query: query, Strokes: brush.Layer{}}
+		return putWorld(worlds, world)
+	})
+	return world, err
+}
+
+// Get returns the world with the id
+func (store *BoltStore) Get(id string) (world World, err error) {
+	err = store.db.View(func(tx *bolt.Tx) error {
+		world, err = getWorld(tx.Bucket(worldsBucket), id)
+		return err
+	})
+	return world, err
+}
+
+// AddStroke validates a stroke and adds it on top of the world's other strokes
+func (store *BoltStore) AddStroke(id string, stroke brush.Stroke) (World, error) {
+	return store.update(id, func(world *World) error {
+		return world.addStroke(stroke)
+	})
+}
+
+// Undo removes the world's most recent stroke
+func (store *BoltStore) Undo(id string) (World, error) {
+	return store.update(id, func(world *World) error {
+		return world.undo()
+	})
+}
+
+// update changes the world with the id and saves it, all within one transaction so concurrent changes aren't lost
+func (store *BoltStore) update(id string, change func(world *World) error) (world World, err error) {
+	err = store.db.Update(func(tx *bolt.Tx) error {
+		worlds := tx.Bucket(worldsBucket)
+		if world, err = getWorld(worlds, id); err != nil {
+			return err
+		}
+		if err := change(&world); err != nil {
+			return err
+		}
+		return putWorld(worlds, world)
+	})
+	return world, err
+}
+
+// getWorld reads the world with the id from the bucket
+func getWorld(worlds *bolt.Bucket, id string) (World, error) {
+	value := worlds.Get([]byte(id))
+	if value == nil {
+		return World{}, ErrNotFound
+	}
+	world := World{}
+	if err := json.Unmarshal(value, &world); err != nil {
+		return World{}, err
+	}
+	return world, nil
+}
+
+// putWorld saves the world in the bucket under its id
+func putWorld(worlds *bolt.Bucket, world World) error {
+	value, err := json.Marshal(world)
+	if err != nil {
+		return err
+	}
+	return worlds.Put([]byte(world.ID), value)
+}
@@ -0,0 +1,136 @@
+package world
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/bcokert/terragen/brush"
+)
+
+// ErrNotFound is returned when there is no world with an id
+var ErrNotFound = errors.New("World not found")
+
+// ErrNothingToUndo is returned when undoing a stroke of a world that has none
+var ErrNothingToUndo = errors.New("World has no strokes to undo")
+
+// MaxStrokes limits the strokes of a world, since every stroke is evaluated at every sample of the world's noise
+const MaxStrokes = 1000
+
+// ErrTooManyStrokes is returned when adding a stroke to a world that already has MaxStrokes
+var ErrTooManyStrokes = fmt.Errorf("World is limited to %d strokes", MaxStrokes)
+
+// A World is a saved noise query, along with the brush strokes that have been painted on top of it
+// The query is kept encoded, so the world can be regenerated exactly as it was created
+type World struct {
+	ID      string      `json:"id"`
+	Query   string      `json:"query"`
+	Strokes brush.Layer `json:"strokes"`
+}
+
+// A Store saves worlds and their strokes. Implementations are safe to use concurrently
+type Store interface {
+	// Create saves a new world for the query, with no strokes
+	Create(query string) (World, error)
+	// Get returns the world with the id
+	Get(id string) (World, error)
+	// AddStroke validates a stroke and adds it on top of the world's other strokes
+	AddStroke(id string, stroke brush.Stroke) (World, error)
+	// Undo removes the world's most recent stroke
+	Undo(id string) (World, error)
+}
+
+// A MemoryStore keeps worlds in memory, so they're lost when the service stops. It is safe to use concurrently
+type MemoryStore struct {
+	mutex  sync.Mutex
+	worlds map[string]*World
+	nextID int
+}
+
+// NewMemoryStore creates an empty store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{worlds: map[string]*World{}, nextID: 1}
+}
+
+// Create saves a new world for the query, with no strokes
+func (store *MemoryStore) Create(query string) (World, error) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	world := &World{ID: strconv.Itoa(store.nextID), Query: query, Strokes: brush.Layer{}}
+	store.worlds[world.ID] = world
+	store.nextID++
+
+	return world.copy(), nil
+}
+
+// Get returns the world with the id
+func (store *MemoryStore) Get(id string) (World, error) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	world, ok := store.worlds[id]
+	if !ok {
+		return World{}, ErrNotFound
+	}
+	return world.copy(), nil
+}
+
+// AddStroke validates a stroke and adds it on top of the world's other strokes
+func (store *MemoryStore) AddStroke(id string, stroke brush.Stroke) (World, error) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	world, ok := store.worlds[id]
+	if !ok {
+		return World{}, ErrNotFound
+	}
+	if err := world.addStroke(stroke); err != nil {
+		return World{}, err
+	}
+	return world.copy(), nil
+}
+
+// Undo removes the world's most recent stroke
+func (store *MemoryStore) Undo(id string) (World, error) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	world, ok := store.worlds[id]
+	if !ok {
+		return World{}, ErrNotFound
+	}
+	if err := world.undo(); err != nil {
+		return World{}, err
+	}
+	return world.copy(), nil
+}
+
+// addStroke validates a stroke and adds it on top of the world's other strokes, up to MaxStrokes
+func (world *World) addStroke(stroke brush.Stroke) error {
+	if err := stroke.Validate(); err != nil {
+		return err
+	}
+	if len(world.Strokes) >= MaxStrokes {
+		return ErrTooManyStrokes
+	}
+	world.Strokes = append(world.Strokes, stroke)
+	return nil
+}
+
+// undo removes the world's most recent stroke
+func (world *World) undo() error {
+	if len(world.Strokes) == 0 {
+		return ErrNothingToUndo
+	}
+	world.Strokes = world.Strokes[:len(world.Strokes)-1]
+	return nil
+}
+
+// copy returns a world that doesn't share its strokes, so it can be used outside of the store's lock
+func (world *World) copy() World {
+	strokes := make(brush.Layer, len(world.Strokes))
+	copy(strokes, world.Strokes)
+	return World{ID: world.ID, Query: world.Query, Strokes: strokes}
+}
@@ -0,0 +1,159 @@
+package world_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/bcokert/terragen/brush"
+	"github.com/bcokert/terragen/storage"
+	"github.com/bcokert/terragen/world"
+)
+
+func TestMemoryStore(t *testing.T) {
+	testStore(t, world.NewMemoryStore())
+	testStoreErrors(t, world.NewMemoryStore())
+}
+
+func TestBoltStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "terragen")
+	if err != nil {
+		t.Fatalf("Failed to create a temporary directory: %s", err.Error())
+	}
+	defer os.RemoveAll(dir)
+
+	database, err := storage.OpenBoltStore(filepath.Join(dir, "terragen.db"))
+	if err != nil {
+		t.Fatalf("Failed to open the database: %s", err.Error())
+	}
+	defer database.Close()
+
+	// Each change is its own transaction, so syncing is skipped to keep filling a world with strokes quick
+	database.DB().NoSync = true
+	store, err := world.NewBoltStore(database.DB())
+	if err != nil {
+		t.Fatalf("Failed to open the store: %s", err.Error())
+	}
+	testStore(t, store)
+	testStoreErrors(t, store)
+}
+
+func TestBoltStore_Reopen(t *testing.T) {
+	dir, err := ioutil.TempDir("", "terragen")
+	if err != nil {
+		t.Fatalf("Failed to create a temporary directory: %s", err.Error())
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "terragen.db")
+	stroke := brush.Stroke{Radius: 1, Operation: brush.Raise, Strength: 1}
+
+	database, _ := storage.OpenBoltStore(path)
+	store, _ := world.NewBoltStore(database.DB())
+	created, _ := store.Create("seed=1")
+	store.AddStroke(created.ID, stroke)
+	database.Close()
+
+	database, err = storage.OpenBoltStore(path)
+	if err != nil {
+		t.Fatalf("Failed to reopen the database: %s", err.Error())
+	}
+	defer database.Close()
+	store, err = world.NewBoltStore(database.DB())
+	if err != nil {
+		t.Fatalf("Failed to reopen the store: %s", err.Error())
+	}
+
+	if saved, err := store.Get(created.ID); err != nil || saved.Query != "seed=1" || !reflect.DeepEqual(saved.Strokes, brush.Layer{stroke}) {
+		t.Errorf("Expected the world and its stroke to be kept, received %+v and %v", saved, err)
+	}
+	if next, _ := store.Create("seed=2"); next.ID == created.ID {
+		t.Errorf("Expected new worlds to have unique ids after reopening, received %s twice", next.ID)
+	}
+}
+
+// testStore checks the behaviour every store shares
+func testStore(t *testing.T, store world.Store) {
+	raise := brush.Stroke{Position: [2]float64{1, 1}, Radius: 1, Operation: brush.Raise, Strength: 2}
+	smooth := brush.Stroke{Position: [2]float64{2, 1}, Radius: 2, Falloff: 1, Operation: brush.Smooth, Strength: 0.5}
+
+	first, err := store.Create("seed=1")
+	if err != nil {
+		t.Fatalf("Expected no error creating a world, received %s", err.Error())
+	}
+	second, _ := store.Create("seed=2")
+	if first.ID == second.ID {
+		t.Errorf("Expected worlds to have unique ids, received %s twice", first.ID)
+	}
+
+	if _, err := store.AddStroke(first.ID, raise); err != nil {
+		t.Errorf("Expected no error adding a stroke, received %s", err.Error())
+	}
+	updated, _ := store.AddStroke(first.ID, smooth)
+	if !reflect.DeepEqual(updated.Strokes, brush.Layer{raise, smooth}) {
+		t.Errorf("Expected strokes in the order they were added, received %v", updated.Strokes)
+	}
+
+	// Worlds returned by the store don't share their strokes with it
+	updated.Strokes[0].Strength = 100
+	if saved, _ := store.Get(first.ID); saved.Strokes[0].Strength != 2 {
+		t.Errorf("Expected the saved stroke to be unchanged, received %v", saved.Strokes[0])
+	}
+
+	undone, _ := store.Undo(first.ID)
+	if !reflect.DeepEqual(undone.Strokes, brush.Layer{raise}) {
+		t.Errorf("Expected the last stroke to be undone, received %v", undone.Strokes)
+	}
+
+	if saved, _ := store.Get(second.ID); saved.Query != "seed=2" || len(saved.Strokes) != 0 {
+		t.Errorf("Expected the other world to be untouched, received %+v", saved)
+	}
+}
+
+// testStoreErrors checks the errors every store shares
+func testStoreErrors(t *testing.T, store world.Store) {
+	created, _ := store.Create("seed=1")
+	stroke := brush.Stroke{Radius: 1, Operation: brush.Lower, Strength: 1}
+	full, _ := store.Create("seed=2")
+	for i := 0; i < world.MaxStrokes; i++ {
+		store.AddStroke(full.ID, stroke)
+	}
+
+	testCases := map[string]struct {
+		Action        func() error
+		ExpectedError string
+	}{
+		"get missing": {
+			Action:        func() error { _, err := store.Get("missing"); return err },
+			ExpectedError: world.ErrNotFound.Error(),
+		},
+		"stroke missing": {
+			Action:        func() error { _, err := store.AddStroke("missing", stroke); return err },
+			ExpectedError: world.ErrNotFound.Error(),
+		},
+		"invalid stroke": {
+			Action:        func() error { _, err := store.AddStroke(created.ID, brush.Stroke{Radius: 1}); return err },
+			ExpectedError: "Operation must be one of raise, lower, flatten or smooth",
+		},
+		"too many strokes": {
+			Action:        func() error { _, err := store.AddStroke(full.ID, stroke); return err },
+			ExpectedError: world.ErrTooManyStrokes.Error(),
+		},
+		"undo missing": {
+			Action:        func() error { _, err := store.Undo("missing"); return err },
+			ExpectedError: world.ErrNotFound.Error(),
+		},
+		"undo nothing": {
+			Action:        func() error { _, err := store.Undo(created.ID); return err },
+			ExpectedError: world.ErrNothingToUndo.Error(),
+		},
+	}
+
+	for name, testCase := range testCases {
+		err := testCase.Action()
+		if err == nil || err.Error() != testCase.ExpectedError {
+			t.Errorf("'%s' failed. Expected error %s, received %v", name, testCase.ExpectedError, err)
+		}
+	}
+}